@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/strongdm/agate/internal/agent"
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+var agentsCmd = &cobra.Command{
+	Use:   "agents",
+	Short: "List known agents and which are installed",
+	Long: `List every agent agate knows about with its model and notes.
+
+A ✓ marks agents whose CLI is installed on PATH (claude and haiku need
+the claude CLI, codex needs the codex CLI); the dummy agent is always
+available. The default agent is the one used when --agent is not given
+and no skill prefers another: the first available of claude, haiku,
+codex, dummy.`,
+	RunE: runAgents,
+}
+
+func init() {
+	rootCmd.AddCommand(agentsCmd)
+}
+
+func runAgents(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+
+	defaultName := ""
+	if available := agent.GetAvailableAgents(); len(available) > 0 {
+		defaultName = available[0].Name()
+	}
+
+	for _, info := range agent.GetAllAgentInfo() {
+		mark := logging.Red("✗")
+		if a := agent.GetAgentByName(info.Name); a != nil && a.Available() {
+			mark = logging.Green("✓")
+		}
+		line := fmt.Sprintf("%s %-7s %-18s %s", mark, info.Name, info.Model, info.Notes)
+		if info.Name == defaultName {
+			line += " " + logging.BoldCyan("(default)")
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	fmt.Fprintf(out, "\nDefault agent: %s\n", defaultName)
+	SetExitCode(0)
+	return nil
+}
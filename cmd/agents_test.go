@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// agentLine returns the agents output line listing name
+func agentLine(t *testing.T, output, name string) string {
+	t.Helper()
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, " "+name+" ") {
+			return line
+		}
+	}
+	t.Fatalf("no line for %s in output:\n%s", name, output)
+	return ""
+}
+
+func TestAgents_ReflectsInstalledCLIs(t *testing.T) {
+	tests := []struct {
+		name        string
+		binaries    []string
+		available   []string
+		missing     []string
+		defaultName string
+	}{
+		{"claude only", []string{"claude"}, []string{"claude", "haiku", "dummy"}, []string{"codex"}, "claude"},
+		{"codex only", []string{"codex"}, []string{"codex", "dummy"}, []string{"claude", "haiku"}, "codex"},
+		{"none", nil, []string{"dummy"}, []string{"claude", "haiku", "codex"}, "dummy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bin := t.TempDir()
+			for _, name := range tt.binaries {
+				if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Setenv("PATH", bin)
+
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			if err := runRoot(t, "agents"); err != nil {
+				t.Fatalf("agents failed: %v", err)
+			}
+			output := out.String()
+
+			for _, name := range tt.available {
+				if line := agentLine(t, output, name); !strings.Contains(line, "✓") {
+					t.Errorf("expected %s available, got %q", name, line)
+				}
+			}
+			for _, name := range tt.missing {
+				if line := agentLine(t, output, name); !strings.Contains(line, "✗") {
+					t.Errorf("expected %s unavailable, got %q", name, line)
+				}
+			}
+			if !strings.Contains(agentLine(t, output, tt.defaultName), "(default)") {
+				t.Errorf("expected %s marked default, got:\n%s", tt.defaultName, output)
+			}
+			if !strings.Contains(output, "Default agent: "+tt.defaultName) {
+				t.Errorf("expected default agent %s, got:\n%s", tt.defaultName, output)
+			}
+		})
+	}
+}
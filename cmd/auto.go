@@ -2,17 +2,35 @@ package cmd
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/strongdm/agate/internal/agent"
 	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+	"github.com/strongdm/agate/internal/workflow"
 )
 
 var autoAgent string
+var autoEvents string
+var autoMaxSteps int
+var autoMaxErrors int
+var autoNoRecovery bool
+var autoWebhook string
+var autoDoubleReview bool
+var autoStrictReview bool
+var autoStrictSkills bool
+var autoKeepGoing bool
+var autoModel string
+var autoEffort string
+var autoNoProbe bool
 
 var autoCmd = &cobra.Command{
 	Use:   "auto",
@@ -26,29 +44,145 @@ Behavior by exit code from each step:
   Other - Error, stop looping
 
 On exit 255, the loop stops so you can take action (create GOAL.md,
-answer interview questions, etc.) then re-run 'agate auto'.
+answer interview questions, log an agent's CLI in, etc.) then re-run
+'agate auto'.
 
 Any text typed on stdin between steps is sent as a suggestion
 via 'agate suggest' before the next step.
 
+When the loop ends, the wall-clock time of each step and the total are
+printed so slow steps stand out.
+
+Use --events <file> to append one JSON line per step for programmatic
+monitoring: {"step":N,"exitCode":N,"action":"...","consecutiveErrors":N}
+
+Use --max-steps <n> to stop after n 'agate next' invocations, as a safety
+cap against runaway runs. Re-run 'agate auto' to continue.
+
+Use --max-errors <n> to stop after n consecutive failed steps (default 3).
+A successful step resets the count. Use --max-errors 1 in CI to stop on
+the first error.
+
+Use --no-recovery to pass --no-recovery to every 'agate next' step, so
+sub-task errors surface as-is instead of running the recovery agent.
+
+Use --double-review to pass --double-review to every 'agate next' step, so
+reviewer sub-tasks need approval from two agents.
+
+Use --strict-review to pass --strict-review to every 'agate next' step, so
+reviews without an explicit APPROVED line count as failures.
+
+Use --strict-skills to pass --strict-skills to every 'agate next' step, so
+a sub-task naming an unknown skill stops the run for a human.
+
+Use --webhook <url> to pass --webhook to every 'agate next' step, so each
+step POSTs a JSON progress update. Failed POSTs only print a warning.
+
+Before the first step, the selected agent gets a trivial test prompt. If
+it errors or doesn't answer within a minute, auto stops with exit 2
+instead of starting a long run against a broken CLI. Use --no-probe to
+skip the check.
+
+Auto holds the project's run-lock (.ai/.run.lock) for the whole loop, so
+a second 'agate auto' or 'agate next' in the same project exits 2 instead
+of racing it.
+
 Exit codes:
   0   - All work complete
+  1   - Stopped at --max-steps with work remaining
+  2   - Error, including a failed agent probe
   255 - Human action required`,
 	RunE: runAuto,
 }
 
 func init() {
 	autoCmd.Flags().StringVarP(&autoAgent, "agent", "a", "", "Select agent: haiku, claude, codex, dummy")
+	autoCmd.Flags().StringVar(&autoEvents, "events", "", "Append JSON step events to this file")
+	autoCmd.Flags().IntVar(&autoMaxSteps, "max-steps", 0, "Stop after this many steps (0 = unlimited)")
+	autoCmd.Flags().IntVar(&autoMaxErrors, "max-errors", DefaultMaxConsecutiveErrors, "Stop after this many consecutive errors")
+	autoCmd.Flags().BoolVar(&autoNoRecovery, "no-recovery", false, "Fail sub-task errors immediately instead of running the recovery agent")
+	autoCmd.Flags().BoolVar(&autoDoubleReview, "double-review", false, "Require reviewer sub-tasks to be approved by two agents")
+	autoCmd.Flags().BoolVar(&autoStrictReview, "strict-review", false, "Fail reviews that lack an explicit APPROVED line")
+	autoCmd.Flags().BoolVar(&autoStrictSkills, "strict-skills", false, "Stop instead of warning when a sub-task names an unknown skill")
+	autoCmd.Flags().StringVar(&autoModel, "model", "", "Model for the codex agent (default: codex's own)")
+	autoCmd.Flags().StringVar(&autoEffort, "effort", "", "Reasoning effort for the codex agent, e.g. low, medium, high")
+	autoCmd.Flags().BoolVar(&autoKeepGoing, "keep-going", false, "Skip a task that keeps failing review instead of stopping for a human")
+	autoCmd.Flags().BoolVar(&autoNoProbe, "no-probe", false, "Skip the agent test prompt before the first step")
+	autoCmd.Flags().StringVar(&autoWebhook, "webhook", "", "POST a JSON progress update to this URL after each step")
 	rootCmd.AddCommand(autoCmd)
 }
 
 func runAuto(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+	if err := checkModelFlags(autoAgent, autoModel, autoEffort); err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+	// Hold the run-lock for the whole loop; each 'agate next' step shares it
+	release, err := acquireRunLock(cwd)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	runner := NewAutoRunner(realExec, os.Stdin, os.Stdout, os.Stderr)
+	runner.MaxSteps = autoMaxSteps
+	runner.MaxConsecutiveErrors = autoMaxErrors
+	runner.NoRecovery = autoNoRecovery
+	runner.DoubleReview = autoDoubleReview
+	runner.StrictReview = autoStrictReview
+	runner.StrictSkills = autoStrictSkills
+	runner.KeepGoing = autoKeepGoing
+	runner.Model = autoModel
+	runner.Effort = autoEffort
+	runner.Webhook = autoWebhook
+	if !autoNoProbe {
+		runner.Probe = agentProbe(autoAgent, agent.ModelOptions{Model: autoModel, Effort: autoEffort}, agent.DefaultProbeTimeout)
+	}
+	if autoEvents != "" {
+		f, err := os.OpenFile(autoEvents, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			PrintError("failed to open events file: %v", err)
+			SetExitCode(2)
+			return err
+		}
+		defer f.Close()
+		runner.Events = f
+	}
 	code := runner.Run(autoAgent)
 	SetExitCode(code)
 	return nil
 }
 
+// agentProbe returns a probe that sends the named agent a test prompt. With
+// no name (or "auto") it probes claude, which plans and reviews by default.
+// An unavailable agent falls back to the first available one, as next does,
+// and the probed agent gets models as the step would.
+func agentProbe(name string, models agent.ModelOptions, timeout time.Duration) func() error {
+	return func() error {
+		if name == "" || name == workflow.AutoAgent {
+			name = "claude"
+		}
+		selected := agent.GetAgentByName(name)
+		if selected == nil || !selected.Available() {
+			agents := agent.GetAvailableAgents()
+			if len(agents) == 0 {
+				return agent.NoAgentsError{}
+			}
+			selected = agents[0]
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return agent.ProbeAgent(ctx, models.Apply(selected))
+	}
+}
+
 // ExecFunc runs an agate subcommand as a subprocess.
 // args are the subcommand arguments (e.g. ["next", "--agent", "claude"]).
 // stdout and stderr receive the child process output.
@@ -61,6 +195,16 @@ func realExec(args []string, stdout, stderr io.Writer) (int, error) {
 	if err != nil {
 		binary = os.Args[0]
 	}
+	// Child processes must use the same project and state dir
+	args = append([]string{}, args...)
+	if projectDirFlag != "" {
+		if dir, err := getProjectDir(); err == nil {
+			args = append(args, "--project-dir", dir)
+		}
+	}
+	if dir := stateDirFlag; dir != project.DefaultStateDir {
+		args = append(args, "--state-dir", dir)
+	}
 	c := exec.Command(binary, args...)
 	c.Stdout = stdout
 	c.Stderr = stderr
@@ -74,6 +218,26 @@ func realExec(args []string, stdout, stderr io.Writer) (int, error) {
 	return 0, nil
 }
 
+// AutoEvent is a structured record of one auto loop step, emitted as a
+// JSON line so a supervising process can track progress.
+type AutoEvent struct {
+	Step              int    `json:"step"`
+	ExitCode          int    `json:"exitCode"`
+	Action            string `json:"action"`
+	ConsecutiveErrors int    `json:"consecutiveErrors"`
+}
+
+// Actions reported in AutoEvent.Action
+const (
+	ActionContinue  = "continue"   // More work, loop again
+	ActionDone      = "done"       // All work complete
+	ActionHuman     = "human"      // Human action required
+	ActionRetry     = "retry"      // Step errored, retrying
+	ActionStop      = "stop"       // Too many errors, stopping
+	ActionExecError = "exec_error" // Could not run the step at all
+	ActionMaxSteps  = "max_steps"  // Step cap reached with work remaining
+)
+
 // AutoRunner implements the auto command loop.
 // All interaction with agate subcommands goes through Exec,
 // making the loop fully testable without real subprocesses.
@@ -82,20 +246,81 @@ type AutoRunner struct {
 	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
+	// Events receives one JSON-encoded AutoEvent per step (defaults to io.Discard)
+	Events io.Writer
+	// MaxSteps caps the total number of next invocations (0 = unlimited)
+	MaxSteps int
+	// MaxConsecutiveErrors is how many failed steps in a row stop the loop
+	// (values below 1 stop on the first error)
+	MaxConsecutiveErrors int
+	// NoRecovery passes --no-recovery to each next step
+	NoRecovery bool
+	// DoubleReview passes --double-review to each next step
+	DoubleReview bool
+	// StrictReview passes --strict-review to each next step
+	StrictReview bool
+	// StrictSkills passes --strict-skills to each next step
+	StrictSkills bool
+	// KeepGoing passes --keep-going to each next step
+	KeepGoing bool
+	// Model and Effort, if set, are passed as --model and --effort to each
+	// next step
+	Model  string
+	Effort string
+	// Webhook, if set, is passed as --webhook to each next step
+	Webhook string
+	// StepDurations holds the wall-clock time of each next invocation from
+	// the last Run, in step order
+	StepDurations []time.Duration
+	// Probe, if set, runs before the first step; an error aborts the run
+	Probe func() error
 }
 
+// DefaultMaxConsecutiveErrors is the consecutive-error threshold used by
+// NewAutoRunner
+const DefaultMaxConsecutiveErrors = 3
+
 // NewAutoRunner creates an AutoRunner.
-func NewAutoRunner(execFn ExecFunc, stdin io.Reader, stdout, stderr io.Writer) *AutoRunner {
-	return &AutoRunner{
-		Exec:   execFn,
-		Stdin:  stdin,
-		Stdout: stdout,
-		Stderr: stderr,
+// An optional events writer receives one JSON line per step.
+func NewAutoRunner(execFn ExecFunc, stdin io.Reader, stdout, stderr io.Writer, events ...io.Writer) *AutoRunner {
+	r := &AutoRunner{
+		Exec:                 execFn,
+		Stdin:                stdin,
+		Stdout:               stdout,
+		Stderr:               stderr,
+		Events:               io.Discard,
+		MaxConsecutiveErrors: DefaultMaxConsecutiveErrors,
+	}
+	if len(events) > 0 && events[0] != nil {
+		r.Events = events[0]
+	}
+	return r
+}
+
+// emit writes a structured event for a step to the events sink
+func (r *AutoRunner) emit(step, exitCode int, action string, consecutiveErrors int) {
+	if r.Events == nil {
+		return
 	}
+	json.NewEncoder(r.Events).Encode(AutoEvent{
+		Step:              step,
+		ExitCode:          exitCode,
+		Action:            action,
+		ConsecutiveErrors: consecutiveErrors,
+	})
 }
 
 // Run executes the auto loop. Returns the process exit code.
 func (r *AutoRunner) Run(agent string) int {
+	if r.Probe != nil {
+		fmt.Fprintf(r.Stdout, "%s Checking that the agent responds...\n", logging.BoldCyan("[auto]"))
+		if err := r.Probe(); err != nil {
+			fmt.Fprintf(r.Stderr, "%s %s\n", logging.BoldCyan("[auto]"), logging.Red(fmt.Sprintf("Agent probe failed: %v", err)))
+			fmt.Fprintf(r.Stderr, "%s Fix the agent CLI, choose another with --agent, or skip this check with --no-probe\n", logging.BoldCyan("[auto]"))
+			return workflow.ExitError
+		}
+	}
+
 	// Read stdin lines in background so we can pick up unsolicited input
 	inputCh := make(chan string, 100)
 	go func() {
@@ -106,11 +331,25 @@ func (r *AutoRunner) Run(agent string) int {
 		}
 	}()
 
-	const maxConsecutiveErrors = 3
+	maxConsecutiveErrors := r.MaxConsecutiveErrors
+	if maxConsecutiveErrors < 1 {
+		maxConsecutiveErrors = 1
+	}
+
+	r.StepDurations = nil
+	defer r.printTimings()
 
 	step := 0
 	consecutiveErrors := 0
+	lastExit := 0
 	for {
+		// Stop before exceeding the step cap; retries count toward it too
+		if r.MaxSteps > 0 && step >= r.MaxSteps {
+			r.emit(step, lastExit, ActionMaxSteps, consecutiveErrors)
+			fmt.Fprintf(r.Stderr, "%s %s\n", logging.BoldCyan("[auto]"), logging.Yellow(fmt.Sprintf("Stopped after reaching max steps (%d) with work remaining", r.MaxSteps)))
+			return 1
+		}
+
 		// Drain any pending input and send as suggestions
 		r.drainSuggestions(inputCh)
 
@@ -121,37 +360,96 @@ func (r *AutoRunner) Run(agent string) int {
 		if agent != "" {
 			args = append(args, "--agent", agent)
 		}
+		if r.NoRecovery {
+			args = append(args, "--no-recovery")
+		}
+		if r.DoubleReview {
+			args = append(args, "--double-review")
+		}
+		if r.StrictReview {
+			args = append(args, "--strict-review")
+		}
+		if r.StrictSkills {
+			args = append(args, "--strict-skills")
+		}
+		if r.KeepGoing {
+			args = append(args, "--keep-going")
+		}
+		if r.Model != "" {
+			args = append(args, "--model", r.Model)
+		}
+		if r.Effort != "" {
+			args = append(args, "--effort", r.Effort)
+		}
+		if r.Webhook != "" {
+			args = append(args, "--webhook", r.Webhook)
+		}
 
+		started := time.Now()
 		exitCode, err := r.Exec(args, r.Stdout, r.Stderr)
+		r.StepDurations = append(r.StepDurations, time.Since(started))
+		lastExit = exitCode
 		if err != nil {
 			fmt.Fprintf(r.Stderr, "%s %s\n", logging.BoldCyan("[auto]"), logging.Yellow(fmt.Sprintf("Failed to execute: %v", err)))
+			r.emit(step, exitCode, ActionExecError, consecutiveErrors)
 			return 2
 		}
 
 		switch exitCode {
 		case 0:
+			r.emit(step, exitCode, ActionDone, consecutiveErrors)
 			fmt.Fprintf(r.Stdout, "%s %s\n", logging.BoldCyan("[auto]"), logging.Green("Done!"))
 			return 0
 		case 1:
 			// More work, loop
 			consecutiveErrors = 0
+			r.emit(step, exitCode, ActionContinue, consecutiveErrors)
 			continue
 		case 255:
 			// Human action needed — exit so user can act
+			r.emit(step, exitCode, ActionHuman, consecutiveErrors)
 			fmt.Fprintf(r.Stdout, "%s %s\n", logging.BoldCyan("[auto]"), logging.Yellow("Human action required, exiting."))
 			return 255
 		default:
 			consecutiveErrors++
 			if consecutiveErrors >= maxConsecutiveErrors {
+				r.emit(step, exitCode, ActionStop, consecutiveErrors)
 				fmt.Fprintf(r.Stderr, "%s %s\n", logging.BoldCyan("[auto]"), logging.Yellow(fmt.Sprintf("Stopped after %d consecutive errors (last exit code %d)", consecutiveErrors, exitCode)))
 				return exitCode
 			}
+			r.emit(step, exitCode, ActionRetry, consecutiveErrors)
 			fmt.Fprintf(r.Stderr, "%s %s\n", logging.BoldCyan("[auto]"), logging.Yellow(fmt.Sprintf("Error (exit code %d), retrying (%d/%d)...", exitCode, consecutiveErrors, maxConsecutiveErrors)))
 			continue
 		}
 	}
 }
 
+// printTimings prints each step's duration and the total, so a slow step
+// stands out when the loop ends
+func (r *AutoRunner) printTimings() {
+	if len(r.StepDurations) == 0 {
+		return
+	}
+	var total time.Duration
+	for _, d := range r.StepDurations {
+		total += d
+	}
+	fmt.Fprintf(r.Stdout, "%s Timing:\n", logging.BoldCyan("[auto]"))
+	for i, d := range r.StepDurations {
+		fmt.Fprintf(r.Stdout, "  Step %d: %s\n", i+1, formatStepDuration(d))
+	}
+	fmt.Fprintf(r.Stdout, "  Total: %s\n", formatStepDuration(total))
+}
+
+// formatStepDuration rounds d for display: milliseconds under a second,
+// tenths of a second otherwise
+func formatStepDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
 // drainSuggestions sends any pending stdin lines as suggestions (non-blocking).
 func (r *AutoRunner) drainSuggestions(ch <-chan string) {
 	for {
@@ -2,11 +2,16 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/strongdm/agate/internal/agent"
 )
 
 // mockCall records a single call to the exec function.
@@ -141,6 +146,166 @@ func TestAutoRunner_ErrorCounterResetsOnSuccess(t *testing.T) {
 	}
 }
 
+func TestAutoRunner_CustomMaxErrors(t *testing.T) {
+	// 5 consecutive errors stop the loop, even after earlier errors reset
+	exec, calls := mockExec([]int{2, 2, 2, 2, 1, 2, 2, 2, 2, 2, 1})
+	var stdout, stderr bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &stdout, &stderr)
+	runner.MaxConsecutiveErrors = 5
+
+	code := runner.Run("")
+	if code != 2 {
+		t.Errorf("expected exit 2, got %d", code)
+	}
+
+	nextCalls := filterCalls(*calls, "next")
+	if len(nextCalls) != 10 {
+		t.Errorf("expected 10 next calls (4 errors + 1 ok + 5 errors), got %d", len(nextCalls))
+	}
+	if !strings.Contains(stderr.String(), "Stopped after 5 consecutive errors") {
+		t.Errorf("expected stop after 5 errors, got stderr: %s", stderr.String())
+	}
+}
+
+func TestAutoRunner_MaxErrorsOneStopsOnFirstError(t *testing.T) {
+	exec, calls := mockExec([]int{1, 2, 0})
+	var stdout, stderr bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &stdout, &stderr)
+	runner.MaxConsecutiveErrors = 1
+
+	if code := runner.Run(""); code != 2 {
+		t.Errorf("expected exit 2, got %d", code)
+	}
+	if nextCalls := filterCalls(*calls, "next"); len(nextCalls) != 2 {
+		t.Errorf("expected 2 next calls, got %d", len(nextCalls))
+	}
+	if strings.Contains(stderr.String(), "retrying") {
+		t.Errorf("expected no retry, got stderr: %s", stderr.String())
+	}
+}
+
+func TestAutoRunner_PassesNoRecoveryFlag(t *testing.T) {
+	exec, calls := mockExec([]int{0})
+	var out bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &out, &out)
+	runner.NoRecovery = true
+
+	runner.Run("dummy")
+
+	nextCalls := filterCalls(*calls, "next")
+	if len(nextCalls) != 1 {
+		t.Fatalf("expected 1 next call, got %d", len(nextCalls))
+	}
+	if args := strings.Join(nextCalls[0].Args, " "); args != "next --agent dummy --no-recovery" {
+		t.Errorf("expected next --agent dummy --no-recovery, got %s", args)
+	}
+}
+
+func TestAutoRunner_PassesDoubleReviewFlag(t *testing.T) {
+	exec, calls := mockExec([]int{0})
+	var out bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &out, &out)
+	runner.DoubleReview = true
+
+	runner.Run("claude")
+
+	nextCalls := filterCalls(*calls, "next")
+	if len(nextCalls) != 1 {
+		t.Fatalf("expected 1 next call, got %d", len(nextCalls))
+	}
+	if args := strings.Join(nextCalls[0].Args, " "); args != "next --agent claude --double-review" {
+		t.Errorf("expected next --agent claude --double-review, got %s", args)
+	}
+}
+
+func TestAutoRunner_PassesStrictReviewFlag(t *testing.T) {
+	exec, calls := mockExec([]int{0})
+	var out bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &out, &out)
+	runner.StrictReview = true
+
+	runner.Run("")
+
+	nextCalls := filterCalls(*calls, "next")
+	if len(nextCalls) != 1 {
+		t.Fatalf("expected 1 next call, got %d", len(nextCalls))
+	}
+	if args := strings.Join(nextCalls[0].Args, " "); args != "next --strict-review" {
+		t.Errorf("expected next --strict-review, got %s", args)
+	}
+}
+
+func TestAutoRunner_PassesStrictSkillsFlag(t *testing.T) {
+	exec, calls := mockExec([]int{0})
+	var out bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &out, &out)
+	runner.StrictSkills = true
+
+	runner.Run("")
+
+	nextCalls := filterCalls(*calls, "next")
+	if len(nextCalls) != 1 {
+		t.Fatalf("expected 1 next call, got %d", len(nextCalls))
+	}
+	if args := strings.Join(nextCalls[0].Args, " "); args != "next --strict-skills" {
+		t.Errorf("expected next --strict-skills, got %s", args)
+	}
+}
+
+func TestAutoRunner_PassesKeepGoingFlag(t *testing.T) {
+	exec, calls := mockExec([]int{0})
+	var out bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &out, &out)
+	runner.KeepGoing = true
+
+	runner.Run("")
+
+	nextCalls := filterCalls(*calls, "next")
+	if len(nextCalls) != 1 {
+		t.Fatalf("expected 1 next call, got %d", len(nextCalls))
+	}
+	if args := strings.Join(nextCalls[0].Args, " "); args != "next --keep-going" {
+		t.Errorf("expected next --keep-going, got %s", args)
+	}
+}
+
+func TestAutoRunner_PassesModelAndEffortFlags(t *testing.T) {
+	exec, calls := mockExec([]int{0})
+	var out bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &out, &out)
+	runner.Model = "o3"
+	runner.Effort = "high"
+
+	runner.Run("codex")
+
+	nextCalls := filterCalls(*calls, "next")
+	if len(nextCalls) != 1 {
+		t.Fatalf("expected 1 next call, got %d", len(nextCalls))
+	}
+	if args := strings.Join(nextCalls[0].Args, " "); args != "next --agent codex --model o3 --effort high" {
+		t.Errorf("expected next --agent codex --model o3 --effort high, got %s", args)
+	}
+}
+
+func TestAutoRunner_PassesWebhookFlag(t *testing.T) {
+	exec, calls := mockExec([]int{1, 0})
+	var out bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &out, &out)
+	runner.Webhook = "https://dashboard.example/hook"
+
+	runner.Run("")
+
+	nextCalls := filterCalls(*calls, "next")
+	if len(nextCalls) != 2 {
+		t.Fatalf("expected 2 next calls, got %d", len(nextCalls))
+	}
+	for _, c := range nextCalls {
+		if args := strings.Join(c.Args, " "); args != "next --webhook https://dashboard.example/hook" {
+			t.Errorf("expected webhook on every step, got %s", args)
+		}
+	}
+}
+
 func TestAutoRunner_StopsOn255(t *testing.T) {
 	exec, calls := mockExec([]int{255})
 	var stdout, stderr bytes.Buffer
@@ -243,6 +408,52 @@ func TestAutoRunner_StopsOnUnexpectedExitCode(t *testing.T) {
 }
 
 // filterCalls returns calls whose first arg matches the given command.
+func TestAutoRunner_EmitsEvents(t *testing.T) {
+	exec, _ := mockExec([]int{1, 1, 0})
+	var out, events bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &out, &out, &events)
+
+	if code := runner.Run(""); code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+
+	want := []AutoEvent{
+		{Step: 1, ExitCode: 1, Action: ActionContinue},
+		{Step: 2, ExitCode: 1, Action: ActionContinue},
+		{Step: 3, ExitCode: 0, Action: ActionDone},
+	}
+
+	lines := strings.Split(strings.TrimSpace(events.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d event lines, got %d: %s", len(want), len(lines), events.String())
+	}
+	for i, line := range lines {
+		var got AutoEvent
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v", i, err)
+		}
+		if got != want[i] {
+			t.Errorf("event %d: expected %+v, got %+v", i, want[i], got)
+		}
+	}
+
+	// Human-readable output is unchanged
+	if !strings.Contains(out.String(), "[auto] Done!") {
+		t.Errorf("expected Done message, got: %s", out.String())
+	}
+}
+
+func TestAutoRunner_NoEventsByDefault(t *testing.T) {
+	exec, _ := mockExec([]int{0})
+	var out bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &out, &out)
+
+	if runner.Events != io.Discard {
+		t.Error("expected events sink to default to io.Discard")
+	}
+	runner.Run("")
+}
+
 func filterCalls(calls []mockCall, command string) []mockCall {
 	var filtered []mockCall
 	for _, c := range calls {
@@ -252,3 +463,145 @@ func filterCalls(calls []mockCall, command string) []mockCall {
 	}
 	return filtered
 }
+
+func TestAutoRunner_StopsAtMaxSteps(t *testing.T) {
+	codes := make([]int, 20)
+	for i := range codes {
+		codes[i] = 1
+	}
+	exec, calls := mockExec(codes)
+	var stdout, stderr, events bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &stdout, &stderr, &events)
+	runner.MaxSteps = 5
+
+	code := runner.Run("")
+	if code != 1 {
+		t.Errorf("expected exit 1, got %d", code)
+	}
+
+	nextCalls := filterCalls(*calls, "next")
+	if len(nextCalls) != 5 {
+		t.Errorf("expected 5 next calls, got %d", len(nextCalls))
+	}
+
+	if !strings.Contains(stderr.String(), "max steps (5)") {
+		t.Errorf("expected max steps message, got stderr: %s", stderr.String())
+	}
+	if strings.Contains(stderr.String(), "consecutive errors") {
+		t.Errorf("max steps should not be reported as consecutive errors: %s", stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(events.String()), "\n")
+	var last AutoEvent
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &last); err != nil {
+		t.Fatalf("failed to parse last event: %v", err)
+	}
+	if last.Action != ActionMaxSteps || last.Step != 5 {
+		t.Errorf("expected final max_steps event at step 5, got %+v", last)
+	}
+}
+
+func TestAutoRunner_MaxStepsCountsRetries(t *testing.T) {
+	exec, calls := mockExec([]int{1, 2, 1, 2, 1, 0})
+	var stdout, stderr bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &stdout, &stderr)
+	runner.MaxSteps = 4
+
+	if code := runner.Run(""); code != 1 {
+		t.Errorf("expected exit 1, got %d", code)
+	}
+	if n := len(filterCalls(*calls, "next")); n != 4 {
+		t.Errorf("expected 4 next calls, got %d", n)
+	}
+}
+
+func TestAutoRunner_RecordsStepDurations(t *testing.T) {
+	inner, _ := mockExec([]int{1, 1, 0})
+	exec := func(args []string, stdout, stderr io.Writer) (int, error) {
+		time.Sleep(10 * time.Millisecond)
+		return inner(args, stdout, stderr)
+	}
+	var out bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &out, &out)
+
+	if code := runner.Run(""); code != 0 {
+		t.Fatalf("expected exit 0, got %d", code)
+	}
+
+	if len(runner.StepDurations) != 3 {
+		t.Fatalf("expected 3 step durations, got %d", len(runner.StepDurations))
+	}
+	for i, d := range runner.StepDurations {
+		if d < 10*time.Millisecond {
+			t.Errorf("step %d duration %v shorter than the exec sleep", i+1, d)
+		}
+	}
+
+	output := out.String()
+	for _, want := range []string{"[auto] Timing:", "Step 1: ", "Step 3: ", "Total: "} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+	if strings.Index(output, "Done!") > strings.Index(output, "Timing:") {
+		t.Errorf("expected timing summary after Done, got: %s", output)
+	}
+}
+
+func TestAutoRunner_PrintsTimingOnStop(t *testing.T) {
+	exec, _ := mockExec([]int{2, 2})
+	var stdout, stderr bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &stdout, &stderr)
+	runner.MaxConsecutiveErrors = 2
+
+	runner.Run("")
+	if len(runner.StepDurations) != 2 {
+		t.Errorf("expected 2 step durations, got %d", len(runner.StepDurations))
+	}
+	if !strings.Contains(stdout.String(), "Total: ") {
+		t.Errorf("expected timing total on stop, got: %s", stdout.String())
+	}
+}
+
+func TestAutoRunner_AbortsWhenProbeHangs(t *testing.T) {
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "claude"), []byte("#!/bin/sh\nexec sleep 10\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	exec, calls := mockExec([]int{0})
+	var stdout, stderr bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &stdout, &stderr)
+	runner.Probe = agentProbe("claude", agent.ModelOptions{}, 200*time.Millisecond)
+
+	start := time.Now()
+	code := runner.Run("claude")
+	if code != 2 {
+		t.Errorf("expected exit 2, got %d", code)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("probe took %s, expected it to stop at the timeout", elapsed)
+	}
+	if len(*calls) != 0 {
+		t.Errorf("expected no steps after a failed probe, got %v", *calls)
+	}
+	if !strings.Contains(stderr.String(), "did not answer") || !strings.Contains(stderr.String(), "--no-probe") {
+		t.Errorf("expected a probe failure message, got:\n%s", stderr.String())
+	}
+}
+
+func TestAutoRunner_RunsAfterProbe(t *testing.T) {
+	exec, calls := mockExec([]int{0})
+	var out bytes.Buffer
+	runner := NewAutoRunner(exec, strings.NewReader(""), &out, &out)
+	probed := false
+	runner.Probe = func() error { probed = true; return nil }
+
+	if code := runner.Run(""); code != 0 {
+		t.Errorf("expected exit 0, got %d", code)
+	}
+	if !probed || len(filterCalls(*calls, "next")) != 1 {
+		t.Errorf("expected a probe then one next call, probed=%v calls=%v", probed, *calls)
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var chatAgent string
+
+var chatCmd = &cobra.Command{
+	Use:   "chat ['question']",
+	Short: "Ask an agent a one-off question about the project",
+	Long: `Ask an agent an ad-hoc question about the codebase and print the answer.
+
+The question is taken from the arguments, or read from stdin if there are
+none. The agent runs in the project directory in safe mode where supported
+(no file writes). Sprint files are never touched and no logs are written.
+
+Example:
+  agate chat 'where is the config file parsed?'
+  echo 'summarize the error handling' | agate chat --agent haiku`,
+	RunE: runChat,
+}
+
+func init() {
+	chatCmd.Flags().StringVarP(&chatAgent, "agent", "a", "", "Select agent: haiku, claude, codex, dummy")
+	rootCmd.AddCommand(chatCmd)
+}
+
+func runChat(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	question := strings.Join(args, " ")
+	if len(args) == 0 {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			PrintError("failed to read prompt from stdin: %v", err)
+			SetExitCode(2)
+			return err
+		}
+		question = string(data)
+	}
+
+	response, err := workflow.Chat(projectAt(cwd), question, workflow.ChatOptions{PreferredAgent: chatAgent})
+	if err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), response)
+	SetExitCode(0)
+	return nil
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChat_ReadsPromptFromStdin(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "GOAL.md"), []byte("# Goal\n\nBuild a CLI in Go."), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	rootCmd.SetIn(strings.NewReader("What does main.go do?\n"))
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "chat", "--agent", "dummy"); err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+	if GetExitCode() != 0 {
+		t.Errorf("expected exit 0, got %d", GetExitCode())
+	}
+	if !strings.Contains(out.String(), "Dummy agent") {
+		t.Errorf("expected dummy response, got %q", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ai")); !os.IsNotExist(err) {
+		t.Error("chat should not create .ai files")
+	}
+}
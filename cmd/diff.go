@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var diffLast bool
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show files changed by the most recent agent invocation",
+	Long: `Show which files the most recent agent invocation wrote, as recorded
+in the Files Written section of its log.
+
+Inside a git repository this shows 'git diff' for those paths; otherwise
+it lists each file with its line count.
+
+By default the most recent invocation that wrote files is shown. Use --last
+to show the very last invocation, even if it wrote nothing.`,
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffLast, "last", false, "Show the last invocation even if it wrote no files")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	output, err := workflow.Diff(projectAt(cwd), diffLast)
+	if err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	fmt.Print(output)
+	return nil
+}
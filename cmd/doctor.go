@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that the environment is ready to run agate",
+	Long: `Check environment readiness before a long 'agate auto' run:
+  - Which agent CLIs are installed and respond to --version
+  - Whether GOAL.md exists
+  - Whether .ai is writable
+  - The detected language and project type
+
+Exit codes:
+  0   - Ready (at least one agent available and GOAL.md present)
+  2   - Error occurred
+  255 - Human action required (install an agent CLI, create GOAL.md)`,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(workflow.ExitError)
+		return err
+	}
+
+	report := workflow.Doctor(projectAt(cwd))
+	fmt.Print(workflow.FormatDoctor(report))
+
+	if report.Ready() {
+		SetExitCode(workflow.ExitDone)
+	} else {
+		SetExitCode(workflow.ExitHumanNeeded)
+	}
+	return nil
+}
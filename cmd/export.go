@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var exportOut string
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Bundle goal, design, sprints, and retros into one report",
+	Long: `Write a single markdown report for sharing progress, containing:
+  - GOAL.md
+  - Every design document
+  - Every sprint, with its computed progress
+  - Every sprint retrospective
+
+The report starts with a table of contents linking to each section.
+It is printed to stdout unless --out is given.`,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportOut, "out", "o", "", "Write the report to this file instead of stdout")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	report, err := workflow.Export(projectAt(cwd))
+	if err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	if exportOut == "" {
+		fmt.Print(report)
+		return nil
+	}
+	if err := os.WriteFile(exportOut, []byte(report), 0644); err != nil {
+		PrintError("failed to write report: %v", err)
+		SetExitCode(2)
+		return err
+	}
+	fmt.Printf("Report written to %s\n", exportOut)
+	return nil
+}
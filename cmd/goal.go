@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+// Editor opens a file for the user to edit and returns once they are done
+type Editor interface {
+	Edit(path string) error
+}
+
+// envEditor runs $VISUAL or $EDITOR (which may include arguments, e.g.
+// "code --wait"), falling back to vi
+type envEditor struct{}
+
+func (envEditor) Edit(path string) error {
+	command := os.Getenv("VISUAL")
+	if command == "" {
+		command = os.Getenv("EDITOR")
+	}
+	if command == "" {
+		command = "vi"
+	}
+	args := strings.Fields(command)
+	c := exec.Command(args[0], append(args[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", command, err)
+	}
+	return nil
+}
+
+// goalEditor is the editor goal edit opens; tests replace it
+var goalEditor Editor = envEditor{}
+
+var goalCmd = &cobra.Command{
+	Use:   "goal",
+	Short: "Work with GOAL.md",
+}
+
+var goalEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit GOAL.md and show what agate detects from it",
+	Long: `Open GOAL.md in $VISUAL or $EDITOR (default: vi), creating it from a
+template if it doesn't exist. After the editor exits, GOAL.md is parsed
+again and the detected language and project type are printed, so you can
+confirm them before planning.
+
+If the goal changed and planning has already started (interview, design,
+or sprints exist), a warning notes that the plan may need redoing.`,
+	RunE: runGoalEdit,
+}
+
+func init() {
+	goalCmd.AddCommand(goalEditCmd)
+	rootCmd.AddCommand(goalCmd)
+}
+
+func runGoalEdit(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	proj := projectAt(cwd)
+	before, err := os.ReadFile(proj.GoalPath())
+	if os.IsNotExist(err) {
+		before = []byte(project.GoalTemplate)
+		if err := os.WriteFile(proj.GoalPath(), before, 0644); err != nil {
+			PrintError("failed to create GOAL.md: %v", err)
+			SetExitCode(2)
+			return err
+		}
+	} else if err != nil {
+		PrintError("failed to read GOAL.md: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	if err := goalEditor.Edit(proj.GoalPath()); err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	goal, err := project.ParseGoal(proj.GoalPath())
+	if err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Language: %s\n", goal.Language)
+	fmt.Fprintf(out, "Type:     %s\n", goal.Type)
+	if err := goal.Validate(); err != nil {
+		fmt.Fprintln(out, logging.Yellow("⚠ "+err.Error()))
+	}
+
+	if bytes.Equal(before, []byte(goal.Content)) {
+		fmt.Fprintln(out, logging.Dim("GOAL.md unchanged"))
+	} else if planningStarted(workflow.GetStatus(os.DirFS(cwd), projectAt(cwd).StateDir())) {
+		fmt.Fprintln(out, logging.Yellow("⚠ Planning has already started; the interview, design, and sprints may need redoing for the new goal"))
+	}
+	SetExitCode(workflow.ExitDone)
+	return nil
+}
+
+// planningStarted reports whether any planning artifact exists
+func planningStarted(status workflow.StatusResult) bool {
+	return status.InterviewExists || status.HasDesignOverview || status.HasDesignDecisions || status.CurrentSprintPath != ""
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// fakeEditor writes content to the file it is asked to edit
+type fakeEditor struct {
+	content string
+	opened  string
+}
+
+func (e *fakeEditor) Edit(path string) error {
+	e.opened = path
+	return os.WriteFile(path, []byte(e.content), 0644)
+}
+
+func useEditor(t *testing.T, e Editor) {
+	t.Helper()
+	saved := goalEditor
+	goalEditor = e
+	t.Cleanup(func() { goalEditor = saved })
+}
+
+func TestGoalEdit_PrintsDetection(t *testing.T) {
+	dir := t.TempDir()
+	editor := &fakeEditor{content: "# Goal\n\nBuild a command line tool in Rust that renames photos.\n"}
+	useEditor(t, editor)
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "goal", "edit"); err != nil {
+		t.Fatalf("goal edit failed: %v", err)
+	}
+	if editor.opened != filepath.Join(dir, "GOAL.md") {
+		t.Errorf("expected GOAL.md to be opened, got %q", editor.opened)
+	}
+	for _, want := range []string{"Language: rust", "Type:     cli"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "Planning has already started") {
+		t.Errorf("unexpected re-planning warning:\n%s", out.String())
+	}
+}
+
+func TestGoalEdit_WarnsWhenPlanned(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+	useEditor(t, &fakeEditor{content: "# Goal\n\nBuild a REST API in Python.\n"})
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "goal", "edit"); err != nil {
+		t.Fatalf("goal edit failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Language: python") || !strings.Contains(out.String(), "Planning has already started") {
+		t.Errorf("expected detection and a re-planning warning:\n%s", out.String())
+	}
+}
+
+func TestGoalEdit_UneditedTemplate(t *testing.T) {
+	dir := t.TempDir()
+	useEditor(t, &fakeEditor{content: project.GoalTemplate})
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "goal", "edit"); err != nil {
+		t.Fatalf("goal edit failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "looks unedited") || !strings.Contains(out.String(), "GOAL.md unchanged") {
+		t.Errorf("expected an unedited warning:\n%s", out.String())
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Summarize the work done so far, sprint by sprint",
+	Long: `Print a timeline of the project: for each sprint, its goal, when it was
+completed (from its retrospective, else its last agent log), how many
+tasks are done, and the failed reviews (❌), replans (🔄) and skipped
+tasks (⏭) recorded in it.`,
+	Args: cobra.NoArgs,
+	RunE: runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	history, err := workflow.History(projectAt(cwd))
+	if err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), workflow.FormatHistory(history))
+	return nil
+}
@@ -2,14 +2,18 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/strongdm/agate/internal/workflow"
 	"github.com/spf13/cobra"
 )
 
+var suggestFile string
+
 var interruptCmd = &cobra.Command{
-	Use:     "suggest 'prompt'",
+	Use:     "suggest ['prompt']",
 	Aliases: []string{"interrupt"},
 	Short:   "Send a suggestion to guide the next task",
 	Long: `Send a suggestion to guide the next agent invocation.
@@ -21,42 +25,73 @@ Use this to:
   - Reorder tasks
   - Modify priorities
 
+For longer, multi-line guidance, read the suggestion from a file with
+--file, or from stdin with --file -.
+
 Alias: interrupt (for backwards compatibility)
 
 Example:
   agate suggest 'focus on error handling first'
-  agate suggest 'add input validation before processing'`,
-	Args: cobra.ExactArgs(1),
+  agate suggest 'add input validation before processing'
+  agate suggest --file notes.md
+  git diff | agate suggest --file -`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runInterrupt,
 }
 
 func init() {
+	interruptCmd.Flags().StringVarP(&suggestFile, "file", "f", "", "Read the suggestion from this file ('-' for stdin)")
 	rootCmd.AddCommand(interruptCmd)
 }
 
 func runInterrupt(cmd *cobra.Command, args []string) error {
-	cwd, err := os.Getwd()
+	cwd, err := getProjectDir()
 	if err != nil {
-		PrintError("failed to get current directory: %v", err)
+		PrintError("failed to get project directory: %v", err)
 		SetExitCode(2)
 		return err
 	}
 
-	prompt := args[0]
+	var prompt string
+	switch {
+	case suggestFile != "" && len(args) > 0:
+		PrintError("give the suggestion as an argument or with --file, not both")
+		SetExitCode(2)
+		return fmt.Errorf("both prompt and --file given")
+	case suggestFile != "":
+		var data []byte
+		if suggestFile == "-" {
+			data, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			data, err = os.ReadFile(suggestFile)
+		}
+		if err != nil {
+			PrintError("failed to read suggestion: %v", err)
+			SetExitCode(2)
+			return err
+		}
+		prompt = strings.TrimSpace(string(data))
+	case len(args) > 0:
+		prompt = args[0]
+	default:
+		PrintError("give a suggestion, e.g. agate suggest 'focus on error handling', or use --file")
+		SetExitCode(2)
+		return fmt.Errorf("no prompt")
+	}
 	if prompt == "" {
 		PrintError("interrupt prompt cannot be empty")
 		SetExitCode(2)
 		return fmt.Errorf("empty prompt")
 	}
 
-	result, err := workflow.AddInterrupt(cwd, prompt)
+	result, err := workflow.AddInterrupt(projectAt(cwd), prompt)
 	if err != nil {
 		PrintError("%v", err)
 		SetExitCode(2)
 		return err
 	}
 
-	fmt.Println(result)
+	fmt.Fprintln(cmd.OutOrStdout(), result)
 	SetExitCode(0)
 	return nil
 }
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSuggest_FromFile(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+	file := filepath.Join(t.TempDir(), "notes.md")
+	if err := os.WriteFile(file, []byte("Prefer small functions.\nAdd tests first.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "suggest", "--file", file); err != nil {
+		t.Fatalf("suggest failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Suggestion noted: Prefer small functions.\nAdd tests first.") {
+		t.Errorf("expected the file's suggestion, got:\n%s", out.String())
+	}
+}
+
+func TestSuggest_FromStdin(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+	rootCmd.SetIn(strings.NewReader("Focus on error handling\n"))
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "suggest", "--file", "-"); err != nil {
+		t.Fatalf("suggest failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Suggestion noted: Focus on error handling") {
+		t.Errorf("expected the stdin suggestion, got:\n%s", out.String())
+	}
+}
+
+func TestSuggest_RejectsMissingOrDoubleInput(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+
+	tests := map[string][]string{
+		"no suggestion":     {"-C", dir, "suggest"},
+		"argument and file": {"-C", dir, "suggest", "--file", "-", "hint"},
+		"empty stdin":       {"-C", dir, "suggest", "--file", "-"},
+	}
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			rootCmd.SetIn(strings.NewReader(" \n"))
+			if err := runRoot(t, args...); err == nil {
+				t.Fatal("expected an error")
+			}
+			if GetExitCode() != 2 {
+				t.Errorf("expected exit code 2, got %d", GetExitCode())
+			}
+		})
+	}
+}
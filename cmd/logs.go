@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsKeepSprints int
+	logsMaxAge      string
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Manage agent invocation logs",
+}
+
+var logsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove logs of old sprints",
+	Long: `Remove the log directories (.ai/logs/sprint-NNN) of old sprints.
+
+  --keep-sprints N  Keep the logs of the current sprint and the N-1 before it
+  --max-age 30d     Remove logs not written to for longer than this
+                    (units: d, h, m)
+
+A sprint's logs are removed when either limit applies. The current sprint's
+logs are always kept.
+
+To prune automatically whenever a new sprint starts, set log_keep_sprints
+and/or log_max_age in .ai/config.yaml.`,
+	RunE: runLogsPrune,
+}
+
+func init() {
+	logsPruneCmd.Flags().IntVar(&logsKeepSprints, "keep-sprints", 0, "Number of most recent sprints whose logs to keep")
+	logsPruneCmd.Flags().StringVar(&logsMaxAge, "max-age", "", "Remove logs older than this (e.g. 30d, 12h)")
+	logsCmd.AddCommand(logsPruneCmd)
+	rootCmd.AddCommand(logsCmd)
+}
+
+func runLogsPrune(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	opts := logging.PruneOptions{KeepSprints: logsKeepSprints}
+	if logsKeepSprints < 0 {
+		err := fmt.Errorf("--keep-sprints must not be negative")
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+	if logsMaxAge != "" {
+		if opts.MaxAge, err = project.ParseAge(logsMaxAge); err != nil {
+			err = fmt.Errorf("--max-age %v", err)
+			PrintError("%v", err)
+			SetExitCode(2)
+			return err
+		}
+	}
+	opts.CurrentSprint = workflow.GetStatus(os.DirFS(cwd), projectAt(cwd).StateDir()).CurrentSprintNum
+
+	removed, err := logging.PruneLogs(projectAt(cwd), opts)
+	if err != nil {
+		PrintError("failed to prune logs: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	for _, path := range removed {
+		if rel, err := filepath.Rel(cwd, path); err == nil {
+			path = rel
+		}
+		fmt.Fprintf(out, "Removed %s\n", path)
+	}
+	fmt.Fprintf(out, "Pruned logs of %d sprint(s)\n", len(removed))
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestLogsPrune_KeepsCurrentSprint(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [x] Done\n  - [x] go-coder: Work\n")
+	sprints := map[string]string{
+		"02-b.md": "# Sprint 2\n\n- [x] Done\n  - [x] go-coder: Work\n",
+		"03-c.md": "# Sprint 3\n\n- [ ] Task\n  - [ ] go-coder: Work\n",
+	}
+	for name, content := range sprints {
+		if err := os.WriteFile(filepath.Join(dir, ".ai", "sprints", name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, num := range []int{1, 2, 3} {
+		if err := os.MkdirAll(logging.GetLogsDir(project.New(dir), num), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "logs", "prune", "--keep-sprints", "1"); err != nil {
+		t.Fatalf("logs prune failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Pruned logs of 2 sprint(s)") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+	if _, err := os.Stat(logging.GetLogsDir(project.New(dir), 3)); err != nil {
+		t.Errorf("expected current sprint logs to survive: %v", err)
+	}
+	for _, num := range []int{1, 2} {
+		if _, err := os.Stat(logging.GetLogsDir(project.New(dir), num)); !os.IsNotExist(err) {
+			t.Errorf("expected sprint %d logs removed, got %v", num, err)
+		}
+	}
+}
+
+func TestLogsPrune_BadMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+
+	if err := runRoot(t, "-C", dir, "logs", "prune", "--max-age", "soon"); err == nil {
+		t.Fatal("expected error for bad --max-age")
+	}
+	if code := GetExitCode(); code != 2 {
+		t.Errorf("expected exit 2, got %d", code)
+	}
+}
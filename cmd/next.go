@@ -3,15 +3,35 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
 
+	"github.com/strongdm/agate/internal/agent"
 	"github.com/strongdm/agate/internal/logging"
 	"github.com/strongdm/agate/internal/workflow"
 	"github.com/spf13/cobra"
 )
 
 var nextTail bool
+var nextTailFile string
+var nextStreamFormat string
 var nextAgent string
+var nextWatch bool
+var nextTask int
+var nextPhaseOnly bool
+var nextNoRecovery bool
+var nextWebhook string
+var nextDoubleReview bool
+var nextStrictReview bool
+var nextStrictSkills bool
+var nextKeepGoing bool
+var nextModel string
+var nextEffort string
+var nextExplain bool
+var nextResumeSprint int
+var nextFresh bool
 
 var nextCmd = &cobra.Command{
 	Use:   "next",
@@ -23,11 +43,80 @@ This command determines the current phase and executes the next step:
   - If in implementation phase: implements one task
   - If in review phase: reviews changes
 
+Use --watch to keep running: after each step agate watches GOAL.md and
+.ai/design/*.md (under --state-dir, if set) and re-runs the next step whenever they change, until
+all work is complete or human action is required.
+
+Use --task N to work on the first unchecked sub-task of task N (1-based)
+in the current sprint instead of the first incomplete task.
+
+Use --phase-only to generate just the next planning artifact (interview,
+design, decisions, or sprint plan) and stop. Once planning is complete it
+reports so instead of executing sprint tasks.
+
+Use --fresh to redo the most recently generated planning artifact: the
+interview, design overview, or technical decisions, whichever came last.
+The old file is backed up to .ai/design/.drafts/ and restored if the new
+one fails. Once planning is complete, use --resume-sprint instead.
+
+Use --no-recovery to report a failed sub-task's error as-is instead of
+running the recovery agent to fix the environment and retry.
+
+Use --double-review to run reviewer sub-tasks on two agents in parallel
+(e.g. claude and codex). The sub-task passes only if both approve; any
+issues either reviewer finds are recorded as the failure reason.
+
+Use --strict-review to fail any review that doesn't end in a clear verdict:
+the response must have a line that is just APPROVED (or SPRINT_COMPLETE).
+Empty or ambiguous responses are reported and count as a failed review.
+
+Use --strict-skills to stop for a human (exit 255) when a sub-task names a
+skill that doesn't exist, instead of warning and running the agent without
+skill guidance. The message suggests the closest skill name.
+
+Use --keep-going for exploratory runs: a task that still fails review
+after its retries and a replan is marked skipped (⏭) and the sprint moves
+on to its next task instead of stopping for a human. Skipped tasks are
+listed when the sprint completes.
+
+Use --model and --effort to choose the codex agent's model and reasoning
+effort; without them codex uses its own configured defaults.
+
+Use --explain to print what the next step would be and why (phase,
+sub-task, agent, and the reason for choosing that agent) without running
+an agent or changing any file.
+
+Only one agate process advances a project at a time: next holds a run-lock
+(.ai/.run.lock, naming its PID) while it works and exits 2 if another live
+agate process holds it. A lock left by a process that died is reclaimed.
+
+Use --resume-sprint N to regenerate sprint N when its file is mangled
+beyond parsing. The planner rewrites it from the goal, the design, and the
+sprints before it; the old file is backed up to .ai/sprints/.drafts/ first.
+
+Use --tail-file <path> to also append the streamed agent output to a file,
+so it can be reviewed after the run. Without --tail the output goes only
+to the file.
+
+Use --stream-format json to stream newline-delimited JSON events instead of
+the raw agent output, for tools wrapping agate. Each agent invocation
+emits {"type":"chunk","bytes":N} as output arrives, one
+{"type":"file_written","path":"..."} per file it wrote, and a final
+{"type":"done","status":"success"} (or "error"). Events go wherever the
+stream does: stdout with --tail, the file with --tail-file. With --tail,
+stdout carries only events; progress and status messages go to stderr.
+
+Use --webhook <url> to POST a JSON progress update after each step:
+{"phase":"...","sprint":N,"completed":N,"total":N,"exitCode":N}
+A failed POST prints a warning but never stops the run.
+
 Use --agent to select which AI agent to use:
   --agent haiku   Claude 3.5 Haiku (fast, cheap)
   --agent claude  Claude Opus 4.5 (most capable)
   --agent codex   GPT 5.2 (OpenAI)
   --agent dummy   No-op (for testing)
+  --agent auto    Pick as usual, but if the agent fails to run, retry the
+                  same sub-task with the next available agent
 
 Exit codes:
   0   - All work complete (all sprints done)
@@ -39,24 +128,179 @@ Exit codes:
 
 func init() {
 	nextCmd.Flags().BoolVarP(&nextTail, "tail", "t", false, "Stream agent output to terminal in real-time")
-	nextCmd.Flags().StringVarP(&nextAgent, "agent", "a", "", "Select agent: haiku, claude, codex, dummy")
+	nextCmd.Flags().StringVar(&nextTailFile, "tail-file", "", "Append streamed agent output to this file (relative to the project directory)")
+	nextCmd.Flags().StringVar(&nextStreamFormat, "stream-format", agent.StreamFormatText, "Format of the streamed output: text or json")
+	nextCmd.Flags().StringVarP(&nextAgent, "agent", "a", "", "Select agent: haiku, claude, codex, dummy, auto")
+	nextCmd.Flags().BoolVarP(&nextWatch, "watch", "w", false, "Re-run when GOAL.md or design files change")
+	nextCmd.Flags().IntVar(&nextTask, "task", 0, "Work on this task number (1-based) in the current sprint")
+	nextCmd.Flags().BoolVar(&nextPhaseOnly, "phase-only", false, "Run only the next planning phase; never execute sprint tasks")
+	nextCmd.Flags().BoolVar(&nextFresh, "fresh", false, "Regenerate the most recent planning artifact, backing up the old one")
+	nextCmd.Flags().BoolVar(&nextNoRecovery, "no-recovery", false, "Fail sub-task errors immediately instead of running the recovery agent")
+	nextCmd.Flags().BoolVar(&nextDoubleReview, "double-review", false, "Require reviewer sub-tasks to be approved by two agents")
+	nextCmd.Flags().BoolVar(&nextStrictReview, "strict-review", false, "Fail reviews that lack an explicit APPROVED line")
+	nextCmd.Flags().BoolVar(&nextStrictSkills, "strict-skills", false, "Stop instead of warning when a sub-task names an unknown skill")
+	nextCmd.Flags().StringVar(&nextModel, "model", "", "Model for the codex agent (default: codex's own)")
+	nextCmd.Flags().StringVar(&nextEffort, "effort", "", "Reasoning effort for the codex agent, e.g. low, medium, high")
+	nextCmd.Flags().BoolVar(&nextKeepGoing, "keep-going", false, "Skip a task that keeps failing review instead of stopping for a human")
+	nextCmd.Flags().IntVar(&nextResumeSprint, "resume-sprint", 0, "Regenerate this sprint number from the goal, backing up the old file")
+	nextCmd.Flags().BoolVar(&nextExplain, "explain", false, "Explain the next step and its agent choice without running it")
+	nextCmd.Flags().StringVar(&nextWebhook, "webhook", "", "POST a JSON progress update to this URL after each step")
 	rootCmd.AddCommand(nextCmd)
 }
 
 func runNext(cmd *cobra.Command, args []string) error {
-	cwd, err := os.Getwd()
+	cwd, err := getProjectDir()
 	if err != nil {
-		PrintError("failed to get current directory: %v", err)
+		PrintError("failed to get project directory: %v", err)
 		SetExitCode(2)
 		return err
 	}
 
+	if cmd.Flags().Changed("task") && nextTask <= 0 {
+		err := fmt.Errorf("--task needs a positive task number")
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+	if err := checkModelFlags(nextAgent, nextModel, nextEffort); err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+	if nextExplain {
+		return runNextExplain(cmd, cwd)
+	}
+	if nextResumeSprint != 0 && (nextResumeSprint < 0 || nextWatch) {
+		err := fmt.Errorf("--resume-sprint needs a positive sprint number and cannot be combined with --watch")
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+	if nextFresh && (nextWatch || nextResumeSprint != 0) {
+		err := fmt.Errorf("--fresh cannot be combined with --watch or --resume-sprint")
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	release, err := acquireRunLock(cwd)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if nextWatch {
+		return runNextWatch(cwd)
+	}
+	return runNextStep(cwd)
+}
+
+// checkModelFlags rejects --model and --effort when --agent names an agent
+// that ignores them; with no agent (or auto) they apply to codex steps only
+func checkModelFlags(agentName, model, effort string) error {
+	if model == "" && effort == "" || agentName == "" || agentName == workflow.AutoAgent {
+		return nil
+	}
+	if !agent.SupportsModelOptions(agentName) {
+		return fmt.Errorf("--model and --effort only apply to the codex agent, not %s", agentName)
+	}
+	return nil
+}
+
+// acquireRunLock takes the project's run-lock so that concurrent agate runs
+// don't race on the sprint files, reporting a held lock as an error
+func acquireRunLock(cwd string) (func(), error) {
+	release, err := projectAt(cwd).AcquireRunLock()
+	if err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return nil, err
+	}
+	return release, nil
+}
+
+// runNextExplain prints the step runNextStep would take, without taking it
+func runNextExplain(cmd *cobra.Command, cwd string) error {
+	exp, err := workflow.Explain(projectAt(cwd), workflow.NextOptions{
+		PreferredAgent: nextAgent,
+		TaskNumber:     nextTask,
+		PhaseOnly:      nextPhaseOnly,
+		KeepGoing:      nextKeepGoing,
+	})
+	if err != nil {
+		PrintError("%v", err)
+		SetExitCode(workflow.ExitError)
+		return err
+	}
+	fmt.Fprint(cmd.OutOrStdout(), exp.String())
+	SetExitCode(workflow.ExitDone)
+	return nil
+}
+
+// runNextWatch runs a step, then re-runs it whenever GOAL.md or the design
+// files change, until the workflow is done, fails, or needs a human. A
+// failed step's error is returned.
+func runNextWatch(cwd string) error {
+	stop := make(chan struct{})
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+	go func() {
+		<-sigCh
+		close(stop)
+	}()
+
+	watcher := workflow.NewWatcher(os.DirFS(cwd), projectAt(cwd).StateDir(), workflow.RealClock())
+	var stepErr error
+	step := func() int {
+		if stepErr = runNextStep(cwd); stepErr != nil && GetExitCode() == workflow.ExitDone {
+			SetExitCode(workflow.ExitError)
+		}
+		if code := GetExitCode(); code != workflow.ExitDone && code != workflow.ExitError && code != workflow.ExitHumanNeeded {
+			fmt.Println(logging.Dim("Watching GOAL.md and " + projectAt(cwd).StatePath("design") + "/ for changes (Ctrl-C to stop)..."))
+		}
+		return GetExitCode()
+	}
+	SetExitCode(workflow.WatchLoop(watcher, step, stop))
+	return stepErr
+}
+
+// runNextStep executes a single step and sets the exit code
+func runNextStep(cwd string) error {
+	if nextWebhook != "" {
+		defer notifyWebhook(workflow.NewWebhook(nextWebhook), cwd)
+	}
+
 	opts := workflow.NextOptions{
 		PreferredAgent: nextAgent,
+		ModelOptions:   agent.ModelOptions{Model: nextModel, Effort: nextEffort},
+		TaskNumber:     nextTask,
+		PhaseOnly:      nextPhaseOnly,
+		NoRecovery:     nextNoRecovery,
+		DoubleReview:   nextDoubleReview,
+		StrictReview:   nextStrictReview,
+		StrictSkills:   nextStrictSkills,
+		KeepGoing:      nextKeepGoing,
+		Fresh:          nextFresh,
+	}
+
+	if nextStreamFormat != agent.StreamFormatText && nextStreamFormat != agent.StreamFormatJSON {
+		err := fmt.Errorf("unknown --stream-format %q (want %s or %s)", nextStreamFormat, agent.StreamFormatText, agent.StreamFormatJSON)
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
 	}
+	jsonStream := nextStreamFormat == agent.StreamFormatJSON
 
-	// Set up streaming if -tail is enabled
-	if nextTail {
+	// Set up streaming if -tail is enabled. JSON events get stdout to
+	// themselves, so it stays valid NDJSON: everything meant for people
+	// (progress, status, the result message) goes to stderr instead.
+	if nextTail && jsonStream {
+		events := os.Stdout
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = events }()
+		opts.StreamOutput = events
+	} else if nextTail {
 		// Create a split view for terminal output
 		sv := logging.NewSplitView(os.Stdout, 2)
 		if sv.IsTTY() {
@@ -67,21 +311,42 @@ func runNext(cmd *cobra.Command, args []string) error {
 		opts.StreamOutput = sv
 	}
 
-	result, err := workflow.NextWithOptions(cwd, opts)
-	if err != nil {
-		// Check for explicit human-needed error (e.g. too many review failures)
-		var humanErr *workflow.HumanNeededError
-		if errors.As(err, &humanErr) {
-			PrintError("%v", err)
-			SetExitCode(workflow.ExitHumanNeeded)
+	// Tee the stream into --tail-file for later review. A relative path is
+	// in the project directory, like every other path agate takes with -C.
+	if nextTailFile != "" {
+		tailPath := nextTailFile
+		if !filepath.IsAbs(tailPath) {
+			tailPath = filepath.Join(cwd, tailPath)
+		}
+		f, err := os.OpenFile(tailPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			PrintError("failed to open tail file: %v", err)
+			SetExitCode(2)
 			return err
 		}
-		// Check if this is a human-action error (no goal, etc.)
-		// by inspecting current state
-		fsys := os.DirFS(cwd)
-		status := workflow.GetStatus(fsys)
-		exitCode := workflow.GetExitCode(status)
-		if exitCode == workflow.ExitHumanNeeded {
+		defer f.Close()
+		if opts.StreamOutput != nil {
+			opts.StreamOutput = io.MultiWriter(opts.StreamOutput, f)
+		} else {
+			opts.StreamOutput = f
+		}
+	}
+	if jsonStream && opts.StreamOutput != nil {
+		opts.Events = agent.NewEventWriter(opts.StreamOutput)
+		opts.StreamOutput = opts.Events
+	}
+
+	var result *workflow.Result
+	var err error
+	if nextResumeSprint > 0 {
+		result, err = workflow.RegenerateSprint(projectAt(cwd), nextResumeSprint, opts)
+	} else {
+		result, err = workflow.NextWithOptions(projectAt(cwd), opts)
+	}
+	if err != nil {
+		// Explicit human-needed errors (no goal, too many review failures,
+		// an agent that isn't logged in)
+		if isHumanNeeded(err) {
 			PrintError("%v", err)
 			SetExitCode(workflow.ExitHumanNeeded)
 			return err
@@ -92,11 +357,24 @@ func runNext(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Println(result.Message)
-
-	// Determine exit code from current state after the step
-	fsys := os.DirFS(cwd)
-	status := workflow.GetStatus(fsys)
-	SetExitCode(workflow.GetExitCode(status))
+	SetExitCode(result.ExitCode)
 
 	return nil
 }
+
+// isHumanNeeded reports whether err needs a human to act before retrying:
+// a HumanNeededError, or an agent CLI that isn't logged in
+func isHumanNeeded(err error) bool {
+	var humanErr *workflow.HumanNeededError
+	var authErr *agent.AuthError
+	return errors.As(err, &humanErr) || errors.As(err, &authErr)
+}
+
+// notifyWebhook posts the state after a step and the exit code it set.
+// Failures only warn: a dashboard outage must not abort the run.
+func notifyWebhook(hook *workflow.Webhook, cwd string) {
+	status := workflow.GetStatus(os.DirFS(cwd), projectAt(cwd).StateDir())
+	if err := hook.Post(workflow.NewWebhookPayload(status, GetExitCode())); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", logging.Yellow(fmt.Sprintf("Warning: %v", err)))
+	}
+}
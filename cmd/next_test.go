@@ -0,0 +1,404 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/agent"
+	"github.com/strongdm/agate/internal/project"
+	"github.com/strongdm/agate/internal/workflow"
+)
+
+func TestNextTaskFlag_RunsTargetedTask(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Blocked task\n  - [ ] go-coder: Needs input\n\n- [ ] Ready task\n  - [ ] go-coder: Do work\n  - [ ] _reviewer: Review work\n")
+
+	if err := runRoot(t, "-C", dir, "next", "--agent", "dummy", "--task", "2"); err != nil {
+		t.Fatalf("next failed: %v", err)
+	}
+	if code := GetExitCode(); code != workflow.ExitMoreWork {
+		t.Errorf("expected exit %d, got %d", workflow.ExitMoreWork, code)
+	}
+
+	sprint, err := workflow.ParseSprint(filepath.Join(dir, ".ai", "sprints", "01-a.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sprint.Tasks[0].SubTasks[0].Checked {
+		t.Error("task 1 should not have been worked on")
+	}
+	if !sprint.Tasks[1].SubTasks[0].Checked {
+		t.Error("expected task 2's first sub-task to run")
+	}
+}
+
+func TestNext_AgentNotLoggedInNeedsHuman(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+	bin := t.TempDir()
+	calls := filepath.Join(bin, "calls")
+	stub := "#!/bin/sh\necho call >> " + calls + "\necho 'Invalid API key · Please run /login' >&2\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(bin, "claude"), []byte(stub), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	err := runRoot(t, "-C", dir, "next", "--agent", "claude")
+	if err == nil || !strings.Contains(err.Error(), "claude /login") {
+		t.Fatalf("expected an error pointing to claude /login, got %v", err)
+	}
+	if code := GetExitCode(); code != workflow.ExitHumanNeeded {
+		t.Errorf("expected exit %d, got %d", workflow.ExitHumanNeeded, code)
+	}
+	data, _ := os.ReadFile(calls)
+	if n := strings.Count(string(data), "call"); n != 1 {
+		t.Errorf("expected no recovery attempt after an auth failure, got %d claude calls", n)
+	}
+}
+
+func TestNext_RunLockBlocksConcurrentRun(t *testing.T) {
+	dir := t.TempDir()
+	sprint := "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n"
+	writeExecutionProject(t, dir, sprint)
+	other := exec.Command("sleep", "30")
+	if err := other.Start(); err != nil {
+		t.Skipf("cannot start a helper process: %v", err)
+	}
+	defer func() { other.Process.Kill(); other.Wait() }()
+	lockPath := project.New(dir).RunLockPath()
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n", other.Process.Pid)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runRoot(t, "-C", dir, "next", "--agent", "dummy")
+	if err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Fatalf("expected a held run-lock to stop next, got %v", err)
+	}
+	if code := GetExitCode(); code != workflow.ExitError {
+		t.Errorf("expected exit %d, got %d", workflow.ExitError, code)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, ".ai", "sprints", "01-a.md")); string(data) != sprint {
+		t.Errorf("expected the sprint untouched while locked, got:\n%s", data)
+	}
+
+	// Once the other run is gone its lock is stale and next reclaims it
+	other.Process.Kill()
+	other.Wait()
+	if err := runRoot(t, "-C", dir, "next", "--agent", "dummy"); err != nil {
+		t.Fatalf("expected next to reclaim a stale lock, got %v", err)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Error("expected next to release the run-lock when done")
+	}
+}
+
+func TestNext_StateDirFlagAppliesToThisRun(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+	state := filepath.Join(dir, "build", "agate-state")
+	if err := os.MkdirAll(filepath.Dir(state), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, ".ai"), state); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runRoot(t, "-C", dir, "--state-dir", "build/agate-state", "next", "--agent", "dummy"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(state, "sprints", "01-a.md")); !strings.Contains(string(data), "- [x] go-coder: Work") {
+		t.Errorf("expected the sub-task checked off under the custom state dir, got:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ai")); !os.IsNotExist(err) {
+		t.Error("expected the default .ai dir not to be created")
+	}
+	if p := project.New(dir); p.StateDir() != project.DefaultStateDir {
+		t.Errorf("expected --state-dir not to leak into other projects, got %s", p.StateDir())
+	}
+}
+
+func TestNext_ModelAndEffortReachCodex(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+	bin := t.TempDir()
+	args := filepath.Join(bin, "args")
+	stub := "#!/bin/sh\necho \"$@\" > " + args + "\necho OK\n"
+	if err := os.WriteFile(filepath.Join(bin, "codex"), []byte(stub), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	if err := runRoot(t, "-C", dir, "next", "--agent", "codex", "--model", "o3", "--effort", "high"); err != nil {
+		t.Fatalf("next failed: %v", err)
+	}
+	data, err := os.ReadFile(args)
+	if err != nil {
+		t.Fatalf("codex did not run: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "--full-auto-net exec --model o3 -c model_reasoning_effort=high ") {
+		t.Errorf("expected model and effort flags, got %q", data)
+	}
+}
+
+func TestNext_ModelRejectedForOtherAgents(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+
+	if err := runRoot(t, "-C", dir, "next", "--agent", "claude", "--model", "o3"); err == nil || !strings.Contains(err.Error(), "codex") {
+		t.Errorf("expected a --model error for claude, got %v", err)
+	}
+	if code := GetExitCode(); code != workflow.ExitError {
+		t.Errorf("expected exit %d, got %d", workflow.ExitError, code)
+	}
+}
+
+func TestNextTaskFlag_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+
+	for _, task := range []string{"5", "0", "-1"} {
+		if err := runRoot(t, "-C", dir, "next", "--agent", "dummy", "--task", task); err == nil {
+			t.Errorf("expected error for --task %s", task)
+		}
+		if code := GetExitCode(); code != workflow.ExitError {
+			t.Errorf("--task %s: expected exit %d, got %d", task, workflow.ExitError, code)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, ".ai", "sprints", "01-a.md")); strings.Contains(string(data), "[x]") {
+		t.Errorf("an invalid --task should not run a sub-task, got:\n%s", data)
+	}
+}
+
+func TestNextWebhook_PostsProgress(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n  - [ ] _reviewer: Review work\n")
+
+	var payloads []workflow.WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p workflow.WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		payloads = append(payloads, p)
+	}))
+	defer server.Close()
+
+	if err := runRoot(t, "-C", dir, "next", "--agent", "dummy", "--webhook", server.URL); err != nil {
+		t.Fatalf("next failed: %v", err)
+	}
+	if len(payloads) != 1 {
+		t.Fatalf("expected 1 webhook call, got %d", len(payloads))
+	}
+	want := workflow.WebhookPayload{Phase: workflow.PhaseExecution, Sprint: 1, Completed: 1, Total: 2, ExitCode: workflow.ExitMoreWork}
+	if payloads[0] != want {
+		t.Errorf("expected payload %+v, got %+v", want, payloads[0])
+	}
+}
+
+func TestNextWebhook_FailureDoesNotAbort(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n  - [ ] _reviewer: Review work\n")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if err := runRoot(t, "-C", dir, "next", "--agent", "dummy", "--webhook", server.URL); err != nil {
+		t.Fatalf("next failed: %v", err)
+	}
+	if code := GetExitCode(); code != workflow.ExitMoreWork {
+		t.Errorf("expected exit %d despite webhook failure, got %d", workflow.ExitMoreWork, code)
+	}
+}
+
+func TestNextTailFile_ReceivesStream(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Write main\n  - [ ] _reviewer: Review main\n")
+	tailFile := filepath.Join(t.TempDir(), "tail.log")
+
+	if err := runRoot(t, "-C", dir, "next", "--agent", "dummy", "--tail-file", tailFile); err != nil {
+		t.Fatalf("next failed: %v", err)
+	}
+	if err := runRoot(t, "-C", dir, "next", "--agent", "dummy", "--tail-file", tailFile); err != nil {
+		t.Fatalf("second next failed: %v", err)
+	}
+
+	data, err := os.ReadFile(tailFile)
+	if err != nil {
+		t.Fatalf("expected tail file to be written: %v", err)
+	}
+	// Both steps append: the coder's file output, then the review verdict
+	if !strings.Contains(string(data), "### File: main.go") {
+		t.Errorf("expected coder stream in tail file, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), "APPROVED") {
+		t.Errorf("expected reviewer stream appended to tail file, got:\n%s", data)
+	}
+}
+
+func TestNextTailFile_RelativeToProject(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Write main\n")
+	t.Chdir(t.TempDir())
+
+	if err := runRoot(t, "-C", dir, "next", "--agent", "dummy", "--tail-file", "tail.log"); err != nil {
+		t.Fatalf("next failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tail.log")); err != nil {
+		t.Errorf("expected the tail file in the project directory: %v", err)
+	}
+	if _, err := os.Stat("tail.log"); !os.IsNotExist(err) {
+		t.Errorf("expected no tail file in the working directory, stat err: %v", err)
+	}
+}
+
+func TestNextTailFile_Unwritable(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+
+	err := runRoot(t, "-C", dir, "next", "--agent", "dummy", "--tail-file", filepath.Join(dir, "missing", "tail.log"))
+	if err == nil {
+		t.Fatal("expected error for unwritable tail file")
+	}
+	if code := GetExitCode(); code != workflow.ExitError {
+		t.Errorf("expected exit %d, got %d", workflow.ExitError, code)
+	}
+}
+
+func TestNextExplain_NoWrites(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [x] Done task\n  - [x] go-coder: Old work\n\n- [ ] Ready task\n  - [ ] go-coder: Do work\n  - [ ] _reviewer: Review work\n")
+
+	// Every command refreshes the built-in skills; --explain must add nothing else
+	if _, err := project.EnsureBuiltinSkills(filepath.Join(dir, ".ai", "skills")); err != nil {
+		t.Fatal(err)
+	}
+	snapshot := func() map[string]string {
+		files := map[string]string{}
+		filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				data, _ := os.ReadFile(path)
+				files[path] = string(data)
+			}
+			return nil
+		})
+		return files
+	}
+	before := snapshot()
+
+	var out strings.Builder
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "next", "--explain", "--agent", "dummy"); err != nil {
+		t.Fatalf("next --explain failed: %v", err)
+	}
+	if code := GetExitCode(); code != workflow.ExitDone {
+		t.Errorf("expected exit code %d, got %d", workflow.ExitDone, code)
+	}
+
+	for _, want := range []string{"Phase:", "execution", "Task:      2. Ready task", "Sub-task:  [go-coder] Do work", "Agent:     dummy", "requested with --agent"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+
+	after := snapshot()
+	if len(after) != len(before) {
+		t.Errorf("expected no new files, had %d now %d", len(before), len(after))
+	}
+	for path, content := range before {
+		if after[path] != content {
+			t.Errorf("%s was modified", path)
+		}
+	}
+}
+
+// TestNext_JSONStreamKeepsStdoutNDJSON verifies that with --tail
+// --stream-format json every stdout line is a JSON event, with progress and
+// the result message on stderr instead.
+func TestNext_JSONStreamKeepsStdoutNDJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Write code\n  - [ ] _reviewer: Review code\n")
+
+	stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	origOut, origErr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	err = runRoot(t, "-C", dir, "next", "--agent", "dummy", "--tail", "--stream-format", "json")
+	os.Stdout, os.Stderr = origOut, origErr
+	if err != nil {
+		t.Fatalf("next failed: %v", err)
+	}
+
+	out, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		var event agent.StreamEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("stdout line is not JSON: %q\nstdout:\n%s", line, out)
+		}
+		types = append(types, event.Type)
+	}
+	if len(types) == 0 || types[len(types)-1] != agent.EventDone {
+		t.Errorf("expected the events to end with done, got %v", types)
+	}
+
+	human, err := os.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(human), "Wrote 1 file(s)") {
+		t.Errorf("expected progress messages on stderr, got:\n%s", human)
+	}
+}
+
+func TestReplay_RunLockBlocksReplay(t *testing.T) {
+	dir := t.TempDir()
+	sprint := "# Sprint 1\n\n- [x] Task\n  - [x] go-coder: Work\n"
+	writeExecutionProject(t, dir, sprint)
+	other := exec.Command("sleep", "30")
+	if err := other.Start(); err != nil {
+		t.Skipf("cannot start a helper process: %v", err)
+	}
+	defer func() { other.Process.Kill(); other.Wait() }()
+	if err := os.WriteFile(project.New(dir).RunLockPath(), []byte(fmt.Sprintf("%d\n", other.Process.Pid)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runRoot(t, "-C", dir, "replay", "--sprint", "1")
+	if err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Fatalf("expected a held run-lock to stop replay, got %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, ".ai", "sprints", "01-a.md")); string(data) != sprint {
+		t.Errorf("expected the sprint untouched while locked, got:\n%s", data)
+	}
+}
+
+func TestNext_WatchStopsOnStepError(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+
+	err := runRoot(t, "-C", dir, "next", "--watch", "--stream-format", "xml")
+	if err == nil || !strings.Contains(err.Error(), "unknown --stream-format") {
+		t.Fatalf("expected the failed step's error, got %v", err)
+	}
+	if code := GetExitCode(); code != workflow.ExitError {
+		t.Errorf("expected exit %d, got %d", workflow.ExitError, code)
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var replaySprintNum int
+var replayAgent string
+var replayTail bool
+
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Re-execute a sprint from scratch",
+	Long: `Replay a sprint to reproduce a run: every task and sub-task checkbox is
+unchecked, ❌/🔄 markers and failure annotations are cleared, and then
+'agate next' steps run until the sprint is complete again.
+
+Every sprint before the target must be complete. Later sprints are not
+touched, and the replay stops once the target sprint is done instead of
+assessing the goal or planning another sprint.
+
+The sprint file is backed up to .ai/sprints/.drafts/ before it is reset,
+and replay holds the project's run-lock, so it exits 2 while another agate
+process is running.
+
+The replay uses the dummy agent unless --agent selects another one. A dummy
+replay is dry: the files in the dummy agent's output are not written, so
+the project's own files are left alone. A real agent works on the project
+as 'agate next' would. When it finishes, the failed-review and replan
+counts of the original run and the replay are printed for comparison.
+
+Exit codes:
+  0   - Sprint replayed to completion
+  2   - Error occurred
+  255 - Human action required`,
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().IntVar(&replaySprintNum, "sprint", 0, "Sprint number to replay (required)")
+	replayCmd.Flags().StringVarP(&replayAgent, "agent", "a", "dummy", "Select agent: haiku, claude, codex, dummy")
+	replayCmd.Flags().BoolVarP(&replayTail, "tail", "t", false, "Stream agent output to terminal in real-time")
+	replayCmd.MarkFlagRequired("sprint")
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	release, err := acquireRunLock(cwd)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	opts := workflow.ReplayOptions{
+		SprintNum:      replaySprintNum,
+		PreferredAgent: replayAgent,
+		OnStep: func(step int, result *workflow.Result) {
+			fmt.Printf("%s %s\n", logging.Dim(fmt.Sprintf("[%d]", step)), result.Message)
+		},
+	}
+	if replayTail {
+		opts.StreamOutput = os.Stdout
+	}
+
+	fmt.Println(logging.Bold(fmt.Sprintf("Replaying sprint %d with %s", replaySprintNum, replayAgent)))
+	result, err := workflow.Replay(projectAt(cwd), opts)
+	if result != nil && result.BackupPath != "" {
+		fmt.Printf("  Original sprint backed up to %s\n", result.BackupPath)
+	}
+	if err != nil {
+		if isHumanNeeded(err) {
+			PrintError("%v", err)
+			SetExitCode(workflow.ExitHumanNeeded)
+			return err
+		}
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(logging.Green(fmt.Sprintf("✓ Sprint %d replayed to completion in %d steps", result.SprintNum, len(result.Steps))))
+	fmt.Printf("  Failed reviews: original %d, replay %d\n", result.OriginalFailures, result.ReplayFailures)
+	fmt.Printf("  Replans:        original %d, replay %d\n", result.OriginalReplans, result.ReplayReplans)
+	SetExitCode(workflow.ExitDone)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/strongdm/agate/internal/project"
 	"github.com/spf13/cobra"
@@ -11,6 +12,12 @@ import (
 
 var version = "0.1.0"
 
+// stateDirFlag relocates the .ai state directory (relative to the project dir)
+var stateDirFlag string
+
+// projectDirFlag runs agate against a project other than the working directory
+var projectDirFlag string
+
 var rootCmd = &cobra.Command{
 	Use:   "agate",
 	Short: "AI orchestrator CLI",
@@ -27,20 +34,44 @@ Agents (use --agent with auto/next):
   haiku   Claude 3.5 Haiku  - Fast, cheap, good for testing
   codex   GPT 5.2           - OpenAI alternative
   dummy   No-op             - For workflow testing`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := project.CleanStateDir(stateDirFlag)
+		if err != nil {
+			PrintError("%v", err)
+			SetExitCode(2)
+			return err
+		}
+		stateDirFlag = dir
+		if projectDirFlag != "" {
+			info, err := os.Stat(projectDirFlag)
+			if err != nil || !info.IsDir() {
+				err = fmt.Errorf("project dir %s does not exist or is not a directory", projectDirFlag)
+				PrintError("%v", err)
+				SetExitCode(2)
+				return err
+			}
+		}
+
 		// Regenerate built-in skills on every command
 		// This ensures _ prefixed skills are always up to date
-		wd, err := os.Getwd()
+		wd, err := getProjectDir()
 		if err != nil {
-			return // Silently skip if we can't get working directory
+			return nil // Silently skip if we can't get working directory
 		}
-		skillsDir := filepath.Join(wd, ".ai", "skills")
+		skillsDir := projectAt(wd).SkillsDir()
 		// Only regenerate if the skills directory exists (project is initialized)
 		if _, err := os.Stat(skillsDir); err == nil {
-			if err := project.EnsureBuiltinSkills(skillsDir); err != nil {
+			backups, err := project.EnsureBuiltinSkills(skillsDir)
+			for _, backup := range backups {
+				name, _, _ := strings.Cut(filepath.Base(backup), ".")
+				fmt.Fprintf(os.Stderr, "Warning: %s.md had local edits and was refreshed; the edited copy is in %s. Put project changes in %s.md or under %q instead.\n",
+					name, backup, strings.TrimPrefix(name, "_"), project.UserCustomizationsHeading)
+			}
+			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to regenerate built-in skills: %v\n", err)
 			}
 		}
+		return nil
 	},
 }
 
@@ -55,6 +86,9 @@ func init() {
 	// Disable the default completion command
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
+	rootCmd.PersistentFlags().StringVarP(&projectDirFlag, "project-dir", "C", "", "Run as if agate was started in this directory")
+	rootCmd.PersistentFlags().StringVar(&stateDirFlag, "state-dir", project.DefaultStateDir, "State directory relative to the project (e.g. build/agate-state)")
+
 	// Silence Cobra's automatic error and usage printing for RunE errors.
 	// Our commands handle their own error output via PrintError.
 	// Cobra still prints errors for unknown commands, bad flags, etc.
@@ -80,6 +114,20 @@ func ExitWithCode() {
 	os.Exit(exitCode)
 }
 
+// getProjectDir returns the directory agate operates on: --project-dir if
+// set, otherwise the working directory
+func getProjectDir() (string, error) {
+	if projectDirFlag != "" {
+		return filepath.Abs(projectDirFlag)
+	}
+	return os.Getwd()
+}
+
+// projectAt returns the project in dir, with its state in --state-dir
+func projectAt(dir string) *project.Project {
+	return &project.Project{Dir: dir, AIDir: stateDirFlag}
+}
+
 // PrintError prints an error message to stderr
 func PrintError(format string, args ...interface{}) {
 	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+	"github.com/strongdm/agate/internal/workflow"
+)
+
+// runRoot executes the root command with args and resets global flag state
+func runRoot(t *testing.T, args ...string) error {
+	t.Helper()
+	t.Cleanup(func() {
+		projectDirFlag = ""
+		nextAgent = ""
+		nextTailFile = ""
+		logsKeepSprints = 0
+		logsMaxAge = ""
+		nextTask = 0
+		nextCmd.Flags().Lookup("task").Changed = false
+		nextPhaseOnly = false
+		nextNoRecovery = false
+		nextWebhook = ""
+		nextDoubleReview = false
+		nextStrictReview = false
+		nextStrictSkills = false
+		nextKeepGoing = false
+		nextModel = ""
+		nextEffort = ""
+		nextExplain = false
+		nextResumeSprint = 0
+		nextFresh = false
+		nextStreamFormat = "text"
+		nextWatch = false
+		nextTail = false
+		sprintAddFrom = ""
+		exportIssuesRepo = ""
+		exportIssuesSprint = 0
+		suggestFile = ""
+		chatAgent = ""
+		statusPlain = false
+		statusExitCodeOnly = false
+		statusWatch = false
+		statusInterval = workflow.DefaultStatusWatchInterval
+		stateDirFlag = project.DefaultStateDir
+		rootCmd.SetArgs(nil)
+		rootCmd.SetIn(nil)
+		rootCmd.SetOut(nil)
+		SetExitCode(0)
+	})
+	rootCmd.SetArgs(args)
+	return rootCmd.Execute()
+}
+
+// writeExecutionProject creates a project in dir that is in the execution
+// phase with the given sprint 1 content
+func writeExecutionProject(t *testing.T, dir, sprint string) {
+	t.Helper()
+	files := map[string]string{
+		"GOAL.md":                 "# Goal\n\nBuild a CLI in Go.",
+		".ai/interview.md":        "- [x] All questions answered\n",
+		".ai/design/overview.md":  "# Design\n",
+		".ai/design/decisions.md": "# Decisions\n",
+		".ai/sprints/01-a.md":     sprint,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".ai", "skills"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectDirFlag_OperatesOnDir(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+
+	// The test's working directory has no GOAL.md, so status there would
+	// need a human; the target project has work remaining
+	if err := runRoot(t, "-C", dir, "status"); err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	if code := GetExitCode(); code != workflow.ExitMoreWork {
+		t.Errorf("expected exit %d for target project, got %d", workflow.ExitMoreWork, code)
+	}
+
+	// Built-in skills are regenerated in the target project, not cwd
+	if _, err := os.Stat(filepath.Join(dir, ".ai", "skills", "_reviewer.md")); err != nil {
+		t.Errorf("expected built-in skills in target project: %v", err)
+	}
+}
+
+func TestProjectDirFlag_MissingDir(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "nope")
+	if err := runRoot(t, "--project-dir", missing, "status"); err == nil {
+		t.Error("expected error for missing project dir")
+	}
+	if code := GetExitCode(); code != workflow.ExitError {
+		t.Errorf("expected exit %d, got %d", workflow.ExitError, code)
+	}
+}
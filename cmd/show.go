@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var showSprintNum int
+
+var showCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Render workflow files in a readable form",
+}
+
+var showSprintCmd = &cobra.Command{
+	Use:   "sprint",
+	Short: "Show the current sprint as a task tree",
+	Long: `Show a sprint as a tree of tasks and sub-tasks with resolved progress:
+each task's failed-review (❌) and replan (🔄) counts, each sub-task's
+skill and checked state, and the overall progress bar.
+
+Defaults to the current sprint. Use --sprint N to show another one.`,
+	RunE: runShowSprint,
+}
+
+func init() {
+	showSprintCmd.Flags().IntVar(&showSprintNum, "sprint", 0, "Sprint number to show (default: current sprint)")
+	showCmd.AddCommand(showSprintCmd)
+	rootCmd.AddCommand(showCmd)
+}
+
+func runShowSprint(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	output, err := workflow.ShowSprint(projectAt(cwd), showSprintNum)
+	if err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	fmt.Print(output)
+	return nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var skillsCmd = &cobra.Command{
+	Use:   "skills",
+	Short: "Manage project skills",
+}
+
+var skillsLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Validate skill frontmatter and structure",
+	Long: `Check every skill in .ai/skills/ (under --state-dir, if set) for:
+  - A name field matching the file name
+  - Known agent names in agents:
+  - A valid phase: value
+  - Names that collide with built-in skills (warning only)
+
+Exit codes:
+  0 - No errors (warnings may be printed)
+  2 - One or more skills have errors`,
+	RunE: runSkillsLint,
+}
+
+func init() {
+	skillsCmd.AddCommand(skillsLintCmd)
+	rootCmd.AddCommand(skillsCmd)
+}
+
+func runSkillsLint(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	results, err := project.LintSkills(projectAt(cwd).SkillsDir())
+	if err != nil {
+		PrintError("failed to read skills: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	errorCount := 0
+	for _, r := range results {
+		if len(r.Errors) == 0 && len(r.Warnings) == 0 {
+			fmt.Printf("%s %s\n", logging.Green("+"), r.File)
+			continue
+		}
+		mark := logging.Yellow("!")
+		if len(r.Errors) > 0 {
+			mark = logging.Red("x")
+		}
+		fmt.Printf("%s %s\n", mark, r.File)
+		for _, e := range r.Errors {
+			fmt.Printf("    %s %v\n", logging.Red("error:"), e)
+		}
+		for _, w := range r.Warnings {
+			fmt.Printf("    %s %s\n", logging.Yellow("warning:"), w)
+		}
+		errorCount += len(r.Errors)
+	}
+
+	if errorCount > 0 {
+		err := fmt.Errorf("%d skill error(s) found", errorCount)
+		PrintError("%v", err)
+		SetExitCode(workflow.ExitError)
+		return err
+	}
+	fmt.Printf("%d skill(s) OK\n", len(results))
+	return nil
+}
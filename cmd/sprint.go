@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var sprintAddFrom string
+var exportIssuesRepo string
+var exportIssuesSprint int
+
+var sprintCmd = &cobra.Command{
+	Use:   "sprint",
+	Short: "Manage sprint files",
+}
+
+var sprintAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a hand-written sprint as the next sprint",
+	Long: `Add a sprint you wrote yourself instead of having the planner generate
+it. The sprint is read from --from, or from stdin, and must parse the way
+a planned sprint does: at least one task, each with skill-assigned
+sub-tasks, e.g.
+
+  # Sprint 3: Add caching
+
+  - [ ] Cache responses
+    - [ ] go-coder: Add an LRU cache to the client
+    - [ ] _reviewer: Review the cache
+
+It is written after the highest-numbered sprint, named from its heading.
+If the heading names another sprint number, a warning says so.`,
+	Args: cobra.NoArgs,
+	RunE: runSprintAdd,
+}
+
+var sprintExportIssuesCmd = &cobra.Command{
+	Use:   "export-issues",
+	Short: "Create a GitHub issue for each task in a sprint",
+	Long: `Create one GitHub issue per top-level task in a sprint, for teams that
+track work in GitHub. Each issue is titled with the task text, and its body
+lists the sub-tasks as a checklist, with completed sub-tasks checked.
+
+Issues are created with the gh CLI, which must be installed and logged in.
+Use --repo owner/name to choose the repository; by default gh uses the
+project's own. The sprint file is not changed.
+
+Re-running is safe: a task whose issue already exists (same title, same
+task and sprint) is listed instead of created again, so an export that
+failed partway can simply be run again. Existing issues are found with
+GitHub search, which can take a minute to index new issues: wait a little
+before re-running an export that just created some.
+
+Defaults to the current sprint. Use --sprint N to export another one.`,
+	Args: cobra.NoArgs,
+	RunE: runSprintExportIssues,
+}
+
+func init() {
+	sprintAddCmd.Flags().StringVar(&sprintAddFrom, "from", "", "Read the sprint from this file (default: stdin)")
+	sprintExportIssuesCmd.Flags().StringVar(&exportIssuesRepo, "repo", "", "GitHub repository as owner/name (default: the project's)")
+	sprintExportIssuesCmd.Flags().IntVar(&exportIssuesSprint, "sprint", 0, "Sprint number to export (default: current sprint)")
+	sprintCmd.AddCommand(sprintAddCmd)
+	sprintCmd.AddCommand(sprintExportIssuesCmd)
+	rootCmd.AddCommand(sprintCmd)
+}
+
+func runSprintAdd(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	var content []byte
+	if sprintAddFrom != "" {
+		content, err = os.ReadFile(sprintAddFrom)
+	} else {
+		content, err = io.ReadAll(cmd.InOrStdin())
+	}
+	if err != nil {
+		PrintError("failed to read sprint: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	added, err := workflow.AddSprint(projectAt(cwd), string(content))
+	if err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if added.Warning != "" {
+		fmt.Fprintln(out, logging.Yellow("⚠ "+added.Warning))
+	}
+	path := added.Path
+	if rel, err := filepath.Rel(cwd, path); err == nil {
+		path = rel
+	}
+	fmt.Fprintf(out, "%s Added sprint %d as %s\n", logging.Green("✓"), added.Num, path)
+	SetExitCode(workflow.ExitDone)
+	return nil
+}
+
+func runSprintExportIssues(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	exported, err := workflow.ExportIssues(projectAt(cwd), exportIssuesSprint, exportIssuesRepo, workflow.GHIssueClient{Dir: cwd})
+	out := cmd.OutOrStdout()
+	created, existing := 0, 0
+	for _, issue := range exported {
+		if issue.Existing {
+			existing++
+			fmt.Fprintf(out, "%s %s -> %s (already exists)\n", logging.Dim("="), issue.Title, issue.URL)
+			continue
+		}
+		created++
+		fmt.Fprintf(out, "%s %s -> %s\n", logging.Green("✓"), issue.Title, issue.URL)
+	}
+	if err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+	if created == 1 {
+		fmt.Fprint(out, "Created 1 issue")
+	} else {
+		fmt.Fprintf(out, "Created %d issues", created)
+	}
+	if existing > 0 {
+		fmt.Fprintf(out, " (%d already existed)", existing)
+	}
+	fmt.Fprintln(out)
+	SetExitCode(workflow.ExitDone)
+	return nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSprintAdd_AddsHandWrittenSprint(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [x] Task\n  - [x] go-coder: Work\n")
+	from := filepath.Join(t.TempDir(), "sprint.md")
+	sprint := "# Sprint 2: Add Caching!\n\n- [ ] Cache responses\n  - [ ] go-coder: Add an LRU cache\n  - [ ] go-reviewer: Review the cache\n"
+	if err := os.WriteFile(from, []byte(sprint), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "sprint", "add", "--from", from); err != nil {
+		t.Fatalf("sprint add failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, ".ai", "sprints", "02-add-caching.md"))
+	if err != nil {
+		t.Fatalf("expected 02-add-caching.md: %v\n%s", err, out.String())
+	}
+	if string(content) != sprint {
+		t.Errorf("sprint content changed:\n%s", content)
+	}
+	if !strings.Contains(out.String(), "Added sprint 2") || strings.Contains(out.String(), "⚠") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestSprintAdd_RejectsInvalidSprint(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+	rootCmd.SetIn(strings.NewReader("# Sprint 2\n\n- [ ] Task with no sub-tasks\n"))
+
+	if err := runRoot(t, "-C", dir, "sprint", "add"); err == nil {
+		t.Fatal("expected an invalid sprint to be rejected")
+	}
+	if GetExitCode() != 2 {
+		t.Errorf("expected exit code 2, got %d", GetExitCode())
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, ".ai", "sprints"))
+	if len(entries) != 1 {
+		t.Errorf("expected no new sprint file, got %d files", len(entries))
+	}
+}
+
+func TestSprintAdd_WarnsOnOverlappingNumber(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+	rootCmd.SetIn(strings.NewReader("# Sprint 1: Redo\n\n- [ ] Task\n  - [ ] go-coder: Redo it\n"))
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "sprint", "add"); err != nil {
+		t.Fatalf("sprint add failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "overlaps") {
+		t.Errorf("expected an overlap warning:\n%s", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ai", "sprints", "02-redo.md")); err != nil {
+		t.Errorf("expected the sprint to be added as sprint 2: %v", err)
+	}
+}
+
+func TestSprintExportIssues_CreatesIssuesWithGH(t *testing.T) {
+	dir := t.TempDir()
+	sprint := "# Sprint 1\n\n- [x] Parse input\n  - [x] go-coder: Write the parser\n\n- [ ] Add CLI\n  - [ ] go-coder: Wire up flags\n"
+	writeExecutionProject(t, dir, sprint)
+	bin := t.TempDir()
+	calls := filepath.Join(bin, "calls")
+	stub := "#!/bin/sh\nif [ \"$2\" = list ]; then echo '[]'; exit 0; fi\necho \"$@\" >> " + calls + "\ncat >> " + calls + "\necho https://github.com/acme/widgets/issues/7\n"
+	if err := os.WriteFile(filepath.Join(bin, "gh"), []byte(stub), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "sprint", "export-issues", "--repo", "acme/widgets"); err != nil {
+		t.Fatalf("export-issues failed: %v", err)
+	}
+	data, _ := os.ReadFile(calls)
+	for _, want := range []string{
+		"issue create --title Parse input --body-file - --repo acme/widgets",
+		"- [x] go-coder: Write the parser",
+		"issue create --title Add CLI --body-file - --repo acme/widgets",
+		"- [ ] go-coder: Wire up flags",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected gh to receive %q, got:\n%s", want, data)
+		}
+	}
+	if !strings.Contains(out.String(), "Add CLI -> https://github.com/acme/widgets/issues/7") || !strings.Contains(out.String(), "Created 2 issues") {
+		t.Errorf("expected created issues to be listed, got:\n%s", out.String())
+	}
+	if content, _ := os.ReadFile(filepath.Join(dir, ".ai", "sprints", "01-a.md")); string(content) != sprint {
+		t.Errorf("expected the sprint file untouched, got:\n%s", content)
+	}
+}
+
+func TestSprintExportIssues_SkipsExistingIssues(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [x] Parse input\n  - [x] go-coder: Write the parser\n\n- [ ] Add CLI\n  - [ ] go-coder: Wire up flags\n")
+	bin := t.TempDir()
+	calls := filepath.Join(bin, "calls")
+	existing := `[{"title":"Parse input","body":"Task 1 of sprint 1 (` + "`01-a.md`" + `).\r\n\r\n- [ ] go-coder: Write the parser","url":"https://github.com/acme/widgets/issues/3"}]`
+	stub := "#!/bin/sh\nif [ \"$2\" = list ]; then\n  case \"$*\" in *'\"Parse input\" in:title'*) printf '%s\\n' '" + existing + "' ;; *) echo '[]' ;; esac\n  exit 0\nfi\necho \"$@\" >> " + calls + "\necho https://github.com/acme/widgets/issues/8\n"
+	if err := os.WriteFile(filepath.Join(bin, "gh"), []byte(stub), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "sprint", "export-issues"); err != nil {
+		t.Fatalf("export-issues failed: %v", err)
+	}
+	data, _ := os.ReadFile(calls)
+	if strings.Contains(string(data), "--title Parse input") || !strings.Contains(string(data), "--title Add CLI") {
+		t.Errorf("expected only the missing issue to be created, got:\n%s", data)
+	}
+	if !strings.Contains(out.String(), "Parse input -> https://github.com/acme/widgets/issues/3 (already exists)") || !strings.Contains(out.String(), "Created 1 issue (1 already existed)") {
+		t.Errorf("expected the existing issue to be listed, got:\n%s", out.String())
+	}
+}
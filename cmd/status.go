@@ -3,11 +3,18 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/strongdm/agate/internal/logging"
 	"github.com/strongdm/agate/internal/workflow"
 	"github.com/spf13/cobra"
 )
 
+var statusPlain bool
+var statusExitCodeOnly bool
+var statusWatch bool
+var statusInterval time.Duration
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show current progress and relevant files",
@@ -18,6 +25,17 @@ var statusCmd = &cobra.Command{
   - Sprint progress
   - Next recommended action
 
+Use --plain for stable ASCII output with no colors or glyphs, one
+"key: value" line per fact, suitable for piping into other tools.
+
+Use --exit-code-only (-q) to print nothing and only set the exit code,
+e.g. 'agate status -q || agate next'.
+
+Use --watch to keep redrawing the status every --interval (default 5s),
+e.g. to monitor 'agate auto' from another terminal. It stops once the
+project is complete, or on Ctrl-C. When stdout isn't a terminal each
+update is printed below the last instead of redrawing in place.
+
 Exit codes:
   0   - All work complete (all sprints done)
   1   - More work remains (run 'agate next')
@@ -27,18 +45,38 @@ Exit codes:
 }
 
 func init() {
+	statusCmd.Flags().BoolVar(&statusPlain, "plain", false, "Plain ASCII output without colors, for scripts")
+	statusCmd.Flags().BoolVarP(&statusExitCodeOnly, "exit-code-only", "q", false, "Print nothing; only set the exit code")
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Redraw the status on an interval until the project is complete")
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", workflow.DefaultStatusWatchInterval, "How often --watch redraws")
 	rootCmd.AddCommand(statusCmd)
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
-	cwd, err := os.Getwd()
+	cwd, err := getProjectDir()
 	if err != nil {
-		PrintError("failed to get current directory: %v", err)
+		PrintError("failed to get project directory: %v", err)
 		SetExitCode(2)
 		return err
 	}
 
-	output, result, err := workflow.StatusWithResult(cwd)
+	if statusExitCodeOnly {
+		SetExitCode(workflow.GetExitCode(workflow.GetStatus(os.DirFS(cwd), projectAt(cwd).StateDir())))
+		return nil
+	}
+
+	if statusWatch {
+		return runStatusWatch(cmd, cwd)
+	}
+
+	if statusPlain {
+		output, result := workflow.StatusPlainWithResult(projectAt(cwd))
+		fmt.Print(output)
+		SetExitCode(workflow.GetExitCode(result))
+		return nil
+	}
+
+	output, result, err := workflow.StatusWithResult(projectAt(cwd))
 	if err != nil {
 		PrintError("%v", err)
 		SetExitCode(2)
@@ -49,3 +87,25 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	SetExitCode(workflow.GetExitCode(result))
 	return nil
 }
+
+// runStatusWatch redraws the status until the project is complete or the
+// user interrupts
+func runStatusWatch(cmd *cobra.Command, cwd string) error {
+	if statusInterval <= 0 {
+		err := fmt.Errorf("--interval must be positive, got %s", statusInterval)
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	watcher := workflow.NewStatusWatcher(projectAt(cwd), os.DirFS(cwd), workflow.RealClock())
+	watcher.Interval = statusInterval
+	watcher.Plain = statusPlain
+	if sv := logging.NewSplitView(os.Stdout, 0); sv.IsTTY() && cmd.OutOrStdout() == os.Stdout {
+		watcher.Clear = sv.Clear
+	}
+	// Ctrl-C ends the process as usual; there is nothing to clean up, and
+	// catching it would leave the user waiting out the interval
+	SetExitCode(watcher.Run(cmd.OutOrStdout(), nil))
+	return nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+	"github.com/strongdm/agate/internal/workflow"
+)
+
+// captureStdout runs fn with os.Stdout redirected and returns what it wrote
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestStatusExitCodeOnly(t *testing.T) {
+	tests := []struct {
+		name   string
+		sprint string // "" for a project without GOAL.md
+		want   int
+	}{
+		{"human needed", "", workflow.ExitHumanNeeded},
+		{"more work", "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n", workflow.ExitMoreWork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.sprint != "" {
+				writeExecutionProject(t, dir, tt.sprint)
+			}
+			want := workflow.GetExitCode(workflow.GetStatus(os.DirFS(dir), project.DefaultStateDir))
+			if want != tt.want {
+				t.Fatalf("fixture computes exit %d, expected %d", want, tt.want)
+			}
+
+			var runErr error
+			out := captureStdout(t, func() {
+				runErr = runRoot(t, "-C", dir, "status", "-q")
+			})
+			if runErr != nil {
+				t.Fatalf("status failed: %v", runErr)
+			}
+			if out != "" {
+				t.Errorf("expected no stdout, got %q", out)
+			}
+			if code := GetExitCode(); code != want {
+				t.Errorf("expected exit %d, got %d", want, code)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the last checkbox change in a sprint",
+	Long: `Revert the most recent checkbox change agate made to a sprint file.
+
+Every check, uncheck, and ❌ failure marker agate writes is recorded in an
+append-only journal in the sprints directory. 'agate undo' restores the
+line changed by the latest entry and removes that entry, so running it
+repeatedly walks back through earlier changes.
+
+If the line has been edited since the change, undo refuses and the sprint
+file must be fixed by hand.
+
+Exit codes:
+  0   - Change reverted (or nothing to undo)
+  2   - Error occurred`,
+	RunE: runUndo,
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	entry, err := workflow.Undo(projectAt(cwd).SprintsDir())
+	if errors.Is(err, workflow.ErrNothingToUndo) {
+		fmt.Fprintln(cmd.OutOrStdout(), "Nothing to undo")
+		SetExitCode(0)
+		return nil
+	}
+	if err != nil {
+		PrintError("%v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Reverted %s in %s line %d\n", entry.Op, entry.File, entry.Line)
+	fmt.Fprintf(out, "  - %s\n", entry.After)
+	fmt.Fprintf(out, "  + %s\n", entry.Before)
+	SetExitCode(0)
+	return nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUndo_RevertsLastStep(t *testing.T) {
+	dir := t.TempDir()
+	sprint := "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n  - [ ] _reviewer: Review work\n"
+	writeExecutionProject(t, dir, sprint)
+
+	if err := runRoot(t, "-C", dir, "next", "--agent", "dummy"); err != nil {
+		t.Fatalf("next failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "undo"); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Reverted check in 01-a.md line 4") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".ai", "sprints", "01-a.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != sprint {
+		t.Errorf("expected sprint restored, got:\n%s", data)
+	}
+
+	out.Reset()
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "undo"); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Nothing to undo") {
+		t.Errorf("expected nothing to undo, got: %s", out.String())
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check GOAL.md, sprints, and skills for problems",
+	Long: `Lint the whole project state before sharing it:
+  - GOAL.md exists, is readable, and is not empty or the template
+  - Every sprint parses into tasks with well-formed nested checkboxes
+  - Every skill named by a sub-task exists in .ai/skills/ (or is built in)
+  - Sprint numbers are unique and run from 1 without gaps
+  - Skill files have valid frontmatter (as 'agate skills lint')
+
+Each problem is printed as file:line: message.
+
+Exit codes:
+  0 - No problems found
+  2 - One or more problems found`,
+	RunE: runValidate,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	cwd, err := getProjectDir()
+	if err != nil {
+		PrintError("failed to get project directory: %v", err)
+		SetExitCode(2)
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	issues := workflow.Validate(projectAt(cwd))
+	for _, issue := range issues {
+		fmt.Fprintf(out, "%s %s\n", logging.Red("x"), issue)
+	}
+
+	if len(issues) > 0 {
+		err := fmt.Errorf("%d problem(s) found", len(issues))
+		PrintError("%v", err)
+		SetExitCode(workflow.ExitError)
+		return err
+	}
+	fmt.Fprintln(out, logging.Green("Project state OK"))
+	SetExitCode(workflow.ExitDone)
+	return nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+	"github.com/strongdm/agate/internal/workflow"
+)
+
+func TestValidate_CleanProject(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n  - [ ] _reviewer: Review\n")
+	skill := project.FormatSkillWithFrontmatter(project.SkillMetadata{Name: "go-coder", Agents: []string{"claude"}, Phase: "implement", Version: 1}, "# Go coder\n")
+	if err := os.MkdirAll(filepath.Join(dir, ".ai", "skills"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".ai", "skills", "go-coder.md"), []byte(skill), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "validate"); err != nil {
+		t.Fatalf("validate failed: %v\n%s", err, out.String())
+	}
+	if code := GetExitCode(); code != workflow.ExitDone {
+		t.Errorf("expected exit %d, got %d", workflow.ExitDone, code)
+	}
+	if !strings.Contains(out.String(), "Project state OK") {
+		t.Errorf("expected OK message, got:\n%s", out.String())
+	}
+}
+
+func TestValidate_ReportsProblems(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutionProject(t, dir, "# Sprint 1\n\n- [ ] Task\n  - [ ] nope-coder: Work\n")
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	if err := runRoot(t, "-C", dir, "validate"); err == nil {
+		t.Fatal("expected validate to fail")
+	}
+	if code := GetExitCode(); code != workflow.ExitError {
+		t.Errorf("expected exit %d, got %d", workflow.ExitError, code)
+	}
+	if !strings.Contains(out.String(), `.ai/sprints/01-a.md:4: unknown skill "nope-coder"`) {
+		t.Errorf("expected file:line issue, got:\n%s", out.String())
+	}
+}
@@ -5,12 +5,12 @@ go 1.24.0
 toolchain go1.24.5
 
 require (
+	github.com/fatih/color v1.18.0
 	github.com/spf13/cobra v1.8.0
 	golang.org/x/term v0.39.0
 )
 
 require (
-	github.com/fatih/color v1.18.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/strongdm/agate/internal/logging"
 )
@@ -42,12 +45,32 @@ type SafeModeAgent interface {
 	ExecuteSafeWithStream(ctx context.Context, prompt string, workDir string, output io.Writer) (string, error)
 }
 
+// ResultAgent is an agent that returns a full Result, including its CLI's
+// stderr, which is kept whether or not the run succeeds
+type ResultAgent interface {
+	Agent
+
+	// ExecuteResult runs a prompt, in safe mode if safe (see SafeModeAgent)
+	// and streaming to output if it is set. It sets AgentName, Output,
+	// Error and Stderr.
+	ExecuteResult(ctx context.Context, prompt string, workDir string, output io.Writer, safe bool) Result
+}
+
 // Result represents the result of an agent execution
 type Result struct {
 	AgentName string
 	Output    string
 	Error     error
 	LogPath   string // Path to the log file for this invocation
+	// FilesWritten lists project-relative paths written by WriteFiles, or
+	// by the agent itself when tracked (see ExecuteOptions.TrackWrites)
+	FilesWritten []string
+	// WritesTracked reports that TrackWrites compared the tree before and
+	// after the run, so an empty FilesWritten means nothing changed
+	WritesTracked bool
+	// Stderr is the agent CLI's stderr, kept even on success (warnings,
+	// deprecations, partial diagnostics). Only a ResultAgent sets it.
+	Stderr string
 }
 
 // AgentInfo contains metadata about an agent for display purposes
@@ -91,9 +114,36 @@ type ExecuteOptions struct {
 	PromptSummary string
 	// StreamWriter for real-time output streaming (optional, for -tail flag)
 	StreamWriter io.Writer
+	// Events, if set, receives a file_written event per file the invocation
+	// wrote and a final done event. Its chunk events come from also using
+	// it as (or within) StreamWriter.
+	Events *EventWriter
 	// SafeMode disables YOLO mode (--dangerously-skip-permissions) for agents
 	// Use this for planning phases where file writes are not needed
 	SafeMode bool
+	// WriteFiles, if set, is called with the agent output after a successful
+	// execution and returns the paths it wrote, which are recorded in the log
+	WriteFiles func(output string) []string
+	// KeepRaw also saves the verbatim response next to the log, as
+	// <log>.raw, in addition to any logger-wide setting
+	KeepRaw bool
+	// TrackWrites also records in FilesWritten the files the agent itself
+	// creates or modifies under workDir (e.g. codex in full-auto mode),
+	// found by comparing modification times before and after the run.
+	// .git and the workDir-relative directories in TrackIgnore are skipped.
+	TrackWrites bool
+	TrackIgnore []string
+	// Warn, if set, receives warnings such as a fallback to the next agent
+	// (printed to stdout in yellow if nil)
+	Warn func(msg string)
+}
+
+func (o ExecuteOptions) warn(msg string) {
+	if o.Warn == nil {
+		fmt.Printf("%s\n", logging.Yellow(msg))
+		return
+	}
+	o.Warn(msg)
 }
 
 // CheckCLI checks if a CLI tool is available
@@ -204,9 +254,12 @@ func ExecuteWithLogging(ctx context.Context, agent Agent, prompt string, workDir
 		)
 		if err != nil {
 			// Log error but continue execution
-			fmt.Printf("%s\n", logging.Yellow(fmt.Sprintf("Warning: failed to start log: %v", err)))
+			opts.warn(fmt.Sprintf("Warning: failed to start log: %v", err))
 		} else {
 			logFile.SetPrompt(prompt)
+			if opts.KeepRaw {
+				logFile.SetKeepRaw(true)
+			}
 			result.LogPath = logFile.Path
 		}
 	}
@@ -222,7 +275,18 @@ func ExecuteWithLogging(ctx context.Context, agent Agent, prompt string, workDir
 	}
 	countingWriter := NewCountingWriter(baseWriter, true)
 
-	if opts.SafeMode {
+	var before fileSnapshot
+	if opts.TrackWrites {
+		before = takeSnapshot(workDir, opts.TrackIgnore)
+	}
+
+	release, slotErr := acquireSlot(ctx, agent)
+	if slotErr != nil {
+		execErr = slotErr
+	} else if resultAgent, ok := agent.(ResultAgent); ok {
+		r := resultAgent.ExecuteResult(ctx, prompt, workDir, countingWriter, opts.SafeMode)
+		output, execErr, result.Stderr = r.Output, r.Error, r.Stderr
+	} else if opts.SafeMode {
 		if safeAgent, ok := agent.(SafeModeAgent); ok {
 			output, execErr = safeAgent.ExecuteSafeWithStream(ctx, prompt, workDir, countingWriter)
 		} else {
@@ -235,11 +299,34 @@ func ExecuteWithLogging(ctx context.Context, agent Agent, prompt string, workDir
 			output, execErr = agent.Execute(ctx, prompt, workDir)
 		}
 	}
+	release()
 	countingWriter.PrintFinal()
 
 	result.Output = output
 	result.Error = execErr
 
+	var direct []string
+	if opts.TrackWrites {
+		after := takeSnapshot(workDir, opts.TrackIgnore)
+		direct = changedSince(before, after)
+		result.WritesTracked = before != nil && after != nil
+	}
+	if execErr == nil && opts.WriteFiles != nil {
+		result.FilesWritten = opts.WriteFiles(output)
+	}
+	result.FilesWritten = mergeFiles(result.FilesWritten, direct)
+
+	if opts.Events != nil {
+		for _, f := range result.FilesWritten {
+			opts.Events.Emit(StreamEvent{Type: EventFileWritten, Path: f})
+		}
+		status := "success"
+		if execErr != nil {
+			status = "error"
+		}
+		opts.Events.Emit(StreamEvent{Type: EventDone, Status: status})
+	}
+
 	// Complete logging
 	if logFile != nil {
 		logFile.SetResponse(output)
@@ -248,10 +335,72 @@ func ExecuteWithLogging(ctx context.Context, agent Agent, prompt string, workDir
 		} else {
 			logFile.SetStatus("success")
 		}
+		for _, f := range result.FilesWritten {
+			logFile.AddFileWritten(f)
+		}
+		if result.Stderr != "" {
+			logFile.SetNotes("Agent stderr:\n\n```\n" + result.Stderr + "\n```")
+		}
 		if closeErr := logFile.Close(); closeErr != nil {
-			fmt.Printf("%s\n", logging.Yellow(fmt.Sprintf("Warning: failed to close log: %v", closeErr)))
+			opts.warn(fmt.Sprintf("Warning: failed to close log: %v", closeErr))
 		}
 	}
 
 	return result
 }
+
+// DefaultProbeTimeout bounds how long ProbeAgent waits for a reply to its
+// test prompt; a live agent answers it in seconds
+const DefaultProbeTimeout = 60 * time.Second
+
+// probePrompt is the trivial prompt ProbeAgent sends
+const probePrompt = "This is a connectivity check. Reply with just: OK"
+
+// ProbeAgent checks that an agent answers a trivial prompt before ctx is
+// done, so a hanging or broken CLI is caught before a long run starts. The
+// prompt runs in safe mode where supported, in the temp directory.
+func ProbeAgent(ctx context.Context, a Agent) error {
+	workDir := os.TempDir()
+	var err error
+	if safe, ok := a.(SafeModeAgent); ok {
+		_, err = safe.ExecuteSafeWithStream(ctx, probePrompt, workDir, io.Discard)
+	} else {
+		_, err = a.Execute(ctx, probePrompt, workDir)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%s did not answer a test prompt in time; check that its CLI runs and is logged in", a.Name())
+	}
+	if err != nil {
+		return fmt.Errorf("%s failed a test prompt: %w", a.Name(), err)
+	}
+	return nil
+}
+
+// versionProbeTimeout bounds how long ProbeVersion waits for a CLI
+const versionProbeTimeout = 10 * time.Second
+
+// ProbeVersion runs "<name> --version" and returns the first line of output.
+// This is a lightweight check that the CLI is installed and runnable.
+func ProbeVersion(name string) (string, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", fmt.Errorf("%s not found in PATH", name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), versionProbeTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("%s --version timed out", name)
+		}
+		return "", fmt.Errorf("%s --version failed: %w", name, err)
+	}
+
+	version := strings.TrimSpace(string(out))
+	if idx := strings.Index(version, "\n"); idx >= 0 {
+		version = strings.TrimSpace(version[:idx])
+	}
+	return version, nil
+}
@@ -1,7 +1,15 @@
 package agent
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
 )
 
 func TestCheckCLI(t *testing.T) {
@@ -84,3 +92,159 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestExecuteWithLogging_RecordsFilesWritten(t *testing.T) {
+	dir := t.TempDir()
+	logger := logging.NewLogger(project.New(dir), 1)
+
+	result := ExecuteWithLogging(context.Background(), NewDummyAgent(), "implement it", dir, ExecuteOptions{
+		Logger: logger,
+		Phase:  "implement",
+		Skill:  "go-coder",
+		WriteFiles: func(output string) []string {
+			return []string{"main.go", "cmd/root.go"}
+		},
+	})
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if len(result.FilesWritten) != 2 {
+		t.Errorf("expected 2 files written, got %v", result.FilesWritten)
+	}
+
+	content, err := os.ReadFile(result.LogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !contains(string(content), "## Files Written\n\n- main.go\n- cmd/root.go\n") {
+		t.Errorf("expected files in log:\n%s", content)
+	}
+}
+
+func TestExecuteWithLogging_KeepRaw(t *testing.T) {
+	dir := t.TempDir()
+
+	result := ExecuteWithLogging(context.Background(), NewDummyAgent(), "implement it", dir, ExecuteOptions{
+		Logger:  logging.NewLogger(project.New(dir), 1),
+		Phase:   "implement",
+		Skill:   "go-coder",
+		KeepRaw: true,
+	})
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+
+	raw, err := os.ReadFile(result.LogPath + ".raw")
+	if err != nil {
+		t.Fatalf("expected raw response file: %v", err)
+	}
+	if string(raw) != result.Output {
+		t.Errorf("raw file %q does not match output %q", raw, result.Output)
+	}
+}
+
+func TestExecuteWithLogging_TrackWrites(t *testing.T) {
+	dir := t.TempDir()
+	bin := t.TempDir()
+	// A codex stand-in that edits files directly instead of emitting them
+	script := "#!/bin/sh\nmkdir -p pkg\necho 'package pkg' > pkg/new.go\necho '// edited' >> main.go\necho x > .ai/notes\necho done\n"
+	if err := os.WriteFile(filepath.Join(bin, "codex"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	for name, content := range map[string]string{"main.go": "package main\n", "README.md": "# r\n"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		old := time.Now().Add(-time.Hour)
+		os.Chtimes(path, old, old)
+	}
+	os.MkdirAll(filepath.Join(dir, ".ai"), 0755)
+
+	result := ExecuteWithLogging(context.Background(), NewCodexAgent(), "implement it", dir, ExecuteOptions{
+		Logger:      logging.NewLogger(project.New(dir), 1),
+		Phase:       "implement",
+		Skill:       "go-coder",
+		TrackWrites: true,
+		TrackIgnore: []string{".ai"},
+	})
+	if result.Error != nil {
+		t.Fatalf("unexpected error: %v", result.Error)
+	}
+	if !reflect.DeepEqual(result.FilesWritten, []string{"main.go", "pkg/new.go"}) {
+		t.Errorf("expected main.go and pkg/new.go, got %v", result.FilesWritten)
+	}
+	if !result.WritesTracked {
+		t.Error("expected WritesTracked to be set")
+	}
+
+	content, err := os.ReadFile(result.LogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !contains(string(content), "## Files Written\n\n- main.go\n- pkg/new.go\n") {
+		t.Errorf("expected tracked files in log:\n%s", content)
+	}
+}
+
+func TestChangedSince_MergesWithEmitted(t *testing.T) {
+	stamp := fileStamp{modTime: time.Unix(100, 0), size: 1}
+	before := fileSnapshot{"a.go": stamp, "b.go": stamp, "old.go": stamp}
+	after := fileSnapshot{"a.go": stamp, "b.go": {modTime: time.Unix(200, 0), size: 1}, "c.go": stamp}
+
+	// Deleting old.go counts as a change
+	changed := changedSince(before, after)
+	if !reflect.DeepEqual(changed, []string{"b.go", "c.go", "old.go"}) {
+		t.Errorf("unexpected changes %v", changed)
+	}
+	if got := mergeFiles([]string{"c.go", "main.go"}, changed); !reflect.DeepEqual(got, []string{"c.go", "main.go", "b.go", "old.go"}) {
+		t.Errorf("unexpected merge %v", got)
+	}
+	if changedSince(nil, after) != nil {
+		t.Error("expected no changes without a before snapshot")
+	}
+}
+
+// writeClaudeStub puts a claude stand-in running body on PATH
+func writeClaudeStub(t *testing.T, body string) {
+	t.Helper()
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "claude"), []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestProbeAgent_Hangs(t *testing.T) {
+	writeClaudeStub(t, "exec sleep 10")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := ProbeAgent(ctx, NewClaudeAgent())
+	if err == nil || !contains(err.Error(), "did not answer") {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("probe took %s, expected it to stop at the timeout", elapsed)
+	}
+}
+
+func TestProbeAgent_Responds(t *testing.T) {
+	writeClaudeStub(t, "echo OK")
+
+	if err := ProbeAgent(context.Background(), NewClaudeAgent()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestProbeAgent_Fails(t *testing.T) {
+	writeClaudeStub(t, "echo 'not logged in' >&2; exit 1")
+
+	err := ProbeAgent(context.Background(), NewClaudeAgent())
+	if err == nil || !contains(err.Error(), "failed a test prompt") {
+		t.Fatalf("expected a failure, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cliAuth describes how an agent CLI reports being logged out and how to
+// log it back in
+type cliAuth struct {
+	// Login is the command that logs the CLI in
+	Login string
+	// Signatures are the CLI's own auth-error messages, matched
+	// case-insensitively against its stderr. They are specific phrases, not
+	// bare words, so ordinary output mentioning e.g. "unauthorized" doesn't
+	// match.
+	Signatures []string
+}
+
+// claudeAuth is the Claude CLI's; haiku shares it
+var claudeAuth = cliAuth{
+	Login: "claude /login",
+	Signatures: []string{
+		"please run /login",
+		"invalid api key",
+		"oauth token has expired",
+		`"type":"authentication_error"`,
+	},
+}
+
+// codexAuth is the Codex CLI's
+var codexAuth = cliAuth{
+	Login: "codex login",
+	Signatures: []string{
+		"run `codex login`",
+		"run 'codex login'",
+		"unexpected status 401 unauthorized",
+	},
+}
+
+// AuthError reports that an agent CLI failed because it isn't logged in.
+// A human has to log in; retrying or recovering with the same agent can't help.
+type AuthError struct {
+	Agent string
+	// Login is the command that logs the agent's CLI in
+	Login  string
+	Stderr string
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("%s is not logged in; run '%s' and try again", e.Agent, e.Login)
+}
+
+// isAuthFailure reports whether stderr holds one of the CLI's auth errors
+func (a cliAuth) isAuthFailure(stderr string) bool {
+	lower := strings.ToLower(stderr)
+	for _, sig := range a.Signatures {
+		if strings.Contains(lower, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// cliError builds the error for a failed agent CLI run: an AuthError if
+// stderr shows the CLI isn't logged in, otherwise err with stderr attached
+func cliError(name string, auth cliAuth, err error, stderr string) error {
+	if auth.isAuthFailure(stderr) {
+		return &AuthError{Agent: name, Login: auth.Login, Stderr: strings.TrimSpace(stderr)}
+	}
+	return fmt.Errorf("%s execution failed: %w\nstderr: %s", name, err, stderr)
+}
@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFailingCLI writes a stub CLI that prints stderr and exits 1
+func writeFailingCLI(t *testing.T, stderr string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cli")
+	script := "#!/bin/sh\necho '" + stderr + "' >&2\nexit 1\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write stub: %v", err)
+	}
+	return path
+}
+
+func TestAgents_AuthFailureIsAuthError(t *testing.T) {
+	tests := []struct {
+		agent Agent
+		login string
+	}{
+		{&ClaudeAgent{cliPath: writeFailingCLI(t, "Invalid API key · Please run /login")}, "claude /login"},
+		{&HaikuAgent{cliPath: writeFailingCLI(t, "Not logged in · Please run /login")}, "claude /login"},
+		{&CodexAgent{cliPath: writeFailingCLI(t, "stream error: unexpected status 401 Unauthorized: Missing bearer token")}, "codex login"},
+	}
+
+	for _, tt := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, err := tt.agent.Execute(ctx, "prompt", t.TempDir())
+		cancel()
+
+		var authErr *AuthError
+		if !errors.As(err, &authErr) {
+			t.Errorf("%s: expected an AuthError, got %v", tt.agent.Name(), err)
+			continue
+		}
+		if authErr.Agent != tt.agent.Name() || !strings.Contains(err.Error(), tt.login) {
+			t.Errorf("%s: expected a message pointing to %q, got %q", tt.agent.Name(), tt.login, err)
+		}
+	}
+}
+
+func TestAgents_OtherFailureIsNotAuthError(t *testing.T) {
+	tests := []struct {
+		agent  Agent
+		stderr string
+	}{
+		{&ClaudeAgent{cliPath: writeFailingCLI(t, "panic: something broke")}, "something broke"},
+		// Agent output that merely mentions auth isn't an auth failure
+		{&ClaudeAgent{cliPath: writeFailingCLI(t, "test failed: GET /admin returned 401 Unauthorized")}, "401 Unauthorized"},
+		{&CodexAgent{cliPath: writeFailingCLI(t, "handler rejects unauthorized users; user is not logged in")}, "not logged in"},
+	}
+
+	for _, tt := range tests {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, err := tt.agent.(StreamingAgent).ExecuteWithStream(ctx, "prompt", t.TempDir(), nil)
+		cancel()
+
+		var authErr *AuthError
+		if err == nil || errors.As(err, &authErr) {
+			t.Errorf("%s: expected a plain execution error, got %v", tt.agent.Name(), err)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.agent.Name()+" execution failed") || !strings.Contains(err.Error(), tt.stderr) {
+			t.Errorf("%s: expected stderr in the error, got %q", tt.agent.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// runCLI runs an agent CLI in workDir and returns its trimmed stdout as
+// Output and its stderr, which is kept whether or not the run succeeds.
+// Stdout is also copied to output if it is set. auth tells a failure from
+// the CLI being logged out.
+func runCLI(ctx context.Context, name string, auth cliAuth, cliPath string, args []string, workDir string, output io.Writer) Result {
+	cmd := exec.CommandContext(ctx, cliPath, args...)
+	cmd.Dir = workDir
+
+	var stdout, stderr bytes.Buffer
+	// Tee stdout to both the buffer (for return) and the output writer (for streaming)
+	if output != nil {
+		cmd.Stdout = io.MultiWriter(&stdout, output)
+	} else {
+		cmd.Stdout = &stdout
+	}
+	cmd.Stderr = &stderr
+
+	result := Result{AgentName: name}
+	err := cmd.Run()
+	result.Stderr = strings.TrimSpace(stderr.String())
+	if err != nil {
+		// Check if it's a context error
+		if ctx.Err() != nil {
+			result.Error = ctx.Err()
+		} else {
+			result.Error = cliError(name, auth, err, stderr.String())
+		}
+		return result
+	}
+
+	result.Output = strings.TrimSpace(stdout.String())
+	return result
+}
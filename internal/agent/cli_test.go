@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// writeStderrStub writes a CLI stub that succeeds but warns on stderr
+func writeStderrStub(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stub-cli")
+	script := "#!/bin/sh\necho 'warning: --print is deprecated' >&2\necho 'APPROVED'\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write stub: %v", err)
+	}
+	return path
+}
+
+func TestExecuteWithLogging_CapturesStderrOnSuccess(t *testing.T) {
+	stub := writeStderrStub(t)
+	agents := []Agent{
+		&ClaudeAgent{cliPath: stub},
+		&HaikuAgent{cliPath: stub},
+		&CodexAgent{cliPath: stub},
+	}
+
+	for _, a := range agents {
+		t.Run(a.Name(), func(t *testing.T) {
+			dir := t.TempDir()
+			result := ExecuteWithLogging(context.Background(), a, "review it", dir, ExecuteOptions{
+				Logger: logging.NewLogger(project.New(dir), 1),
+				Phase:  "implement",
+				Skill:  "_reviewer",
+			})
+			if result.Error != nil {
+				t.Fatalf("unexpected error: %v", result.Error)
+			}
+			if result.Output != "APPROVED" {
+				t.Errorf("stderr should not leak into output, got %q", result.Output)
+			}
+			if result.Stderr != "warning: --print is deprecated" {
+				t.Errorf("unexpected stderr %q", result.Stderr)
+			}
+
+			content, err := os.ReadFile(result.LogPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(content), "## Notes\n\nAgent stderr:\n\n```\nwarning: --print is deprecated\n```") {
+				t.Errorf("expected stderr in log notes:\n%s", content)
+			}
+		})
+	}
+}
+
+func TestExecuteWithLogging_NoStderrNoNotes(t *testing.T) {
+	dir := t.TempDir()
+	result := ExecuteWithLogging(context.Background(), NewDummyAgent(), "do it", dir, ExecuteOptions{
+		Logger: logging.NewLogger(project.New(dir), 1),
+		Phase:  "implement",
+		Skill:  "go-coder",
+	})
+	if result.Stderr != "" {
+		t.Errorf("expected no stderr, got %q", result.Stderr)
+	}
+	content, err := os.ReadFile(result.LogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "## Notes") {
+		t.Errorf("expected no notes section:\n%s", content)
+	}
+}
+
+func TestMultiAgent_ExecuteAllCapturesStderr(t *testing.T) {
+	stub := writeStderrStub(t)
+	m := NewMultiAgent([]Agent{&ClaudeAgent{cliPath: stub}, NewDummyAgent()})
+
+	results := m.ExecuteAll(context.Background(), "review it", t.TempDir())
+	if results[0].Stderr != "warning: --print is deprecated" {
+		t.Errorf("unexpected claude stderr %q", results[0].Stderr)
+	}
+	if results[1].Stderr != "" {
+		t.Errorf("unexpected dummy stderr %q", results[1].Stderr)
+	}
+}
+
+func TestExecuteResult_SetsStderr(t *testing.T) {
+	dir := t.TempDir()
+	failing := filepath.Join(dir, "failing-cli")
+	if err := os.WriteFile(failing, []byte("#!/bin/sh\necho 'boom' >&2\nexit 3\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	stub := writeStderrStub(t)
+
+	for _, tc := range []struct {
+		agent  ResultAgent
+		stderr string
+		failed bool
+	}{
+		{&ClaudeAgent{cliPath: stub}, "warning: --print is deprecated", false},
+		{&HaikuAgent{cliPath: stub}, "warning: --print is deprecated", false},
+		{&CodexAgent{cliPath: stub}, "warning: --print is deprecated", false},
+		{&ClaudeAgent{cliPath: failing}, "boom", true},
+	} {
+		r := tc.agent.ExecuteResult(context.Background(), "review it", dir, nil, true)
+		if (r.Error != nil) != tc.failed {
+			t.Errorf("%s: unexpected error %v", tc.agent.Name(), r.Error)
+		}
+		if r.AgentName != tc.agent.Name() || r.Stderr != tc.stderr {
+			t.Errorf("%s: got agent %q stderr %q", tc.agent.Name(), r.AgentName, r.Stderr)
+		}
+	}
+}
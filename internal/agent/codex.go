@@ -1,17 +1,19 @@
 package agent
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os/exec"
-	"strings"
 )
 
 // CodexAgent implements Agent for Codex CLI
 type CodexAgent struct {
 	cliPath string
+	// Model, if set, is passed to codex as --model
+	Model string
+	// Effort, if set, is passed to codex as its model_reasoning_effort
+	Effort string
 }
 
 // NewCodexAgent creates a new Codex agent
@@ -20,6 +22,47 @@ func NewCodexAgent() *CodexAgent {
 	return &CodexAgent{cliPath: path}
 }
 
+// ModelOptions picks the model and reasoning effort for agents that let the
+// caller choose them, currently only codex; "" keeps the agent's own default
+type ModelOptions struct {
+	Model  string
+	Effort string
+}
+
+// IsZero reports whether o leaves every agent at its default
+func (o ModelOptions) IsZero() bool {
+	return o.Model == "" && o.Effort == ""
+}
+
+// Apply returns a configured with o: a copy carrying o's model and effort
+// if a supports them, else a itself
+func (o ModelOptions) Apply(a Agent) Agent {
+	codex, ok := a.(*CodexAgent)
+	if !ok || o.IsZero() {
+		return a
+	}
+	configured := *codex
+	configured.Model, configured.Effort = o.Model, o.Effort
+	return &configured
+}
+
+// SupportsModelOptions reports whether the named agent honors ModelOptions
+func SupportsModelOptions(name string) bool {
+	return name == "codex"
+}
+
+// args returns the codex CLI arguments to run prompt in full-auto mode
+func (a *CodexAgent) args(prompt string) []string {
+	args := []string{"--full-auto-net", "exec"}
+	if a.Model != "" {
+		args = append(args, "--model", a.Model)
+	}
+	if a.Effort != "" {
+		args = append(args, "-c", "model_reasoning_effort="+a.Effort)
+	}
+	return append(args, prompt)
+}
+
 // Name returns the agent name
 func (a *CodexAgent) Name() string {
 	return "codex"
@@ -30,60 +73,23 @@ func (a *CodexAgent) Available() bool {
 	return a.cliPath != ""
 }
 
-// Execute runs a prompt using Codex CLI
-func (a *CodexAgent) Execute(ctx context.Context, prompt string, workDir string) (string, error) {
+// ExecuteResult runs a prompt using Codex CLI in full-auto mode, streaming
+// to output if set. Codex has no safe mode, so safe is ignored.
+func (a *CodexAgent) ExecuteResult(ctx context.Context, prompt string, workDir string, output io.Writer, safe bool) Result {
 	if !a.Available() {
-		return "", fmt.Errorf("codex CLI not available")
-	}
-
-	// Use codex CLI in full-auto mode
-	cmd := exec.CommandContext(ctx, a.cliPath, "--full-auto-net", "exec", prompt)
-	cmd.Dir = workDir
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	if err != nil {
-		// Check if it's a context error
-		if ctx.Err() != nil {
-			return "", ctx.Err()
-		}
-		return "", fmt.Errorf("codex execution failed: %w\nstderr: %s", err, stderr.String())
+		return Result{AgentName: a.Name(), Error: fmt.Errorf("codex CLI not available")}
 	}
+	return runCLI(ctx, a.Name(), codexAuth, a.cliPath, a.args(prompt), workDir, output)
+}
 
-	return strings.TrimSpace(stdout.String()), nil
+// Execute runs a prompt using Codex CLI
+func (a *CodexAgent) Execute(ctx context.Context, prompt string, workDir string) (string, error) {
+	r := a.ExecuteResult(ctx, prompt, workDir, nil, false)
+	return r.Output, r.Error
 }
 
 // ExecuteWithStream runs a prompt and streams output to the writer
 func (a *CodexAgent) ExecuteWithStream(ctx context.Context, prompt string, workDir string, output io.Writer) (string, error) {
-	if !a.Available() {
-		return "", fmt.Errorf("codex CLI not available")
-	}
-
-	// Use codex CLI in full-auto mode
-	cmd := exec.CommandContext(ctx, a.cliPath, "--full-auto-net", "exec", prompt)
-	cmd.Dir = workDir
-
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-
-	// Tee stdout to both the buffer (for return) and the output writer (for streaming)
-	if output != nil {
-		cmd.Stdout = io.MultiWriter(&stdout, output)
-	} else {
-		cmd.Stdout = &stdout
-	}
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	if err != nil {
-		if ctx.Err() != nil {
-			return "", ctx.Err()
-		}
-		return "", fmt.Errorf("codex execution failed: %w\nstderr: %s", err, stderr.String())
-	}
-
-	return strings.TrimSpace(stdout.String()), nil
+	r := a.ExecuteResult(ctx, prompt, workDir, output, false)
+	return r.Output, r.Error
 }
@@ -98,6 +98,67 @@ echo "STREAMING_TEST_OUTPUT"
 	}
 }
 
+// TestCodexAgent_ModelAndEffortFlags tests --model and the reasoning effort
+// are forwarded to codex only when configured
+func TestCodexAgent_ModelAndEffortFlags(t *testing.T) {
+	stubPath := filepath.Join(t.TempDir(), "codex")
+	stubScript := `#!/bin/sh
+echo "$@"
+`
+	if err := os.WriteFile(stubPath, []byte(stubScript), 0755); err != nil {
+		t.Fatalf("failed to write stub: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		model, effort string
+		want          string
+	}{
+		{"unset", "", "", "--full-auto-net exec test prompt"},
+		{"model", "gpt-5-codex", "", "--full-auto-net exec --model gpt-5-codex test prompt"},
+		{"effort", "", "high", "--full-auto-net exec -c model_reasoning_effort=high test prompt"},
+		{"both", "o3", "low", "--full-auto-net exec --model o3 -c model_reasoning_effort=low test prompt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &CodexAgent{cliPath: stubPath, Model: tt.model, Effort: tt.effort}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			output, err := agent.ExecuteWithStream(ctx, "test prompt", t.TempDir(), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output != tt.want {
+				t.Errorf("expected args %q, got %q", tt.want, output)
+			}
+		})
+	}
+}
+
+// TestModelOptions_Apply tests only Codex agents pick up the model and
+// effort, on a copy that leaves the original agent alone
+func TestModelOptions_Apply(t *testing.T) {
+	opts := ModelOptions{Model: "o3", Effort: "high"}
+	original := NewCodexAgent()
+
+	codex, ok := opts.Apply(original).(*CodexAgent)
+	if !ok || codex.Model != "o3" || codex.Effort != "high" {
+		t.Errorf("expected model o3 and effort high, got %+v", codex)
+	}
+	if original.Model != "" || original.Effort != "" {
+		t.Errorf("expected the original agent unchanged, got %+v", original)
+	}
+
+	claude := NewClaudeAgent()
+	if got := opts.Apply(claude); got != Agent(claude) {
+		t.Errorf("expected a non-codex agent unchanged, got %+v", got)
+	}
+	if got := (ModelOptions{}).Apply(original); got != Agent(original) {
+		t.Errorf("expected zero options to keep the agent, got %+v", got)
+	}
+}
+
 // TestCodexAgent_NotAvailable tests behavior when codex is not available
 func TestCodexAgent_NotAvailable(t *testing.T) {
 	agent := &CodexAgent{cliPath: ""}
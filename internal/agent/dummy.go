@@ -56,7 +56,6 @@ Implement the core functionality.
 
 - [ ] Implement core feature
   - [ ] go-coder: Write the main logic
-  - [ ] go-reviewer: Review for Go idioms
   - [ ] _reviewer: Validate feature complete
 `
 	} else if strings.Contains(promptLower, "technical decisions document") || strings.Contains(promptLower, "adr style") {
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// Stream formats for agent output consumers
+const (
+	StreamFormatText = "text" // The agent's raw output (default)
+	StreamFormatJSON = "json" // Newline-delimited StreamEvents
+)
+
+// Stream event types
+const (
+	EventChunk       = "chunk"        // The agent produced Bytes more bytes of output
+	EventFileWritten = "file_written" // The invocation wrote Path
+	EventDone        = "done"         // The invocation finished with Status
+)
+
+// StreamEvent is one line written by an EventWriter
+type StreamEvent struct {
+	Type   string `json:"type"`
+	Bytes  int    `json:"bytes,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// EventWriter turns agent output into newline-delimited JSON events for
+// tools wrapping agate. Used as an ExecuteOptions.StreamWriter, each write
+// becomes a chunk event carrying only its size; set as ExecuteOptions.Events,
+// ExecuteWithLogging adds a file_written event per file and a final done
+// event.
+type EventWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewEventWriter creates an EventWriter that writes events to w
+func NewEventWriter(w io.Writer) *EventWriter {
+	return &EventWriter{enc: json.NewEncoder(w)}
+}
+
+// Write implements io.Writer, emitting a chunk event for p
+func (e *EventWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err := e.Emit(StreamEvent{Type: EventChunk, Bytes: len(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Emit writes one event as a JSON line
+func (e *EventWriter) Emit(event StreamEvent) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.enc.Encode(event)
+}
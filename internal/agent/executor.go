@@ -114,9 +114,7 @@ func (e *Executor) Execute(ctx context.Context, invCtx InvocationContext, prompt
 	if promptSummary == "" {
 		promptSummary = fmt.Sprintf("Task %d", invCtx.TaskIndex)
 	}
-	if len(promptSummary) > 60 {
-		promptSummary = promptSummary[:57] + "..."
-	}
+	promptSummary = logging.TruncateRunes(promptSummary, 60)
 
 	// Start logging if logger is provided
 	var logFile *logging.LogFile
@@ -142,7 +140,10 @@ func (e *Executor) Execute(ctx context.Context, invCtx InvocationContext, prompt
 	var output string
 	var execErr error
 
-	if opts.StreamWriter != nil {
+	release, slotErr := acquireSlot(ctx, agent)
+	if slotErr != nil {
+		execErr = slotErr
+	} else if opts.StreamWriter != nil {
 		// Try streaming execution if agent supports it
 		if streamAgent, ok := agent.(StreamingAgent); ok {
 			output, execErr = streamAgent.ExecuteWithStream(ctx, prompt, opts.WorkDir, opts.StreamWriter)
@@ -153,6 +154,7 @@ func (e *Executor) Execute(ctx context.Context, invCtx InvocationContext, prompt
 	} else {
 		output, execErr = agent.Execute(ctx, prompt, opts.WorkDir)
 	}
+	release()
 
 	result.Output = output
 	result.Error = execErr
@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// maxSnapshotFiles bounds the files a snapshot records, so tracking writes
+// in a huge tree costs a bounded walk instead of a slow one
+const maxSnapshotFiles = 20000
+
+// fileStamp is what a snapshot records per file to notice a change
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// fileSnapshot maps slash-separated paths relative to the snapshot root to
+// their stamps. A nil snapshot means the tree was too large to record.
+type fileSnapshot map[string]fileStamp
+
+// takeSnapshot records the regular files under root, skipping .git and the
+// root-relative directories in ignore
+func takeSnapshot(root string, ignore []string) fileSnapshot {
+	skip := map[string]bool{".git": true}
+	for _, dir := range ignore {
+		skip[filepath.ToSlash(filepath.Clean(dir))] = true
+	}
+
+	snap := fileSnapshot{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Unreadable entries can't be tracked; skip them
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if skip[rel] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if len(snap) >= maxSnapshotFiles {
+			return filepath.SkipAll
+		}
+		snap[rel] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	if err != nil || len(snap) >= maxSnapshotFiles {
+		return nil
+	}
+	return snap
+}
+
+// changedSince returns the files that are new in after, whose stamp differs
+// from before, or that were deleted since before, sorted. It returns nil if
+// either snapshot is nil.
+func changedSince(before, after fileSnapshot) []string {
+	if before == nil || after == nil {
+		return nil
+	}
+	var changed []string
+	for path, stamp := range after {
+		if old, ok := before[path]; !ok || !old.modTime.Equal(stamp.modTime) || old.size != stamp.size {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// mergeFiles appends the paths in extra that aren't already in files
+func mergeFiles(files, extra []string) []string {
+	seen := make(map[string]bool, len(files))
+	for _, f := range files {
+		seen[filepath.ToSlash(f)] = true
+	}
+	for _, f := range extra {
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	return files
+}
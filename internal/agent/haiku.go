@@ -1,12 +1,10 @@
 package agent
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"os/exec"
-	"strings"
 )
 
 // HaikuAgent implements Agent for Claude CLI with Haiku model
@@ -31,114 +29,40 @@ func (a *HaikuAgent) Available() bool {
 	return a.cliPath != ""
 }
 
-// Execute runs a prompt using Claude CLI with haiku model
-func (a *HaikuAgent) Execute(ctx context.Context, prompt string, workDir string) (string, error) {
+// ExecuteResult runs a prompt using Claude CLI with haiku model, in YOLO
+// mode unless safe, streaming to output if set
+func (a *HaikuAgent) ExecuteResult(ctx context.Context, prompt string, workDir string, output io.Writer, safe bool) Result {
 	if !a.Available() {
-		return "", fmt.Errorf("claude CLI not available")
+		return Result{AgentName: a.Name(), Error: fmt.Errorf("claude CLI not available")}
 	}
 
-	// Use claude CLI in YOLO mode with --model haiku flag
-	cmd := exec.CommandContext(ctx, a.cliPath, "--dangerously-skip-permissions", "--model", "haiku", "--print", "-p", prompt)
-	cmd.Dir = workDir
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	if err != nil {
-		if ctx.Err() != nil {
-			return "", ctx.Err()
-		}
-		return "", fmt.Errorf("haiku execution failed: %w\nstderr: %s", err, stderr.String())
+	args := []string{"--model", "haiku", "--print", "-p", prompt}
+	if !safe {
+		args = append([]string{"--dangerously-skip-permissions"}, args...)
 	}
+	return runCLI(ctx, a.Name(), claudeAuth, a.cliPath, args, workDir, output)
+}
 
-	return strings.TrimSpace(stdout.String()), nil
+// Execute runs a prompt using Claude CLI with haiku model
+func (a *HaikuAgent) Execute(ctx context.Context, prompt string, workDir string) (string, error) {
+	r := a.ExecuteResult(ctx, prompt, workDir, nil, false)
+	return r.Output, r.Error
 }
 
 // ExecuteWithStream runs a prompt and streams output to the writer
 func (a *HaikuAgent) ExecuteWithStream(ctx context.Context, prompt string, workDir string, output io.Writer) (string, error) {
-	if !a.Available() {
-		return "", fmt.Errorf("claude CLI not available")
-	}
-
-	// Use claude CLI in YOLO mode with --model haiku flag
-	cmd := exec.CommandContext(ctx, a.cliPath, "--dangerously-skip-permissions", "--model", "haiku", "--print", "-p", prompt)
-	cmd.Dir = workDir
-
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-
-	if output != nil {
-		cmd.Stdout = io.MultiWriter(&stdout, output)
-	} else {
-		cmd.Stdout = &stdout
-	}
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	if err != nil {
-		if ctx.Err() != nil {
-			return "", ctx.Err()
-		}
-		return "", fmt.Errorf("haiku execution failed: %w\nstderr: %s", err, stderr.String())
-	}
-
-	return strings.TrimSpace(stdout.String()), nil
+	r := a.ExecuteResult(ctx, prompt, workDir, output, false)
+	return r.Output, r.Error
 }
 
 // ExecuteSafe runs a prompt without YOLO mode (safe for planning)
 func (a *HaikuAgent) ExecuteSafe(ctx context.Context, prompt string, workDir string) (string, error) {
-	if !a.Available() {
-		return "", fmt.Errorf("claude CLI not available")
-	}
-
-	// Use claude CLI WITHOUT --dangerously-skip-permissions
-	cmd := exec.CommandContext(ctx, a.cliPath, "--model", "haiku", "--print", "-p", prompt)
-	cmd.Dir = workDir
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	if err != nil {
-		if ctx.Err() != nil {
-			return "", ctx.Err()
-		}
-		return "", fmt.Errorf("haiku execution failed: %w\nstderr: %s", err, stderr.String())
-	}
-
-	return strings.TrimSpace(stdout.String()), nil
+	r := a.ExecuteResult(ctx, prompt, workDir, nil, true)
+	return r.Output, r.Error
 }
 
 // ExecuteSafeWithStream runs a prompt in safe mode and streams output
 func (a *HaikuAgent) ExecuteSafeWithStream(ctx context.Context, prompt string, workDir string, output io.Writer) (string, error) {
-	if !a.Available() {
-		return "", fmt.Errorf("claude CLI not available")
-	}
-
-	// Use claude CLI WITHOUT --dangerously-skip-permissions
-	cmd := exec.CommandContext(ctx, a.cliPath, "--model", "haiku", "--print", "-p", prompt)
-	cmd.Dir = workDir
-
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-
-	if output != nil {
-		cmd.Stdout = io.MultiWriter(&stdout, output)
-	} else {
-		cmd.Stdout = &stdout
-	}
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	if err != nil {
-		if ctx.Err() != nil {
-			return "", ctx.Err()
-		}
-		return "", fmt.Errorf("haiku execution failed: %w\nstderr: %s", err, stderr.String())
-	}
-
-	return strings.TrimSpace(stdout.String()), nil
+	r := a.ExecuteResult(ctx, prompt, workDir, output, true)
+	return r.Output, r.Error
 }
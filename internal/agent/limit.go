@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultMaxConcurrent is how many real agents may execute at once unless
+// SetMaxConcurrent says otherwise
+const DefaultMaxConcurrent = 2
+
+var (
+	slotsMu sync.Mutex
+	slots   = make(chan struct{}, DefaultMaxConcurrent)
+)
+
+// SetMaxConcurrent bounds how many real agent executions run at once in
+// this process, so parallel runs (e.g. double review) don't all trip the
+// provider's rate limit together. n < 1 removes the bound. Executions
+// already holding a slot keep it.
+func SetMaxConcurrent(n int) {
+	slotsMu.Lock()
+	defer slotsMu.Unlock()
+	if n < 1 {
+		slots = nil
+		return
+	}
+	if cap(slots) != n {
+		slots = make(chan struct{}, n)
+	}
+}
+
+// acquireSlot waits for an execution slot for a, or until ctx is done. The
+// dummy agent calls no provider and needs no slot. The returned release
+// must be called once the execution finishes; it is a no-op on error.
+func acquireSlot(ctx context.Context, a Agent) (release func(), err error) {
+	if a.Name() == "dummy" {
+		return func() {}, nil
+	}
+	slotsMu.Lock()
+	ch := slots
+	slotsMu.Unlock()
+	if ch == nil {
+		return func() {}, nil
+	}
+	select {
+	case ch <- struct{}{}:
+		return func() { <-ch }, nil
+	case <-ctx.Done():
+		return func() {}, ctx.Err()
+	}
+}
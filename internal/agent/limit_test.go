@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// trackingAgent records how many of its executions overlap
+type trackingAgent struct {
+	name    string
+	mu      *sync.Mutex
+	running *int
+	peak    *int
+}
+
+func (a *trackingAgent) Name() string    { return a.name }
+func (a *trackingAgent) Available() bool { return true }
+
+func (a *trackingAgent) Execute(ctx context.Context, prompt, workDir string) (string, error) {
+	a.mu.Lock()
+	*a.running++
+	if *a.running > *a.peak {
+		*a.peak = *a.running
+	}
+	a.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	a.mu.Lock()
+	*a.running--
+	a.mu.Unlock()
+	return "OK", nil
+}
+
+// peakConcurrency runs n agents named name at once and returns how many
+// executions overlapped at most
+func peakConcurrency(name string, n int) int {
+	var mu sync.Mutex
+	var running, peak int
+	agents := make([]Agent, n)
+	for i := range agents {
+		agents[i] = &trackingAgent{name: name, mu: &mu, running: &running, peak: &peak}
+	}
+	NewMultiAgent(agents).ExecuteAll(context.Background(), "prompt", "")
+	return peak
+}
+
+func TestSetMaxConcurrent_BoundsRealAgents(t *testing.T) {
+	SetMaxConcurrent(2)
+	t.Cleanup(func() { SetMaxConcurrent(DefaultMaxConcurrent) })
+
+	if peak := peakConcurrency("claude", 6); peak != 2 {
+		t.Errorf("expected at most 2 concurrent executions, got %d", peak)
+	}
+}
+
+func TestSetMaxConcurrent_DummyExempt(t *testing.T) {
+	SetMaxConcurrent(1)
+	t.Cleanup(func() { SetMaxConcurrent(DefaultMaxConcurrent) })
+
+	if peak := peakConcurrency("dummy", 4); peak != 4 {
+		t.Errorf("expected dummy executions to run unbounded, got a peak of %d", peak)
+	}
+}
+
+func TestAcquireSlot_HonorsContext(t *testing.T) {
+	SetMaxConcurrent(1)
+	t.Cleanup(func() { SetMaxConcurrent(DefaultMaxConcurrent) })
+
+	a := &trackingAgent{name: "claude"}
+	release, err := acquireSlot(context.Background(), a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := acquireSlot(ctx, a); err == nil {
+		t.Error("expected waiting for a slot to stop when the context is done")
+	}
+}
@@ -28,12 +28,7 @@ func (m *MultiAgent) ExecuteAll(ctx context.Context, prompt string, workDir stri
 		wg.Add(1)
 		go func(idx int, a Agent) {
 			defer wg.Done()
-			output, err := a.Execute(ctx, prompt, workDir)
-			results[idx] = Result{
-				AgentName: a.Name(),
-				Output:    output,
-				Error:     err,
-			}
+			results[idx] = executeResult(ctx, a, prompt, workDir)
 		}(i, agent)
 	}
 
@@ -41,15 +36,55 @@ func (m *MultiAgent) ExecuteAll(ctx context.Context, prompt string, workDir stri
 	return results
 }
 
-// ExecuteFirst runs the prompt on all agents and returns the first successful result
+// executeResult runs a single agent and wraps its output in a Result, taken
+// whole from a ResultAgent so its stderr is kept
+func executeResult(ctx context.Context, a Agent, prompt string, workDir string) Result {
+	release, err := acquireSlot(ctx, a)
+	defer release()
+	if err != nil {
+		return Result{AgentName: a.Name(), Error: err}
+	}
+	if ra, ok := a.(ResultAgent); ok {
+		return ra.ExecuteResult(ctx, prompt, workDir, nil, false)
+	}
+	output, err := a.Execute(ctx, prompt, workDir)
+	return Result{AgentName: a.Name(), Output: output, Error: err}
+}
+
+// ExecuteFirst runs the prompt on all agents and returns the first successful
+// result as soon as it arrives, cancelling the agents still running
 func (m *MultiAgent) ExecuteFirst(ctx context.Context, prompt string, workDir string) (Result, error) {
-	results := m.ExecuteAll(ctx, prompt, workDir)
+	return m.executeFirst(ctx, func(ctx context.Context, a Agent) Result {
+		return executeResult(ctx, a, prompt, workDir)
+	})
+}
 
-	// Return first successful result
-	for _, r := range results {
-		if r.Error == nil {
-			return r, nil
+// executeFirst runs run on every agent in parallel under a shared context.
+// The first success cancels that context and is returned without waiting for
+// the others; if every agent fails, their errors are combined in agent order.
+func (m *MultiAgent) executeFirst(ctx context.Context, run func(ctx context.Context, a Agent) Result) (Result, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type indexed struct {
+		idx    int
+		result Result
+	}
+	// Buffered so agents finishing after we return never block
+	done := make(chan indexed, len(m.agents))
+	for i, agent := range m.agents {
+		go func(idx int, a Agent) {
+			done <- indexed{idx, run(ctx, a)}
+		}(i, agent)
+	}
+
+	results := make([]Result, len(m.agents))
+	for range m.agents {
+		r := <-done
+		if r.result.Error == nil {
+			return r.result, nil
 		}
+		results[r.idx] = r.result
 	}
 
 	// All failed, return combined error
@@ -125,25 +160,41 @@ func (m *MultiAgent) ExecuteAllWithLogging(ctx context.Context, prompt string, w
 	return results
 }
 
-// ExecuteFirstWithLogging runs the prompt on all agents and returns the first successful result with logging
+// ExecuteFirstWithLogging runs the prompt on all agents with logging and
+// returns the first successful result, cancelling the agents still running
 func (m *MultiAgent) ExecuteFirstWithLogging(ctx context.Context, prompt string, workDir string, opts ExecuteOptions) (Result, error) {
-	results := m.ExecuteAllWithLogging(ctx, prompt, workDir, opts)
-
-	// Return first successful result
-	for _, r := range results {
-		if r.Error == nil {
-			return r, nil
-		}
-	}
+	return m.executeFirst(ctx, func(ctx context.Context, a Agent) Result {
+		return ExecuteWithLogging(ctx, a, prompt, workDir, opts)
+	})
+}
 
-	// All failed, return combined error
+// ExecuteInOrderWithLogging runs the prompt on each agent in turn, with
+// logging, until one succeeds. Each attempt gets its own log. It stops early
+// when ctx is done, since later agents would fail the same way. If every
+// attempt fails, the last result is returned with an error naming each
+// failure and wrapping the last one.
+func (m *MultiAgent) ExecuteInOrderWithLogging(ctx context.Context, prompt string, workDir string, opts ExecuteOptions) Result {
+	var result Result
 	var errs []string
-	for _, r := range results {
-		if r.Error != nil {
-			errs = append(errs, fmt.Sprintf("%s: %v", r.AgentName, r.Error))
+	for i, a := range m.agents {
+		result = ExecuteWithLogging(ctx, a, prompt, workDir, opts)
+		if result.Error == nil {
+			return result
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", a.Name(), result.Error))
+		if ctx.Err() != nil {
+			return result
+		}
+		if i+1 < len(m.agents) {
+			reason, _, _ := strings.Cut(result.Error.Error(), "\n")
+			opts.warn(fmt.Sprintf("⚠ %s failed: %s. Trying %s...", a.Name(), reason, m.agents[i+1].Name()))
 		}
 	}
-	return Result{}, fmt.Errorf("all agents failed:\n%s", strings.Join(errs, "\n"))
+	if len(errs) > 1 {
+		last := m.agents[len(m.agents)-1].Name()
+		result.Error = fmt.Errorf("all agents failed: %s; %s: %w", strings.Join(errs[:len(errs)-1], "; "), last, result.Error)
+	}
+	return result
 }
 
 // ExecuteOnAgentWithLogging runs the prompt on a specific agent by name with logging
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeAgent returns output or err after delay, or ctx.Err() if cancelled first
+type fakeAgent struct {
+	name      string
+	delay     time.Duration
+	output    string
+	err       error
+	cancelled chan struct{}
+}
+
+func (a *fakeAgent) Name() string    { return a.name }
+func (a *fakeAgent) Available() bool { return true }
+
+func (a *fakeAgent) Execute(ctx context.Context, prompt string, workDir string) (string, error) {
+	select {
+	case <-time.After(a.delay):
+		return a.output, a.err
+	case <-ctx.Done():
+		if a.cancelled != nil {
+			close(a.cancelled)
+		}
+		return "", ctx.Err()
+	}
+}
+
+func TestMultiAgent_ExecuteFirstReturnsPromptly(t *testing.T) {
+	slow := &fakeAgent{name: "slow", delay: 10 * time.Second, output: "late", cancelled: make(chan struct{})}
+	fast := &fakeAgent{name: "fast", delay: 10 * time.Millisecond, output: "quick"}
+	m := NewMultiAgent([]Agent{slow, fast})
+
+	start := time.Now()
+	result, err := m.ExecuteFirst(context.Background(), "prompt", t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("ExecuteFirst waited for the slow agent: %s", elapsed)
+	}
+	if result.AgentName != "fast" || result.Output != "quick" {
+		t.Errorf("expected fast agent result, got %+v", result)
+	}
+
+	select {
+	case <-slow.cancelled:
+	case <-time.After(2 * time.Second):
+		t.Error("expected the slow agent's context to be cancelled")
+	}
+}
+
+func TestMultiAgent_ExecuteFirstSkipsFailures(t *testing.T) {
+	failing := &fakeAgent{name: "failing", delay: time.Millisecond, err: errors.New("boom")}
+	ok := &fakeAgent{name: "ok", delay: 50 * time.Millisecond, output: "done"}
+	m := NewMultiAgent([]Agent{failing, ok})
+
+	result, err := m.ExecuteFirst(context.Background(), "prompt", t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.AgentName != "ok" {
+		t.Errorf("expected the successful agent, got %+v", result)
+	}
+}
+
+func TestMultiAgent_ExecuteFirstAllFail(t *testing.T) {
+	a := &fakeAgent{name: "a", delay: 20 * time.Millisecond, err: errors.New("first")}
+	b := &fakeAgent{name: "b", delay: time.Millisecond, err: errors.New("second")}
+	m := NewMultiAgent([]Agent{a, b})
+
+	_, err := m.ExecuteFirst(context.Background(), "prompt", t.TempDir())
+	if err == nil {
+		t.Fatal("expected error when all agents fail")
+	}
+	// Errors are reported in agent order, not arrival order
+	if !strings.Contains(err.Error(), "a: first\nb: second") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMultiAgent_ExecuteAllWaitsForAll(t *testing.T) {
+	slow := &fakeAgent{name: "slow", delay: 50 * time.Millisecond, output: "late"}
+	fast := &fakeAgent{name: "fast", delay: time.Millisecond, output: "quick"}
+	m := NewMultiAgent([]Agent{slow, fast})
+
+	results := m.ExecuteAll(context.Background(), "prompt", t.TempDir())
+	if len(results) != 2 || results[0].Output != "late" || results[1].Output != "quick" {
+		t.Errorf("expected both results in agent order, got %+v", results)
+	}
+}
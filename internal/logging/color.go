@@ -1,14 +1,73 @@
 package logging
 
-import "github.com/fatih/color"
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+)
 
 // Color sprint functions for consistent terminal output styling.
 // These respect NO_COLOR and non-TTY environments automatically.
+// SetTheme remaps them.
 var (
-	Green    = color.New(color.FgGreen).SprintFunc()
-	Yellow   = color.New(color.FgYellow).SprintFunc()
-	Cyan     = color.New(color.FgCyan).SprintFunc()
-	Bold     = color.New(color.Bold).SprintFunc()
-	BoldCyan = color.New(color.Bold, color.FgCyan).SprintFunc()
-	Dim      = color.New(color.Faint).SprintFunc()
+	Green    func(a ...interface{}) string
+	Yellow   func(a ...interface{}) string
+	Red      func(a ...interface{}) string
+	Cyan     func(a ...interface{}) string
+	Bold     func(a ...interface{}) string
+	BoldCyan func(a ...interface{}) string
+	Dim      func(a ...interface{}) string
+)
+
+// Terminal color themes
+const (
+	ThemeDark  = "dark"  // The default, for dark backgrounds
+	ThemeLight = "light" // Darker hues that stay readable on light backgrounds
+	ThemeMono  = "mono"  // No color codes at all, even when color is forced
 )
+
+// ThemeEnv names the environment variable that selects the theme
+const ThemeEnv = "AGATE_THEME"
+
+func init() {
+	theme := os.Getenv(ThemeEnv)
+	if err := SetTheme(theme); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using the %s theme\n", err, ThemeDark)
+		SetTheme(ThemeDark)
+	}
+}
+
+// SetTheme remaps the color functions to the named theme ("" is the
+// default dark theme)
+func SetTheme(name string) error {
+	sprint := func(attrs ...color.Attribute) func(a ...interface{}) string {
+		return color.New(attrs...).SprintFunc()
+	}
+
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", ThemeDark:
+		Green = sprint(color.FgGreen)
+		Yellow = sprint(color.FgYellow)
+		Red = sprint(color.FgRed)
+		Cyan = sprint(color.FgCyan)
+		Bold = sprint(color.Bold)
+		BoldCyan = sprint(color.Bold, color.FgCyan)
+		Dim = sprint(color.Faint)
+	case ThemeLight:
+		Green = sprint(color.FgGreen)
+		Yellow = sprint(color.FgMagenta)
+		Red = sprint(color.FgRed)
+		Cyan = sprint(color.FgBlue)
+		Bold = sprint(color.Bold)
+		BoldCyan = sprint(color.Bold, color.FgBlue)
+		Dim = sprint(color.FgHiBlack)
+	case ThemeMono:
+		Green, Yellow, Red, Cyan = fmt.Sprint, fmt.Sprint, fmt.Sprint, fmt.Sprint
+		Bold, BoldCyan, Dim = fmt.Sprint, fmt.Sprint, fmt.Sprint
+	default:
+		return fmt.Errorf("unknown theme %q (want %s, %s, or %s)", name, ThemeDark, ThemeLight, ThemeMono)
+	}
+	return nil
+}
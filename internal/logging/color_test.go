@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestSetTheme_Mono(t *testing.T) {
+	// Force color, as on a TTY, so only the theme can strip it
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() {
+		color.NoColor = noColor
+		SetTheme(ThemeDark)
+	})
+
+	if err := SetTheme(ThemeDark); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(Green("ok"), "\x1b[") {
+		t.Fatalf("expected color codes from the dark theme, got %q", Green("ok"))
+	}
+
+	if err := SetTheme(ThemeMono); err != nil {
+		t.Fatal(err)
+	}
+	helpers := []func(a ...interface{}) string{Green, Yellow, Red, Cyan, Bold, BoldCyan, Dim}
+	for i, h := range helpers {
+		if got := h("text"); got != "text" {
+			t.Errorf("helper %d: expected plain text in mono, got %q", i, got)
+		}
+	}
+}
+
+func TestSetTheme_Light(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() {
+		color.NoColor = noColor
+		SetTheme(ThemeDark)
+	})
+
+	if err := SetTheme("Light"); err != nil {
+		t.Fatal(err)
+	}
+	dark := color.New(color.FgYellow).Sprint("warn")
+	if got := Yellow("warn"); got == dark || !strings.Contains(got, "\x1b[") {
+		t.Errorf("expected a light-theme color for Yellow, got %q", got)
+	}
+}
+
+func TestSetTheme_Unknown(t *testing.T) {
+	t.Cleanup(func() { SetTheme(ThemeDark) })
+	if err := SetTheme("neon"); err == nil {
+		t.Error("expected an error for an unknown theme")
+	}
+}
@@ -71,6 +71,74 @@ func FormatInvocation(inv *Invocation) string {
 	return sb.String()
 }
 
+// ParseInvocationTimestamp returns the Timestamp recorded in an invocation
+// log written by FormatInvocation
+func ParseInvocationTimestamp(content string) (time.Time, bool) {
+	return parseTimeAfter(content, "| Timestamp | ")
+}
+
+// ParseInvocationStatus returns the Status recorded in an invocation log
+// written by FormatInvocation
+func ParseInvocationStatus(content string) (string, bool) {
+	for _, line := range strings.Split(content, "\n") {
+		if value, ok := strings.CutPrefix(line, "| Status | "); ok {
+			return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "|")), true
+		}
+	}
+	return "", false
+}
+
+// responseOpen starts the response section FormatInvocation writes; the
+// response follows, then the closing fence
+const responseOpen = "## Response\n\n```\n"
+
+// ParseInvocationResponse returns the agent response recorded in an
+// invocation log written by FormatInvocation, without the log's own fence
+// around it. Fences inside the response are kept.
+func ParseInvocationResponse(content string) (string, bool) {
+	start := strings.Index(content, "\n"+responseOpen)
+	if start < 0 {
+		return "", false
+	}
+	body := content[start+1+len(responseOpen):]
+
+	// The response ends at the fence closing it, followed by the next
+	// section FormatInvocation writes or the end of the log
+	end := -1
+	for _, next := range []string{"## Files Written\n", "## Error\n", "## Notes\n"} {
+		if i := strings.Index(body, "```\n\n"+next); i >= 0 && (end < 0 || i < end) && (i == 0 || body[i-1] == '\n') {
+			end = i
+		}
+	}
+	if end < 0 {
+		end = strings.LastIndex(body, "```")
+		if end < 0 {
+			return "", false
+		}
+	}
+	return strings.TrimSuffix(body[:end], "\n"), true
+}
+
+// ParseRetroGenerated returns when a retrospective written by FormatRetro
+// was generated
+func ParseRetroGenerated(content string) (time.Time, bool) {
+	return parseTimeAfter(content, "Generated: ")
+}
+
+// parseTimeAfter parses the RFC3339 time that follows prefix at the start of
+// a line in content
+func parseTimeAfter(content, prefix string) (time.Time, bool) {
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		value := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line[len(prefix):]), "|"))
+		t, err := time.Parse(time.RFC3339, value)
+		return t, err == nil
+	}
+	return time.Time{}, false
+}
+
 // FormatRetro formats a retrospective summary
 func FormatRetro(sprintNumber int, summary string, skillUpdates map[string]string) string {
 	var sb strings.Builder
@@ -105,28 +173,107 @@ func FormatInterview(questions []InterviewQuestion) string {
 		sb.WriteString(fmt.Sprintf("### Q%d: %s\n\n", i+1, q.Title))
 		sb.WriteString(q.Question)
 		sb.WriteString("\n\n")
-		if len(q.Options) > 0 {
+		if kind := q.EffectiveKind(); kind != QuestionText {
+			if kind == QuestionSingle {
+				sb.WriteString(singleChoiceGuidance + "\n\n")
+			} else {
+				sb.WriteString(multiChoiceGuidance + "\n\n")
+			}
 			for _, opt := range q.Options {
 				sb.WriteString(fmt.Sprintf("- [ ] %s\n", opt))
 			}
 			sb.WriteString("- [ ] No preference\n")
 			sb.WriteString("\n> Notes:\n")
 		} else {
+			// A free-text question keeps any options as suggestions
+			if len(q.Options) > 0 {
+				sb.WriteString(fmt.Sprintf("*Suggestions: %s*\n\n", strings.Join(q.Options, ", ")))
+			}
 			sb.WriteString("> Answer:\n")
 		}
 		sb.WriteString("\n---\n\n")
 	}
 
-	sb.WriteString("- [ ] All questions answered (check when complete)\n")
+	sb.WriteString(InterviewCompletionLine + "\n")
 
 	return sb.String()
 }
 
+// Interview question kinds
+const (
+	QuestionText   = "text"   // Free-text answer
+	QuestionSingle = "single" // Pick exactly one option
+	QuestionMulti  = "multi"  // Pick any number of options
+)
+
+// Guidance lines rendered above option lists. The parser uses the
+// single-choice line to recover the question kind from the interview file.
+const (
+	singleChoiceGuidance = "*Choose one (check a single box):*"
+	multiChoiceGuidance  = "*Choose all that apply:*"
+)
+
 // InterviewQuestion represents a single interview question
 type InterviewQuestion struct {
 	Title    string
 	Question string
 	Options  []string
+	Kind     string // QuestionText, QuestionSingle, or QuestionMulti (empty = infer)
+}
+
+// EffectiveKind returns the question kind, inferring it when unset:
+// questions with options are multi-choice, others are free text. A text
+// question may still carry options, which are shown as suggestions.
+func (q InterviewQuestion) EffectiveKind() string {
+	if len(q.Options) == 0 {
+		return QuestionText
+	}
+	switch q.Kind {
+	case QuestionText, QuestionSingle:
+		return q.Kind
+	}
+	return QuestionMulti
+}
+
+// ParseQuestionKind normalizes a TYPE value such as "single-choice" or
+// "free text" to a question kind constant ("" if unrecognized)
+func ParseQuestionKind(value string) string {
+	v := strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case strings.HasPrefix(v, "single"), v == "radio":
+		return QuestionSingle
+	case strings.HasPrefix(v, "multi"), v == "checkbox":
+		return QuestionMulti
+	case strings.HasPrefix(v, "text"), strings.HasPrefix(v, "free"):
+		return QuestionText
+	}
+	return ""
+}
+
+// InterviewCompletionLine is the checkbox the user checks when the interview is done
+const InterviewCompletionLine = "- [ ] All questions answered (check when complete)"
+
+// HasInterviewCompletionMarker reports whether the interview has a completion
+// checkbox (checked or not) or a legacy Status line. Without one, the user has
+// no way to mark the interview complete.
+func HasInterviewCompletionMarker(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "- [") && strings.Contains(trimmed, "All questions answered") {
+			return true
+		}
+		if strings.HasPrefix(trimmed, "Status:") {
+			return true
+		}
+	}
+	return false
+}
+
+// AppendInterviewCompletionLine adds an unchecked completion checkbox to the
+// end of an interview file's content
+func AppendInterviewCompletionLine(content string) string {
+	content = strings.TrimRight(content, "\n")
+	return content + "\n\n---\n\n" + InterviewCompletionLine + "\n"
 }
 
 // ParseInterviewStatus checks if the interview is complete
@@ -148,14 +295,33 @@ func ParseInterviewStatus(content string) bool {
 
 // ParseInterviewAnswers extracts answers from interview file.
 // Supports the new checkbox/blockquote format and the legacy **Answer**: format.
+// A "> Answer:" or "> Notes:" blockquote continues over the following ">"
+// lines, so answers may span several lines.
 func ParseInterviewAnswers(content string) map[string]string {
 	answers := make(map[string]string)
 
 	lines := strings.Split(content, "\n")
 	var currentQuestion string
 	var checked []string
+	singleChoice := false
+	var quote []string // Lines of the open answer/notes blockquote
+	inQuote := false
+
+	flushQuote := func() {
+		text := strings.TrimSpace(strings.Join(quote, "\n"))
+		if inQuote && text != "" {
+			if existing, ok := answers[currentQuestion]; ok && existing != "" {
+				answers[currentQuestion] = existing + "\n" + text
+			} else {
+				answers[currentQuestion] = text
+			}
+		}
+		quote = nil
+		inQuote = false
+	}
 
 	flushQuestion := func() {
+		flushQuote()
 		if currentQuestion != "" && len(checked) > 0 {
 			if existing, ok := answers[currentQuestion]; ok && existing != "" {
 				answers[currentQuestion] = strings.Join(checked, ", ") + "\n" + existing
@@ -164,6 +330,7 @@ func ParseInterviewAnswers(content string) map[string]string {
 			}
 		}
 		checked = nil
+		singleChoice = false
 	}
 
 	for _, line := range lines {
@@ -176,29 +343,31 @@ func ParseInterviewAnswers(content string) map[string]string {
 		} else if currentQuestion != "" {
 			trimmed := strings.TrimSpace(line)
 
-			// Checked checkbox
-			if strings.HasPrefix(trimmed, "- [x] ") || strings.HasPrefix(trimmed, "- [X] ") {
-				val := trimmed[6:]
-				if val != "No preference" {
-					checked = append(checked, val)
+			// Blockquote answer/notes, continued by following ">" lines
+			if strings.HasPrefix(trimmed, "> Notes:") || strings.HasPrefix(trimmed, "> Answer:") {
+				flushQuote()
+				inQuote = true
+				_, text, _ := strings.Cut(trimmed, ":")
+				quote = append(quote, strings.TrimSpace(text))
+				continue
+			}
+			if inQuote {
+				if strings.HasPrefix(trimmed, ">") {
+					quote = append(quote, strings.TrimSpace(strings.TrimPrefix(trimmed, ">")))
+					continue
 				}
+				flushQuote()
 			}
 
-			// Blockquote answer/notes
-			if strings.HasPrefix(trimmed, "> Notes:") || strings.HasPrefix(trimmed, "> Answer:") {
-				var prefix string
-				if strings.HasPrefix(trimmed, "> Notes:") {
-					prefix = "> Notes:"
-				} else {
-					prefix = "> Answer:"
-				}
-				text := strings.TrimSpace(strings.TrimPrefix(trimmed, prefix))
-				if text != "" {
-					if existing, ok := answers[currentQuestion]; ok && existing != "" {
-						answers[currentQuestion] = existing + "\n" + text
-					} else {
-						answers[currentQuestion] = text
-					}
+			if trimmed == singleChoiceGuidance {
+				singleChoice = true
+			}
+
+			// Checked checkbox (single-choice questions keep only the first)
+			if strings.HasPrefix(trimmed, "- [x] ") || strings.HasPrefix(trimmed, "- [X] ") {
+				val := trimmed[6:]
+				if val != "No preference" && !(singleChoice && len(checked) > 0) {
+					checked = append(checked, val)
 				}
 			}
 
@@ -1,8 +1,10 @@
 package logging
 
 import (
+	"errors"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFormatInterview_WithOptions(t *testing.T) {
@@ -151,6 +153,55 @@ What auth method?
 	}
 }
 
+func TestParseInterviewAnswers_MultiLineAnswer(t *testing.T) {
+	content := `### Q1: Deployment Target
+
+Where will this be deployed?
+
+> Answer: AWS ECS with Fargate,
+> behind an ALB.
+>
+> Staging mirrors production.
+Trailing prose is not part of the answer.
+
+### Q2: Database
+
+> Answer: Postgres
+`
+	answers := ParseInterviewAnswers(content)
+
+	want := "AWS ECS with Fargate,\nbehind an ALB.\n\nStaging mirrors production."
+	if got := answers["Deployment Target"]; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got := answers["Database"]; got != "Postgres" {
+		t.Errorf("expected 'Postgres', got %q", got)
+	}
+}
+
+func TestParseInterviewAnswers_MultiLineNotesWithCheckboxes(t *testing.T) {
+	content := `### Q1: Features
+
+Which features?
+
+- [x] Auth
+- [ ] Billing
+- [x] Logging
+
+> Notes:
+> Auth via OIDC only.
+> Logs go to stdout as JSON.
+
+---
+`
+	answers := ParseInterviewAnswers(content)
+
+	want := "Auth, Logging\nAuth via OIDC only.\nLogs go to stdout as JSON."
+	if got := answers["Features"]; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
 func TestParseInterviewAnswers_NoOptionQuestion(t *testing.T) {
 	content := `### Q1: Deployment Target
 
@@ -235,3 +286,187 @@ Where to deploy?
 		t.Errorf("expected 'Kubernetes' for Deploy, got %q", answers["Deploy"])
 	}
 }
+
+func TestFormatInterview_Kinds(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       InterviewQuestion
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "text",
+			q:       InterviewQuestion{Title: "Deploy", Question: "Where?", Kind: QuestionText},
+			want:    []string{"> Answer:"},
+			notWant: []string{"No preference", singleChoiceGuidance, multiChoiceGuidance},
+		},
+		{
+			name:    "single",
+			q:       InterviewQuestion{Title: "DB", Question: "Which DB?", Options: []string{"Postgres", "MySQL"}, Kind: QuestionSingle},
+			want:    []string{singleChoiceGuidance, "- [ ] Postgres", "- [ ] No preference", "> Notes:"},
+			notWant: []string{multiChoiceGuidance, "> Answer:"},
+		},
+		{
+			name:    "multi",
+			q:       InterviewQuestion{Title: "Features", Question: "Which?", Options: []string{"Auth", "Logging"}, Kind: QuestionMulti},
+			want:    []string{multiChoiceGuidance, "- [ ] Auth", "- [ ] Logging"},
+			notWant: []string{singleChoiceGuidance},
+		},
+		{
+			name:    "text kind keeps options as suggestions",
+			q:       InterviewQuestion{Title: "Name", Question: "Name?", Options: []string{"a", "b"}, Kind: QuestionText},
+			want:    []string{"*Suggestions: a, b*", "> Answer:"},
+			notWant: []string{"- [ ] a", "No preference"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatInterview([]InterviewQuestion{tt.q})
+			for _, w := range tt.want {
+				if !strings.Contains(result, w) {
+					t.Errorf("expected %q in output:\n%s", w, result)
+				}
+			}
+			for _, nw := range tt.notWant {
+				if strings.Contains(result, nw) {
+					t.Errorf("did not expect %q in output:\n%s", nw, result)
+				}
+			}
+		})
+	}
+}
+
+func TestParseInterviewAnswers_Kinds(t *testing.T) {
+	questions := []InterviewQuestion{
+		{Title: "Deploy", Question: "Where?", Options: []string{"Fly", "ECS"}, Kind: QuestionText},
+		{Title: "DB", Question: "Which DB?", Options: []string{"Postgres", "MySQL"}, Kind: QuestionSingle},
+		{Title: "Features", Question: "Which?", Options: []string{"Auth", "Logging"}, Kind: QuestionMulti},
+	}
+	content := FormatInterview(questions)
+	content = strings.Replace(content, "> Answer:", "> Answer: Kubernetes", 1)
+	content = strings.Replace(content, "- [ ] Postgres", "- [x] Postgres", 1)
+	content = strings.Replace(content, "- [ ] MySQL", "- [x] MySQL", 1)
+	content = strings.Replace(content, "- [ ] Auth", "- [x] Auth", 1)
+	content = strings.Replace(content, "- [ ] Logging", "- [x] Logging", 1)
+
+	answers := ParseInterviewAnswers(content)
+
+	if got := answers["Deploy"]; got != "Kubernetes" {
+		t.Errorf("text: expected 'Kubernetes', got %q", got)
+	}
+	if got := answers["DB"]; got != "Postgres" {
+		t.Errorf("single: expected only first checked option 'Postgres', got %q", got)
+	}
+	if got := answers["Features"]; got != "Auth, Logging" {
+		t.Errorf("multi: expected 'Auth, Logging', got %q", got)
+	}
+}
+
+func TestParseQuestionKind(t *testing.T) {
+	tests := map[string]string{
+		"text":          QuestionText,
+		"free-text":     QuestionText,
+		"single":        QuestionSingle,
+		"Single-Choice": QuestionSingle,
+		"multi":         QuestionMulti,
+		"multi-choice":  QuestionMulti,
+		"bogus":         "",
+	}
+	for input, want := range tests {
+		if got := ParseQuestionKind(input); got != want {
+			t.Errorf("ParseQuestionKind(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestHasInterviewCompletionMarker(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"unchecked", "### Q1: A\n\n" + InterviewCompletionLine + "\n", true},
+		{"checked", "- [x] All questions answered (check when complete)\n", true},
+		{"legacy status", "Status: PENDING\n", true},
+		{"missing", "# Project Interview\n\n### Q1: A\n\n> Answer: yes\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasInterviewCompletionMarker(tt.content); got != tt.want {
+				t.Errorf("HasInterviewCompletionMarker() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppendInterviewCompletionLine(t *testing.T) {
+	content := AppendInterviewCompletionLine("### Q1: A\n\n> Answer: yes\n\n")
+	if !HasInterviewCompletionMarker(content) {
+		t.Errorf("expected completion marker after append:\n%s", content)
+	}
+	if ParseInterviewStatus(content) {
+		t.Error("appended completion box should be unchecked")
+	}
+	if !strings.HasSuffix(content, InterviewCompletionLine+"\n") {
+		t.Errorf("expected completion line at end, got:\n%s", content)
+	}
+}
+
+func TestParseTimestamps_RoundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 14, 9, 26, 53, 0, time.UTC)
+	log := FormatInvocation(&Invocation{Timestamp: at, Sprint: 1, Phase: "implement", Agent: "dummy"})
+	if got, ok := ParseInvocationTimestamp(log); !ok || !got.Equal(at) {
+		t.Errorf("ParseInvocationTimestamp = %v, %v; want %v", got, ok, at)
+	}
+
+	retro := FormatRetro(1, "Went well.", nil)
+	if got, ok := ParseRetroGenerated(retro); !ok || time.Since(got) > time.Minute {
+		t.Errorf("ParseRetroGenerated = %v, %v; want about now", got, ok)
+	}
+
+	if _, ok := ParseInvocationTimestamp("no metadata here"); ok {
+		t.Error("expected no timestamp in content without one")
+	}
+}
+
+func TestParseInvocationStatus_RoundTrip(t *testing.T) {
+	for _, status := range []string{"success", "error", ""} {
+		log := FormatInvocation(&Invocation{Timestamp: time.Now(), Phase: "implement", Status: status})
+		if got, ok := ParseInvocationStatus(log); !ok || got != status {
+			t.Errorf("ParseInvocationStatus = %q, %v; want %q", got, ok, status)
+		}
+	}
+	if _, ok := ParseInvocationStatus("no metadata here"); ok {
+		t.Error("expected no status in content without one")
+	}
+}
+
+func TestParseInvocationResponse_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		inv  Invocation
+	}{
+		{"plain", Invocation{Response: "APPROVED"}},
+		{"fenced response", Invocation{Response: "Fix this:\n\n```go\nx := 1\n```\n\nThen rerun."}},
+		{"heading in response", Invocation{Response: "## Summary\n\nLooks fine.\n"}},
+		{"with files and notes", Invocation{Response: "done", FilesWritten: []string{"main.go"}, Notes: "Agent stderr:\n\n```\nwarn\n```"}},
+		{"with error", Invocation{Response: "partial\n```\nlog\n```", Error: errors.New("exit status 1")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.inv.Prompt = "## Response\n\nnot this one"
+			got, ok := ParseInvocationResponse(FormatInvocation(&tt.inv))
+			if !ok {
+				t.Fatal("expected a response")
+			}
+			if want := strings.TrimSuffix(tt.inv.Response, "\n"); got != want {
+				t.Errorf("expected %q, got %q", want, got)
+			}
+		})
+	}
+
+	if _, ok := ParseInvocationResponse("# Agent Invocation Log\n"); ok {
+		t.Error("expected no response in a log without one")
+	}
+}
@@ -4,27 +4,78 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
-	"sync/atomic"
+	"sync"
 	"time"
+	"unicode/utf8"
+
+	"github.com/strongdm/agate/internal/project"
 )
 
 // Logger manages log files for agent invocations
 type Logger struct {
 	baseDir      string
 	sprintNumber int
-	sequence     int64
+	sequence     int64 // Last sequence number this logger allocated
+	keepRaw      bool  // Also write each response verbatim to <log>.raw
 }
 
-// NewLogger creates a new logger for the given project directory and sprint
-func NewLogger(projectDir string, sprintNumber int) *Logger {
+// NewLogger creates a new logger for the given project and sprint
+func NewLogger(proj *project.Project, sprintNumber int) *Logger {
 	return &Logger{
-		baseDir:      filepath.Join(projectDir, ".ai", "logs", fmt.Sprintf("sprint-%03d", sprintNumber)),
+		baseDir:      GetLogsDir(proj, sprintNumber),
 		sprintNumber: sprintNumber,
-		sequence:     0,
 	}
 }
 
+// SetKeepRaw sets whether invocations started by this logger also save the
+// verbatim response next to their log (see LogFile.SetKeepRaw)
+func (l *Logger) SetKeepRaw(keep bool) {
+	l.keepRaw = keep
+}
+
+// sequenceMu serializes sequence allocation across Logger instances
+var sequenceMu sync.Mutex
+
+// nextSequence returns one more than the highest sequence number among the
+// log files in dir (1 if there are none)
+func nextSequence(dir string) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 1, nil
+		}
+		return 0, err
+	}
+
+	var max int64
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if n := logSequence(e.Name()); n > max {
+			max = n
+		}
+	}
+	return max + 1, nil
+}
+
+// logSequence returns the sequence number a log file name starts with, or 0
+// if it has none
+func logSequence(name string) int64 {
+	prefix, _, ok := strings.Cut(name, "-")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
 // Invocation represents a single agent invocation to be logged
 type Invocation struct {
 	Timestamp    time.Time
@@ -49,6 +100,7 @@ type LogFile struct {
 	file       *os.File
 	invocation *Invocation
 	startTime  time.Time
+	keepRaw    bool
 }
 
 // StartInvocation begins logging a new agent invocation
@@ -58,15 +110,27 @@ func (l *Logger) StartInvocation(phase, task string, taskIndex int, agent, skill
 		return nil, fmt.Errorf("failed to create log directory: %w", err)
 	}
 
-	// Generate sequence number
-	seq := atomic.AddInt64(&l.sequence, 1)
+	// Reserve the next sequence number and create the log file.
+	// The lock spans all Logger instances so loggers for the same sprint
+	// never pick the same number.
+	sequenceMu.Lock()
+	seq, err := nextSequence(l.baseDir)
+	if err != nil {
+		sequenceMu.Unlock()
+		return nil, fmt.Errorf("failed to scan log directory: %w", err)
+	}
+	if seq <= l.sequence {
+		seq = l.sequence + 1
+	}
+	l.sequence = seq
 
 	// Generate filename: {seq}-{phase}-{task}-{skill}-{agent}.md
 	filename := fmt.Sprintf("%03d-%s-%02d-%s-%s.md", seq, phase, taskIndex, skill, agent)
 	path := filepath.Join(l.baseDir, filename)
 
-	// Create log file
-	file, err := os.Create(path)
+	// Create log file (never overwrite an existing log)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	sequenceMu.Unlock()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create log file: %w", err)
 	}
@@ -86,6 +150,7 @@ func (l *Logger) StartInvocation(phase, task string, taskIndex int, agent, skill
 		file:       file,
 		invocation: inv,
 		startTime:  time.Now(),
+		keepRaw:    l.keepRaw,
 	}
 
 	// Print console summary
@@ -127,10 +192,29 @@ func (lf *LogFile) SetNotes(notes string) {
 	lf.invocation.Notes = notes
 }
 
+// SetKeepRaw sets whether Close also writes the response, byte for byte, to
+// RawPath. The markdown log fences the response, which mangles responses
+// that contain fences themselves.
+func (lf *LogFile) SetKeepRaw(keep bool) {
+	lf.keepRaw = keep
+}
+
+// RawPath returns where the verbatim response is written when kept
+func (lf *LogFile) RawPath() string {
+	return lf.Path + ".raw"
+}
+
 // Close finalizes and writes the log file
 func (lf *LogFile) Close() error {
 	lf.invocation.Duration = time.Since(lf.startTime)
 
+	if lf.keepRaw {
+		if err := os.WriteFile(lf.RawPath(), []byte(lf.invocation.Response), 0644); err != nil {
+			lf.file.Close()
+			return fmt.Errorf("failed to write raw response: %w", err)
+		}
+	}
+
 	// Write the formatted log
 	content := FormatInvocation(lf.invocation)
 	if _, err := lf.file.WriteString(content); err != nil {
@@ -152,23 +236,69 @@ func (lf *LogFile) GetRelativePath(projectDir string) string {
 
 // PrintInvocationStart prints the console summary line with timestamp
 func PrintInvocationStart(agent, skill, summary, logPath string) {
-	// Truncate summary if too long
-	if len(summary) > 50 {
-		summary = summary[:47] + "..."
+	fmt.Println(invocationStartLine(time.Now(), agent, skill, summary, logPath))
+}
+
+// invocationStartLine formats the console summary line, truncating the
+// summary to 50 characters
+func invocationStartLine(now time.Time, agent, skill, summary, logPath string) string {
+	ts := strings.TrimSuffix(strings.ToLower(now.Format("3:04PM")), "m")
+	return fmt.Sprintf("%s %s %s: %q %s",
+		Dim("["+ts+"]"), Cyan("["+agent+"]"), skill, TruncateRunes(summary, 50), Dim("→ "+logPath))
+}
+
+// TruncateRunes truncates text to maxLen characters (runes, not bytes) with
+// "...", so multibyte task names are never cut mid-character
+func TruncateRunes(text string, maxLen int) string {
+	if utf8.RuneCountInString(text) <= maxLen {
+		return text
+	}
+	if maxLen <= 3 {
+		return "..."
 	}
-	ts := strings.TrimSuffix(strings.ToLower(time.Now().Format("3:04PM")), "m")
-	fmt.Printf("%s %s %s: %q %s\n",
-		Dim("["+ts+"]"), Cyan("["+agent+"]"), skill, summary, Dim("→ "+logPath))
+	return string([]rune(text)[:maxLen-3]) + "..."
 }
 
 // GetLogsDir returns the logs directory for a sprint
-func GetLogsDir(projectDir string, sprintNumber int) string {
-	return filepath.Join(projectDir, ".ai", "logs", fmt.Sprintf("sprint-%03d", sprintNumber))
+func GetLogsDir(proj *project.Project, sprintNumber int) string {
+	return filepath.Join(proj.LogsDir(), fmt.Sprintf("sprint-%03d", sprintNumber))
+}
+
+// ListLogs returns all log files for a sprint, oldest first
+func ListLogs(proj *project.Project, sprintNumber int) ([]string, error) {
+	dir := GetLogsDir(proj, sprintNumber)
+	names, err := logNames(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []string
+	for _, name := range names {
+		logs = append(logs, filepath.Join(dir, name))
+	}
+	return logs, nil
 }
 
-// ListLogs returns all log files for a sprint
-func ListLogs(projectDir string, sprintNumber int) ([]string, error) {
-	dir := GetLogsDir(projectDir, sprintNumber)
+// WalkLogs calls fn with the path of each log file for a sprint, newest
+// first, until fn returns false. Only file names are listed up front, so
+// callers can read as many logs as they need one at a time.
+func WalkLogs(proj *project.Project, sprintNumber int, fn func(path string) bool) error {
+	dir := GetLogsDir(proj, sprintNumber)
+	names, err := logNames(dir)
+	if err != nil {
+		return err
+	}
+	for i := len(names) - 1; i >= 0; i-- {
+		if !fn(filepath.Join(dir, names[i])) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// logNames returns the names of the log files in dir ordered by sequence
+// number, so logs past 999 sort after 999 rather than among the 100s
+func logNames(dir string) ([]string, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -177,22 +307,25 @@ func ListLogs(projectDir string, sprintNumber int) ([]string, error) {
 		return nil, err
 	}
 
-	var logs []string
+	var names []string
 	for _, e := range entries {
 		if !e.IsDir() && filepath.Ext(e.Name()) == ".md" {
-			logs = append(logs, filepath.Join(dir, e.Name()))
+			names = append(names, e.Name())
 		}
 	}
-	return logs, nil
+	sort.SliceStable(names, func(i, j int) bool {
+		return logSequence(names[i]) < logSequence(names[j])
+	})
+	return names, nil
 }
 
 // EnsureRetrosDir ensures the retros directory exists
-func EnsureRetrosDir(projectDir string) error {
-	dir := filepath.Join(projectDir, ".ai", "retros")
+func EnsureRetrosDir(proj *project.Project) error {
+	dir := proj.RetrosDir()
 	return os.MkdirAll(dir, 0755)
 }
 
 // GetRetroPath returns the path to a sprint's retrospective file
-func GetRetroPath(projectDir string, sprintNumber int) string {
-	return filepath.Join(projectDir, ".ai", "retros", fmt.Sprintf("sprint-%03d.md", sprintNumber))
+func GetRetroPath(proj *project.Project, sprintNumber int) string {
+	return filepath.Join(proj.RetrosDir(), fmt.Sprintf("sprint-%03d.md", sprintNumber))
 }
@@ -0,0 +1,227 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestLogger_SequenceAcrossInstances(t *testing.T) {
+	dir := t.TempDir()
+
+	first := NewLogger(project.New(dir), 1)
+	second := NewLogger(project.New(dir), 1)
+
+	paths := make(map[string]bool)
+	for i, l := range []*Logger{first, second, first, second} {
+		lf, err := l.StartInvocation("implement", "task", 1, "dummy", "_implementer", "summary")
+		if err != nil {
+			t.Fatalf("invocation %d: %v", i, err)
+		}
+		if paths[lf.Path] {
+			t.Fatalf("invocation %d reused log path %s", i, lf.Path)
+		}
+		paths[lf.Path] = true
+		if err := lf.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, want := range []string{"001", "002", "003", "004"} {
+		name := want + "-implement-01-_implementer-dummy.md"
+		if !paths[filepath.Join(GetLogsDir(project.New(dir), 1), name)] {
+			t.Errorf("expected log %d named %s, got %v", i+1, name, paths)
+		}
+	}
+}
+
+func TestLogger_SequenceConcurrent(t *testing.T) {
+	dir := t.TempDir()
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lf, err := NewLogger(project.New(dir), 2).StartInvocation("review", "task", 0, "dummy", "_reviewer", "summary")
+			if err != nil {
+				errs <- err
+				return
+			}
+			errs <- lf.Close()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	logs, err := ListLogs(project.New(dir), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != n {
+		t.Errorf("expected %d distinct log files, got %d", n, len(logs))
+	}
+}
+
+func TestNextSequence_ResumesFromExisting(t *testing.T) {
+	dir := t.TempDir()
+	l := NewLogger(project.New(dir), 3)
+
+	lf, err := l.StartInvocation("implement", "task", 0, "dummy", "_implementer", "summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf.Close()
+
+	seq, err := nextSequence(GetLogsDir(project.New(dir), 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq != 2 {
+		t.Errorf("expected next sequence 2, got %d", seq)
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	tests := []struct {
+		text   string
+		maxLen int
+		want   string
+	}{
+		{"short", 10, "short"},
+		{"exactly ten", 11, "exactly ten"},
+		{"truncate this text", 10, "truncat..."},
+		{"实现用户登录和注册功能", 8, "实现用户登..."},
+		{"🚀🚀🚀🚀🚀🚀", 5, "🚀🚀..."},
+		{"anything", 3, "..."},
+	}
+	for _, tt := range tests {
+		got := TruncateRunes(tt.text, tt.maxLen)
+		if got != tt.want {
+			t.Errorf("TruncateRunes(%q, %d) = %q, want %q", tt.text, tt.maxLen, got, tt.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("TruncateRunes(%q, %d) produced invalid UTF-8", tt.text, tt.maxLen)
+		}
+	}
+}
+
+func TestInvocationStartLine_MultibyteSummary(t *testing.T) {
+	summary := strings.Repeat("实现用户登录功能🚀", 10)
+	line := invocationStartLine(time.Now(), "claude", "go-coder", summary, ".ai/logs/sprint-001/001.md")
+
+	if !utf8.ValidString(line) {
+		t.Fatalf("line contains a broken rune: %q", line)
+	}
+	if strings.Contains(line, `\x`) {
+		t.Errorf("summary was quoted with escaped bytes: %s", line)
+	}
+	if !strings.Contains(line, `..."`) {
+		t.Errorf("expected truncated summary, got: %s", line)
+	}
+	if !strings.Contains(line, "→ .ai/logs/sprint-001/001.md") {
+		t.Errorf("expected log path suffix, got: %s", line)
+	}
+}
+
+func TestLogFile_KeepRawRoundTrips(t *testing.T) {
+	dir := t.TempDir()
+	response := "Here is the file:\n\n```go\npackage main\n```\n\n````\nnested ``` fence\n````\n  trailing spaces  \n"
+
+	logger := NewLogger(project.New(dir), 1)
+	logger.SetKeepRaw(true)
+	lf, err := logger.StartInvocation("implement", "task", 1, "dummy", "go-coder", "summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf.SetResponse(response)
+	if err := lf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(lf.Path + ".raw")
+	if err != nil {
+		t.Fatalf("expected raw response file: %v", err)
+	}
+	if string(raw) != response {
+		t.Errorf("raw response did not round-trip:\ngot  %q\nwant %q", raw, response)
+	}
+
+	// Raw files don't count as logs
+	logs, err := ListLogs(project.New(dir), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 {
+		t.Errorf("expected 1 log, got %v", logs)
+	}
+}
+
+func TestLogFile_NoRawByDefault(t *testing.T) {
+	dir := t.TempDir()
+	lf, err := NewLogger(project.New(dir), 1).StartInvocation("implement", "task", 1, "dummy", "go-coder", "summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf.SetResponse("done")
+	if err := lf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(lf.RawPath()); !os.IsNotExist(err) {
+		t.Errorf("expected no raw file, got err %v", err)
+	}
+}
+
+func TestWalkLogs_NewestFirstBySequence(t *testing.T) {
+	dir := t.TempDir()
+	logsDir := GetLogsDir(project.New(dir), 1)
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"999-implement-00-a-claude.md", "1000-implement-00-a-claude.md", "100-implement-00-a-claude.md", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(logsDir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logs, err := ListLogs(project.New(dir), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, l := range logs {
+		names = append(names, filepath.Base(l))
+	}
+	if got := strings.Join(names, ","); got != "100-implement-00-a-claude.md,999-implement-00-a-claude.md,1000-implement-00-a-claude.md" {
+		t.Errorf("expected logs in sequence order, got %s", got)
+	}
+
+	var walked []string
+	if err := WalkLogs(project.New(dir), 1, func(path string) bool {
+		walked = append(walked, filepath.Base(path))
+		return len(walked) < 2
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(walked, ","); got != "1000-implement-00-a-claude.md,999-implement-00-a-claude.md" {
+		t.Errorf("expected the two newest logs, newest first, got %s", got)
+	}
+
+	if err := WalkLogs(project.New(dir), 2, func(string) bool { t.Error("unexpected log"); return true }); err != nil {
+		t.Errorf("expected no error for a sprint without logs, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// PruneOptions selects which sprint log directories PruneLogs removes. A
+// directory is removed when either limit applies to it; zero disables a
+// limit.
+type PruneOptions struct {
+	// CurrentSprint's logs are never removed
+	CurrentSprint int
+	// KeepSprints keeps the logs of this many sprints, counting back from
+	// CurrentSprint (which is included)
+	KeepSprints int
+	// MaxAge removes logs not written to for longer than this
+	MaxAge time.Duration
+	// Now is the time MaxAge is measured from (default: time.Now())
+	Now time.Time
+}
+
+// sprintLogDirRe matches the per-sprint log directories GetLogsDir creates
+var sprintLogDirRe = regexp.MustCompile(`^sprint-(\d+)$`)
+
+// PruneLogs removes old sprint log directories and returns the removed
+// paths, oldest first. Only sprint-NNN directories directly under the logs
+// directory are considered; other files, and symlinks, are left alone.
+func PruneLogs(proj *project.Project, opts PruneOptions) ([]string, error) {
+	if opts.KeepSprints <= 0 && opts.MaxAge <= 0 {
+		return nil, fmt.Errorf("nothing to prune by: set a number of sprints to keep or a maximum age")
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	logsDir := proj.LogsDir()
+	entries, err := os.ReadDir(logsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var removed []string
+	for _, e := range entries {
+		m := sprintLogDirRe.FindStringSubmatch(e.Name())
+		if m == nil || !e.IsDir() {
+			continue
+		}
+		num, _ := strconv.Atoi(m[1])
+		if num == opts.CurrentSprint {
+			continue
+		}
+
+		expired := opts.KeepSprints > 0 && num <= opts.CurrentSprint-opts.KeepSprints
+		if !expired && opts.MaxAge > 0 {
+			info, err := e.Info()
+			if err != nil {
+				return removed, err
+			}
+			expired = opts.Now.Sub(info.ModTime()) > opts.MaxAge
+		}
+		if !expired {
+			continue
+		}
+
+		path := filepath.Join(logsDir, e.Name())
+		if err := os.RemoveAll(path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		removed = append(removed, path)
+	}
+	sort.Strings(removed)
+	return removed, nil
+}
@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// writeSprintLogs creates a log in each sprint's log dir and backdates the
+// dir by its age
+func writeSprintLogs(t *testing.T, projectDir string, ages map[int]time.Duration, now time.Time) {
+	t.Helper()
+	for num, age := range ages {
+		dir := GetLogsDir(project.New(projectDir), num)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "001-implement-01-go-coder-dummy.md"), []byte("# log\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(dir, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPruneLogs_KeepSprints(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeSprintLogs(t, dir, map[int]time.Duration{0: 0, 1: 0, 2: 0, 3: 0, 4: 0}, now)
+
+	removed, err := PruneLogs(project.New(dir), PruneOptions{CurrentSprint: 4, KeepSprints: 2, Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 3 {
+		t.Errorf("expected sprints 0-2 removed, got %v", removed)
+	}
+	for num, want := range map[int]bool{0: false, 1: false, 2: false, 3: true, 4: true} {
+		if _, err := os.Stat(GetLogsDir(project.New(dir), num)); (err == nil) != want {
+			t.Errorf("sprint %d logs: exist = %v, want %v", num, err == nil, want)
+		}
+	}
+}
+
+func TestPruneLogs_MaxAgeKeepsCurrent(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	day := 24 * time.Hour
+	writeSprintLogs(t, dir, map[int]time.Duration{1: 60 * day, 2: 40 * day, 3: 2 * day}, now)
+
+	// Sprint 2 is current: its logs survive even though they're old
+	removed, err := PruneLogs(project.New(dir), PruneOptions{CurrentSprint: 2, MaxAge: 30 * day, Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0] != GetLogsDir(project.New(dir), 1) {
+		t.Errorf("expected only sprint 1 removed, got %v", removed)
+	}
+	for _, num := range []int{2, 3} {
+		if _, err := os.Stat(GetLogsDir(project.New(dir), num)); err != nil {
+			t.Errorf("expected sprint %d logs to survive: %v", num, err)
+		}
+	}
+}
+
+func TestPruneLogs_OnlySprintDirs(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeSprintLogs(t, dir, map[int]time.Duration{1: 0, 5: 0}, now)
+
+	logsDir := filepath.Dir(GetLogsDir(project.New(dir), 1))
+	for _, name := range []string{"notes", "sprint-old"} {
+		if err := os.Mkdir(filepath.Join(logsDir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(logsDir, "sprint-002"), []byte("file"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := PruneLogs(project.New(dir), PruneOptions{CurrentSprint: 5, KeepSprints: 1, Now: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0] != GetLogsDir(project.New(dir), 1) {
+		t.Errorf("expected only sprint 1 removed, got %v", removed)
+	}
+	for _, name := range []string{"notes", "sprint-old", "sprint-002"} {
+		if _, err := os.Stat(filepath.Join(logsDir, name)); err != nil {
+			t.Errorf("expected %s to be left alone: %v", name, err)
+		}
+	}
+}
+
+func TestPruneLogs_RequiresLimit(t *testing.T) {
+	if _, err := PruneLogs(project.New(t.TempDir()), PruneOptions{CurrentSprint: 1}); err == nil {
+		t.Error("expected error without a limit")
+	}
+}
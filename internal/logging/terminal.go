@@ -123,6 +123,18 @@ func (sv *SplitView) redrawStatusBar() {
 	fmt.Fprint(sv.output, "\033[u")
 }
 
+// Clear erases the screen and moves the cursor to the top left, so the next
+// write redraws from the top. It does nothing when the output isn't a TTY.
+func (sv *SplitView) Clear() {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+
+	if !sv.isTTY {
+		return
+	}
+	fmt.Fprint(sv.output, "\033[H\033[2J")
+}
+
 // Setup initializes the terminal for split view mode
 func (sv *SplitView) Setup() {
 	if !sv.isTTY {
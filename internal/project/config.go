@@ -0,0 +1,379 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds optional project settings from .ai/config.yaml
+// (under the state dir, if relocated)
+type Config struct {
+	// InterviewAgent selects the agent used to generate interview questions
+	InterviewAgent string
+
+	// TaskTimeout is the time budget for a single sub-task agent execution
+	TaskTimeout time.Duration
+
+	// EscalationOrder lists agents to fall back through when a sub-task
+	// times out; the retry uses the next available agent after the one
+	// that timed out (wrapping around)
+	EscalationOrder []string
+
+	// SprintMinTasks and SprintMaxTasks bound the number of top-level tasks
+	// the planner is asked to put in each sprint
+	SprintMinTasks int
+	SprintMaxTasks int
+
+	// AssessContextSprints is how many of the most recent completed sprints
+	// the assess prompt includes in full; older ones are cut to their goal
+	AssessContextSprints int
+
+	// StallLimit is how many next invocations in a row may pass without the
+	// current sprint's completed sub-task count changing before a human is
+	// asked
+	StallLimit int
+
+	// MaxConcurrentAgents bounds how many real agent executions run at
+	// once, so parallel runs don't hit provider rate limits together
+	MaxConcurrentAgents int
+
+	// KeepRawResponses saves each sub-task's agent response verbatim next
+	// to its log, as <log>.raw, for reprocessing
+	KeepRawResponses bool
+
+	// LogKeepSprints and LogMaxAge prune old sprint logs each time a new
+	// sprint starts (0 disables each limit; see logging.PruneLogs)
+	LogKeepSprints int
+	LogMaxAge      time.Duration
+
+	// Root is a sub-directory of the project (e.g. services/api in a
+	// monorepo) that file paths in agent output are relative to
+	Root string
+
+	// Hooks are shell commands run at points in the workflow
+	Hooks Hooks
+}
+
+// Hooks holds the commands configured under the hooks: section
+type Hooks struct {
+	// PostImplement commands run in the project dir after an implementation
+	// sub-task writes its files; a non-zero exit fails the sub-task
+	PostImplement []string
+
+	// Test commands run for _test-gate sub-tasks, which pass only if every
+	// command exits zero
+	Test []string
+}
+
+// Defaults for optional config values
+const (
+	DefaultTaskTimeout    = 10 * time.Minute
+	DefaultSprintMinTasks = 2
+	DefaultSprintMaxTasks = 4
+
+	DefaultAssessContextSprints = 3
+	DefaultStallLimit           = 6
+	DefaultMaxConcurrentAgents  = 2
+)
+
+// DefaultEscalationOrder falls back from claude to the faster haiku, and from
+// codex back to claude
+var DefaultEscalationOrder = []string{"claude", "haiku", "codex"}
+
+// DefaultConfig returns the configuration used when no config file exists
+func DefaultConfig() *Config {
+	return &Config{
+		TaskTimeout:     DefaultTaskTimeout,
+		EscalationOrder: append([]string{}, DefaultEscalationOrder...),
+		SprintMinTasks:  DefaultSprintMinTasks,
+		SprintMaxTasks:  DefaultSprintMaxTasks,
+
+		AssessContextSprints: DefaultAssessContextSprints,
+		StallLimit:           DefaultStallLimit,
+		MaxConcurrentAgents:  DefaultMaxConcurrentAgents,
+	}
+}
+
+// NextEscalationAgent returns the agent after current in the escalation
+// order for which available returns true, or "" if there is none
+func (c *Config) NextEscalationAgent(current string, available func(name string) bool) string {
+	order := c.EscalationOrder
+	start := -1
+	for i, name := range order {
+		if name == current {
+			start = i
+			break
+		}
+	}
+	for i := 1; i <= len(order); i++ {
+		name := order[(start+i+len(order))%len(order)]
+		if name != current && available(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// ConfigPath returns the path to the project config file
+func (p *Project) ConfigPath() string {
+	return filepath.Join(p.DataDir(), "config.yaml")
+}
+
+// LoadConfig reads the project config, returning defaults if the file doesn't exist
+func (p *Project) LoadConfig() (*Config, error) {
+	return LoadConfig(p.ConfigPath())
+}
+
+// LoadConfig reads a config file, returning defaults if it doesn't exist
+func LoadConfig(path string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+
+	if err := ParseConfig(string(content), cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ParseConfig parses simple YAML into cfg (avoiding an external YAML
+// dependency, like skill frontmatter). Supported are "key: value" lines,
+// one level of nested sections, and block lists of "- item" lines:
+//
+//	task_timeout: 10m
+//	hooks:
+//	  post_implement:
+//	    - go test ./...
+func ParseConfig(content string, cfg *Config) error {
+	section := "" // Top-level key whose value is a nested block
+	listKey := "" // Key whose value is a block list of "- item" lines
+	minSet, maxSet := false, false
+
+	for i, line := range strings.Split(content, "\n") {
+		line = stripComment(line)
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		indented := line[0] == ' ' || line[0] == '\t'
+
+		if trimmed == "-" || strings.HasPrefix(trimmed, "- ") {
+			if listKey == "" {
+				return fmt.Errorf("line %d: list item without a key", i+1)
+			}
+			cfg.appendListItem(listKey, unquote(strings.TrimSpace(trimmed[1:])))
+			continue
+		}
+
+		parts := strings.SplitN(trimmed, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("line %d: expected 'key: value'", i+1)
+		}
+
+		key := strings.TrimSpace(parts[0])
+		value := unquote(strings.TrimSpace(parts[1]))
+
+		if !indented {
+			section = ""
+		}
+		if section != "" {
+			key = section + "." + key
+		}
+		listKey = ""
+
+		// An empty value opens a nested section or a block list
+		if value == "" {
+			if !indented {
+				section = key
+			}
+			listKey = key
+			cfg.resetList(key)
+			continue
+		}
+
+		switch key {
+		case "interview_agent":
+			cfg.InterviewAgent = value
+		case "task_timeout":
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("line %d: task_timeout must be a positive duration like 10m", i+1)
+			}
+			cfg.TaskTimeout = d
+		case "escalation_order":
+			cfg.EscalationOrder = parseList(value)
+		case "sprint_min_tasks", "sprint_max_tasks":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("line %d: %s must be a positive integer", i+1, key)
+			}
+			if key == "sprint_min_tasks" {
+				cfg.SprintMinTasks, minSet = n, true
+			} else {
+				cfg.SprintMaxTasks, maxSet = n, true
+			}
+		case "assess_context_sprints":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("line %d: assess_context_sprints must be a positive integer", i+1)
+			}
+			cfg.AssessContextSprints = n
+		case "stall_limit":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("line %d: stall_limit must be a positive integer", i+1)
+			}
+			cfg.StallLimit = n
+		case "max_concurrent_agents":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("line %d: max_concurrent_agents must be a positive integer", i+1)
+			}
+			cfg.MaxConcurrentAgents = n
+		case "keep_raw_responses":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("line %d: keep_raw_responses must be true or false", i+1)
+			}
+			cfg.KeepRawResponses = b
+		case "log_keep_sprints":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("line %d: log_keep_sprints must be a non-negative integer", i+1)
+			}
+			cfg.LogKeepSprints = n
+		case "log_max_age":
+			d, err := ParseAge(value)
+			if err != nil {
+				return fmt.Errorf("line %d: log_max_age %v", i+1, err)
+			}
+			cfg.LogMaxAge = d
+		case "root":
+			root := filepath.Clean(filepath.FromSlash(value))
+			if filepath.IsAbs(root) || root == ".." || strings.HasPrefix(root, ".."+string(filepath.Separator)) {
+				return fmt.Errorf("line %d: root must be a relative path inside the project", i+1)
+			}
+			cfg.Root = root
+		case "hooks.post_implement":
+			cfg.Hooks.PostImplement = parseCommandList(value)
+		case "hooks.test":
+			cfg.Hooks.Test = parseCommandList(value)
+		}
+	}
+
+	// Only two explicit bounds can conflict; a single one moves the other
+	if cfg.SprintMinTasks > cfg.SprintMaxTasks {
+		switch {
+		case minSet && maxSet:
+			return fmt.Errorf("sprint_min_tasks (%d) must not exceed sprint_max_tasks (%d)", cfg.SprintMinTasks, cfg.SprintMaxTasks)
+		case minSet:
+			cfg.SprintMaxTasks = cfg.SprintMinTasks
+		case maxSet:
+			cfg.SprintMinTasks = cfg.SprintMaxTasks
+		}
+	}
+	return nil
+}
+
+// ParseAge parses a positive age like 30d, 12h, or 90m. Days are accepted
+// in addition to the units time.ParseDuration knows.
+func ParseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("must be a positive age like 30d or 12h, got %q", value)
+}
+
+// resetList clears a list setting before its block list items are read
+func (c *Config) resetList(key string) {
+	switch key {
+	case "escalation_order":
+		c.EscalationOrder = nil
+	case "hooks.post_implement":
+		c.Hooks.PostImplement = nil
+	case "hooks.test":
+		c.Hooks.Test = nil
+	}
+}
+
+// appendListItem adds a block list item to a list setting
+func (c *Config) appendListItem(key, item string) {
+	if item == "" {
+		return
+	}
+	switch key {
+	case "escalation_order":
+		c.EscalationOrder = append(c.EscalationOrder, item)
+	case "hooks.post_implement":
+		c.Hooks.PostImplement = append(c.Hooks.PostImplement, item)
+	case "hooks.test":
+		c.Hooks.Test = append(c.Hooks.Test, item)
+	}
+}
+
+// parseCommandList parses an inline command value. A bracketed list holds
+// several commands; anything else is a single command, which may itself
+// contain commas.
+func parseCommandList(value string) []string {
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		return parseList(value)
+	}
+	return []string{value}
+}
+
+// parseList parses an inline list value: "a, b" or "[a, b]"
+func parseList(value string) []string {
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = unquote(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// stripComment removes a trailing # comment from a config line. As in
+// YAML, a # starts a comment only at the line start or after whitespace,
+// and never inside quotes, so hook commands can contain one.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// unquote strips matching single or double quotes from a value
+func unquote(value string) string {
+	if len(value) >= 2 {
+		if (value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'') {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
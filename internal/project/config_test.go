@@ -0,0 +1,293 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig_Missing(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.InterviewAgent != "" {
+		t.Errorf("expected empty interview agent by default, got %q", cfg.InterviewAgent)
+	}
+}
+
+func TestLoadConfig_InterviewAgent(t *testing.T) {
+	tmpDir := t.TempDir()
+	proj := New(tmpDir)
+	os.MkdirAll(proj.DataDir(), 0755)
+	content := "# agate settings\ninterview_agent: \"claude\" # question generation\n"
+	if err := os.WriteFile(proj.ConfigPath(), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := proj.LoadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.InterviewAgent != "claude" {
+		t.Errorf("expected interview agent 'claude', got %q", cfg.InterviewAgent)
+	}
+}
+
+func TestParseConfig_Escalation(t *testing.T) {
+	cfg := DefaultConfig()
+	content := "task_timeout: 90s\nescalation_order: [claude, \"codex\"]\n"
+	if err := ParseConfig(content, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TaskTimeout != 90*time.Second {
+		t.Errorf("expected 90s timeout, got %s", cfg.TaskTimeout)
+	}
+	if len(cfg.EscalationOrder) != 2 || cfg.EscalationOrder[1] != "codex" {
+		t.Errorf("unexpected escalation order: %v", cfg.EscalationOrder)
+	}
+
+	if err := ParseConfig("task_timeout: soon\n", cfg); err == nil {
+		t.Error("expected error for invalid task_timeout")
+	}
+}
+
+func TestConfig_NextEscalationAgent(t *testing.T) {
+	cfg := DefaultConfig()
+	all := func(string) bool { return true }
+
+	tests := []struct {
+		current   string
+		available func(string) bool
+		want      string
+	}{
+		{"claude", all, "haiku"},
+		{"codex", all, "claude"},
+		{"dummy", all, "claude"},
+		{"claude", func(n string) bool { return n == "codex" }, "codex"},
+		{"claude", func(string) bool { return false }, ""},
+	}
+	for _, tt := range tests {
+		if got := cfg.NextEscalationAgent(tt.current, tt.available); got != tt.want {
+			t.Errorf("NextEscalationAgent(%q) = %q, want %q", tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestParseConfig_Hooks(t *testing.T) {
+	cfg := DefaultConfig()
+	content := `task_timeout: 5m
+hooks:
+  post_implement:
+    - go test ./...   # run the suite
+    - "go vet ./..."
+  test: go test ./...
+escalation_order:
+  - codex
+  - claude
+`
+	if err := ParseConfig(content, cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"go test ./...", "go vet ./..."}
+	if !reflect.DeepEqual(cfg.Hooks.PostImplement, want) {
+		t.Errorf("post_implement = %q, want %q", cfg.Hooks.PostImplement, want)
+	}
+	if !reflect.DeepEqual(cfg.Hooks.Test, []string{"go test ./..."}) {
+		t.Errorf("test = %q", cfg.Hooks.Test)
+	}
+	if !reflect.DeepEqual(cfg.EscalationOrder, []string{"codex", "claude"}) {
+		t.Errorf("unexpected block-list escalation order: %v", cfg.EscalationOrder)
+	}
+	if cfg.TaskTimeout != 5*time.Minute {
+		t.Errorf("expected 5m timeout, got %s", cfg.TaskTimeout)
+	}
+}
+
+func TestParseConfig_HooksInline(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := ParseConfig("hooks:\n  post_implement: make check ARGS=a,b\n", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Hooks.PostImplement, []string{"make check ARGS=a,b"}) {
+		t.Errorf("unexpected inline command: %q", cfg.Hooks.PostImplement)
+	}
+
+	// A top-level key after the section is not nested under it
+	cfg = DefaultConfig()
+	if err := ParseConfig("hooks:\n  post_implement: [make lint, make test]\ninterview_agent: haiku\n", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Hooks.PostImplement) != 2 || cfg.InterviewAgent != "haiku" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	// A # inside a word or quotes is part of the command
+	cfg = DefaultConfig()
+	content := "hooks:\n  post_implement:\n    - make build#fast  # comment\n    - \"grep -q ' #todo' notes.md\"\n  test: ./check.sh --tag=#1\n"
+	if err := ParseConfig(content, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"make build#fast", "grep -q ' #todo' notes.md"}; !reflect.DeepEqual(cfg.Hooks.PostImplement, want) {
+		t.Errorf("post_implement = %q, want %q", cfg.Hooks.PostImplement, want)
+	}
+	if want := []string{"./check.sh --tag=#1"}; !reflect.DeepEqual(cfg.Hooks.Test, want) {
+		t.Errorf("test = %q, want %q", cfg.Hooks.Test, want)
+	}
+
+	if err := ParseConfig("- stray\n", DefaultConfig()); err == nil {
+		t.Error("expected error for list item without a key")
+	}
+}
+
+func TestParseConfig_SprintTaskBounds(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.SprintMinTasks != DefaultSprintMinTasks || cfg.SprintMaxTasks != DefaultSprintMaxTasks {
+		t.Errorf("unexpected defaults %d-%d", cfg.SprintMinTasks, cfg.SprintMaxTasks)
+	}
+
+	if err := ParseConfig("sprint_min_tasks: 3\nsprint_max_tasks: 6\n", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SprintMinTasks != 3 || cfg.SprintMaxTasks != 6 {
+		t.Errorf("expected 3-6, got %d-%d", cfg.SprintMinTasks, cfg.SprintMaxTasks)
+	}
+
+	for _, content := range []string{
+		"sprint_min_tasks: 0\n",
+		"sprint_max_tasks: many\n",
+		"sprint_min_tasks: 5\nsprint_max_tasks: 2\n",
+	} {
+		if err := ParseConfig(content, DefaultConfig()); err == nil {
+			t.Errorf("expected error for %q", content)
+		}
+	}
+
+	// A single bound past the other's default moves the default with it
+	cfg = DefaultConfig()
+	if err := ParseConfig("sprint_min_tasks: 6\n", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SprintMinTasks != 6 || cfg.SprintMaxTasks != 6 {
+		t.Errorf("expected 6-6, got %d-%d", cfg.SprintMinTasks, cfg.SprintMaxTasks)
+	}
+	cfg = DefaultConfig()
+	if err := ParseConfig("sprint_max_tasks: 1\n", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SprintMinTasks != 1 || cfg.SprintMaxTasks != 1 {
+		t.Errorf("expected 1-1, got %d-%d", cfg.SprintMinTasks, cfg.SprintMaxTasks)
+	}
+}
+
+func TestParseConfig_AssessContextSprints(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.AssessContextSprints != DefaultAssessContextSprints {
+		t.Errorf("expected default %d, got %d", DefaultAssessContextSprints, cfg.AssessContextSprints)
+	}
+
+	if err := ParseConfig("assess_context_sprints: 5\n", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AssessContextSprints != 5 {
+		t.Errorf("expected 5, got %d", cfg.AssessContextSprints)
+	}
+
+	if err := ParseConfig("assess_context_sprints: 0\n", DefaultConfig()); err == nil {
+		t.Error("expected error for zero assess_context_sprints")
+	}
+}
+
+func TestParseConfig_StallLimit(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.StallLimit != DefaultStallLimit {
+		t.Errorf("expected default %d, got %d", DefaultStallLimit, cfg.StallLimit)
+	}
+	if err := ParseConfig("stall_limit: 10\n", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.StallLimit != 10 {
+		t.Errorf("expected 10, got %d", cfg.StallLimit)
+	}
+	if err := ParseConfig("stall_limit: -1\n", DefaultConfig()); err == nil {
+		t.Error("expected error for negative stall_limit")
+	}
+}
+
+func TestParseConfig_KeepRawResponses(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.KeepRawResponses {
+		t.Error("expected raw responses off by default")
+	}
+	if err := ParseConfig("keep_raw_responses: true\n", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.KeepRawResponses {
+		t.Error("expected keep_raw_responses to be set")
+	}
+	if err := ParseConfig("keep_raw_responses: sometimes\n", DefaultConfig()); err == nil {
+		t.Error("expected error for non-boolean keep_raw_responses")
+	}
+}
+
+func TestParseConfig_LogRetention(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := ParseConfig("log_keep_sprints: 5\nlog_max_age: 30d\n", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.LogKeepSprints != 5 || cfg.LogMaxAge != 30*24*time.Hour {
+		t.Errorf("expected 5 sprints and 30d, got %d and %s", cfg.LogKeepSprints, cfg.LogMaxAge)
+	}
+	for _, bad := range []string{"log_keep_sprints: -1\n", "log_max_age: 0d\n", "log_max_age: soon\n"} {
+		if err := ParseConfig(bad, DefaultConfig()); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	for in, want := range map[string]time.Duration{"30d": 30 * 24 * time.Hour, "12h": 12 * time.Hour, "90m": 90 * time.Minute} {
+		if got, err := ParseAge(in); err != nil || got != want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "d", "-1d", "1.5d", "-2h"} {
+		if _, err := ParseAge(bad); err == nil {
+			t.Errorf("ParseAge(%q): expected error", bad)
+		}
+	}
+}
+
+func TestParseConfig_Root(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := ParseConfig("root: services/api/\n", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Root != filepath.Join("services", "api") {
+		t.Errorf("expected cleaned root, got %q", cfg.Root)
+	}
+
+	for _, root := range []string{"/srv/api", "..", "../other", "services/../../other"} {
+		if err := ParseConfig("root: "+root+"\n", DefaultConfig()); err == nil {
+			t.Errorf("expected error for root %q", root)
+		}
+	}
+}
+
+func TestParseConfig_MaxConcurrentAgents(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.MaxConcurrentAgents != DefaultMaxConcurrentAgents {
+		t.Errorf("expected default %d, got %d", DefaultMaxConcurrentAgents, cfg.MaxConcurrentAgents)
+	}
+	if err := ParseConfig("max_concurrent_agents: 4\n", cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.MaxConcurrentAgents != 4 {
+		t.Errorf("expected 4, got %d", cfg.MaxConcurrentAgents)
+	}
+	if err := ParseConfig("max_concurrent_agents: 0\n", DefaultConfig()); err == nil {
+		t.Error("expected error for zero max_concurrent_agents")
+	}
+}
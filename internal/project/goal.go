@@ -1,9 +1,13 @@
 package project
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 // Goal represents parsed GOAL.md content
@@ -15,9 +19,12 @@ type Goal struct {
 
 // ParseGoal reads and parses GOAL.md
 func ParseGoal(path string) (*Goal, error) {
+	if err := CheckGoalFile(path); err != nil {
+		return nil, err
+	}
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return nil, goalFileError(err)
 	}
 
 	content := string(data)
@@ -30,26 +37,184 @@ func ParseGoal(path string) (*Goal, error) {
 	return goal, nil
 }
 
+// CheckGoalFile returns a user-facing error if the GOAL.md at path is
+// missing, is a directory or other non-regular file, or can't be read
+func CheckGoalFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return goalFileError(err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("GOAL.md is a directory; replace it with a markdown file describing what you want to build")
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("GOAL.md is not a regular file; replace it with a markdown file describing what you want to build")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return goalFileError(err)
+	}
+	return f.Close()
+}
+
+// goalFileError turns a filesystem error on GOAL.md into a message that
+// says what to fix
+func goalFileError(err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("GOAL.md not found. Create a GOAL.md file describing what you want to build")
+	case errors.Is(err, fs.ErrPermission):
+		return fmt.Errorf("GOAL.md is not readable (permission denied); fix its permissions, e.g. chmod u+r GOAL.md")
+	}
+	return fmt.Errorf("cannot read GOAL.md: %w", err)
+}
+
+// GoalTemplate is placeholder GOAL.md content for a new project. Validate
+// rejects goals that still contain its instructions.
+const GoalTemplate = `# Goal
+
+<!-- Describe what you want to build: what it does, who it is for, and the
+language or stack to use. Replace this comment with your goal. -->
+`
+
+// goalTemplateMarkers are placeholder phrases that show a goal was never
+// filled in (matched case-insensitively)
+var goalTemplateMarkers = []string{
+	"replace this comment with your goal",
+	"describe what you want to build",
+	"<your goal",
+	"[your goal",
+}
+
+// minGoalLength is the fewest characters of goal text (ignoring headings
+// and comments) that can describe something to build
+const minGoalLength = 10
+
+var (
+	htmlCommentRe  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	goalHeadingsRe = regexp.MustCompile(`(?m)^#+\s.*$`)
+)
+
+// Validate reports whether the goal has real content: not empty, not just
+// headings, and not the unedited template
+func (g *Goal) Validate() error {
+	// Placeholders only count outside comments, so a goal written below
+	// the template's comment is fine
+	text := htmlCommentRe.ReplaceAllString(g.Content, "")
+	if marker := templateMarker(text); marker != "" {
+		return fmt.Errorf("GOAL.md looks unedited: it still contains the placeholder %q", marker)
+	}
+
+	text = strings.TrimSpace(goalHeadingsRe.ReplaceAllString(text, ""))
+	if text == "" {
+		if marker := templateMarker(g.Content); marker != "" {
+			return fmt.Errorf("GOAL.md looks unedited: it only has the template's comment (%q)", marker)
+		}
+		return fmt.Errorf("GOAL.md looks empty: describe what you want to build")
+	}
+	if n := utf8.RuneCountInString(text); n < minGoalLength {
+		return fmt.Errorf("GOAL.md looks empty: %q is too short to describe what you want to build", text)
+	}
+	return nil
+}
+
+// templateMarker returns the first goalTemplateMarkers phrase in text, or ""
+func templateMarker(text string) string {
+	lower := strings.ToLower(text)
+	for _, marker := range goalTemplateMarkers {
+		if strings.Contains(lower, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// goalBulletRe matches an unindented "- " or "* " bullet, with an optional
+// checkbox, capturing its text
+var goalBulletRe = regexp.MustCompile(`^[-*]\s+(?:\[[ xX]\]\s+)?(.*\S)\s*$`)
+
+// goalSectionRe matches a markdown heading, capturing its level and text
+var goalSectionRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+
+// nonGoalHeadingRe matches headings of sections that list what not to
+// build, such as "Non-goals" or "Out of scope"
+var nonGoalHeadingRe = regexp.MustCompile(`(?i)\b(non[- ]?goals?|out[- ]of[- ]scope|not in scope|won'?t (do|build|have))\b`)
+
+// Requirements returns the goal's top-level bullet points as explicit
+// requirements. Nested bullets, bullets inside code fences or HTML comments,
+// bullets under non-goal headings (and their sub-headings), and empty
+// bullets are skipped.
+func (g *Goal) Requirements() []string {
+	var reqs []string
+	inFence := false
+	skipLevel := 0 // Heading level of the non-goal section being skipped
+	text := htmlCommentRe.ReplaceAllString(g.Content, "")
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if m := goalSectionRe.FindStringSubmatch(line); m != nil {
+			level := len(m[1])
+			if skipLevel == 0 || level <= skipLevel {
+				skipLevel = 0
+				if nonGoalHeadingRe.MatchString(m[2]) {
+					skipLevel = level
+				}
+			}
+			continue
+		}
+		if skipLevel > 0 {
+			continue
+		}
+		if m := goalBulletRe.FindStringSubmatch(line); m != nil {
+			reqs = append(reqs, m[1])
+		}
+	}
+	return reqs
+}
+
+// swiftPattern matches Swift the language rather than the adjective
+// ("a swift response"): SwiftUI/SwiftPM, "in/using Swift", "Swift app" and
+// the like, or "swift" alongside an Apple platform
+var swiftPattern = regexp.MustCompile(`(?s)\b(swiftui|swiftpm|swift package manager)\b` +
+	`|\b(in|using) swift\b` +
+	`|\bswift (language|package|cli|app|library|project|code|program)s?\b` +
+	`|\bswift\b.*\b(ios|ipados|macos|watchos|xcode)\b` +
+	`|\b(ios|ipados|macos|watchos|xcode)\b.*\bswift\b`)
+
 // detectLanguage attempts to detect the programming language from goal content
 func detectLanguage(content string) string {
 	lower := strings.ToLower(content)
 
-	// Check for explicit language mentions
-	patterns := map[string]*regexp.Regexp{
-		"go":         regexp.MustCompile(`\b(go|golang)\b`),
-		"python":     regexp.MustCompile(`\b(python|py)\b`),
-		"rust":       regexp.MustCompile(`\b(rust)\b`),
-		"javascript": regexp.MustCompile(`\b(javascript|js|node|nodejs)\b`),
-		"typescript": regexp.MustCompile(`\b(typescript|ts)\b`),
-		"java":       regexp.MustCompile(`\b(java)\b`),
-		"ruby":       regexp.MustCompile(`\b(ruby)\b`),
-		"c++":        regexp.MustCompile(`\b(c\+\+|cpp)\b`),
-		"c":          regexp.MustCompile(`\b(c language|in c)\b`),
-	}
-
-	for lang, pattern := range patterns {
-		if pattern.MatchString(lower) {
-			return lang
+	// Check for explicit language mentions. Order matters where goals name
+	// more than one language: the first match wins, so a goal porting a PHP
+	// service to Go is a Go goal. Kotlin goes ahead of Java because Kotlin
+	// goals often mention Java interop.
+	patterns := []struct {
+		lang    string
+		pattern *regexp.Regexp
+	}{
+		{"go", regexp.MustCompile(`\b(go|golang)\b`)},
+		{"python", regexp.MustCompile(`\b(python|py)\b`)},
+		{"rust", regexp.MustCompile(`\b(rust)\b`)},
+		{"javascript", regexp.MustCompile(`\b(javascript|js|node|nodejs)\b`)},
+		{"typescript", regexp.MustCompile(`\b(typescript|ts)\b`)},
+		{"kotlin", regexp.MustCompile(`\b(kotlin|ktor)\b`)},
+		{"java", regexp.MustCompile(`\b(java)\b`)},
+		{"ruby", regexp.MustCompile(`\b(ruby)\b`)},
+		{"c++", regexp.MustCompile(`\b(c\+\+|cpp)\b`)},
+		{"c", regexp.MustCompile(`\b(c language|in c)\b`)},
+		{"swift", swiftPattern},
+		{"php", regexp.MustCompile(`\b(php|laravel|symfony)\b`)},
+	}
+
+	for _, p := range patterns {
+		if p.pattern.MatchString(lower) {
+			return p.lang
 		}
 	}
 
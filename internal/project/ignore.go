@@ -0,0 +1,159 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFileName is the gitignore-style file in the project root listing
+// paths agate should leave alone
+const IgnoreFileName = ".agateignore"
+
+// Ignore matches project-relative paths against .agateignore patterns.
+// A nil *Ignore matches nothing.
+type Ignore struct {
+	patterns []ignorePattern
+}
+
+// ignorePattern is one compiled .agateignore line
+type ignorePattern struct {
+	re      *regexp.Regexp
+	negate  bool // "!pattern" re-includes a path
+	dirOnly bool // "pattern/" only matches directories
+}
+
+// IgnorePath returns the path to the project's .agateignore
+func (p *Project) IgnorePath() string {
+	return filepath.Join(p.Dir, IgnoreFileName)
+}
+
+// LoadIgnore reads the project's .agateignore, returning an empty matcher
+// if the file doesn't exist
+func (p *Project) LoadIgnore() (*Ignore, error) {
+	return LoadIgnore(p.IgnorePath())
+}
+
+// LoadIgnore reads an ignore file, returning an empty matcher if it
+// doesn't exist
+func LoadIgnore(path string) (*Ignore, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Ignore{}, nil
+		}
+		return nil, err
+	}
+	return ParseIgnore(string(content)), nil
+}
+
+// ParseIgnore compiles gitignore-style patterns, one per line. Supported
+// are # comments, ! negation, a trailing / for directories, a leading or
+// inner / to anchor a pattern to the project root, and the *, ?, [...]
+// and ** wildcards. As in git, the last matching pattern wins.
+func ParseIgnore(content string) *Ignore {
+	ig := &Ignore{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			line = line[1:] // Escaped leading # or !
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		// Without an inner slash a pattern matches a name at any depth
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		prefix := "^"
+		if !anchored {
+			prefix = "^(?:.*/)?"
+		}
+		re, err := regexp.Compile(prefix + globToRegexp(line) + "$")
+		if err != nil {
+			continue // Malformed character class; git ignores these too
+		}
+		p.re = re
+		ig.patterns = append(ig.patterns, p)
+	}
+	return ig
+}
+
+// globToRegexp translates a gitignore glob to a regular expression body
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
+
+// Match reports whether the project-relative path (a directory if isDir)
+// is ignored. A path inside an ignored directory is always ignored, as in
+// git, so a negation can't re-include it.
+func (ig *Ignore) Match(path string, isDir bool) bool {
+	if ig == nil || len(ig.patterns) == 0 {
+		return false
+	}
+	path = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./")
+	parts := strings.Split(path, "/")
+	for i := 1; i < len(parts); i++ {
+		if ig.matchOne(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return ig.matchOne(path, isDir)
+}
+
+// matchOne applies the patterns to a single path, last match winning
+func (ig *Ignore) matchOne(path string, isDir bool) bool {
+	ignored := false
+	for _, p := range ig.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.re.MatchString(path) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,103 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnore_Globs(t *testing.T) {
+	ig := ParseIgnore(`# Build output
+*.log
+/build
+docs/*.pdf
+data?.bin
+**/fixtures/*.json
+`)
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"app.log", true},
+		{"logs/deep/app.log", true},
+		{"app.go", false},
+		{"build", true},
+		{"build/out.o", true},
+		{"src/build/out.o", false}, // Leading / anchors to the root
+		{"docs/guide.pdf", true},
+		{"docs/sub/guide.pdf", false}, // * doesn't cross directories
+		{"data1.bin", true},
+		{"data10.bin", false},
+		{"fixtures/a.json", true},
+		{"test/unit/fixtures/a.json", true},
+	}
+	for _, tt := range tests {
+		if got := ig.Match(tt.path, false); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestIgnore_Directories(t *testing.T) {
+	ig := ParseIgnore("vendor/\nnode_modules\n")
+
+	if !ig.Match("vendor", true) {
+		t.Error("expected vendor directory to be ignored")
+	}
+	if ig.Match("vendor", false) {
+		t.Error("trailing slash should only match directories")
+	}
+	if !ig.Match("vendor/github.com/pkg/errors/errors.go", false) {
+		t.Error("expected files under vendor/ to be ignored")
+	}
+	if !ig.Match("web/node_modules/left-pad/index.js", false) {
+		t.Error("expected files under a nested node_modules to be ignored")
+	}
+	if ig.Match("vendored.go", false) {
+		t.Error("vendored.go should not match vendor/")
+	}
+}
+
+func TestIgnore_Negation(t *testing.T) {
+	ig := ParseIgnore("*.gen.go\n!keep.gen.go\ndist/\n!dist/index.html\n")
+
+	if !ig.Match("api.gen.go", false) {
+		t.Error("expected api.gen.go to be ignored")
+	}
+	if ig.Match("pkg/keep.gen.go", false) {
+		t.Error("expected negated keep.gen.go to be included")
+	}
+	// Like git, a file can't be re-included when its directory is ignored
+	if !ig.Match("dist/index.html", false) {
+		t.Error("expected dist/index.html to stay ignored")
+	}
+}
+
+func TestLoadIgnore(t *testing.T) {
+	dir := t.TempDir()
+	proj := New(dir)
+
+	ig, err := proj.LoadIgnore()
+	if err != nil {
+		t.Fatalf("unexpected error for missing file: %v", err)
+	}
+	if ig.Match("anything.go", false) {
+		t.Error("missing .agateignore should match nothing")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, IgnoreFileName), []byte("*.tmp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ig, err = proj.LoadIgnore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ig.Match("scratch.tmp", false) {
+		t.Error("expected scratch.tmp to be ignored")
+	}
+
+	var none *Ignore
+	if none.Match("scratch.tmp", false) {
+		t.Error("nil matcher should match nothing")
+	}
+}
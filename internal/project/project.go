@@ -1,20 +1,72 @@
 package project
 
 import (
+	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/strongdm/agate/internal/fsutil"
 )
 
+// DefaultStateDir is where agate keeps its state, relative to the project dir
+const DefaultStateDir = ".ai"
+
+// CleanStateDir validates a state directory and returns it as a clean,
+// slash-separated path. The directory must be relative to the project dir
+// and stay inside it, so that fs.FS rooted at the project can still reach
+// it. An empty dir means the default.
+func CleanStateDir(dir string) (string, error) {
+	if dir == "" {
+		return DefaultStateDir, nil
+	}
+	if filepath.IsAbs(dir) {
+		return "", fmt.Errorf("state dir must be relative to the project dir: %s", dir)
+	}
+	clean := path.Clean(filepath.ToSlash(dir))
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("state dir must be inside the project dir: %s", dir)
+	}
+	return clean, nil
+}
+
 // Project represents an agate project
 type Project struct {
 	Dir string
+	// AIDir is the state directory relative to Dir, slash-separated
+	// (defaults to DefaultStateDir)
+	AIDir string
 }
 
-// New creates a new project instance
+// New creates a new project instance using the default state directory
 func New(dir string) *Project {
-	return &Project{Dir: dir}
+	return &Project{Dir: dir, AIDir: DefaultStateDir}
+}
+
+// Open creates a project instance whose state lives in stateDir, which is
+// validated with CleanStateDir
+func Open(dir, stateDir string) (*Project, error) {
+	clean, err := CleanStateDir(stateDir)
+	if err != nil {
+		return nil, err
+	}
+	return &Project{Dir: dir, AIDir: clean}, nil
+}
+
+// StateDir returns the state directory relative to the project dir (e.g.
+// ".ai" or "build/agate-state"). Suitable for use with fs.FS paths.
+func (p *Project) StateDir() string {
+	if p.AIDir == "" {
+		return DefaultStateDir
+	}
+	return p.AIDir
+}
+
+// StatePath joins elem onto the state dir as a slash-separated relative path
+// for use with fs.FS
+func (p *Project) StatePath(elem ...string) string {
+	return path.Join(append([]string{p.StateDir()}, elem...)...)
 }
 
 // GoalPath returns the path to GOAL.md
@@ -22,35 +74,67 @@ func (p *Project) GoalPath() string {
 	return filepath.Join(p.Dir, "GOAL.md")
 }
 
-// HasGoal checks if GOAL.md exists
+// HasGoal checks if GOAL.md exists. Use CheckGoal to also verify it is a
+// readable file.
 func (p *Project) HasGoal() bool {
 	_, err := os.Stat(p.GoalPath())
 	return err == nil
 }
 
+// CheckGoal returns a user-facing error if GOAL.md is missing, not a
+// regular file, or unreadable
+func (p *Project) CheckGoal() error {
+	return CheckGoalFile(p.GoalPath())
+}
+
 // DesignDir returns the path to the design directory
 func (p *Project) DesignDir() string {
-	return filepath.Join(p.Dir, ".ai", "design")
+	return filepath.Join(p.DataDir(), "design")
 }
 
 // SprintsDir returns the path to the sprints directory
 func (p *Project) SprintsDir() string {
-	return filepath.Join(p.Dir, ".ai", "sprints")
+	return filepath.Join(p.DataDir(), "sprints")
 }
 
 // SkillsDir returns the path to the skills directory
 func (p *Project) SkillsDir() string {
-	return filepath.Join(p.Dir, ".ai", "skills")
+	return filepath.Join(p.DataDir(), "skills")
 }
 
-// DataDir returns the path to the .ai directory
+// DataDir returns the path to the state (.ai) directory
 func (p *Project) DataDir() string {
-	return filepath.Join(p.Dir, ".ai")
+	return filepath.Join(p.Dir, filepath.FromSlash(p.StateDir()))
 }
 
 // DraftsDir returns the path to the design drafts directory
 func (p *Project) DraftsDir() string {
-	return filepath.Join(p.Dir, ".ai", "design", ".drafts")
+	return filepath.Join(p.DesignDir(), ".drafts")
+}
+
+// SprintDraftsDir returns the path to where replaced sprint files are kept
+func (p *Project) SprintDraftsDir() string {
+	return filepath.Join(p.SprintsDir(), ".drafts")
+}
+
+// LogsDir returns the path to the agent invocation logs directory
+func (p *Project) LogsDir() string {
+	return filepath.Join(p.DataDir(), "logs")
+}
+
+// RetrosDir returns the path to the sprint retrospectives directory
+func (p *Project) RetrosDir() string {
+	return filepath.Join(p.DataDir(), "retros")
+}
+
+// PromptsDir returns the path to the prompt template overrides directory
+func (p *Project) PromptsDir() string {
+	return filepath.Join(p.DataDir(), "prompts")
+}
+
+// InterviewPath returns the path to the interview file
+func (p *Project) InterviewPath() string {
+	return filepath.Join(p.DataDir(), "interview.md")
 }
 
 // EnsureDirectories creates the required project directories
@@ -1,9 +1,14 @@
 package project
 
 import (
+	"encoding/json"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestProject_HasGoal(t *testing.T) {
@@ -69,6 +74,16 @@ func TestDetectLanguage(t *testing.T) {
 		{"Write a Rust game engine", "rust"},
 		{"Build a JavaScript app", "javascript"},
 		{"Create a TypeScript library", "typescript"},
+		{"Build an Android app in Kotlin", "kotlin"},
+		{"Port our Java service to Kotlin with Ktor", "kotlin"},
+		{"Build an iOS app in Swift", "swift"},
+		{"Write a SwiftUI habit tracker", "swift"},
+		{"Create a Swift package for parsing dates", "swift"},
+		{"Build a PHP blog", "php"},
+		{"Create a Laravel admin panel", "php"},
+		{"Port this PHP service to Go", "go"},
+		{"Rewrite the Swift backend in Python", "python"},
+		{"Provide a swift response to support tickets", "unknown"},
 		{"Something without a language", "unknown"},
 	}
 
@@ -80,6 +95,163 @@ func TestDetectLanguage(t *testing.T) {
 	}
 }
 
+func TestCheckGoal_Directory(t *testing.T) {
+	proj := New(t.TempDir())
+	if err := os.Mkdir(proj.GoalPath(), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := proj.CheckGoal()
+	if err == nil || !strings.Contains(err.Error(), "GOAL.md is a directory") {
+		t.Errorf("expected directory error, got %v", err)
+	}
+	if _, err := ParseGoal(proj.GoalPath()); err == nil || !strings.Contains(err.Error(), "GOAL.md is a directory") {
+		t.Errorf("expected ParseGoal directory error, got %v", err)
+	}
+}
+
+func TestCheckGoal_Unreadable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can read files regardless of permissions")
+	}
+	proj := New(t.TempDir())
+	if err := os.WriteFile(proj.GoalPath(), []byte("Build a CLI in Go."), 0000); err != nil {
+		t.Fatal(err)
+	}
+
+	err := proj.CheckGoal()
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("expected permission error, got %v", err)
+	}
+}
+
+func TestGoalFileError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&fs.PathError{Op: "open", Path: "GOAL.md", Err: fs.ErrPermission}, "not readable (permission denied)"},
+		{&fs.PathError{Op: "stat", Path: "GOAL.md", Err: fs.ErrNotExist}, "GOAL.md not found"},
+		{errors.New("disk on fire"), "cannot read GOAL.md: disk on fire"},
+	}
+	for _, tt := range tests {
+		if got := goalFileError(tt.err).Error(); !strings.Contains(got, tt.want) {
+			t.Errorf("goalFileError(%v) = %q, want it to contain %q", tt.err, got, tt.want)
+		}
+	}
+
+	proj := New(t.TempDir())
+	if err := os.WriteFile(proj.GoalPath(), []byte("Build a CLI in Go."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := proj.CheckGoal(); err != nil {
+		t.Errorf("expected readable goal to pass, got %v", err)
+	}
+}
+
+func TestGoal_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"empty", "", "looks empty"},
+		{"whitespace", "  \n\n", "looks empty"},
+		{"heading only", "# Goal\n\n## Overview\n", "looks empty"},
+		{"comment only", "# Goal\n\n<!-- TODO -->\n", "looks empty"},
+		{"too short", "# Goal\n\nA CLI\n", "too short"},
+		{"template", GoalTemplate, "looks unedited"},
+		{"template with text", GoalTemplate + "\nBuild a CLI in Go.\n", ""},
+		{"template comment emptied", "# Goal\n\n<!-- -->\n", "looks empty"},
+		{"placeholder", "# Goal\n\n<your goal here>\n", "looks unedited"},
+		{"placeholder phrase in text", "# Goal\n\nReplace this comment with your goal.\n", "looks unedited"},
+		{"valid", "# Goal\n\nBuild a CLI in Go.", ""},
+		{"valid multibyte", "# 目标\n\n构建一个命令行待办事项工具", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Goal{Content: tt.content}).Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected valid goal, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGoal_Requirements(t *testing.T) {
+	content := "# Goal\n\nBuild a todo CLI in Go.\n\n" +
+		"- Add, list, and remove todos\n" +
+		"  - nested detail is not a requirement\n" +
+		"* Persist todos to ~/.todo.json\n" +
+		"- [ ] Support due dates\n" +
+		"- [x] Print --help\n" +
+		"-\n" +
+		"<!-- - commented out -->\n" +
+		"```\n- not a requirement\n```\n"
+	got := (&Goal{Content: content}).Requirements()
+	want := []string{
+		"Add, list, and remove todos",
+		"Persist todos to ~/.todo.json",
+		"Support due dates",
+		"Print --help",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Requirements() = %q, want %q", got, want)
+	}
+
+	if reqs := (&Goal{Content: "# Goal\n\nBuild a CLI in Go.\n"}).Requirements(); len(reqs) != 0 {
+		t.Errorf("expected no requirements from prose, got %q", reqs)
+	}
+
+	// Bullets under non-goal headings are not requirements
+	content = "# Goal\n\nBuild a todo CLI in Go.\n\n## Requirements\n\n- Add todos\n\n" +
+		"## Non-goals\n\n- A web UI\n\n### Maybe later\n\n- Sync\n\n" +
+		"## Out of Scope\n\n* Multi-user support\n\n## Nice to have\n\n- Colors\n"
+	got = (&Goal{Content: content}).Requirements()
+	if want := []string{"Add todos", "Colors"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Requirements() = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateSkills_Languages(t *testing.T) {
+	tests := []struct {
+		language string
+		coder    string
+		mention  string
+	}{
+		{"kotlin", "kotlin-coder", "coroutines"},
+		{"swift", "swift-coder", "guard let"},
+		{"php", "php-coder", "PSR-12"},
+	}
+
+	for _, tt := range tests {
+		skills := GenerateSkills(tt.language, "general")
+		if len(skills) != 1 {
+			t.Fatalf("%s: expected 1 skill, got %d", tt.language, len(skills))
+		}
+		skill := skills[0]
+		if skill.Name != tt.coder || skill.Metadata.Phase != "implement" {
+			t.Errorf("%s: expected implement skill %s, got %s (%s)", tt.language, tt.coder, skill.Name, skill.Metadata.Phase)
+		}
+		if errs := LintSkill(&skill); len(errs) > 0 {
+			t.Errorf("%s: generated skill fails lint: %v", tt.language, errs)
+		}
+		if !strings.Contains(skill.Content, tt.mention) {
+			t.Errorf("%s: expected idiomatic guidance mentioning %q", tt.language, tt.mention)
+		}
+		if !strings.Contains(skill.Content, "Do NOT modify sprint") {
+			t.Errorf("%s: expected checkbox disclaimer", tt.language)
+		}
+	}
+}
+
 func TestDetectProjectType(t *testing.T) {
 	tests := []struct {
 		content  string
@@ -186,3 +358,572 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestProject_CustomStateDir(t *testing.T) {
+	dir := t.TempDir()
+	p, err := Open(dir, "build/agate-state/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := filepath.Join(dir, "build", "agate-state")
+
+	paths := map[string]string{
+		"DataDir":       p.DataDir(),
+		"DesignDir":     p.DesignDir(),
+		"SprintsDir":    p.SprintsDir(),
+		"SkillsDir":     p.SkillsDir(),
+		"DraftsDir":     p.DraftsDir(),
+		"LogsDir":       p.LogsDir(),
+		"RetrosDir":     p.RetrosDir(),
+		"InterviewPath": p.InterviewPath(),
+		"ConfigPath":    p.ConfigPath(),
+	}
+	for name, got := range paths {
+		if got != state && !strings.HasPrefix(got, state+string(filepath.Separator)) {
+			t.Errorf("%s = %s, expected it under %s", name, got, state)
+		}
+	}
+
+	if err := p.EnsureDirectories(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(state, "sprints")); err != nil {
+		t.Errorf("expected sprints dir under custom state dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ai")); !os.IsNotExist(err) {
+		t.Error("expected default .ai dir not to be created")
+	}
+
+	if got := p.StatePath("sprints", "01-initial.md"); got != "build/agate-state/sprints/01-initial.md" {
+		t.Errorf("StatePath = %s", got)
+	}
+
+	// Other projects keep the default
+	if got := New(dir).StatePath("sprints"); got != ".ai/sprints" {
+		t.Errorf("expected a new project to use the default state dir, got %s", got)
+	}
+}
+
+func TestOpen_InvalidStateDir(t *testing.T) {
+	for _, dir := range []string{"/abs/path", "..", "../outside", "."} {
+		if _, err := Open(t.TempDir(), dir); err == nil {
+			t.Errorf("expected error for state dir %q", dir)
+		}
+	}
+	if p, err := Open(t.TempDir(), ""); err != nil || p.StateDir() != DefaultStateDir {
+		t.Errorf("expected an empty state dir to mean the default, got %v (%v)", p, err)
+	}
+}
+
+func TestInterpolateSkillVars(t *testing.T) {
+	vars := map[string]string{"GO_VERSION": "1.24", "STYLE_URL": "https://example.com/style#go"}
+	lookup := func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+
+	tests := []struct {
+		name       string
+		content    string
+		want       string
+		unresolved []string
+	}{
+		{"resolved", "Target Go ${GO_VERSION}. See ${STYLE_URL}.", "Target Go 1.24. See https://example.com/style#go.", nil},
+		{"unresolved", "Use ${MISSING} and ${MISSING}.", "Use ${MISSING} and ${MISSING}.", []string{"MISSING"}},
+		{"escaped", "Literal $${GO_VERSION}, real ${GO_VERSION}.", "Literal ${GO_VERSION}, real 1.24.", nil},
+		{"not a placeholder", "Costs $5 or $GO_VERSION.", "Costs $5 or $GO_VERSION.", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, unresolved := InterpolateSkillVars(tt.content, lookup)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if strings.Join(unresolved, ",") != strings.Join(tt.unresolved, ",") {
+				t.Errorf("unresolved = %v, want %v", unresolved, tt.unresolved)
+			}
+		})
+	}
+}
+
+func TestLoadSkill_Interpolation(t *testing.T) {
+	aiDir := t.TempDir()
+	skillsDir := filepath.Join(aiDir, "skills")
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	vars := "GO_VERSION: \"1.22\"\nSTYLE_URL: https://example.com/style # team guide\n"
+	if err := os.WriteFile(filepath.Join(aiDir, "skill-vars.yaml"), []byte(vars), 0644); err != nil {
+		t.Fatal(err)
+	}
+	skill := "# Go Coder\n\nGo ${GO_VERSION}, style ${STYLE_URL}, owner ${AGATE_TEST_OWNER}, ${AGATE_TEST_UNSET}, $${GO_VERSION}\n"
+	path := filepath.Join(skillsDir, "go-coder.md")
+	if err := os.WriteFile(path, []byte(skill), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Environment wins over the vars file
+	t.Setenv("AGATE_TEST_OWNER", "platform")
+	t.Setenv("GO_VERSION", "1.24")
+
+	loaded, err := LoadSkill(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Go 1.24, style https://example.com/style, owner platform, ${AGATE_TEST_UNSET}, ${GO_VERSION}"
+	if !strings.Contains(loaded.Content, want) {
+		t.Errorf("expected %q in content:\n%s", want, loaded.Content)
+	}
+}
+
+func builtinSkill(t *testing.T, name string) Skill {
+	t.Helper()
+	for _, s := range BuiltinSkills() {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("builtin skill %s not found", name)
+	return Skill{}
+}
+
+func TestEnsureBuiltinSkills_VersionBumpPreservesCustomizations(t *testing.T) {
+	dir := t.TempDir()
+	builtin := builtinSkill(t, "_reviewer")
+
+	// An older on-disk builtin with a user customization block
+	old := builtin.Metadata
+	old.Version = builtin.Metadata.Version - 1
+	body := "# Outdated Reviewer\n\nOld instructions.\n\n## User Customizations\n\nAlways check error wrapping.\n"
+	path := filepath.Join(dir, "_reviewer.md")
+	original := FormatSkillWithFrontmatter(old, body)
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Loading a skill never rewrites it
+	if _, err := LoadSkill(path); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("expected LoadSkill to leave the file alone, got:\n%s", data)
+	}
+
+	if _, err := EnsureBuiltinSkills(dir); err != nil {
+		t.Fatal(err)
+	}
+	skill, err := LoadSkill(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(skill.Content, "Outdated Reviewer") {
+		t.Error("expected outdated builtin body to be refreshed")
+	}
+	if !strings.Contains(skill.Content, "## User Customizations\n\nAlways check error wrapping.") {
+		t.Errorf("expected user customizations preserved:\n%s", skill.Content)
+	}
+	if skill.Metadata.Version != builtin.Metadata.Version {
+		t.Errorf("expected version %d, got %d", builtin.Metadata.Version, skill.Metadata.Version)
+	}
+}
+
+func TestEnsureBuiltinSkills_KeepsNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	builtin := builtinSkill(t, "_reviewer")
+
+	// A builtin written by a newer agate
+	newer := builtin.Metadata
+	newer.Version = builtin.Metadata.Version + 1
+	path := filepath.Join(dir, "_reviewer.md")
+	content := FormatSkillWithFrontmatter(newer, "# Newer Reviewer\n")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := EnsureBuiltinSkills(dir); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Errorf("expected a newer builtin not to be downgraded, got:\n%s", data)
+	}
+}
+
+func TestLoadSkills_MergesInlineAndFileCustomizations(t *testing.T) {
+	dir := t.TempDir()
+	builtin := builtinSkill(t, "_reviewer")
+	body := JoinUserCustomizations(builtin.Content, "Inline rule.")
+	if err := os.WriteFile(filepath.Join(dir, "_reviewer.md"), []byte(FormatSkillWithFrontmatter(builtin.Metadata, body)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "reviewer.md"), []byte("File rule.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	skills, err := LoadSkills(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reviewer := GetSkillByName(skills, "_reviewer")
+	if reviewer == nil {
+		t.Fatal("_reviewer not loaded")
+	}
+	if n := strings.Count(reviewer.Content, UserCustomizationsHeading); n != 1 {
+		t.Errorf("expected a single customizations heading, got %d:\n%s", n, reviewer.Content)
+	}
+	if !strings.Contains(reviewer.Content, "Inline rule.\n\nFile rule.") {
+		t.Errorf("expected inline and file customizations merged:\n%s", reviewer.Content)
+	}
+}
+
+func TestEnsureBuiltinSkills_KeepsCustomizations(t *testing.T) {
+	dir := t.TempDir()
+	builtin := builtinSkill(t, "_reviewer")
+	body := JoinUserCustomizations("# Stale body\n", "Keep me.")
+	if err := os.WriteFile(filepath.Join(dir, "_reviewer.md"), []byte(FormatSkillWithFrontmatter(builtin.Metadata, body)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := EnsureBuiltinSkills(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "_reviewer.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "Stale body") || !strings.Contains(string(data), "## User Customizations\n\nKeep me.") {
+		t.Errorf("expected refreshed body with customizations kept:\n%s", data)
+	}
+}
+
+func TestEnsureBuiltinSkills_SkipsUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := EnsureBuiltinSkills(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// A customized builtin must also be recognized as unchanged
+	builtin := builtinSkill(t, "_reviewer")
+	customized := FormatSkillWithFrontmatter(builtin.Metadata, JoinUserCustomizations(builtin.Content, "Keep me."))
+	if err := os.WriteFile(filepath.Join(dir, "_reviewer.md"), []byte(customized), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Backdate every file so a rewrite would be visible in its mtime
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if err := os.Chtimes(filepath.Join(dir, e.Name()), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := EnsureBuiltinSkills(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range entries {
+		info, err := os.Stat(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.ModTime().Equal(old) {
+			t.Errorf("%s was rewritten on the second run", e.Name())
+		}
+	}
+}
+
+func TestEnsureBuiltinSkills_BacksUpUserEdits(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := EnsureBuiltinSkills(dir); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "_reviewer.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(data), "# Reviewer", "# Reviewer\n\nAlways run the linter.", 1)
+	if err := os.WriteFile(path, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := EnsureBuiltinSkills(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 || filepath.Dir(backups[0]) != filepath.Join(dir, ".drafts") || !strings.HasPrefix(filepath.Base(backups[0]), "_reviewer.") {
+		t.Fatalf("expected one _reviewer backup in .drafts, got %v", backups)
+	}
+	saved, err := os.ReadFile(backups[0])
+	if err != nil || string(saved) != edited {
+		t.Errorf("expected the backup to hold the user's edit, got %q (%v)", saved, err)
+	}
+	refreshed, _ := os.ReadFile(path)
+	if strings.Contains(string(refreshed), "Always run the linter.") {
+		t.Error("expected _reviewer.md to be refreshed to the built-in")
+	}
+	if skills, _ := LoadSkills(dir); GetSkillByName(skills, "_reviewer") == nil || len(skills) != len(BuiltinSkills()) {
+		t.Errorf("backups must not load as skills, got %d skills", len(skills))
+	}
+}
+
+func TestEnsureBuiltinSkills_NoBackupForOwnWrites(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := EnsureBuiltinSkills(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Adding a User Customizations block is not an edit to back up
+	path := filepath.Join(dir, "_reviewer.md")
+	builtin := builtinSkill(t, "_reviewer")
+	customized := FormatSkillWithFrontmatter(builtin.Metadata, JoinUserCustomizations(builtin.Content, "Keep me."))
+	if err := os.WriteFile(path, []byte(customized), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if backups, err := EnsureBuiltinSkills(dir); err != nil || len(backups) != 0 {
+		t.Fatalf("expected no backups for customizations, got %v (%v)", backups, err)
+	}
+
+	// Nor is an older built-in that agate itself wrote
+	older := FormatSkillWithFrontmatter(builtin.Metadata, "# Reviewer\n\nAn older built-in body.\n")
+	if err := os.WriteFile(path, []byte(older), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sums := map[string]string{}
+	data, _ := os.ReadFile(filepath.Join(dir, builtinSumsName))
+	if err := json.Unmarshal(data, &sums); err != nil {
+		t.Fatal(err)
+	}
+	sums["_reviewer"] = builtinSum(older)
+	data, _ = json.Marshal(sums)
+	if err := os.WriteFile(filepath.Join(dir, builtinSumsName), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if backups, err := EnsureBuiltinSkills(dir); err != nil || len(backups) != 0 {
+		t.Fatalf("expected no backups for agate's own older built-in, got %v (%v)", backups, err)
+	}
+	if refreshed, _ := os.ReadFile(path); strings.Contains(string(refreshed), "older built-in body") {
+		t.Error("expected the older built-in to be refreshed")
+	}
+}
+
+func TestEnsureBuiltinSkills_NoChecksumFileIsNotAnEdit(t *testing.T) {
+	dir := t.TempDir()
+	builtin := builtinSkill(t, "_reviewer")
+	older := FormatSkillWithFrontmatter(builtin.Metadata, "# Reviewer\n\nAn older built-in body.\n")
+	path := filepath.Join(dir, "_reviewer.md")
+	if err := os.WriteFile(path, []byte(older), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := EnsureBuiltinSkills(dir)
+	if err != nil || len(backups) != 0 {
+		t.Fatalf("expected no backups without a checksum file, got %v (%v)", backups, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".drafts")); !os.IsNotExist(err) {
+		t.Error("expected no .drafts directory")
+	}
+	if refreshed, _ := os.ReadFile(path); strings.Contains(string(refreshed), "older built-in body") {
+		t.Error("expected the older built-in to be refreshed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, builtinSumsName)); err != nil {
+		t.Errorf("expected the checksum file to be written: %v", err)
+	}
+}
+
+func TestLintSkill_Clean(t *testing.T) {
+	meta, body := ParseSkillMetadata("---\nname: go-coder\nagents: [codex, claude]\nphase: implement\nversion: 1\n---\n\n# Go Coder\n")
+	if errs := LintSkill(&Skill{Name: "go-coder", Metadata: meta, Content: body}); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}
+
+func TestLintSkill_MissingName(t *testing.T) {
+	meta, body := ParseSkillMetadata("---\nagents: [claude]\n---\n\n# Skill\n")
+	errs := LintSkill(&Skill{Name: "my-skill", Metadata: meta, Content: body})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "missing required frontmatter field: name") {
+		t.Errorf("expected missing name error, got %v", errs)
+	}
+}
+
+func TestLintSkill_UnknownAgentAndPhase(t *testing.T) {
+	meta, body := ParseSkillMetadata("---\nname: my-skill\nagents: [claude, cluade]\nphase: implementation\n---\n\n# Skill\n")
+	errs := LintSkill(&Skill{Name: "my-skill", Metadata: meta, Content: body})
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), `unknown agent "cluade"`) {
+		t.Errorf("expected unknown agent error, got %v", errs[0])
+	}
+	if !strings.Contains(errs[1].Error(), `invalid phase "implementation"`) {
+		t.Errorf("expected invalid phase error, got %v", errs[1])
+	}
+}
+
+func TestLintSkills_Dir(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := EnsureBuiltinSkills(dir); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"go-coder.md": "---\nname: go-coder\nagents: [codex]\nphase: implement\n---\n\n# Go Coder\n",
+		"reviewer.md": "---\nname: reviewer\nagents: [claude]\n---\n\nAlso check docs.\n",
+		"_mine.md":    "---\nname: _mine\nagents: [claude]\n---\n\n# Mine\n",
+		"broken.md":   "# No frontmatter\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := LintSkills(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byFile := make(map[string]SkillLintResult)
+	for _, r := range results {
+		byFile[r.File] = r
+	}
+
+	// Built-in skills lint clean
+	for _, b := range BuiltinSkills() {
+		if r := byFile[b.Name+".md"]; len(r.Errors) != 0 || len(r.Warnings) != 0 {
+			t.Errorf("builtin %s: unexpected findings %+v", b.Name, r)
+		}
+	}
+	if r := byFile["go-coder.md"]; len(r.Errors) != 0 || len(r.Warnings) != 0 {
+		t.Errorf("go-coder: unexpected findings %+v", r)
+	}
+	if r := byFile["reviewer.md"]; len(r.Errors) != 0 || len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], "built-in _reviewer") {
+		t.Errorf("reviewer: expected collision warning, got %+v", r)
+	}
+	if r := byFile["_mine.md"]; len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], "reserved _ prefix") {
+		t.Errorf("_mine: expected reserved prefix warning, got %+v", r)
+	}
+	if r := byFile["broken.md"]; len(r.Errors) < 2 {
+		t.Errorf("broken: expected missing frontmatter and name errors, got %+v", r)
+	}
+}
+
+func TestSkillMetadata_ContextCommands(t *testing.T) {
+	meta, body := ParseSkillMetadata("---\nname: go-reviewer\nagents: [claude]\ncontext_commands: [go version, go vet ./...]\n---\n\n# Go Reviewer\n")
+	if len(meta.ContextCommands) != 2 || meta.ContextCommands[1] != "go vet ./..." {
+		t.Fatalf("expected 2 context commands, got %q", meta.ContextCommands)
+	}
+	if errs := LintSkill(&Skill{Name: "go-reviewer", Metadata: meta, Content: body}); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+
+	again, _ := ParseSkillMetadata(FormatSkillWithFrontmatter(meta, body))
+	if strings.Join(again.ContextCommands, "|") != "go version|go vet ./..." {
+		t.Errorf("context commands did not round-trip: %q", again.ContextCommands)
+	}
+}
+
+func TestLintSkill_DisallowedContextCommand(t *testing.T) {
+	meta, body := ParseSkillMetadata("---\nname: my-skill\nagents: [claude]\ncontext_commands: [go versions, curl example.com]\n---\n\n# Skill\n")
+	errs := LintSkill(&Skill{Name: "my-skill", Metadata: meta, Content: body})
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if !strings.Contains(errs[1].Error(), `context command "curl example.com" is not allowed`) {
+		t.Errorf("expected disallowed command error, got %v", errs[1])
+	}
+}
+
+func TestIsAllowedContextCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{"go version", true},
+		{"go vet ./...", true},
+		{"go list -m -json all", true},
+		{"go env GOPATH GOOS", true},
+		{"git status --short", true},
+		{"git log --oneline -10", true},
+		{"git log -n 5 -- main.go", true},
+		{"git diff --stat HEAD~1", true},
+		{"npm ls --depth=0", true},
+		// Flags that write files or run programs
+		{"go env -w GOFLAGS=-toolexec=/tmp/x", false},
+		{"go env -u GOPROXY", false},
+		{"go vet -vettool=/tmp/x ./...", false},
+		{"go vet -vettool /tmp/x ./...", false},
+		{"go list -toolexec=/tmp/x ./...", false},
+		{"go list -exec /tmp/x ./...", false},
+		{"go vet -mod=mod ./...", false},
+		{"git diff --output=/tmp/f", false},
+		{"git log --output=/tmp/f", false},
+		{"git log --ext-diff", false},
+		{"git status --ignored=matching x", false},
+		// Version commands take nothing more
+		{"node --version -e 1", false},
+		{"node --version script.js", false},
+		{"go versions", false},
+		{"curl example.com", false},
+	}
+	for _, tt := range tests {
+		if got := IsAllowedContextCommand(tt.command); got != tt.want {
+			t.Errorf("IsAllowedContextCommand(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestLintSkill_DisallowedContextCommandFlag(t *testing.T) {
+	meta, body := ParseSkillMetadata("---\nname: my-skill\nagents: [claude]\ncontext_commands: [go env -w GOFLAGS=x]\n---\n\n# Skill\n")
+	errs := LintSkill(&Skill{Name: "my-skill", Metadata: meta, Content: body})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `flag "-w" is not allowed`) {
+		t.Errorf("expected a disallowed flag error, got %v", errs)
+	}
+}
+
+// TestLoadSkill_InterpolationIgnoresUndeclaredEnv verifies a placeholder
+// naming an environment variable that is neither declared in the skill vars
+// file nor AGATE_-prefixed is left intact, and that its warning is printed
+// once however often the skill is loaded.
+func TestLoadSkill_InterpolationIgnoresUndeclaredEnv(t *testing.T) {
+	skillsDir := filepath.Join(t.TempDir(), "skills")
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(skillsDir, "leaky.md")
+	if err := os.WriteFile(path, []byte("# Leaky\n\nToken ${AGATE_TEST_SECRET_TOKEN_X} and ${TEST_SECRET_TOKEN}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_SECRET_TOKEN", "hunter2")
+	t.Setenv("AGATE_TEST_SECRET_TOKEN_X", "visible")
+
+	stderr, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stderr
+	os.Stderr = stderr
+	defer func() { os.Stderr = orig }()
+
+	for i := 0; i < 3; i++ {
+		loaded, err := LoadSkill(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(loaded.Content, "hunter2") || !strings.Contains(loaded.Content, "Token visible and ${TEST_SECRET_TOKEN}") {
+			t.Fatalf("expected only the AGATE_ variable expanded, got:\n%s", loaded.Content)
+		}
+	}
+
+	os.Stderr = orig
+	warnings, err := os.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(warnings), "unresolved variable ${TEST_SECRET_TOKEN}"); n != 1 {
+		t.Errorf("expected the warning once, got %d times:\n%s", n, warnings)
+	}
+}
@@ -0,0 +1,144 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// runLockName is the lock file, in the state dir, naming the PID of the
+// agate process running steps in the project
+const runLockName = ".run.lock"
+
+// runLockSettle is how long a lock without a readable PID is re-read before
+// it is treated as held: its holder may be between creating the file and
+// writing its PID
+const runLockSettle = 500 * time.Millisecond
+
+// RunLockPath returns the path to the project's run-lock file
+func (p *Project) RunLockPath() string {
+	return filepath.Join(p.DataDir(), runLockName)
+}
+
+// RunLockedError reports that another live agate process holds the run-lock.
+// PID is 0 when the lock names no PID.
+type RunLockedError struct {
+	PID  int
+	Path string
+}
+
+func (e *RunLockedError) Error() string {
+	if e.PID == 0 {
+		return fmt.Sprintf("another agate process is starting in this project, or its run lock is corrupt; if no agate is running, delete %s", e.Path)
+	}
+	return fmt.Sprintf("another agate process (pid %d) is already running in this project; wait for it to finish or stop it (lock: %s)", e.PID, e.Path)
+}
+
+// AcquireRunLock takes the project's run-lock so that only one agate process
+// advances the project at a time. A lock naming a dead process is
+// reclaimed; a lock held by this process or its parent (auto running each
+// step as a child 'agate next') is shared. A lock without a readable PID
+// counts as held, since its holder may not have written the PID yet. The
+// returned release func removes the lock if this call created it.
+func (p *Project) AcquireRunLock() (release func(), err error) {
+	path := p.RunLockPath()
+	dataDir := p.DataDir()
+	_, statErr := os.Stat(dataDir)
+	createdDir := errors.Is(statErr, fs.ErrNotExist)
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dataDir, err)
+	}
+	cleanup := func() {
+		if createdDir {
+			os.Remove(dataDir) // Only succeeds if nothing else was written
+		}
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			pid := os.Getpid()
+			_, werr := fmt.Fprintf(f, "%d\n", pid)
+			if cerr := f.Close(); werr == nil {
+				werr = cerr
+			}
+			if werr != nil {
+				os.Remove(path)
+				cleanup()
+				return nil, fmt.Errorf("failed to write run lock: %w", werr)
+			}
+			return func() {
+				if holder, ok := readRunLock(path); ok && holder == pid {
+					os.Remove(path)
+				}
+				cleanup()
+			}, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			cleanup()
+			return nil, fmt.Errorf("failed to create run lock: %w", err)
+		}
+
+		holder, ok := settledRunLock(path)
+		switch {
+		case !ok:
+			if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+				continue // Released while we waited; try again
+			}
+			return nil, &RunLockedError{Path: path}
+		case holder == os.Getpid() || holder == os.Getppid():
+			return func() {}, nil
+		case processAlive(holder):
+			return nil, &RunLockedError{PID: holder, Path: path}
+		}
+		// The holder is dead: reclaim the lock and try again
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove stale run lock: %w", err)
+		}
+	}
+	return nil, fmt.Errorf("failed to acquire run lock %s: it keeps being recreated", path)
+}
+
+// settledRunLock reads the PID from a run-lock file, re-reading it for up to
+// runLockSettle while it has none, as when its holder has just created it
+func settledRunLock(path string) (int, bool) {
+	deadline := time.Now().Add(runLockSettle)
+	for {
+		if pid, ok := readRunLock(path); ok {
+			return pid, true
+		}
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) || time.Now().After(deadline) {
+			return 0, false
+		}
+		time.Sleep(runLockSettle / 10)
+	}
+}
+
+// readRunLock returns the PID recorded in a run-lock file
+func readRunLock(path string) (int, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, true
+}
+
+// processAlive reports whether a process with the given PID exists
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = proc.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
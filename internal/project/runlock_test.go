@@ -0,0 +1,158 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// startSleeper starts a long-lived process to stand in for another agate run
+func startSleeper(t *testing.T) *exec.Cmd {
+	t.Helper()
+	sleeper := exec.Command("sleep", "30")
+	if err := sleeper.Start(); err != nil {
+		t.Skipf("cannot start a helper process: %v", err)
+	}
+	t.Cleanup(func() {
+		sleeper.Process.Kill()
+		sleeper.Wait()
+	})
+	return sleeper
+}
+
+func TestAcquireRunLock_CreatesAndReleases(t *testing.T) {
+	dir := t.TempDir()
+	p := New(dir)
+
+	release, err := p.AcquireRunLock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pid, ok := readRunLock(p.RunLockPath()); !ok || pid != os.Getpid() {
+		t.Errorf("expected the lock to hold our pid, got %d (%v)", pid, ok)
+	}
+	release()
+	if _, err := os.Stat(p.RunLockPath()); !os.IsNotExist(err) {
+		t.Error("expected release to remove the lock")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".ai")); !os.IsNotExist(err) {
+		t.Error("expected release to remove the state dir it created for the lock")
+	}
+}
+
+func TestAcquireRunLock_HeldByLiveProcess(t *testing.T) {
+	dir := t.TempDir()
+	p := New(dir)
+	sleeper := startSleeper(t)
+	if err := os.MkdirAll(p.DataDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p.RunLockPath(), []byte(fmt.Sprintf("%d\n", sleeper.Process.Pid)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := p.AcquireRunLock()
+	var locked *RunLockedError
+	if !errors.As(err, &locked) || locked.PID != sleeper.Process.Pid {
+		t.Fatalf("expected RunLockedError for pid %d, got %v", sleeper.Process.Pid, err)
+	}
+	if pid, _ := readRunLock(p.RunLockPath()); pid != sleeper.Process.Pid {
+		t.Error("expected a held lock to be left alone")
+	}
+}
+
+func TestAcquireRunLock_ReclaimsStaleLock(t *testing.T) {
+	dir := t.TempDir()
+	p := New(dir)
+	sleeper := startSleeper(t)
+	deadPID := sleeper.Process.Pid
+	sleeper.Process.Kill()
+	sleeper.Wait()
+	if err := os.MkdirAll(p.DataDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p.RunLockPath(), []byte(fmt.Sprintf("%d\n", deadPID)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := p.AcquireRunLock()
+	if err != nil {
+		t.Fatalf("expected a stale lock to be reclaimed, got %v", err)
+	}
+	defer release()
+	if pid, _ := readRunLock(p.RunLockPath()); pid != os.Getpid() {
+		t.Errorf("expected the reclaimed lock to hold our pid, got %d", pid)
+	}
+}
+
+func TestAcquireRunLock_SharedWithParent(t *testing.T) {
+	dir := t.TempDir()
+	p := New(dir)
+	if err := os.MkdirAll(p.DataDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p.RunLockPath(), []byte(fmt.Sprintf("%d\n", os.Getppid())), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := p.AcquireRunLock()
+	if err != nil {
+		t.Fatalf("expected a lock held by the parent (auto) to be shared, got %v", err)
+	}
+	release()
+	if pid, _ := readRunLock(p.RunLockPath()); pid != os.Getppid() {
+		t.Error("expected releasing a shared lock to leave the parent's lock in place")
+	}
+}
+
+// TestAcquireRunLock_EmptyLockIsHeld verifies a lock with no PID, as between
+// another process creating it and writing its PID, is not reclaimed.
+func TestAcquireRunLock_EmptyLockIsHeld(t *testing.T) {
+	for name, content := range map[string]string{"empty": "", "garbage": "not a pid\n"} {
+		t.Run(name, func(t *testing.T) {
+			p := New(t.TempDir())
+			if err := os.MkdirAll(p.DataDir(), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(p.RunLockPath(), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := p.AcquireRunLock()
+			var locked *RunLockedError
+			if !errors.As(err, &locked) || locked.PID != 0 {
+				t.Fatalf("expected RunLockedError with no pid, got %v", err)
+			}
+			if data, _ := os.ReadFile(p.RunLockPath()); string(data) != content {
+				t.Errorf("expected the lock to be left alone, got %q", data)
+			}
+		})
+	}
+}
+
+// TestAcquireRunLock_WaitsForPIDWrite verifies a lock whose holder writes
+// its PID shortly after creating it is reported as held by that process.
+func TestAcquireRunLock_WaitsForPIDWrite(t *testing.T) {
+	p := New(t.TempDir())
+	sleeper := startSleeper(t)
+	if err := os.MkdirAll(p.DataDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p.RunLockPath(), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(runLockSettle / 5)
+		os.WriteFile(p.RunLockPath(), []byte(fmt.Sprintf("%d\n", sleeper.Process.Pid)), 0644)
+	}()
+
+	_, err := p.AcquireRunLock()
+	var locked *RunLockedError
+	if !errors.As(err, &locked) || locked.PID != sleeper.Process.Pid {
+		t.Fatalf("expected RunLockedError for pid %d, got %v", sleeper.Process.Pid, err)
+	}
+}
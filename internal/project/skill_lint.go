@@ -0,0 +1,232 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SkillAgents lists the agent names a skill's agents: field may use.
+// It mirrors the agent registry, which this package can't import.
+var SkillAgents = []string{"claude", "haiku", "codex", "dummy"}
+
+// SkillPhases lists the valid values for a skill's phase: field
+var SkillPhases = []string{"implement", "review", "planning", "replan", "recover", "retrospective", "reference"}
+
+// ContextCommandAllowlist lists the commands a skill's context_commands:
+// field may run. A command is allowed when its leading words match an entry
+// and every further argument is allowed for that entry (see
+// contextCommandArgs), so "go vet ./..." is allowed but "go vet
+// -vettool=x" is not. Every entry only reads the project or toolchain.
+var ContextCommandAllowlist = []string{
+	"go version",
+	"go env",
+	"go vet",
+	"go list",
+	"git status",
+	"git log",
+	"git diff",
+	"node --version",
+	"npm --version",
+	"npm ls",
+	"python3 --version",
+	"cargo --version",
+	"rustc --version",
+}
+
+// contextCommandSpec lists the arguments an allowlisted command may be given
+// after its leading words
+type contextCommandSpec struct {
+	flags       []string // allowed flags, given bare or as flag=value
+	positionals bool     // whether non-flag arguments (packages, revisions, paths) are allowed
+	countFlag   bool     // whether git's -<n> commit limit is allowed
+}
+
+// contextCommandArgs is keyed by ContextCommandAllowlist entry; an entry
+// missing here takes no arguments. Flags are allowed one by one because
+// many write files or run programs: "go env -w", "go vet -vettool", "go
+// list -toolexec", "git diff --output", and so on.
+var contextCommandArgs = map[string]contextCommandSpec{
+	"go env":     {flags: []string{"-json"}, positionals: true},
+	"go vet":     {flags: []string{"-json"}, positionals: true},
+	"go list":    {flags: []string{"-json", "-m", "-deps", "-test", "-e"}, positionals: true},
+	"git status": {flags: []string{"-s", "--short", "-b", "--branch", "--porcelain"}},
+	"git log":    {flags: []string{"--oneline", "--stat", "--graph", "--decorate", "-n", "--max-count", "--"}, positionals: true, countFlag: true},
+	"git diff":   {flags: []string{"--stat", "--shortstat", "--name-only", "--name-status", "--cached", "--staged", "--"}, positionals: true},
+	"npm ls":     {flags: []string{"--all", "--depth", "--json"}, positionals: true},
+}
+
+// IsAllowedContextCommand reports whether command is allowed by
+// CheckContextCommand
+func IsAllowedContextCommand(command string) bool {
+	return CheckContextCommand(command) == nil
+}
+
+// CheckContextCommand returns an error unless command starts with the words
+// of an entry in ContextCommandAllowlist and passes only the arguments that
+// entry allows
+func CheckContextCommand(command string) error {
+	words := strings.Fields(command)
+	for _, entry := range ContextCommandAllowlist {
+		allowed := strings.Fields(entry)
+		if len(words) < len(allowed) || strings.Join(words[:len(allowed)], " ") != entry {
+			continue
+		}
+		spec := contextCommandArgs[entry]
+		for _, arg := range words[len(allowed):] {
+			if err := spec.check(arg); err != nil {
+				return fmt.Errorf("context command %q: %w", command, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("context command %q is not allowed (allowed: %s)", command, strings.Join(ContextCommandAllowlist, ", "))
+}
+
+// check returns an error unless arg is allowed by the spec
+func (s contextCommandSpec) check(arg string) error {
+	if !strings.HasPrefix(arg, "-") {
+		if s.positionals {
+			return nil
+		}
+		return fmt.Errorf("argument %q is not allowed", arg)
+	}
+	if s.countFlag && isCountFlag(arg) {
+		return nil
+	}
+	name, _, _ := strings.Cut(arg, "=")
+	if containsString(s.flags, name) {
+		return nil
+	}
+	if len(s.flags) == 0 {
+		return fmt.Errorf("flag %q is not allowed", arg)
+	}
+	return fmt.Errorf("flag %q is not allowed (allowed: %s)", arg, strings.Join(s.flags, ", "))
+}
+
+// isCountFlag reports whether arg is a commit limit like -5
+func isCountFlag(arg string) bool {
+	if len(arg) < 2 {
+		return false
+	}
+	for _, r := range arg[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// LintSkill validates a skill's frontmatter: a name matching its file, known
+// agents, a valid phase, a positive version, and allowlisted context
+// commands. skill.Name is the file name (without .md) and skill.Metadata is
+// the frontmatter as parsed, before LoadSkill fills in defaults.
+func LintSkill(skill *Skill) []error {
+	var errs []error
+	meta := skill.Metadata
+
+	if meta.Name == "" {
+		errs = append(errs, fmt.Errorf("missing required frontmatter field: name"))
+	} else if meta.Name != skill.Name {
+		errs = append(errs, fmt.Errorf("name %q does not match file name %q", meta.Name, skill.Name+".md"))
+	}
+
+	if len(meta.Agents) == 0 {
+		errs = append(errs, fmt.Errorf("agents list is empty (every agent would be allowed)"))
+	}
+	for _, a := range meta.Agents {
+		if !containsString(SkillAgents, a) {
+			errs = append(errs, fmt.Errorf("unknown agent %q (known: %s)", a, strings.Join(SkillAgents, ", ")))
+		}
+	}
+
+	if meta.Phase != "" && !containsString(SkillPhases, meta.Phase) {
+		errs = append(errs, fmt.Errorf("invalid phase %q (valid: %s)", meta.Phase, strings.Join(SkillPhases, ", ")))
+	}
+
+	if meta.Version < 1 {
+		errs = append(errs, fmt.Errorf("version must be a positive integer, got %d", meta.Version))
+	}
+
+	for _, c := range meta.ContextCommands {
+		if err := CheckContextCommand(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// SkillLintResult holds the lint findings for one skill file
+type SkillLintResult struct {
+	File     string
+	Errors   []error
+	Warnings []string
+}
+
+// LintSkills lints every skill file in skillsDir, also warning about names
+// that collide with the built-in skills. Results are sorted by file name.
+func LintSkills(skillsDir string) ([]SkillLintResult, error) {
+	entries, err := os.ReadDir(skillsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".md" {
+			names[strings.TrimSuffix(e.Name(), ".md")] = true
+		}
+	}
+
+	builtins := make(map[string]bool)
+	for _, b := range BuiltinSkills() {
+		builtins[b.Name] = true
+	}
+
+	var results []SkillLintResult
+	for name := range names {
+		file := name + ".md"
+		result := SkillLintResult{File: file}
+
+		content, err := os.ReadFile(filepath.Join(skillsDir, file))
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+			results = append(results, result)
+			continue
+		}
+
+		meta, body := ParseSkillMetadata(string(content))
+		if !strings.HasPrefix(string(content), "---\n") {
+			result.Errors = append(result.Errors, fmt.Errorf("missing frontmatter block"))
+		}
+		result.Errors = append(result.Errors, LintSkill(&Skill{Name: name, Metadata: meta, Content: body})...)
+		if strings.TrimSpace(body) == "" {
+			result.Warnings = append(result.Warnings, "skill has no content")
+		}
+
+		switch {
+		case IsBuiltinSkill(name) && !builtins[name]:
+			result.Warnings = append(result.Warnings, "uses the reserved _ prefix but is not a built-in skill")
+		case !IsBuiltinSkill(name) && builtins["_"+name]:
+			result.Warnings = append(result.Warnings, fmt.Sprintf("shares a name with built-in _%s; its content is merged in as user customizations", name))
+		}
+
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].File < results[j].File })
+	return results, nil
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -1,10 +1,17 @@
 package project
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 )
 
 // SkillMetadata contains parsed skill frontmatter
@@ -14,6 +21,7 @@ type SkillMetadata struct {
 	Phase               string   `yaml:"phase"`
 	CanModifyCheckboxes bool     `yaml:"can_modify_checkboxes"`
 	Version             int      `yaml:"version"`
+	ContextCommands     []string `yaml:"context_commands"` // Read-only commands whose output is added to the prompt
 }
 
 // Skill represents a generated skill with metadata
@@ -70,22 +78,27 @@ func ParseSkillMetadata(content string) (SkillMetadata, string) {
 		case "version":
 			fmt.Sscanf(value, "%d", &meta.Version)
 		case "agents":
-			// Parse [claude, codex] format
-			value = strings.Trim(value, "[]")
-			agents := strings.Split(value, ",")
-			meta.Agents = nil
-			for _, a := range agents {
-				a = strings.TrimSpace(a)
-				if a != "" {
-					meta.Agents = append(meta.Agents, a)
-				}
-			}
+			meta.Agents = parseFrontmatterList(value)
+		case "context_commands":
+			meta.ContextCommands = parseFrontmatterList(value)
 		}
 	}
 
 	return meta, strings.TrimPrefix(body, "\n")
 }
 
+// parseFrontmatterList parses a [a, b] frontmatter list
+func parseFrontmatterList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(strings.Trim(value, "[]"), ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // FormatSkillWithFrontmatter adds frontmatter to skill content
 func FormatSkillWithFrontmatter(meta SkillMetadata, content string) string {
 	var sb strings.Builder
@@ -98,6 +111,9 @@ func FormatSkillWithFrontmatter(meta SkillMetadata, content string) string {
 	}
 	sb.WriteString(fmt.Sprintf("can_modify_checkboxes: %t\n", meta.CanModifyCheckboxes))
 	sb.WriteString(fmt.Sprintf("version: %d\n", meta.Version))
+	if len(meta.ContextCommands) > 0 {
+		sb.WriteString(fmt.Sprintf("context_commands: [%s]\n", strings.Join(meta.ContextCommands, ", ")))
+	}
 	sb.WriteString("---\n\n")
 	sb.WriteString(content)
 
@@ -132,6 +148,12 @@ func GenerateSkills(language, projectType string) []Skill {
 		skills = append(skills, rustSkills()...)
 	case "javascript", "typescript":
 		skills = append(skills, jsSkills()...)
+	case "kotlin":
+		skills = append(skills, kotlinSkills()...)
+	case "swift":
+		skills = append(skills, swiftSkills()...)
+	case "php":
+		skills = append(skills, phpSkills()...)
 	default:
 		skills = append(skills, genericSkills()...)
 	}
@@ -177,6 +199,17 @@ func LoadSkill(path string) (*Skill, error) {
 		meta.Name = name
 	}
 
+	// Skill vars live alongside the skills dir: .ai/skill-vars.yaml
+	varsPath := filepath.Join(filepath.Dir(filepath.Dir(path)), "skill-vars.yaml")
+	vars, err := LoadSkillVars(varsPath)
+	if err != nil {
+		warnOnce(varsPath, fmt.Sprintf("Warning: failed to load skill vars: %v", err))
+	}
+	body, unresolved := InterpolateSkillVars(body, skillVarLookup(vars))
+	for _, key := range unresolved {
+		warnOnce(name+"\x00"+key, fmt.Sprintf("Warning: skill %s: unresolved variable ${%s} (declare it in %s, or use an %s environment variable)", name, key, varsPath, SkillEnvPrefix))
+	}
+
 	return &Skill{
 		Name:     name,
 		Metadata: meta,
@@ -184,6 +217,116 @@ func LoadSkill(path string) (*Skill, error) {
 	}, nil
 }
 
+// UserCustomizationsHeading separates user additions from built-in skill content
+const UserCustomizationsHeading = "## User Customizations"
+
+// SplitUserCustomizations splits a skill body into the part before the
+// User Customizations heading and the (trimmed) customizations after it
+func SplitUserCustomizations(body string) (string, string) {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == UserCustomizationsHeading {
+			base := strings.TrimRight(strings.Join(lines[:i], "\n"), "\n")
+			custom := strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+			return base, custom
+		}
+	}
+	return body, ""
+}
+
+// JoinUserCustomizations appends customizations to a skill body under the
+// User Customizations heading
+func JoinUserCustomizations(base, custom string) string {
+	if custom == "" {
+		return base
+	}
+	return strings.TrimRight(base, "\n") + "\n\n" + UserCustomizationsHeading + "\n\n" + custom + "\n"
+}
+
+// skillVarRe matches ${VAR} placeholders, with an optional escaping $ ($${VAR})
+var skillVarRe = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// InterpolateSkillVars replaces ${VAR} placeholders in content using lookup.
+// Unresolved placeholders are left intact and their names returned.
+// $${VAR} is an escape that produces a literal ${VAR}.
+func InterpolateSkillVars(content string, lookup func(key string) (string, bool)) (string, []string) {
+	var unresolved []string
+	seen := make(map[string]bool)
+	result := skillVarRe.ReplaceAllStringFunc(content, func(match string) string {
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+		key := skillVarRe.FindStringSubmatch(match)[1]
+		if v, ok := lookup(key); ok {
+			return v
+		}
+		if !seen[key] {
+			seen[key] = true
+			unresolved = append(unresolved, key)
+		}
+		return match
+	})
+	return result, unresolved
+}
+
+// SkillEnvPrefix marks environment variables that skill content may read
+// without declaring them in the skill vars file
+const SkillEnvPrefix = "AGATE_"
+
+// skillVarLookup resolves a skill placeholder. Only variables declared in
+// the skill vars file or named with SkillEnvPrefix resolve, so skills,
+// whose content is sent to external agents, can't pull arbitrary secrets
+// out of the environment. The environment overrides a declared value.
+func skillVarLookup(vars map[string]string) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		declared, isDeclared := vars[key]
+		if !isDeclared && !strings.HasPrefix(key, SkillEnvPrefix) {
+			return "", false
+		}
+		if v, ok := os.LookupEnv(key); ok {
+			return v, true
+		}
+		return declared, isDeclared
+	}
+}
+
+// warnedOnce records the warnings warnOnce has printed
+var warnedOnce sync.Map
+
+// warnOnce prints msg to stderr the first time key is seen in this
+// process, so a warning about a skill isn't repeated on every load
+func warnOnce(key, msg string) {
+	if _, seen := warnedOnce.LoadOrStore(key, true); !seen {
+		fmt.Fprintln(os.Stderr, msg)
+	}
+}
+
+// LoadSkillVars reads "KEY: value" pairs from a skill vars file.
+// A missing file yields no vars.
+func LoadSkillVars(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	vars := make(map[string]string)
+	for i, line := range strings.Split(string(content), "\n") {
+		line = stripComment(line)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%s line %d: expected 'KEY: value'", path, i+1)
+		}
+		vars[strings.TrimSpace(parts[0])] = unquote(strings.TrimSpace(parts[1]))
+	}
+	return vars, nil
+}
+
 // LoadSkills loads all skills from a directory
 // User override mechanism: If both _foo.md and foo.md exist, the user's
 // foo.md content is appended to the built-in _foo.md content.
@@ -223,8 +366,12 @@ func LoadSkills(skillsDir string) ([]Skill, error) {
 		builtinName := "_" + name
 		builtin, hasBuiltin := skillMap[builtinName]
 		if hasBuiltin {
-			// Append user content to built-in content
-			builtin.Content = builtin.Content + "\n\n## User Customizations\n\n" + skill.Content
+			// Append user content to built-in content (after any inline customizations)
+			base, custom := SplitUserCustomizations(builtin.Content)
+			if custom != "" {
+				custom += "\n\n"
+			}
+			builtin.Content = JoinUserCustomizations(base, custom+skill.Content)
 			// Mark the standalone user override for removal (it's now merged)
 			toDelete = append(toDelete, name)
 		}
@@ -403,6 +550,114 @@ Write modern, clean JavaScript/TypeScript code and tests together.
 	}
 }
 
+func kotlinSkills() []Skill {
+	return []Skill{
+		{
+			Name: "kotlin-coder",
+			Metadata: SkillMetadata{
+				Name:                "kotlin-coder",
+				Agents:              []string{"claude", "codex"},
+				Phase:               "implement",
+				CanModifyCheckboxes: false,
+				Version:             1,
+			},
+			Content: `# Kotlin Coder
+
+Write idiomatic Kotlin code and tests together.
+
+## Style
+- Follow the Kotlin coding conventions
+- Prefer val over var and immutable collections
+- Use data classes for simple data holders
+- Use expression bodies and scope functions where they read clearly
+
+## Null Safety and Errors
+- Model absence with nullable types, not sentinel values
+- Avoid !! — use ?., ?:, and early returns instead
+- Use sealed classes or Result for expected failures
+
+## Concurrency
+- Use coroutines and structured concurrency, not raw threads
+
+## Testing
+- Write tests alongside your implementation — do NOT leave testing for a separate step
+- Use JUnit 5 or kotlin.test
+- Test happy path, error conditions, and edge cases
+` + CheckboxDisclaimer,
+		},
+	}
+}
+
+func swiftSkills() []Skill {
+	return []Skill{
+		{
+			Name: "swift-coder",
+			Metadata: SkillMetadata{
+				Name:                "swift-coder",
+				Agents:              []string{"claude", "codex"},
+				Phase:               "implement",
+				CanModifyCheckboxes: false,
+				Version:             1,
+			},
+			Content: `# Swift Coder
+
+Write safe, idiomatic Swift code and tests together.
+
+## Style
+- Follow the Swift API Design Guidelines
+- Prefer let over var and value types (structs, enums) over classes
+- Use protocols and extensions to share behavior
+
+## Optionals and Errors
+- Unwrap optionals with if let, guard let, or ??; avoid force unwrapping
+- Use throws and typed errors for recoverable failures
+
+## Concurrency
+- Use async/await and actors rather than callbacks and manual locking
+
+## Testing
+- Write tests alongside your implementation — do NOT leave testing for a separate step
+- Use XCTest (or Swift Testing) via Swift Package Manager
+- Test happy path, error conditions, and edge cases
+` + CheckboxDisclaimer,
+		},
+	}
+}
+
+func phpSkills() []Skill {
+	return []Skill{
+		{
+			Name: "php-coder",
+			Metadata: SkillMetadata{
+				Name:                "php-coder",
+				Agents:              []string{"claude", "codex"},
+				Phase:               "implement",
+				CanModifyCheckboxes: false,
+				Version:             1,
+			},
+			Content: `# PHP Coder
+
+Write modern, clean PHP code and tests together.
+
+## Style
+- Follow PSR-12 formatting and PSR-4 autoloading
+- Use declare(strict_types=1) and type declarations everywhere
+- Manage dependencies with Composer
+
+## Safety
+- Use prepared statements for database access, never string-built SQL
+- Escape output and validate all input
+- Throw exceptions for errors rather than returning false
+
+## Testing
+- Write tests alongside your implementation — do NOT leave testing for a separate step
+- Use PHPUnit (or Pest)
+- Test happy path, error conditions, and edge cases
+` + CheckboxDisclaimer,
+		},
+	}
+}
+
 func genericSkills() []Skill {
 	return []Skill{
 		{
@@ -681,6 +936,9 @@ when the task has been fully implemented and passes review.
 ## Output
 - SPRINT_COMPLETE if all goals met
 - ISSUES_FOUND with description if problems exist
+- If a task fails only because tasks are in the wrong order (e.g. it needs
+  a later task's work), add a line MOVE_TASK: <n> TO <m> after ISSUES_FOUND
+  to move task n to position m instead of rewriting the sprint
 `,
 		},
 		{
@@ -790,24 +1048,115 @@ Generate a structured retrospective with:
 	}
 }
 
+// builtinSumsName is the file in the skills directory recording a checksum
+// of each built-in skill as last written, to tell user edits from an
+// older built-in
+const builtinSumsName = ".builtin-sums.json"
+
 // EnsureBuiltinSkills writes all built-in skills to the skills directory
-// This is called on every agate command to ensure fresh built-ins
-func EnsureBuiltinSkills(skillsDir string) error {
+// This is called on every agate command to ensure fresh built-ins; files
+// already up to date, or written by a newer agate with a higher version,
+// are left untouched. A built-in the user edited outside its User
+// Customizations block is copied to .drafts/ before it is replaced; the
+// copies' paths are returned.
+func EnsureBuiltinSkills(skillsDir string) ([]string, error) {
 	// Ensure directory exists
 	if err := os.MkdirAll(skillsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create skills directory: %w", err)
+		return nil, fmt.Errorf("failed to create skills directory: %w", err)
+	}
+
+	sumsPath := filepath.Join(skillsDir, builtinSumsName)
+	sums := map[string]string{}
+	if data, err := os.ReadFile(sumsPath); err == nil {
+		json.Unmarshal(data, &sums)
 	}
+	sumsChanged := false
 
+	var backups []string
 	builtins := BuiltinSkills()
 	for _, skill := range builtins {
 		path := filepath.Join(skillsDir, skill.Name+".md")
-		content := FormatSkillWithFrontmatter(skill.Metadata, skill.Content)
+		body := skill.Content
+		// Keep a User Customizations block the user added to the builtin file
+		existing, readErr := os.ReadFile(path)
+		if readErr == nil {
+			oldMeta, oldBody := ParseSkillMetadata(string(existing))
+			if oldMeta.Version > skill.Metadata.Version {
+				continue // Never downgrade a newer agate's built-in
+			}
+			if _, custom := SplitUserCustomizations(oldBody); custom != "" {
+				body = JoinUserCustomizations(body, custom)
+			}
+		}
+		content := FormatSkillWithFrontmatter(skill.Metadata, body)
+		written := sums[skill.Name]
+		if sum := builtinSum(content); sum != written {
+			sums[skill.Name] = sum
+			sumsChanged = true
+		}
+		// Skip unchanged files so a no-op command touches nothing on disk
+		if readErr == nil && bytes.Equal(existing, []byte(content)) {
+			continue
+		}
+		if readErr == nil && builtinEdited(string(existing), content, written) {
+			backup, err := backupBuiltinSkill(skillsDir, skill.Name, existing)
+			if err != nil {
+				return backups, err
+			}
+			backups = append(backups, backup)
+		}
 		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-			return fmt.Errorf("failed to write builtin skill %s: %w", skill.Name, err)
+			return backups, fmt.Errorf("failed to write builtin skill %s: %w", skill.Name, err)
 		}
 	}
 
-	return nil
+	if sumsChanged {
+		data, _ := json.MarshalIndent(sums, "", "  ")
+		if err := os.WriteFile(sumsPath, append(data, '\n'), 0644); err != nil {
+			return backups, fmt.Errorf("failed to record builtin skill checksums: %w", err)
+		}
+	}
+	return backups, nil
+}
+
+// builtinSum checksums a built-in skill file without its User
+// Customizations block, the one part users are meant to edit
+func builtinSum(content string) string {
+	_, body := ParseSkillMetadata(content)
+	base, _ := SplitUserCustomizations(body)
+	frontmatter := content[:len(content)-len(body)]
+	sum := sha256.Sum256([]byte(frontmatter + strings.TrimRight(base, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// builtinEdited reports whether existing, a built-in skill file on disk,
+// differs from replacement by more than its User Customizations block and
+// isn't the built-in agate last wrote, whose checksum is written. Without a
+// recorded checksum (a project from before checksums were kept) there is
+// nothing to tell an edit from an older built-in, so none is reported.
+func builtinEdited(existing, replacement, written string) bool {
+	if written == "" {
+		return false
+	}
+	sum := builtinSum(existing)
+	if sum == builtinSum(replacement) {
+		return false
+	}
+	return sum != written
+}
+
+// backupBuiltinSkill copies a built-in skill's current content to
+// .drafts/ in the skills directory
+func backupBuiltinSkill(skillsDir, name string, content []byte) (string, error) {
+	drafts := filepath.Join(skillsDir, ".drafts")
+	if err := os.MkdirAll(drafts, 0755); err != nil {
+		return "", fmt.Errorf("failed to create skill drafts directory: %w", err)
+	}
+	backup := filepath.Join(drafts, fmt.Sprintf("%s.%s.md", name, time.Now().Format("20060102-150405")))
+	if err := os.WriteFile(backup, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to back up builtin skill %s: %w", name, err)
+	}
+	return backup, nil
 }
 
 // IsBuiltinSkill checks if a skill name is a built-in (has _ prefix)
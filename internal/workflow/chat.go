@@ -0,0 +1,63 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/strongdm/agate/internal/agent"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// ChatOptions contains options for an ad-hoc chat prompt
+type ChatOptions struct {
+	// PreferredAgent overrides automatic agent selection
+	PreferredAgent string
+}
+
+// Chat sends a one-off question to an agent in the project dir and returns
+// its response. Unlike Next it has no side effects on workflow state: no
+// sprint updates, no logs, and safe mode (no file writes) where the agent
+// supports it.
+func Chat(proj *project.Project, question string, opts ChatOptions) (string, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return "", fmt.Errorf("prompt cannot be empty")
+	}
+
+	selectedAgent := getSelectedAgent(PlanOptions{PreferredAgent: opts.PreferredAgent})
+	if selectedAgent == nil {
+		return "", agent.NoAgentsError{}
+	}
+
+	cfg, err := proj.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.TaskTimeout)
+	defer cancel()
+
+	prompt := buildChatPrompt(question)
+	var output string
+	if safeAgent, ok := selectedAgent.(agent.SafeModeAgent); ok {
+		output, err = safeAgent.ExecuteSafe(ctx, prompt, proj.Dir)
+	} else {
+		output, err = selectedAgent.Execute(ctx, prompt, proj.Dir)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %w", selectedAgent.Name(), err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// buildChatPrompt frames an ad-hoc question so the agent answers it
+// without changing the project
+func buildChatPrompt(question string) string {
+	return fmt.Sprintf(`You are answering a question about the software project in the working directory.
+Read whatever files you need, but do not create, modify, or delete any files.
+
+## Question
+
+%s
+`, question)
+}
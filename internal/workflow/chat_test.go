@@ -0,0 +1,74 @@
+package workflow
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// snapshotFiles maps every file under dir to its content
+func snapshotFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[path] = string(data)
+		return nil
+	})
+	return files
+}
+
+func TestChat_NoSideEffects(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Build\n  - [ ] go-coder: Write code\n",
+	})
+	before := snapshotFiles(t, tmpDir)
+
+	response, err := Chat(project.New(tmpDir), "Where is the config parsed?", ChatOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+	if response == "" {
+		t.Error("expected a response")
+	}
+
+	after := snapshotFiles(t, tmpDir)
+	if len(after) != len(before) {
+		t.Errorf("expected no new files, had %d now %d", len(before), len(after))
+	}
+	for path, content := range before {
+		if after[path] != content {
+			t.Errorf("expected %s unchanged", path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".ai", "logs")); !os.IsNotExist(err) {
+		t.Error("expected no logs to be written")
+	}
+}
+
+func TestChat_UninitializedProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := Chat(project.New(tmpDir), "What does this do?", ChatOptions{PreferredAgent: "dummy"}); err != nil {
+		t.Fatalf("chat failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".ai")); !os.IsNotExist(err) {
+		t.Error("expected no .ai directory to be created")
+	}
+}
+
+func TestChat_EmptyPrompt(t *testing.T) {
+	_, err := Chat(project.New(t.TempDir()), "  \n", ChatOptions{PreferredAgent: "dummy"})
+	if err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("expected empty prompt error, got: %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// ParseFilesWritten extracts the paths listed in a log's ## Files Written section
+func ParseFilesWritten(logContent string) []string {
+	var files []string
+	inSection := false
+	for _, line := range strings.Split(logContent, "\n") {
+		if strings.HasPrefix(line, "## ") {
+			inSection = strings.TrimSpace(line) == "## Files Written"
+			continue
+		}
+		if inSection && strings.HasPrefix(line, "- ") {
+			files = append(files, strings.TrimSpace(strings.TrimPrefix(line, "- ")))
+		}
+	}
+	return files
+}
+
+// listAllLogs returns every invocation log under the logs dir, oldest first
+// (ordered by sprint directory, then sequence-prefixed filename)
+func listAllLogs(proj *project.Project) ([]string, error) {
+	logsDir := proj.LogsDir()
+	sprintDirs, err := os.ReadDir(logsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, d := range sprintDirs {
+		if d.IsDir() && strings.HasPrefix(d.Name(), "sprint-") {
+			names = append(names, d.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var logs []string
+	for _, name := range names {
+		entries, err := os.ReadDir(filepath.Join(logsDir, name))
+		if err != nil {
+			continue
+		}
+		var files []string
+		for _, e := range entries {
+			if !e.IsDir() && filepath.Ext(e.Name()) == ".md" {
+				files = append(files, e.Name())
+			}
+		}
+		sort.Strings(files)
+		for _, f := range files {
+			logs = append(logs, filepath.Join(logsDir, name, f))
+		}
+	}
+	return logs, nil
+}
+
+// FindLatestLog returns the most recent invocation log and the files it wrote.
+// If last is false, it returns the most recent log that recorded written files.
+func FindLatestLog(proj *project.Project, last bool) (string, []string, error) {
+	logs, err := listAllLogs(proj)
+	if err != nil {
+		return "", nil, err
+	}
+	for i := len(logs) - 1; i >= 0; i-- {
+		content, err := os.ReadFile(logs[i])
+		if err != nil {
+			continue
+		}
+		files := ParseFilesWritten(string(content))
+		if last || len(files) > 0 {
+			return logs[i], files, nil
+		}
+	}
+	return "", nil, nil
+}
+
+// Diff summarizes the files changed by the most recent invocation.
+// Inside a git repository it shows git diff output for those paths;
+// otherwise it lists each file with its line count.
+func Diff(proj *project.Project, last bool) (string, error) {
+	projectDir := proj.Dir
+	logPath, files, err := FindLatestLog(proj, last)
+	if err != nil {
+		return "", fmt.Errorf("failed to read logs: %w", err)
+	}
+	if logPath == "" {
+		return "No invocations with written files found.\n", nil
+	}
+
+	var sb strings.Builder
+	rel, err := filepath.Rel(projectDir, logPath)
+	if err != nil {
+		rel = logPath
+	}
+	sb.WriteString(fmt.Sprintf("From %s\n\n", rel))
+	if len(files) == 0 {
+		sb.WriteString("No files written.\n")
+		return sb.String(), nil
+	}
+
+	if isGitRepo(projectDir) {
+		args := append([]string{"diff", "--stat", "--"}, files...)
+		if out, err := runGit(projectDir, args...); err == nil && strings.TrimSpace(out) != "" {
+			sb.WriteString(out)
+			sb.WriteString("\n")
+		}
+		// New files don't show up in git diff until they are tracked
+		args = append([]string{"ls-files", "--others", "--exclude-standard", "--"}, files...)
+		if out, err := runGit(projectDir, args...); err == nil {
+			for _, f := range strings.Fields(out) {
+				sb.WriteString(fmt.Sprintf(" %s (new, untracked)\n", f))
+			}
+		}
+		args = append([]string{"diff", "--"}, files...)
+		if out, err := runGit(projectDir, args...); err == nil && out != "" {
+			sb.WriteString("\n")
+			sb.WriteString(out)
+		}
+		return sb.String(), nil
+	}
+
+	for _, f := range files {
+		content, err := os.ReadFile(filepath.Join(projectDir, f))
+		if err != nil {
+			sb.WriteString(fmt.Sprintf(" %s | (missing)\n", f))
+			continue
+		}
+		lines := strings.Count(string(content), "\n")
+		if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+			lines++
+		}
+		sb.WriteString(fmt.Sprintf(" %s | %d lines\n", f, lines))
+	}
+	sb.WriteString(fmt.Sprintf(" %d file(s) written\n", len(files)))
+	return sb.String(), nil
+}
+
+// isGitRepo reports whether dir is inside a git work tree
+func isGitRepo(dir string) bool {
+	out, err := runGit(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
+// runGit runs a git command in dir and returns its stdout
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,178 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestParseAndWriteFiles_ReturnsPaths(t *testing.T) {
+	dir := t.TempDir()
+	output := "### File: main.go\n```go\npackage main\n```\n\n### File: internal/app/app.go\n```go\npackage app\n```\n"
+
+	files := parseAndWriteFiles(dir, "", output, StdoutReporter{})
+
+	if strings.Join(files, ",") != "main.go,internal/app/app.go" {
+		t.Errorf("unexpected files: %v", files)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "internal", "app", "app.go")); err != nil {
+		t.Errorf("expected file to be written: %v", err)
+	}
+}
+
+// writeInvocationLog writes a closed invocation log that recorded files
+func writeInvocationLog(t *testing.T, dir string, sprint int, files ...string) string {
+	t.Helper()
+	lf, err := logging.NewLogger(project.New(dir), sprint).StartInvocation("implement", "task", 0, "dummy", "go-coder", "summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		lf.AddFileWritten(f)
+	}
+	if err := lf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return lf.Path
+}
+
+func TestFindLatestLog(t *testing.T) {
+	dir := t.TempDir()
+	writeInvocationLog(t, dir, 1, "old.go")
+	withFiles := writeInvocationLog(t, dir, 2, "main.go", "go.mod")
+	last := writeInvocationLog(t, dir, 2) // Reviewer step, no files
+
+	path, files, err := FindLatestLog(project.New(dir), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != withFiles || strings.Join(files, ",") != "main.go,go.mod" {
+		t.Errorf("expected latest log with files, got %s %v", path, files)
+	}
+
+	path, files, err = FindLatestLog(project.New(dir), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != last || len(files) != 0 {
+		t.Errorf("expected very last log, got %s %v", path, files)
+	}
+}
+
+func TestDiff_NoRepo(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeInvocationLog(t, dir, 1, "main.go")
+
+	output, err := Diff(project.New(dir), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "main.go | 3 lines") {
+		t.Errorf("expected file summary, got:\n%s", output)
+	}
+}
+
+func TestParseAndWriteFiles_Root(t *testing.T) {
+	dir := t.TempDir()
+	output := "### File: main.go\n```go\npackage main\n```\n\n### File: internal/app/app.go\n```go\npackage app\n```\n"
+
+	files := parseAndWriteFiles(dir, filepath.Join("services", "api"), output, StdoutReporter{})
+
+	if strings.Join(files, ",") != "services/api/main.go,services/api/internal/app/app.go" {
+		t.Errorf("unexpected files: %v", files)
+	}
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(f))); err != nil {
+			t.Errorf("expected %s to be written: %v", f, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "main.go")); !os.IsNotExist(err) {
+		t.Errorf("expected nothing written at the project root, stat err: %v", err)
+	}
+}
+
+func TestParseAndWriteFiles_RejectsTraversal(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "repo")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	output := "### File: ../../../escape.go\n```go\npackage escape\n```\n\n" +
+		"### File: " + filepath.Join(parent, "abs.go") + "\n```go\npackage abs\n```\n\n" +
+		"### File: ../shared/util.go\n```go\npackage shared\n```\n"
+
+	files := parseAndWriteFiles(dir, filepath.Join("services", "api"), output, StdoutReporter{})
+
+	// Leaving the root is fine while the path stays inside the project
+	if strings.Join(files, ",") != "services/shared/util.go" {
+		t.Errorf("unexpected files: %v", files)
+	}
+	for _, name := range []string{"escape.go", "abs.go"} {
+		if _, err := os.Stat(filepath.Join(parent, name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be written outside the project, stat err: %v", name, err)
+		}
+	}
+}
+
+func TestParseAndWriteFiles_SkipsIgnored(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".agateignore"), []byte("vendor/\n*.pb.go\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	vendored := filepath.Join(dir, "vendor", "lib", "lib.go")
+	if err := os.MkdirAll(filepath.Dir(vendored), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(vendored, []byte("package lib\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output := "### File: main.go\n```go\npackage main\n```\n\n" +
+		"### File: api/api.pb.go\n```go\npackage api\n```\n\n" +
+		"### File: vendor/lib/lib.go\n```diff\n@@ -1 +1 @@\n-package lib\n+package patched\n```\n"
+
+	files := parseAndWriteFiles(dir, "", output, StdoutReporter{})
+
+	if strings.Join(files, ",") != "main.go" {
+		t.Errorf("expected only main.go written, got %v", files)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "api", "api.pb.go")); !os.IsNotExist(err) {
+		t.Errorf("expected ignored file not to be written, stat err: %v", err)
+	}
+	if data, _ := os.ReadFile(vendored); string(data) != "package lib\n" {
+		t.Errorf("expected ignored file not to be patched, got %q", data)
+	}
+}
+
+func TestResolveOutputPath(t *testing.T) {
+	tests := []struct {
+		root, file string
+		want       string
+		wantErr    bool
+	}{
+		{"", "main.go", "main.go", false},
+		{"", "./cmd/../main.go", "main.go", false},
+		{"services/api", "main.go", "services/api/main.go", false},
+		{"services/api", "../../go.work", "go.work", false},
+		{"", "../main.go", "", true},
+		{"services/api", "../../../main.go", "", true},
+		{"", "/etc/passwd", "", true},
+		{"", ".", "", true},
+	}
+	for _, tt := range tests {
+		got, err := resolveOutputPath(filepath.FromSlash(tt.root), tt.file)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveOutputPath(%q, %q) error = %v, wantErr %v", tt.root, tt.file, err, tt.wantErr)
+			continue
+		}
+		if filepath.ToSlash(got) != tt.want {
+			t.Errorf("resolveOutputPath(%q, %q) = %q, want %q", tt.root, tt.file, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/strongdm/agate/internal/agent"
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// doctorAgents lists the real agents and the CLI binary each one needs
+var doctorAgents = []struct {
+	Name string
+	CLI  string
+}{
+	{"claude", "claude"},
+	{"haiku", "claude"},
+	{"codex", "codex"},
+}
+
+// AgentCheck is the doctor result for a single agent
+type AgentCheck struct {
+	Name      string
+	CLI       string
+	Installed bool
+	Version   string // Output of "<cli> --version" (empty if the probe failed)
+	Error     string // Why the agent is unusable (empty if ready)
+}
+
+// Ready returns true if the agent CLI is installed and responds to a version probe
+func (c AgentCheck) Ready() bool {
+	return c.Installed && c.Error == ""
+}
+
+// DoctorReport summarizes environment readiness for running agate
+type DoctorReport struct {
+	Agents      []AgentCheck
+	HasGoal     bool
+	GoalError   string // Why GOAL.md can't be used (empty if readable or missing)
+	AIWritable  bool
+	AIError     string // Why the state dir is not writable (empty if writable)
+	StateDir    string // The state dir, relative to the project (e.g. ".ai")
+	Language    string
+	ProjectType string
+}
+
+// Ready returns true if at least one real agent is available and GOAL.md
+// exists and is readable
+func (r *DoctorReport) Ready() bool {
+	return r.HasGoal && r.GoalError == "" && r.AgentsReady() > 0
+}
+
+// AgentsReady returns the number of agents that passed their checks
+func (r *DoctorReport) AgentsReady() int {
+	n := 0
+	for _, a := range r.Agents {
+		if a.Ready() {
+			n++
+		}
+	}
+	return n
+}
+
+// Doctor checks whether the environment is ready to run agate in projectDir
+func Doctor(proj *project.Project) *DoctorReport {
+	report := &DoctorReport{}
+
+	// Probe each CLI once, even if several agents share it
+	probes := make(map[string]AgentCheck)
+	for _, a := range doctorAgents {
+		check, ok := probes[a.CLI]
+		if !ok {
+			check = AgentCheck{CLI: a.CLI, Installed: agent.CheckCLI(a.CLI)}
+			if check.Installed {
+				version, err := agent.ProbeVersion(a.CLI)
+				if err != nil {
+					check.Error = err.Error()
+				} else {
+					check.Version = version
+				}
+			} else {
+				check.Error = fmt.Sprintf("%s CLI not found in PATH", a.CLI)
+			}
+			probes[a.CLI] = check
+		}
+		check.Name = a.Name
+		report.Agents = append(report.Agents, check)
+	}
+
+	report.HasGoal = proj.HasGoal()
+	if report.HasGoal {
+		if err := proj.CheckGoal(); err != nil {
+			report.GoalError = err.Error()
+		} else if goal, err := project.ParseGoal(proj.GoalPath()); err == nil {
+			report.Language = goal.Language
+			report.ProjectType = goal.Type
+		}
+	}
+
+	report.StateDir = proj.StateDir()
+	if err := checkWritable(proj.DataDir()); err != nil {
+		report.AIError = err.Error()
+	} else {
+		report.AIWritable = true
+	}
+
+	return report
+}
+
+// checkWritable verifies that files can be created in dir.
+// If dir doesn't exist yet, its parent must be writable so it can be created.
+func checkWritable(dir string) error {
+	target := dir
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		target = filepath.Dir(dir)
+	} else if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(target, ".agate-doctor-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// FormatDoctor renders a doctor report for display
+func FormatDoctor(r *DoctorReport) string {
+	var sb strings.Builder
+
+	sb.WriteString(logging.Bold("AGENTS"))
+	sb.WriteString("\n")
+	for _, a := range r.Agents {
+		if a.Ready() {
+			detail := a.CLI
+			if a.Version != "" {
+				detail = fmt.Sprintf("%s %s", a.CLI, a.Version)
+			}
+			sb.WriteString(fmt.Sprintf("  %s %-7s %s\n", logging.Green("+"), a.Name, logging.Dim(detail)))
+		} else {
+			sb.WriteString(fmt.Sprintf("  %s %-7s %s\n", logging.Red("x"), a.Name, logging.Dim(a.Error)))
+		}
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(logging.Bold("PROJECT"))
+	sb.WriteString("\n")
+	if r.HasGoal && r.GoalError != "" {
+		sb.WriteString(fmt.Sprintf("  %s GOAL.md unusable %s\n", logging.Red("x"), logging.Dim(r.GoalError)))
+	} else if r.HasGoal {
+		sb.WriteString(fmt.Sprintf("  %s GOAL.md present\n", logging.Green("+")))
+	} else {
+		sb.WriteString(fmt.Sprintf("  %s GOAL.md missing\n", logging.Red("x")))
+	}
+	if r.AIWritable {
+		sb.WriteString(fmt.Sprintf("  %s %s writable\n", logging.Green("+"), r.StateDir))
+	} else {
+		sb.WriteString(fmt.Sprintf("  %s %s not writable %s\n", logging.Red("x"), r.StateDir, logging.Dim(r.AIError)))
+	}
+	if r.HasGoal {
+		language := r.Language
+		if language == "" {
+			language = "unknown"
+		}
+		projectType := r.ProjectType
+		if projectType == "" {
+			projectType = "unknown"
+		}
+		sb.WriteString(fmt.Sprintf("  Language: %s\n", language))
+		sb.WriteString(fmt.Sprintf("  Type:     %s\n", projectType))
+	}
+	sb.WriteString("\n")
+
+	if r.Ready() {
+		sb.WriteString(logging.Green("Ready to run 'agate auto'"))
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString(logging.Yellow("Not ready:"))
+		sb.WriteString("\n")
+		if r.AgentsReady() == 0 {
+			sb.WriteString("  - No AI agents available (install claude or codex CLI)\n")
+		}
+		if !r.HasGoal {
+			sb.WriteString("  - Create GOAL.md describing what you want to build\n")
+		}
+	}
+
+	return sb.String()
+}
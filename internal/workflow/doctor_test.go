@@ -0,0 +1,144 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// installStubCLI writes an executable shell script named name into dir.
+// The script prints version on --version and exits with exitCode.
+func installStubCLI(t *testing.T, dir, name, version string, exitCode int) {
+	t.Helper()
+	script := "#!/bin/sh\necho '" + version + "'\nexit " + strconv.Itoa(exitCode) + "\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// doctorProject creates a project dir, optionally with GOAL.md
+func doctorProject(t *testing.T, goal string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if goal != "" {
+		if err := os.WriteFile(filepath.Join(dir, "GOAL.md"), []byte(goal), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func findAgentCheck(r *DoctorReport, name string) AgentCheck {
+	for _, a := range r.Agents {
+		if a.Name == name {
+			return a
+		}
+	}
+	return AgentCheck{}
+}
+
+func TestDoctor_AgentAvailable(t *testing.T) {
+	bin := t.TempDir()
+	installStubCLI(t, bin, "claude", "1.2.3 (Claude Code)", 0)
+	t.Setenv("PATH", bin)
+
+	dir := doctorProject(t, "Build a CLI in Go.")
+	report := Doctor(project.New(dir))
+
+	claude := findAgentCheck(report, "claude")
+	if !claude.Ready() {
+		t.Fatalf("expected claude to be ready, got error %q", claude.Error)
+	}
+	if claude.Version != "1.2.3 (Claude Code)" {
+		t.Errorf("expected version from probe, got %q", claude.Version)
+	}
+	if !findAgentCheck(report, "haiku").Ready() {
+		t.Error("expected haiku to be ready (shares claude CLI)")
+	}
+	if findAgentCheck(report, "codex").Ready() {
+		t.Error("expected codex to be unavailable")
+	}
+
+	if !report.HasGoal || !report.AIWritable {
+		t.Errorf("expected goal and writable .ai, got %+v", report)
+	}
+	if report.Language != "go" || report.ProjectType != "cli" {
+		t.Errorf("expected go/cli, got %s/%s", report.Language, report.ProjectType)
+	}
+	if !report.Ready() {
+		t.Error("expected report to be ready")
+	}
+}
+
+func TestDoctor_NoAgents(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	report := Doctor(project.New(doctorProject(t, "Build a CLI in Go.")))
+
+	if report.AgentsReady() != 0 {
+		t.Errorf("expected no agents ready, got %d", report.AgentsReady())
+	}
+	if report.Ready() {
+		t.Error("expected report not to be ready without agents")
+	}
+}
+
+func TestDoctor_BrokenAgent(t *testing.T) {
+	bin := t.TempDir()
+	installStubCLI(t, bin, "codex", "not logged in", 1)
+	t.Setenv("PATH", bin)
+
+	report := Doctor(project.New(doctorProject(t, "Build a CLI in Go.")))
+
+	codex := findAgentCheck(report, "codex")
+	if !codex.Installed {
+		t.Error("expected codex to be detected as installed")
+	}
+	if codex.Ready() {
+		t.Error("expected codex to fail the version probe")
+	}
+	if report.Ready() {
+		t.Error("expected report not to be ready when the only agent is broken")
+	}
+}
+
+func TestDoctor_MissingGoal(t *testing.T) {
+	bin := t.TempDir()
+	installStubCLI(t, bin, "codex", "codex 0.9", 0)
+	t.Setenv("PATH", bin)
+
+	report := Doctor(project.New(doctorProject(t, "")))
+
+	if report.HasGoal {
+		t.Error("expected HasGoal to be false")
+	}
+	if report.Ready() {
+		t.Error("expected report not to be ready without GOAL.md")
+	}
+}
+
+func TestDoctor_GoalIsDirectory(t *testing.T) {
+	bin := t.TempDir()
+	installStubCLI(t, bin, "codex", "codex 0.9", 0)
+	t.Setenv("PATH", bin)
+
+	dir := doctorProject(t, "")
+	if err := os.Mkdir(filepath.Join(dir, "GOAL.md"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	report := Doctor(project.New(dir))
+
+	if !strings.Contains(report.GoalError, "is a directory") {
+		t.Errorf("expected directory goal error, got %q", report.GoalError)
+	}
+	if report.Ready() {
+		t.Error("expected report not to be ready with an unusable GOAL.md")
+	}
+	if !strings.Contains(FormatDoctor(report), "GOAL.md unusable") {
+		t.Errorf("expected unusable goal in output:\n%s", FormatDoctor(report))
+	}
+}
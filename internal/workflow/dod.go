@@ -0,0 +1,338 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/strongdm/agate/internal/agent"
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// dodVerifiedMarker is written under the Definition of Done heading once a
+// reviewer has confirmed the criteria, so the gate runs only once per sprint
+const dodVerifiedMarker = "<!-- dod: verified -->"
+
+// maxDoDReopens is how many times a failed Definition of Done review may
+// re-open tasks before a human is asked to step in
+const maxDoDReopens = 3
+
+// dodReopensRe matches the marker counting how many times a failed
+// Definition of Done review has re-opened tasks
+var dodReopensRe = regexp.MustCompile(`^<!-- dod: reopened (\d+) -->$`)
+
+var dodHeadingRe = regexp.MustCompile(`(?i)^##\s+definition of done\s*:?\s*$`)
+
+// definitionOfDoneRange returns the line indexes [start, end) of the
+// "## Definition of Done" section, where start is the heading line.
+// The section ends at the next level 1 or 2 heading.
+func definitionOfDoneRange(lines []string) (start, end int, ok bool) {
+	start = -1
+	for i, line := range lines {
+		if start < 0 {
+			if dodHeadingRe.MatchString(strings.TrimSpace(line)) {
+				start = i
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "# ") || strings.HasPrefix(line, "## ") {
+			return start, i, true
+		}
+	}
+	if start < 0 {
+		return 0, 0, false
+	}
+	return start, len(lines), true
+}
+
+// parseDefinitionOfDone fills in the sprint's Definition of Done criteria
+// and whether they have already been verified or re-opened
+func parseDefinitionOfDone(state *SprintState) {
+	lines := strings.Split(state.Content, "\n")
+	start, end, ok := definitionOfDoneRange(lines)
+	if !ok {
+		return
+	}
+
+	var body []string
+	for _, line := range lines[start+1 : end] {
+		if strings.TrimSpace(line) == dodVerifiedMarker {
+			state.DoDVerified = true
+			continue
+		}
+		if m := dodReopensRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			state.DoDReopens, _ = strconv.Atoi(m[1])
+			continue
+		}
+		body = append(body, line)
+	}
+	state.DefinitionOfDone = strings.TrimSpace(strings.Join(body, "\n"))
+}
+
+// DoDPending reports whether the sprint has a Definition of Done that no
+// reviewer has verified yet
+func (s *SprintState) DoDPending() bool {
+	return s.DefinitionOfDone != "" && !s.DoDVerified
+}
+
+// MarkDoDVerified records that the Definition of Done has been verified
+func (s *SprintState) MarkDoDVerified() error {
+	if s.DoDVerified {
+		return nil
+	}
+	lines := strings.Split(s.Content, "\n")
+	start, _, ok := definitionOfDoneRange(lines)
+	if !ok {
+		return fmt.Errorf("sprint has no Definition of Done section")
+	}
+
+	// Inserting a line shifts the task line numbers after it, so re-parse
+	out := make([]string, 0, len(lines)+1)
+	out = append(out, lines[:start+1]...)
+	out = append(out, dodVerifiedMarker)
+	out = append(out, lines[start+1:]...)
+	content := strings.Join(out, "\n")
+	if err := os.WriteFile(s.FilePath, []byte(content), 0644); err != nil {
+		return err
+	}
+	updated, err := ParseSprintContent(content)
+	if err != nil {
+		return err
+	}
+	updated.FilePath = s.FilePath
+	*s = *updated
+	return nil
+}
+
+// RecordDoDReopen counts one more Definition of Done re-open in the sprint
+// file, under the Definition of Done heading
+func (s *SprintState) RecordDoDReopen() error {
+	lines := strings.Split(s.Content, "\n")
+	start, end, ok := definitionOfDoneRange(lines)
+	if !ok {
+		return fmt.Errorf("sprint has no Definition of Done section")
+	}
+
+	marker := fmt.Sprintf("<!-- dod: reopened %d -->", s.DoDReopens+1)
+	replaced := false
+	for i := start + 1; i < end; i++ {
+		if dodReopensRe.MatchString(strings.TrimSpace(lines[i])) {
+			lines[i] = marker
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		// Inserting a line shifts the task line numbers after it
+		lines = append(lines[:start+1], append([]string{marker}, lines[start+1:]...)...)
+	}
+	content := strings.Join(lines, "\n")
+	if err := os.WriteFile(s.FilePath, []byte(content), 0644); err != nil {
+		return err
+	}
+	updated, err := ParseSprintContent(content)
+	if err != nil {
+		return err
+	}
+	updated.FilePath = s.FilePath
+	*s = *updated
+	return nil
+}
+
+// ReopenTask unchecks a task and all of its sub-tasks, adds a failure marker,
+// and records reason on its first sub-task so the next attempt sees it
+func (s *SprintState) ReopenTask(taskIndex int, reason string) error {
+	if taskIndex < 0 || taskIndex >= len(s.Tasks) {
+		return fmt.Errorf("invalid task index: %d", taskIndex)
+	}
+	task := &s.Tasks[taskIndex]
+
+	if err := s.uncheckLineAt(task.LineNum); err != nil {
+		return err
+	}
+	task.Checked = false
+	for i := range task.SubTasks {
+		if err := s.uncheckLineAt(task.SubTasks[i].LineNum); err != nil {
+			return err
+		}
+		task.SubTasks[i].Checked = false
+	}
+	if err := s.AddFailure(taskIndex); err != nil {
+		return err
+	}
+	if reason != "" && len(task.SubTasks) > 0 {
+		return s.AnnotateFailure(taskIndex, 0, reason)
+	}
+	return nil
+}
+
+// parseReopenTasks returns the 0-based task indexes listed on a
+// "REOPEN: 1, 3" line of a Definition of Done review (1-based numbers).
+// Out-of-range numbers are ignored.
+func parseReopenTasks(output string, numTasks int) []int {
+	for _, line := range strings.Split(output, "\n") {
+		idx := strings.Index(strings.ToUpper(line), "REOPEN:")
+		if idx < 0 {
+			continue
+		}
+		var tasks []int
+		seen := make(map[int]bool)
+		for _, f := range strings.FieldsFunc(line[idx+len("REOPEN:"):], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		}) {
+			n, err := strconv.Atoi(strings.TrimPrefix(f, "#"))
+			if err != nil || n < 1 || n > numTasks || seen[n-1] {
+				continue
+			}
+			seen[n-1] = true
+			tasks = append(tasks, n-1)
+		}
+		return tasks
+	}
+	return nil
+}
+
+// buildDoDReviewPrompt asks a reviewer to verify the Definition of Done
+// against the implementation once every task in the sprint is checked
+func buildDoDReviewPrompt(sprint *SprintState, designContent, skillContent string) string {
+	var sb strings.Builder
+
+	sb.WriteString("You are reviewing a completed sprint of a software project.\n\n")
+
+	if designContent != "" {
+		sb.WriteString("## Design Context\n\n")
+		sb.WriteString(designContent)
+		sb.WriteString("\n\n")
+	}
+
+	if skillContent != "" {
+		sb.WriteString("## Skill Guidelines\n\n")
+		sb.WriteString(skillContent)
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString("## Sprint Tasks\n\n")
+	for i, task := range sprint.Tasks {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, task.Text))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString("## Definition of Done\n\n")
+	sb.WriteString(sprint.DefinitionOfDone)
+	sb.WriteString("\n\n")
+
+	sb.WriteString(`## Instructions
+
+All tasks above are checked off. Verify every Definition of Done criterion
+against the implementation in the working directory.
+
+If every criterion is met, respond with: APPROVED
+If any criterion is not met, respond with:
+ISSUES_FOUND: <which criteria are unmet and why>
+REOPEN: <comma-separated numbers of the tasks that must be redone>
+`)
+
+	return sb.String()
+}
+
+// verifyDefinitionOfDone runs one reviewer pass over a complete sprint's
+// Definition of Done. It returns nil if the criteria are met (or the sprint
+// has none); otherwise it re-opens the failing tasks and returns a Result
+// asking for more work. Once the review has re-opened tasks maxDoDReopens
+// times, a further failure returns a HumanNeededError instead.
+func verifyDefinitionOfDone(projectDir string, proj *project.Project, sprint *SprintState, sprintNum int, opts NextOptions) (*Result, error) {
+	if !sprint.DoDPending() {
+		return nil, nil
+	}
+
+	skills, _ := project.LoadSkills(proj.SkillsDir())
+	selectedAgent, err := selectAgent(opts.PreferredAgent, "_reviewer", skills, opts.reporter())
+	if err != nil {
+		return nil, err
+	}
+	selectedAgent = opts.ModelOptions.Apply(selectedAgent)
+
+	designContent := ""
+	if designPath := filepath.Join(proj.DesignDir(), "overview.md"); fileExists(designPath) {
+		if content, err := os.ReadFile(designPath); err == nil {
+			designContent = string(content)
+		}
+	}
+	prompt := buildDoDReviewPrompt(sprint, designContent, getSkillContent(skills, "_reviewer"))
+
+	cfg, err := proj.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.TaskTimeout)
+	defer cancel()
+
+	execResult := agent.ExecuteWithLogging(ctx, selectedAgent, prompt, projectDir, agent.ExecuteOptions{
+		Logger:        logging.NewLogger(proj, sprintNum),
+		Phase:         "dod",
+		Task:          "Verify Definition of Done",
+		TaskIndex:     0,
+		Skill:         "_reviewer",
+		PromptSummary: "Verifying Definition of Done",
+		StreamWriter:  opts.StreamOutput,
+		Events:        opts.Events,
+	})
+	if execResult.Error != nil {
+		return nil, fmt.Errorf("failed to verify Definition of Done: %w", execResult.Error)
+	}
+
+	if approved, _ := reviewOutcome(execResult.Output, opts.StrictReview); approved {
+		if err := sprint.MarkDoDVerified(); err != nil {
+			return nil, fmt.Errorf("failed to record Definition of Done verification: %w", err)
+		}
+		opts.reporter().Success("✓ Definition of Done verified")
+		return nil, nil
+	}
+
+	if sprint.DoDReopens >= maxDoDReopens {
+		_, reason := parseReviewOutcome(execResult.Output)
+		return nil, &HumanNeededError{
+			Message: fmt.Sprintf("Definition of Done is still not met after re-opening tasks %d times (max %d), human intervention needed: %s", sprint.DoDReopens, maxDoDReopens, reason),
+		}
+	}
+	reopened := reopenForDoD(sprint, execResult.Output, opts.reporter())
+	if err := sprint.RecordDoDReopen(); err != nil {
+		return nil, fmt.Errorf("failed to record Definition of Done re-open: %w", err)
+	}
+	return &Result{
+		Message:  fmt.Sprintf("Definition of Done not met. Re-opened %d task(s). Run 'agate next' to continue.", reopened),
+		MoreWork: true,
+		ExitCode: ExitMoreWork,
+	}, nil
+}
+
+// reopenForDoD re-opens the tasks a failed Definition of Done review asked
+// for (the last task if it named none) and returns how many were re-opened
+func reopenForDoD(sprint *SprintState, output string, report Reporter) int {
+	report.Warn("⚠ Definition of Done not met. Re-opening tasks...")
+
+	tasks := parseReopenTasks(output, len(sprint.Tasks))
+	if len(tasks) == 0 && len(sprint.Tasks) > 0 {
+		tasks = []int{len(sprint.Tasks) - 1}
+	}
+
+	_, reason := parseReviewOutcome(output)
+	if reason != "" {
+		reason = "Definition of Done: " + reason
+	}
+
+	reopened := 0
+	for _, i := range tasks {
+		if err := sprint.ReopenTask(i, reason); err != nil {
+			report.Warn(fmt.Sprintf("Warning: failed to re-open task %d: %v", i+1, err))
+			continue
+		}
+		reopened++
+	}
+	return reopened
+}
@@ -0,0 +1,304 @@
+package workflow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+const dodSprint = `# Sprint 1
+
+## Tasks
+
+- [x] Build parser
+  - [x] go-coder: Write parser
+  - [x] _reviewer: Review parser
+
+- [x] Add CLI
+  - [x] go-coder: Wire up CLI
+  - [x] _reviewer: Review CLI
+
+## Definition of Done
+
+- [ ] ` + "`go test ./...`" + ` passes
+- [ ] CLI prints usage with --help
+
+## Notes
+
+Nothing else.
+`
+
+func TestParseSprintContent_DefinitionOfDone(t *testing.T) {
+	state, err := ParseSprintContent(dodSprint)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- [ ] `go test ./...` passes\n- [ ] CLI prints usage with --help"
+	if state.DefinitionOfDone != want {
+		t.Errorf("DefinitionOfDone = %q, want %q", state.DefinitionOfDone, want)
+	}
+	if state.DoDVerified {
+		t.Error("expected DoD to be unverified")
+	}
+	// DoD checkboxes are criteria, not tasks
+	if len(state.Tasks) != 2 {
+		t.Errorf("expected 2 tasks, got %d", len(state.Tasks))
+	}
+	if !state.IsComplete() {
+		t.Error("expected sprint tasks to be complete")
+	}
+}
+
+func TestParseSprintContent_NoDefinitionOfDone(t *testing.T) {
+	state, err := ParseSprintContent("# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Work\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.DefinitionOfDone != "" {
+		t.Errorf("expected empty DefinitionOfDone, got %q", state.DefinitionOfDone)
+	}
+}
+
+func TestParseSprintContent_DefinitionOfDoneTable(t *testing.T) {
+	content := "# Sprint 1\n\n| Task | Skill | Status |\n|---|---|---|\n| Build | | [x] |\n| Write | go-coder | [x] |\n\n## Definition of Done\n\n- [ ] Builds cleanly\n"
+	state, err := ParseSprintContent(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Format != SprintFormatTable {
+		t.Errorf("DoD checkboxes should not prevent table detection, got format %q", state.Format)
+	}
+	if state.DefinitionOfDone != "- [ ] Builds cleanly" {
+		t.Errorf("unexpected DefinitionOfDone %q", state.DefinitionOfDone)
+	}
+}
+
+func TestMarkDoDVerified(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "01-initial.md")
+	if err := os.WriteFile(path, []byte(dodSprint), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sprint, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sprint.MarkDoDVerified(); err != nil {
+		t.Fatalf("MarkDoDVerified failed: %v", err)
+	}
+
+	reparsed, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reparsed.DoDVerified {
+		t.Error("expected DoD to be verified after reparse")
+	}
+	if strings.Contains(reparsed.DefinitionOfDone, dodVerifiedMarker) {
+		t.Errorf("marker should not appear in criteria: %q", reparsed.DefinitionOfDone)
+	}
+	// The in-memory state follows the file, as it would after a re-parse
+	if !reflect.DeepEqual(sprint.Tasks, reparsed.Tasks) || sprint.Content != reparsed.Content {
+		t.Errorf("expected the sprint state to match the file after marking")
+	}
+}
+
+func TestParseReopenTasks(t *testing.T) {
+	tests := []struct {
+		output string
+		want   []int
+	}{
+		{"ISSUES_FOUND: tests fail\nREOPEN: 2", []int{1}},
+		{"REOPEN: 1, #2, 2, 9", []int{0, 1}},
+		{"Reopen: 1 2", []int{0, 1}},
+		{"ISSUES_FOUND: tests fail", nil},
+	}
+	for _, tt := range tests {
+		if got := parseReopenTasks(tt.output, 2); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseReopenTasks(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestReopenForDoD(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "01-initial.md")
+	if err := os.WriteFile(path, []byte(dodSprint), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sprint, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := reopenForDoD(sprint, "ISSUES_FOUND: --help prints nothing\nREOPEN: 2", StdoutReporter{})
+	if n != 1 {
+		t.Errorf("expected 1 task re-opened, got %d", n)
+	}
+
+	reparsed, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reparsed.Tasks[0].Checked {
+		t.Error("task 1 should stay checked")
+	}
+	task := reparsed.Tasks[1]
+	if task.Checked || task.SubTasks[0].Checked || task.SubTasks[1].Checked {
+		t.Errorf("task 2 and its sub-tasks should be unchecked: %+v", task)
+	}
+	if task.FailureCount != 1 {
+		t.Errorf("expected a failure marker, got %d", task.FailureCount)
+	}
+	if task.SubTasks[0].FailureReason != "Definition of Done: --help prints nothing" {
+		t.Errorf("unexpected failure reason %q", task.SubTasks[0].FailureReason)
+	}
+}
+
+func TestReopenForDoD_DefaultsToLastTask(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "01-initial.md")
+	if err := os.WriteFile(path, []byte(dodSprint), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sprint, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reopenForDoD(sprint, "Criteria not met.", StdoutReporter{})
+
+	reparsed, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reparsed.Tasks[0].Checked || reparsed.Tasks[1].Checked {
+		t.Error("expected only the last task to be re-opened")
+	}
+}
+
+// TestVerifyDefinitionOfDone_DummyApproves runs the DoD gate with the dummy
+// agent, which approves, and checks the verification is persisted.
+func TestVerifyDefinitionOfDone_DummyApproves(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{"01-initial.md": dodSprint})
+	proj := project.New(tmpDir)
+	sprintPath := filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md")
+	sprint, err := ParseSprint(sprintPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := verifyDefinitionOfDone(tmpDir, proj, sprint, 1, NextOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != nil {
+		t.Fatalf("expected the gate to pass, got: %s", result.Message)
+	}
+
+	reparsed, err := ParseSprint(sprintPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reparsed.DoDVerified || !reparsed.IsComplete() {
+		t.Error("expected a verified, complete sprint")
+	}
+
+	logs, _ := os.ReadDir(filepath.Join(tmpDir, ".ai", "logs", "sprint-001"))
+	if len(logs) != 1 || !strings.Contains(logs[0].Name(), "-dod-") {
+		t.Errorf("expected one dod log, got %v", logs)
+	}
+}
+
+// TestAssessGoalAndPlanNext_DoDVerifiedOnce checks the gate is skipped once
+// the sprint carries the verified marker.
+func TestAssessGoalAndPlanNext_DoDVerifiedOnce(t *testing.T) {
+	verified := strings.Replace(dodSprint, "## Definition of Done\n", "## Definition of Done\n"+dodVerifiedMarker+"\n", 1)
+	tmpDir := setupExecutionProject(t, map[string]string{"01-initial.md": verified})
+	proj := project.New(tmpDir)
+
+	// Only the planner should have been invoked
+	if _, err := assessGoalAndPlanNext(tmpDir, proj, 1, NextOptions{PreferredAgent: "dummy"}); err != nil {
+		t.Fatalf("assessment failed: %v", err)
+	}
+
+	logs, _ := os.ReadDir(filepath.Join(tmpDir, ".ai", "logs", "sprint-001"))
+	for _, l := range logs {
+		if strings.Contains(l.Name(), "-dod-") {
+			t.Errorf("DoD review should not run again, found %s", l.Name())
+		}
+	}
+}
+
+// TestVerifyDefinitionOfDone_EscalatesAfterMaxReopens verifies a Definition
+// of Done that never passes re-opens tasks maxDoDReopens times, counted in
+// the sprint file, and then asks for a human instead of looping.
+func TestVerifyDefinitionOfDone_EscalatesAfterMaxReopens(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{"01-initial.md": dodSprint})
+	proj := project.New(tmpDir)
+	sprintPath := filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md")
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "printf 'ISSUES_FOUND: --help prints nothing\\nREOPEN: 2\\n'\n")
+	t.Setenv("PATH", bin)
+
+	for i := 1; i <= maxDoDReopens+1; i++ {
+		sprint, err := ParseSprint(sprintPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := verifyDefinitionOfDone(tmpDir, proj, sprint, 1, NextOptions{PreferredAgent: "claude"})
+		if i <= maxDoDReopens {
+			if err != nil || result == nil || result.ExitCode != ExitMoreWork {
+				t.Fatalf("review %d: expected more work, got %+v, %v", i, result, err)
+			}
+			continue
+		}
+		var human *HumanNeededError
+		if !errors.As(err, &human) || !strings.Contains(human.Message, "--help prints nothing") {
+			t.Fatalf("review %d: expected HumanNeededError, got %+v, %v", i, result, err)
+		}
+	}
+
+	sprint, err := ParseSprint(sprintPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sprint.DoDReopens != maxDoDReopens {
+		t.Errorf("expected %d re-opens recorded, got %d", maxDoDReopens, sprint.DoDReopens)
+	}
+	if sprint.Tasks[1].FailureCount != maxDoDReopens {
+		t.Errorf("expected task 2 re-opened %d times, got %d", maxDoDReopens, sprint.Tasks[1].FailureCount)
+	}
+
+	if err := sprint.ResetAll(); err != nil {
+		t.Fatal(err)
+	}
+	if reset, _ := ParseSprint(sprintPath); reset.DoDReopens != 0 {
+		t.Errorf("expected ResetAll to clear the re-open count, got %d", reset.DoDReopens)
+	}
+}
+
+// TestAssessGoalAndPlanNext_DoDGatesPlannedNextSprint verifies the gate runs
+// even when the next sprint was already planned, e.g. by 'sprint add'.
+func TestAssessGoalAndPlanNext_DoDGatesPlannedNextSprint(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": dodSprint,
+		"02-next.md":    "# Sprint 2\n\n- [ ] Task\n  - [ ] go-coder: More code\n",
+	})
+	proj := project.New(tmpDir)
+
+	result, err := assessGoalAndPlanNext(tmpDir, proj, 1, NextOptions{PreferredAgent: "dummy"})
+	if err != nil || result.ExitCode != ExitMoreWork {
+		t.Fatalf("expected more work, got %+v (%v)", result, err)
+	}
+	sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sprint.DoDVerified {
+		t.Error("expected the Definition of Done review to run before moving to sprint 2")
+	}
+}
@@ -15,6 +15,7 @@ const (
 //   - Interview awaiting answers → 255 (human: answer questions)
 //   - Planning phases incomplete → 1 (automation: run agate next)
 //   - Sprint tasks incomplete → 1 (automation: run agate next)
+//   - Sprint complete with skipped tasks → 255 (human: fix skipped tasks)
 //   - All sprints complete → 0 (done)
 func GetExitCode(r StatusResult) int {
 	// No goal = human must create one
@@ -37,8 +38,9 @@ func GetExitCode(r StatusResult) int {
 		return ExitMoreWork
 	}
 
-	if r.Sprint.IsComplete() {
-		return ExitDone
+	// A complete sprint still owes its Definition of Done review
+	if r.Sprint.IsComplete() && !r.Sprint.DoDPending() {
+		return holdForSkipped(r.Sprint, ExitDone)
 	}
 
 	return ExitMoreWork
@@ -1,6 +1,12 @@
 package workflow
 
-import "testing"
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
 
 func TestGetExitCode_NoGoal(t *testing.T) {
 	r := StatusResult{HasGoal: false}
@@ -113,3 +119,77 @@ func TestGetExitCode_ExecutionSprintComplete(t *testing.T) {
 		t.Errorf("expected %d (done), got %d", ExitDone, code)
 	}
 }
+
+func TestGetExitCode_ExecutionSprintCompleteWithSkippedTasks(t *testing.T) {
+	sprint := &SprintState{
+		Tasks: []Task{
+			{Checked: true},
+			{Skipped: true},
+		},
+	}
+	r := StatusResult{
+		HasGoal: true,
+		Phase:   PhaseExecution,
+		Sprint:  sprint,
+	}
+	if code := GetExitCode(r); code != ExitHumanNeeded {
+		t.Errorf("expected %d (human needed), got %d", ExitHumanNeeded, code)
+	}
+}
+
+func TestNextResultExitCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		sprints map[string]string
+		want    int
+	}{
+		{
+			name:    "sub-task done, more remain",
+			sprints: map[string]string{"01-initial.md": "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Write code\n  - [ ] go-coder: Write tests\n"},
+			want:    ExitMoreWork,
+		},
+		{
+			name:    "last sprint complete",
+			sprints: map[string]string{"01-initial.md": "# Sprint 1\n\n- [ ] Task\n  - [x] go-coder: Write code\n  - [ ] go-coder: Write tests\n"},
+			want:    ExitDone,
+		},
+		{
+			name:    "last sprint complete, Definition of Done unverified",
+			sprints: map[string]string{"01-initial.md": "# Sprint 1\n\n- [ ] Task\n  - [x] go-coder: Write code\n  - [ ] go-coder: Write tests\n\n## Definition of Done\n\n- Tests pass\n"},
+			want:    ExitMoreWork,
+		},
+		{
+			name: "sprint complete, next sprint planned",
+			sprints: map[string]string{
+				"01-initial.md": "# Sprint 1\n\n- [ ] Task\n  - [x] go-coder: Write code\n  - [ ] go-coder: Write tests\n",
+				"02-next.md":    "# Sprint 2\n\n- [ ] Task\n  - [ ] go-coder: More code\n",
+			},
+			want: ExitMoreWork,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := setupExecutionProject(t, tt.sprints)
+
+			result, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"})
+			if err != nil {
+				t.Fatalf("next failed: %v", err)
+			}
+			if result.ExitCode != tt.want {
+				t.Errorf("expected exit %d, got %d (%s)", tt.want, result.ExitCode, result.Message)
+			}
+			// The outcome's exit code agrees with the state on disk
+			if status := GetExitCode(GetStatus(os.DirFS(tmpDir), project.DefaultStateDir)); status != result.ExitCode {
+				t.Errorf("result exit %d disagrees with status exit %d", result.ExitCode, status)
+			}
+		})
+	}
+}
+
+func TestNextResultExitCode_HumanNeeded(t *testing.T) {
+	_, err := NextWithOptions(project.New(t.TempDir()), NextOptions{PreferredAgent: "dummy"})
+	var humanErr *HumanNeededError
+	if !errors.As(err, &humanErr) {
+		t.Errorf("expected HumanNeededError without GOAL.md, got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/strongdm/agate/internal/agent"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// Explanation describes the step 'agate next' would take and why. Explain
+// builds it from GetStatus without invoking an agent or changing any file.
+type Explanation struct {
+	Phase  PlanPhase
+	Action string
+
+	// Execution phase only
+	SprintPath string
+	SprintNum  int
+	Task       *Task
+	SubTask    *SubTask
+
+	// Agent is empty when the step needs no agent (e.g. a human must act)
+	Agent       string
+	AgentReason string
+}
+
+// Explain reports what NextWithOptions would do next with opts, and which
+// agent it would use
+func Explain(proj *project.Project, opts NextOptions) (*Explanation, error) {
+	projectDir := proj.Dir
+	status := GetStatus(os.DirFS(projectDir), proj.StateDir())
+	exp := &Explanation{Phase: status.Phase}
+
+	if !status.HasGoal {
+		exp.Action = "Create GOAL.md describing what you want to build (human action)"
+		return exp, nil
+	}
+	if opts.TaskNumber < 0 {
+		return nil, fmt.Errorf("task number must be positive, got %d", opts.TaskNumber)
+	}
+	if opts.PhaseOnly && opts.TaskNumber > 0 {
+		return nil, fmt.Errorf("cannot target task %d with phase-only: phase-only never executes sprint tasks", opts.TaskNumber)
+	}
+
+	if status.Phase != PhaseExecution || opts.PhaseOnly {
+		if opts.TaskNumber > 0 {
+			return nil, fmt.Errorf("cannot target task %d: project is still in the %s phase", opts.TaskNumber, status.Phase)
+		}
+		if status.Phase == PhaseExecution {
+			exp.Action = "Nothing: planning is complete and phase-only never executes sprint tasks"
+			return exp, nil
+		}
+		if status.Phase == PhaseInterview && status.InterviewExists && !status.InterviewComplete {
+			exp.Action = "Wait for answers in " + proj.StatePath("interview.md") + " and its completion box (human action)"
+			return exp, nil
+		}
+		exp.Action = GetNextPlanAction(status.Phase)
+		cfg, _ := proj.LoadConfig()
+		exp.Agent, exp.AgentReason = explainPlanAgent(proj, status.Phase, cfg, opts.PreferredAgent)
+		return exp, nil
+	}
+
+	if status.CurrentSprintPath == "" {
+		exp.Action = "Nothing: no sprint files found"
+		return exp, nil
+	}
+	exp.SprintPath = status.CurrentSprintPath
+	exp.SprintNum = status.CurrentSprintNum
+
+	sprint, err := ParseSprint(filepath.Join(projectDir, status.CurrentSprintPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sprint: %w", err)
+	}
+
+	// Mirror the stall escalation, which counts this run too
+	if !sprint.IsComplete() {
+		cfg, err := proj.LoadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		if progress := nextProgress(proj.SprintsDir(), sprint, exp.SprintNum); stallsOut(progress, cfg.StallLimit) {
+			exp.Action = fmt.Sprintf("Stop: sprint %d has made no progress in %d runs (human action)", exp.SprintNum, progress.Stalled)
+			return exp, nil
+		}
+	}
+
+	var subTask *SubTask
+	if opts.TaskNumber > 0 {
+		if subTask, err = sprint.GetNextSubTaskForTask(opts.TaskNumber - 1); err != nil {
+			return nil, err
+		}
+	} else {
+		if sprint.IsComplete() {
+			skill := "_planner"
+			switch {
+			case sprint.DoDPending():
+				exp.Action = "Verify the sprint's Definition of Done before closing it"
+				skill = "_reviewer"
+			case findSprintByNum(proj.SprintsDir(), exp.SprintNum+1) != "":
+				exp.Action = fmt.Sprintf("Start sprint %d", exp.SprintNum+1)
+				return exp, nil
+			default:
+				exp.Action = "Assess the goal and plan the next sprint"
+			}
+			skills, _ := project.LoadSkills(proj.SkillsDir())
+			selected, reason, _, err := chooseAgent(opts.PreferredAgent, skill, skills)
+			if err != nil {
+				return nil, err
+			}
+			exp.Agent, exp.AgentReason = selected.Name(), reason
+			return exp, nil
+		}
+		if subTask = sprint.GetNextSubTask(); subTask == nil {
+			exp.Action = "Nothing: no unchecked sub-tasks, but the sprint is not complete"
+			return exp, nil
+		}
+	}
+	exp.Task = &sprint.Tasks[subTask.ParentIndex]
+	exp.SubTask = subTask
+
+	if exp.Task.FailureCount >= maxReviewRetries {
+		if exp.Task.ReplanCount > 0 {
+			if opts.KeepGoing {
+				exp.Action = fmt.Sprintf("Skip the task: it failed review %d times even after replan (--keep-going)", exp.Task.FailureCount)
+				return exp, nil
+			}
+			exp.Action = fmt.Sprintf("Stop: task failed review %d times even after replan (human action)", exp.Task.FailureCount)
+			return exp, nil
+		}
+		exp.Action = fmt.Sprintf("Replan the sprint: task failed review %d times", exp.Task.FailureCount)
+		return exp, nil
+	}
+
+	if subTask.Skill == testGateSkill {
+		exp.Action = "Run the hooks.test commands (no agent): the sub-task passes only if they all succeed"
+		return exp, nil
+	}
+
+	skills, _ := project.LoadSkills(proj.SkillsDir())
+	switch subTaskPhase(subTask.Skill, project.GetSkillByName(skills, subTask.Skill)) {
+	case phaseReview:
+		exp.Action = "Review the task's changes"
+	case phaseImplement:
+		exp.Action = "Implement the sub-task"
+	default:
+		exp.Action = "Run the sub-task"
+	}
+	preferred := sprint.preferredAgent(opts.PreferredAgent)
+	selected, reason, _, err := chooseAgent(preferred, subTask.Skill, skills)
+	if err != nil {
+		return nil, err
+	}
+	if preferred != opts.PreferredAgent && selected.Name() == preferred {
+		reason = "the sprint's default_agent"
+	}
+	exp.Agent, exp.AgentReason = selected.Name(), reason
+	if opts.PreferredAgent == AutoAgent {
+		exp.AgentReason += "; --agent auto falls back to the other agents if it fails to run"
+	}
+	return exp, nil
+}
+
+// explainPlanAgent mirrors the agent choice of the planning phases
+// (selectInterviewAgent and getSelectedAgent) and says why
+func explainPlanAgent(proj *project.Project, phase PlanPhase, cfg *project.Config, preferred string) (string, string) {
+	planOpts := PlanOptions{PreferredAgent: preferred}
+	if phase == PhaseInterview {
+		if cfg != nil && cfg.InterviewAgent != "" && agentAvailable(cfg.InterviewAgent) {
+			return cfg.InterviewAgent, "interview_agent is set in " + proj.StatePath("config.yaml")
+		}
+		if preferred != "" && agentAvailable(preferred) {
+			return preferred, "requested with --agent"
+		}
+		if agentAvailable("claude") {
+			return "claude", "claude is preferred for interview questions"
+		}
+	} else if preferred != "" && agentAvailable(preferred) {
+		return preferred, "requested with --agent"
+	}
+	if a := getSelectedAgent(planOpts); a != nil {
+		return a.Name(), "first available agent"
+	}
+	return "", "no agents available"
+}
+
+// agentAvailable reports whether the named agent exists and is installed
+func agentAvailable(name string) bool {
+	a := agent.GetAgentByName(name)
+	return a != nil && a.Available()
+}
+
+// String renders the explanation as aligned "key: value" lines
+func (e *Explanation) String() string {
+	var sb strings.Builder
+	line := func(key, value string) {
+		sb.WriteString(fmt.Sprintf("%-10s %s\n", key+":", value))
+	}
+
+	line("Phase", string(e.Phase))
+	if e.SprintPath != "" {
+		line("Sprint", fmt.Sprintf("%d (%s)", e.SprintNum, e.SprintPath))
+	}
+	if e.Task != nil {
+		line("Task", fmt.Sprintf("%d. %s", e.Task.Index+1, e.Task.Text))
+	}
+	if e.SubTask != nil {
+		line("Sub-task", fmt.Sprintf("[%s] %s", e.SubTask.Skill, e.SubTask.Text))
+		if e.SubTask.FailureReason != "" {
+			line("Last fail", e.SubTask.FailureReason)
+		}
+	}
+	line("Next", e.Action)
+	if e.Agent != "" {
+		line("Agent", e.Agent)
+		line("Why", e.AgentReason)
+	}
+	return sb.String()
+}
@@ -0,0 +1,102 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestExplain_PlanningPhase(t *testing.T) {
+	tmpDir := setupExecutionProject(t, nil)
+	if err := os.Remove(filepath.Join(tmpDir, ".ai", "design", "decisions.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	exp, err := Explain(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if exp.Phase != PhaseDecisions {
+		t.Errorf("expected decisions phase, got %s", exp.Phase)
+	}
+	if exp.Action != GetNextPlanAction(PhaseDecisions) || exp.Agent != "dummy" {
+		t.Errorf("unexpected explanation:\n%s", exp)
+	}
+}
+
+func TestExplain_SubTaskAgentReason(t *testing.T) {
+	// Only the dummy agent is installed, so codex falls back
+	t.Setenv("PATH", t.TempDir())
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Build\n  - [x] go-coder: Write code\n  - [ ] _reviewer: Review code\n",
+	})
+
+	exp, err := Explain(project.New(tmpDir), NextOptions{})
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if exp.SubTask == nil || exp.SubTask.Skill != "_reviewer" {
+		t.Fatalf("expected the reviewer sub-task, got:\n%s", exp)
+	}
+	if exp.Action != "Review the task's changes" {
+		t.Errorf("unexpected action %q", exp.Action)
+	}
+	if exp.Agent != "dummy" || !strings.Contains(exp.AgentReason, "claude is not available") {
+		t.Errorf("expected fallback from claude to dummy, got %s (%s)", exp.Agent, exp.AgentReason)
+	}
+	if !strings.Contains(exp.String(), "Sprint:    1 (.ai/sprints/01-initial.md)") {
+		t.Errorf("expected sprint line in:\n%s", exp)
+	}
+}
+
+func TestExplain_CompleteSprintWithNext(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [x] Build\n  - [x] go-coder: Write code\n",
+	})
+
+	exp, err := Explain(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if exp.Action != "Assess the goal and plan the next sprint" || exp.Agent != "dummy" {
+		t.Errorf("unexpected explanation:\n%s", exp)
+	}
+}
+
+func TestExplain_CompleteSprintDoDPending(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{"01-initial.md": dodSprint})
+
+	exp, err := Explain(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if !strings.Contains(exp.Action, "Definition of Done") || exp.Agent != "dummy" {
+		t.Errorf("expected the Definition of Done review, got:\n%s", exp)
+	}
+}
+
+func TestExplain_StalledSprint(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Build\n  - [ ] go-coder: Write code\n",
+	})
+	proj := project.New(tmpDir)
+	progress := filepath.Join(proj.SprintsDir(), progressName)
+	marker := []byte(`{"sprint":1,"last":0,"stalled":5}`)
+	if err := os.WriteFile(progress, marker, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	exp, err := Explain(proj, NextOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if !strings.HasPrefix(exp.Action, "Stop: sprint 1 has made no progress in 6 runs") || exp.Agent != "" {
+		t.Errorf("expected the stall escalation, got:\n%s", exp)
+	}
+	if data, _ := os.ReadFile(progress); string(data) != string(marker) {
+		t.Errorf("Explain should not update the progress marker, got %s", data)
+	}
+}
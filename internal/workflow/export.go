@@ -0,0 +1,157 @@
+package workflow
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/strongdm/agate/internal/fsutil"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// exportSection is one entry of an export report and its table of contents
+type exportSection struct {
+	Level int // 2 for top-level sections, 3 for documents within them
+	Title string
+	Body  string
+}
+
+// Export bundles GOAL.md, the design documents, every sprint (with computed
+// progress), and all retrospectives into a single markdown report with a
+// table of contents
+func Export(proj *project.Project) (string, error) {
+
+	goal, err := os.ReadFile(proj.GoalPath())
+	if err != nil {
+		return "", fmt.Errorf("failed to read GOAL.md: %w", err)
+	}
+
+	sections := []exportSection{{Level: 2, Title: "Goal", Body: demoteHeadings(string(goal), 3)}}
+
+	sections = append(sections, exportSection{Level: 2, Title: "Design"})
+	designFiles := fsutil.ListMarkdownFiles(proj.DesignDir())
+	if len(designFiles) == 0 {
+		sections[len(sections)-1].Body = "_No design documents yet._"
+	}
+	for _, name := range designFiles {
+		content, err := os.ReadFile(filepath.Join(proj.DesignDir(), name))
+		if err != nil {
+			continue
+		}
+		sections = append(sections, exportSection{
+			Level: 3,
+			Title: "Design: " + strings.TrimSuffix(name, ".md"),
+			Body:  demoteHeadings(string(content), 4),
+		})
+	}
+
+	sections = append(sections, exportSection{Level: 2, Title: "Sprints"})
+	sprints := loadCompletedSprintSummaries(proj.SprintsDir(), math.MaxInt)
+	if len(sprints) == 0 {
+		sections[len(sections)-1].Body = "_No sprints yet._"
+	}
+	for _, s := range sprints {
+		sprint, err := ParseSprintContent(s.Content)
+		if err != nil {
+			continue
+		}
+		completed, total := sprint.GetOverallProgress()
+		pct := 0
+		if total > 0 {
+			pct = completed * 100 / total
+		}
+		state := "in progress"
+		if sprint.IsComplete() {
+			state = "complete"
+		}
+		progress := fmt.Sprintf("**Progress:** %d/%d sub-tasks (%d%%), %s", completed, total, pct, state)
+		sections = append(sections, exportSection{
+			Level: 3,
+			Title: fmt.Sprintf("Sprint %d", s.Num),
+			Body:  progress + "\n\n" + demoteHeadings(s.Content, 4),
+		})
+	}
+
+	sections = append(sections, exportSection{Level: 2, Title: "Retrospectives"})
+	retrosDir := proj.RetrosDir()
+	retroFiles := fsutil.ListMarkdownFiles(retrosDir)
+	if len(retroFiles) == 0 {
+		sections[len(sections)-1].Body = "_No retrospectives yet._"
+	}
+	for _, name := range retroFiles {
+		content, err := os.ReadFile(filepath.Join(retrosDir, name))
+		if err != nil {
+			continue
+		}
+		title := "Retro: " + strings.TrimSuffix(name, ".md")
+		if num := ExtractSprintNum(strings.TrimPrefix(name, "sprint-")); num > 0 {
+			title = fmt.Sprintf("Retro: Sprint %d", num)
+		}
+		sections = append(sections, exportSection{Level: 3, Title: title, Body: demoteHeadings(string(content), 4)})
+	}
+
+	return formatExport(filepath.Base(proj.Dir), sections), nil
+}
+
+// formatExport renders the report title, table of contents, and sections
+func formatExport(projectName string, sections []exportSection) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s Project Report\n\n", projectName))
+
+	sb.WriteString("## Contents\n\n")
+	for _, s := range sections {
+		indent := strings.Repeat("  ", s.Level-2)
+		sb.WriteString(fmt.Sprintf("%s- [%s](#%s)\n", indent, s.Title, headingAnchor(s.Title)))
+	}
+	sb.WriteString("\n")
+
+	for _, s := range sections {
+		sb.WriteString(fmt.Sprintf("%s %s\n\n", strings.Repeat("#", s.Level), s.Title))
+		if body := strings.TrimSpace(s.Body); body != "" {
+			sb.WriteString(body)
+			sb.WriteString("\n\n")
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+var anchorStripRe = regexp.MustCompile(`[^a-z0-9 _-]`)
+
+// headingAnchor returns the GitHub-style anchor for a heading: lowercase,
+// punctuation removed, spaces replaced with hyphens
+func headingAnchor(title string) string {
+	anchor := anchorStripRe.ReplaceAllString(strings.ToLower(title), "")
+	return strings.ReplaceAll(anchor, " ", "-")
+}
+
+// demoteHeadings shifts markdown headings so the top level becomes minLevel,
+// keeping embedded documents nested under their report section. Fenced
+// code blocks are left untouched.
+func demoteHeadings(content string, minLevel int) string {
+	lines := strings.Split(content, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.HasPrefix(line, "#") {
+			continue
+		}
+		level := len(line) - len(strings.TrimLeft(line, "#"))
+		if level > 6 || (len(line) > level && line[level] != ' ') {
+			continue
+		}
+		newLevel := level + minLevel - 1
+		if newLevel > 6 {
+			newLevel = 6
+		}
+		lines[i] = strings.Repeat("#", newLevel) + line[level:]
+	}
+	return strings.Join(lines, "\n")
+}
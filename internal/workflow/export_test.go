@@ -0,0 +1,66 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestExport_IncludesAllSources(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [x] Set up project\n  - [x] go-coder: Create go.mod\n",
+		"02-parser.md":  "# Sprint 2\n\n- [ ] Build parser\n  - [x] go-coder: Write parser\n  - [ ] _reviewer: Review parser\n",
+	})
+	retrosDir := filepath.Join(tmpDir, ".ai", "retros")
+	if err := os.MkdirAll(retrosDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(retrosDir, "sprint-001.md"), []byte("# Retro\n\nWent well.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Export(project.New(tmpDir))
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	for _, want := range []string{
+		// Table of contents links
+		"- [Goal](#goal)\n",
+		"- [Design](#design)\n",
+		"  - [Design: decisions](#design-decisions)\n",
+		"  - [Design: overview](#design-overview)\n",
+		"- [Sprints](#sprints)\n",
+		"  - [Sprint 1](#sprint-1)\n",
+		"  - [Sprint 2](#sprint-2)\n",
+		"- [Retrospectives](#retrospectives)\n",
+		"  - [Retro: Sprint 1](#retro-sprint-1)\n",
+		// Sections and their content
+		"## Goal\n\n### Goal\n\nBuild a CLI in Go.",
+		"### Design: overview\n\n#### Design",
+		"### Sprint 1\n\n**Progress:** 1/1 sub-tasks (100%), complete",
+		"### Sprint 2\n\n**Progress:** 1/2 sub-tasks (50%), in progress",
+		"### Retro: Sprint 1\n\n#### Retro\n\nWent well.",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("expected %q in report:\n%s", want, report)
+		}
+	}
+}
+
+func TestExport_MissingGoal(t *testing.T) {
+	if _, err := Export(project.New(t.TempDir())); err == nil {
+		t.Error("expected error without GOAL.md")
+	}
+}
+
+func TestDemoteHeadings(t *testing.T) {
+	in := "# Title\n\n## Sub\n\n```\n# not a heading\n```\n#hashtag\n"
+	want := "### Title\n\n#### Sub\n\n```\n# not a heading\n```\n#hashtag\n"
+	if got := demoteHeadings(in, 3); got != want {
+		t.Errorf("demoteHeadings = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,83 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// FreshTarget returns the planning phase whose artifact --fresh regenerates:
+// the most recently produced one. That is the phase before the current one,
+// or the interview itself while it is waiting for answers. Nothing has been
+// produced before the interview, and once sprints exist --resume-sprint is
+// the way to redo one.
+func FreshTarget(status StatusResult) (PlanPhase, error) {
+	switch status.Phase {
+	case PhaseInterview:
+		if !status.HasGoal || !status.InterviewExists {
+			return "", fmt.Errorf("nothing to regenerate yet: no planning artifact has been generated")
+		}
+		return PhaseInterview, nil
+	case PhaseDesign:
+		return PhaseInterview, nil
+	case PhaseDecisions:
+		return PhaseDesign, nil
+	case PhaseSprint:
+		return PhaseDecisions, nil
+	case PhaseExecution:
+		return "", fmt.Errorf("planning is complete; use --resume-sprint N to regenerate a sprint")
+	}
+	return "", fmt.Errorf("unknown planning phase: %s", status.Phase)
+}
+
+// planArtifactPath returns the file a planning phase produces
+func planArtifactPath(proj *project.Project, phase PlanPhase) string {
+	switch phase {
+	case PhaseInterview:
+		return proj.InterviewPath()
+	case PhaseDesign:
+		return filepath.Join(proj.DesignDir(), "overview.md")
+	case PhaseDecisions:
+		return filepath.Join(proj.DesignDir(), "decisions.md")
+	}
+	return ""
+}
+
+// RegeneratePlanArtifact regenerates the most recently produced planning
+// artifact (see FreshTarget). The old file is backed up to the design
+// drafts directory, then removed so the phase runs again; if that run
+// fails the old file is put back.
+func RegeneratePlanArtifact(proj *project.Project, opts PlanOptions) (*Result, error) {
+	target, err := FreshTarget(GetStatus(os.DirFS(proj.Dir), proj.StateDir()))
+	if err != nil {
+		return nil, err
+	}
+	path := planArtifactPath(proj, target)
+
+	old, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	backupPath, err := backupToDrafts(proj, proj.DraftsDir(), path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up %s: %w", filepath.Base(path), err)
+	}
+	fmt.Println(logging.Dim(fmt.Sprintf("Backed up %s to %s", filepath.Base(path), backupPath)))
+
+	if err := os.Remove(path); err != nil {
+		return nil, fmt.Errorf("failed to remove %s: %w", filepath.Base(path), err)
+	}
+	result, err := ExecutePlanPhase(proj, opts)
+	if err != nil {
+		if werr := os.WriteFile(path, old, 0644); werr != nil {
+			return nil, fmt.Errorf("%w (and restoring %s failed: %v; it is backed up in %s)", err, filepath.Base(path), werr, backupPath)
+		}
+		return nil, err
+	}
+
+	result.Message = fmt.Sprintf("Regenerated %s (old version in %s). %s", filepath.Base(path), backupPath, result.Message)
+	return result, nil
+}
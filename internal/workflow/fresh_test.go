@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestFreshTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  StatusResult
+		want    PlanPhase
+		wantErr bool
+	}{
+		{"no goal", StatusResult{Phase: PhaseInterview}, "", true},
+		{"no interview yet", StatusResult{HasGoal: true, Phase: PhaseInterview}, "", true},
+		{"unanswered interview", StatusResult{HasGoal: true, InterviewExists: true, Phase: PhaseInterview}, PhaseInterview, false},
+		{"design phase", StatusResult{HasGoal: true, Phase: PhaseDesign}, PhaseInterview, false},
+		{"decisions phase", StatusResult{HasGoal: true, Phase: PhaseDecisions}, PhaseDesign, false},
+		{"sprint phase", StatusResult{HasGoal: true, Phase: PhaseSprint}, PhaseDecisions, false},
+		{"execution phase", StatusResult{HasGoal: true, Phase: PhaseExecution}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FreshTarget(tt.status)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("FreshTarget() = %q, %v; want %q (error: %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestNext_FreshRewritesOverview verifies --fresh in the decisions phase
+// regenerates overview.md, backing up the old one to the design drafts.
+func TestNext_FreshRewritesOverview(t *testing.T) {
+	tmpDir := setupExecutionProject(t, nil)
+	os.Remove(filepath.Join(tmpDir, ".ai", "design", "decisions.md"))
+	if phase := GetCurrentPlanPhase(project.New(tmpDir)); phase != PhaseDecisions {
+		t.Fatalf("expected the decisions phase, got %s", phase)
+	}
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "printf '# Design v2\\n\\nA fresh overview.\\n'\n")
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	result, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "claude", Fresh: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Message, "Regenerated overview.md") {
+		t.Errorf("expected the message to name the regenerated file, got %q", result.Message)
+	}
+	overview, _ := os.ReadFile(filepath.Join(tmpDir, ".ai", "design", "overview.md"))
+	if !strings.Contains(string(overview), "# Design v2") {
+		t.Errorf("expected overview.md rewritten, got:\n%s", overview)
+	}
+	backups, _ := filepath.Glob(filepath.Join(tmpDir, ".ai", "design", ".drafts", "overview.*.md"))
+	if len(backups) != 1 {
+		t.Fatalf("expected one overview backup, got %v", backups)
+	}
+	if old, _ := os.ReadFile(backups[0]); string(old) != "# Design\n" {
+		t.Errorf("expected the backup to hold the old overview, got %q", old)
+	}
+	if phase := GetCurrentPlanPhase(project.New(tmpDir)); phase != PhaseDecisions {
+		t.Errorf("expected to stay in the decisions phase, got %s", phase)
+	}
+}
+
+// TestNext_FreshRestoresOnFailure verifies a failed regeneration puts the
+// old artifact back instead of leaving the phase undone.
+func TestNext_FreshRestoresOnFailure(t *testing.T) {
+	tmpDir := setupExecutionProject(t, nil)
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "echo boom >&2\nexit 1\n")
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if _, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "claude", Fresh: true}); err == nil {
+		t.Fatal("expected the failed regeneration to be reported")
+	}
+	decisions, err := os.ReadFile(filepath.Join(tmpDir, ".ai", "design", "decisions.md"))
+	if err != nil || string(decisions) != "# Decisions\n" {
+		t.Errorf("expected decisions.md restored, got %q (%v)", decisions, err)
+	}
+	if phase := GetCurrentPlanPhase(project.New(tmpDir)); phase != PhaseSprint {
+		t.Errorf("expected to stay in the sprint phase, got %s", phase)
+	}
+}
@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// SprintHistory is one sprint's entry in the project history
+type SprintHistory struct {
+	Num      int
+	Goal     string
+	Tasks    int // Top-level tasks
+	Done     int // Top-level tasks checked
+	Complete bool
+	Markers  MarkerCounts
+	// Invocations is the number of agent invocation logs for the sprint
+	Invocations int
+	// Finished is when a complete sprint's retrospective was generated, or
+	// else its last logged invocation; zero if unknown or not complete
+	Finished time.Time
+}
+
+// History collects every sprint, in order, with its goal, task counts,
+// markers, and finish time from its retrospective and logs
+func History(proj *project.Project) ([]SprintHistory, error) {
+	var history []SprintHistory
+	for _, s := range loadCompletedSprintSummaries(proj.SprintsDir(), math.MaxInt) {
+		sprint, err := ParseSprintContent(s.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sprint %d: %w", s.Num, err)
+		}
+		done, total := sprint.GetProgress()
+		entry := SprintHistory{
+			Num:      s.Num,
+			Goal:     sprintGoalLine(s.Content),
+			Tasks:    total,
+			Done:     done,
+			Complete: sprint.IsComplete(),
+			Markers:  sprint.CountMarkers(),
+		}
+
+		logs, _ := logging.ListLogs(proj, s.Num)
+		entry.Invocations = len(logs)
+		if entry.Complete {
+			entry.Finished = sprintFinished(proj, s.Num, logs)
+		}
+		history = append(history, entry)
+	}
+	return history, nil
+}
+
+// sprintFinished returns when a sprint's retrospective was generated, else
+// the latest timestamp among its invocation logs
+func sprintFinished(proj *project.Project, sprintNum int, logs []string) time.Time {
+	if content, err := os.ReadFile(logging.GetRetroPath(proj, sprintNum)); err == nil {
+		if t, ok := logging.ParseRetroGenerated(string(content)); ok {
+			return t
+		}
+	}
+	var latest time.Time
+	for _, path := range logs {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if t, ok := logging.ParseInvocationTimestamp(string(content)); ok && t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// FormatHistory renders the history as a timeline, one block per sprint
+func FormatHistory(history []SprintHistory) string {
+	if len(history) == 0 {
+		return "No sprints yet.\n"
+	}
+
+	var sb strings.Builder
+	for i, h := range history {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		state := logging.Yellow("in progress")
+		if h.Complete {
+			state = logging.Green("complete")
+			if !h.Finished.IsZero() {
+				state += " " + h.Finished.Local().Format("2006-01-02 15:04")
+			}
+		}
+		sb.WriteString(fmt.Sprintf("%s  %s\n", logging.Bold(fmt.Sprintf("Sprint %d", h.Num)), state))
+		if h.Goal != "" {
+			sb.WriteString(fmt.Sprintf("  %s\n", h.Goal))
+		}
+
+		details := []string{fmt.Sprintf("%d/%d task%s", h.Done, h.Tasks, plural(h.Tasks))}
+		if h.Markers.Failures > 0 {
+			details = append(details, fmt.Sprintf("❌ %d failed review%s", h.Markers.Failures, plural(h.Markers.Failures)))
+		}
+		if h.Markers.Replans > 0 {
+			details = append(details, fmt.Sprintf("🔄 %d replan%s", h.Markers.Replans, plural(h.Markers.Replans)))
+		}
+		if h.Markers.Skipped > 0 {
+			details = append(details, fmt.Sprintf("⏭ %d skipped", h.Markers.Skipped))
+		}
+		details = append(details, fmt.Sprintf("%d agent run%s", h.Invocations, plural(h.Invocations)))
+		sb.WriteString("  " + logging.Dim(strings.Join(details, ", ")) + "\n")
+	}
+
+	var failures, replans int
+	complete := 0
+	for _, h := range history {
+		failures += h.Markers.Failures
+		replans += h.Markers.Replans
+		if h.Complete {
+			complete++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("\n%d of %d sprint%s complete, %d failed review%s, %d replan%s\n",
+		complete, len(history), plural(len(history)), failures, plural(failures), replans, plural(replans)))
+	return sb.String()
+}
@@ -0,0 +1,97 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// writeHistoryLog writes an invocation log for sprintNum logged at at
+func writeHistoryLog(t *testing.T, projectDir string, sprintNum int, name string, at time.Time) {
+	t.Helper()
+	dir := logging.GetLogsDir(project.New(projectDir), sprintNum)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := logging.FormatInvocation(&logging.Invocation{Timestamp: at, Sprint: sprintNum, Phase: "implement", Agent: "dummy"})
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHistory_CountsMarkersAndFinishTimes(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1: Setup\n\n## Goal\n\nScaffold the CLI.\n\n" +
+			"- [x] ❌❌🔄 Set up project\n  - [x] go-coder: Create go.mod\n  - [x] _reviewer: Validate setup\n\n" +
+			"- [x] ❌ Add README\n  - [x] go-coder: Write README\n",
+		"02-parser.md": "# Sprint 2: Parser\n\n- [x] Build parser\n  - [x] go-coder: Write parser\n\n" +
+			"- [ ] ❌❌❌🔄⏭ Fuzz parser\n  - [ ] go-coder: Add fuzzing\n",
+		"03-cli.md": "# Sprint 3: CLI\n\n- [ ] ❌ Wire CLI\n  - [ ] go-coder: Add commands\n\n- [ ] Add help\n  - [ ] go-coder: Write help\n",
+	})
+
+	sprint1Done := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	writeHistoryLog(t, tmpDir, 1, "001-implement-00-go-coder-dummy.md", sprint1Done.Add(-time.Hour))
+	writeHistoryLog(t, tmpDir, 1, "002-implement-00-_reviewer-dummy.md", sprint1Done)
+	writeHistoryLog(t, tmpDir, 1, "003-implement-01-go-coder-dummy.md", sprint1Done.Add(-30*time.Minute))
+	writeHistoryLog(t, tmpDir, 2, "001-implement-00-go-coder-dummy.md", sprint1Done.Add(24*time.Hour))
+	writeHistoryLog(t, tmpDir, 3, "001-implement-00-go-coder-dummy.md", sprint1Done.Add(48*time.Hour))
+
+	// A retrospective's time wins over the logs
+	if err := logging.EnsureRetrosDir(project.New(tmpDir)); err != nil {
+		t.Fatal(err)
+	}
+	retroDone := time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC)
+	retro := "# Sprint 2 Retrospective\n\nGenerated: " + retroDone.Format(time.RFC3339) + "\n"
+	if err := os.WriteFile(logging.GetRetroPath(project.New(tmpDir), 2), []byte(retro), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := History(project.New(tmpDir))
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 sprints, got %d", len(history))
+	}
+
+	want := []SprintHistory{
+		{Num: 1, Goal: "Scaffold the CLI.", Tasks: 2, Done: 2, Complete: true, Markers: MarkerCounts{Failures: 3, Replans: 1}, Invocations: 3, Finished: sprint1Done},
+		{Num: 2, Goal: "Sprint 2: Parser", Tasks: 2, Done: 1, Complete: true, Markers: MarkerCounts{Failures: 3, Replans: 1, Skipped: 1}, Invocations: 1, Finished: retroDone},
+		{Num: 3, Goal: "Sprint 3: CLI", Tasks: 2, Done: 0, Complete: false, Markers: MarkerCounts{Failures: 1}, Invocations: 1},
+	}
+	for i, w := range want {
+		got := history[i]
+		if !got.Finished.Equal(w.Finished) {
+			t.Errorf("sprint %d: finished %v, want %v", w.Num, got.Finished, w.Finished)
+		}
+		got.Finished, w.Finished = time.Time{}, time.Time{}
+		if got != w {
+			t.Errorf("sprint %d:\n got %+v\nwant %+v", w.Num, got, w)
+		}
+	}
+
+	out := FormatHistory(history)
+	for _, s := range []string{
+		"Sprint 1  complete",
+		"Scaffold the CLI.",
+		"2/2 tasks, ❌ 3 failed reviews, 🔄 1 replan, 3 agent runs",
+		"1/2 tasks, ❌ 3 failed reviews, 🔄 1 replan, ⏭ 1 skipped, 1 agent run",
+		"Sprint 3  in progress",
+		"2 of 3 sprints complete, 7 failed reviews, 2 replans",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected %q in output:\n%s", s, out)
+		}
+	}
+}
+
+func TestFormatHistory_Empty(t *testing.T) {
+	if out := FormatHistory(nil); out != "No sprints yet.\n" {
+		t.Errorf("unexpected output for no sprints: %q", out)
+	}
+}
@@ -0,0 +1,106 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// testGateSkill names sub-tasks that act as a deterministic reviewer: they
+// pass only if every hooks.test command exits zero
+const testGateSkill = "_test-gate"
+
+// hookFailure describes the first hook command that exited non-zero
+type hookFailure struct {
+	Command string
+	Output  string
+	Err     error
+}
+
+// Reason summarizes the failure for a sprint annotation: the command and
+// the last lines of its output, where test runners report failures
+func (f *hookFailure) Reason() string {
+	lines := strings.Split(strings.TrimSpace(f.Output), "\n")
+	if len(lines) > 5 {
+		lines = lines[len(lines)-5:]
+	}
+	reason := fmt.Sprintf("hook %q failed (%v)", f.Command, f.Err)
+	if tail := strings.TrimSpace(strings.Join(lines, " ")); tail != "" {
+		reason += ": " + tail
+	}
+	return reason
+}
+
+// runHook runs a hook command with sh -c in projectDir and returns its
+// combined output
+func runHook(ctx context.Context, projectDir, command string) (string, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = projectDir
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// runHooks runs the commands configured for a hook in order, logging each
+// one under the hook's name, and stops at the first failure
+func runHooks(ctx context.Context, projectDir, hook string, commands []string, logger *logging.Logger, subTask *SubTask) *hookFailure {
+	for _, command := range commands {
+		lf, logErr := logger.StartInvocation("hook", subTask.Text, subTask.Index, "hook", hook, command)
+
+		output, err := runHook(ctx, projectDir, command)
+
+		if logErr == nil {
+			lf.SetPrompt(command)
+			lf.SetResponse(output)
+			if err != nil {
+				lf.SetError(err)
+			} else {
+				lf.SetStatus("success")
+			}
+			lf.Close()
+		}
+
+		if err != nil {
+			return &hookFailure{Command: command, Output: output, Err: err}
+		}
+	}
+	return nil
+}
+
+// executeTestGate runs the configured hooks.test commands for a _test-gate
+// sub-task. Like a review, a failure marks the task failed and unchecks its
+// sub-tasks for a retry, with the failing command's output as the reason.
+func executeTestGate(projectDir string, proj *project.Project, sprint *SprintState, task *Task, subTask *SubTask, logger *logging.Logger, report Reporter) (*Result, error) {
+	cfg, err := proj.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if len(cfg.Hooks.Test) == 0 {
+		return nil, &HumanNeededError{
+			Message: fmt.Sprintf("sub-task %q is a %s but no test command is configured: add hooks.test to %s", subTask.Text, testGateSkill, proj.StatePath("config.yaml")),
+		}
+	}
+
+	sprintNum := ExtractSprintNum(filepath.Base(sprint.FilePath))
+	report.Info(sprint.RenderProgressBar(sprintNum, task.Index, subTask.Index, liveProgressBar))
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.TaskTimeout)
+	failure := runHooks(ctx, projectDir, "test", cfg.Hooks.Test, logger, subTask)
+	cancel()
+	if failure != nil {
+		report.Warn(fmt.Sprintf("⚠ Test gate %q failed. Adding failure marker and unchecking tasks for retry...", failure.Command))
+		recordSubTaskFailure(sprint, task, subTask, failure.Reason(), report)
+		return &Result{
+			Message:  "Test gate failed. Tasks unchecked for retry. Run 'agate next' to try again.",
+			MoreWork: true,
+			ExitCode: ExitMoreWork,
+		}, nil
+	}
+
+	report.Success("  Tests passed")
+	return completeSubTask(sprint, task, subTask, report)
+}
@@ -0,0 +1,178 @@
+package workflow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// setupHookProject creates an execution-phase project whose post_implement
+// hook is a stub script with the given body
+func setupHookProject(t *testing.T, hookBody string) (string, string) {
+	t.Helper()
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Build parser\n  - [ ] go-coder: Write parser\n  - [ ] _reviewer: Review parser\n",
+	})
+	writeStubScript(t, tmpDir, "hook.sh", hookBody)
+	config := "hooks:\n  post_implement:\n    - ./hook.sh\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".ai", "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return tmpDir, filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md")
+}
+
+func TestExecuteSubTask_PostImplementHookPasses(t *testing.T) {
+	tmpDir, sprintPath := setupHookProject(t, "echo ok > hook-ran\n")
+
+	result, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(result.Message, "hook failed") {
+		t.Errorf("unexpected hook failure: %s", result.Message)
+	}
+
+	// The hook runs in the project dir after the files are written
+	if _, err := os.Stat(filepath.Join(tmpDir, "hook-ran")); err != nil {
+		t.Errorf("expected hook to run in project dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "main.go")); err != nil {
+		t.Errorf("expected implementation files to be written: %v", err)
+	}
+
+	sprint, err := ParseSprint(sprintPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sprint.Tasks[0].SubTasks[0].Checked {
+		t.Error("expected sub-task to be checked after a passing hook")
+	}
+
+	logs, _ := os.ReadDir(filepath.Join(tmpDir, ".ai", "logs", "sprint-001"))
+	found := false
+	for _, l := range logs {
+		if strings.Contains(l.Name(), "-hook-") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a hook log, got %v", logs)
+	}
+}
+
+func TestExecuteSubTask_PostImplementHookFails(t *testing.T) {
+	tmpDir, sprintPath := setupHookProject(t, "echo 'running tests'\necho '--- FAIL: TestParse'\nexit 1\n")
+
+	result, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.MoreWork || !strings.Contains(result.Message, "hook failed") {
+		t.Errorf("expected hook failure result, got %+v", result)
+	}
+
+	sprint, err := ParseSprint(sprintPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := sprint.Tasks[0]
+	if task.SubTasks[0].Checked {
+		t.Error("sub-task should stay unchecked after a failing hook")
+	}
+	if task.FailureCount != 1 {
+		t.Errorf("expected 1 failure marker, got %d", task.FailureCount)
+	}
+	reason := task.SubTasks[0].FailureReason
+	if !strings.Contains(reason, "./hook.sh") || !strings.Contains(reason, "FAIL: TestParse") {
+		t.Errorf("expected hook output as feedback, got %q", reason)
+	}
+}
+
+// setupTestGateProject creates a project whose sprint has an implemented
+// sub-task followed by a _test-gate, with hooks.test set to a stub script
+func setupTestGateProject(t *testing.T, testBody string) (string, string) {
+	t.Helper()
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Build parser\n  - [x] go-coder: Write parser\n  - [ ] _test-gate: Tests pass\n\n- [ ] Next task\n  - [ ] go-coder: More\n",
+	})
+	writeStubScript(t, tmpDir, "test.sh", testBody)
+	config := "hooks:\n  test: ./test.sh\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".ai", "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return tmpDir, filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md")
+}
+
+func TestExecuteSubTask_TestGatePasses(t *testing.T) {
+	tmpDir, sprintPath := setupTestGateProject(t, "echo 'ok  example.com/parser'\n")
+
+	result, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ExitCode != ExitMoreWork || strings.Contains(result.Message, "failed") {
+		t.Errorf("expected a passing gate, got %+v", result)
+	}
+
+	sprint, err := ParseSprint(sprintPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sprint.Tasks[0].SubTasks[1].Checked || !sprint.Tasks[0].Checked {
+		t.Error("expected the gate and its task to be checked")
+	}
+
+	logs, _ := os.ReadDir(filepath.Join(tmpDir, ".ai", "logs", "sprint-001"))
+	var logged string
+	for _, l := range logs {
+		if strings.Contains(l.Name(), "-hook-") {
+			data, _ := os.ReadFile(filepath.Join(tmpDir, ".ai", "logs", "sprint-001", l.Name()))
+			logged = string(data)
+		}
+	}
+	if !strings.Contains(logged, "ok  example.com/parser") {
+		t.Errorf("expected the test output in the log, got %q", logged)
+	}
+}
+
+func TestExecuteSubTask_TestGateFails(t *testing.T) {
+	tmpDir, sprintPath := setupTestGateProject(t, "echo '--- FAIL: TestParse'\nexit 1\n")
+
+	result, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Message, "Test gate failed") {
+		t.Errorf("expected test gate failure, got %+v", result)
+	}
+
+	sprint, err := ParseSprint(sprintPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := sprint.Tasks[0]
+	if task.FailureCount != 1 {
+		t.Errorf("expected 1 failure marker, got %d", task.FailureCount)
+	}
+	if task.Checked || task.SubTasks[1].Checked {
+		t.Error("expected the gate to stay unchecked for a retry")
+	}
+	if !strings.Contains(task.SubTasks[1].FailureReason, "FAIL: TestParse") {
+		t.Errorf("expected test output as the reason, got %q", task.SubTasks[1].FailureReason)
+	}
+}
+
+func TestExecuteSubTask_TestGateUnconfigured(t *testing.T) {
+	tmpDir, _ := setupTestGateProject(t, "exit 0\n")
+	os.Remove(filepath.Join(tmpDir, ".ai", "config.yaml"))
+
+	_, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"})
+	var human *HumanNeededError
+	if !errors.As(err, &human) || !strings.Contains(err.Error(), "hooks.test") {
+		t.Errorf("expected a human-needed error naming hooks.test, got %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// Issue is a GitHub issue built from a top-level sprint task
+type Issue struct {
+	Title string
+	Body  string
+}
+
+// IssueClient creates GitHub issues. GHIssueClient uses the gh CLI; tests
+// inject a fake to capture the payloads.
+type IssueClient interface {
+	// CreateIssue creates issue in repo ("owner/name", or "" for the
+	// repository of the working directory) and returns its URL
+	CreateIssue(repo string, issue Issue) (string, error)
+	// SearchIssues returns the issues in repo, open or closed, whose title
+	// contains title. GitHub's search index lags issue creation, so an issue
+	// created moments ago may be missing from the results.
+	SearchIssues(repo string, title string) ([]ExportedIssue, error)
+}
+
+// GHIssueClient creates issues with 'gh issue create', run in Dir so an
+// empty repo means the project's own GitHub repository
+type GHIssueClient struct {
+	Dir string
+}
+
+// CreateIssue runs 'gh issue create', passing the body on stdin
+func (c GHIssueClient) CreateIssue(repo string, issue Issue) (string, error) {
+	args := []string{"issue", "create", "--title", issue.Title, "--body-file", "-"}
+	if repo != "" {
+		args = append(args, "--repo", repo)
+	}
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = c.Dir
+	cmd.Stdin = strings.NewReader(issue.Body)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("gh issue create failed: %s", msg)
+		}
+		return "", fmt.Errorf("gh issue create failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SearchIssues runs 'gh issue list' with a title search
+func (c GHIssueClient) SearchIssues(repo string, title string) ([]ExportedIssue, error) {
+	args := []string{"issue", "list", "--state", "all", "--search", titleSearchQuery(title), "--json", "title,body,url", "--limit", "100"}
+	if repo != "" {
+		args = append(args, "--repo", repo)
+	}
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = c.Dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("gh issue list failed: %s", msg)
+		}
+		return nil, fmt.Errorf("gh issue list failed: %w", err)
+	}
+	var found []struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+		URL   string `json:"url"`
+	}
+	if err := json.Unmarshal(out, &found); err != nil {
+		return nil, fmt.Errorf("failed to parse gh issue list output: %w", err)
+	}
+	issues := make([]ExportedIssue, len(found))
+	for i, f := range found {
+		issues[i] = ExportedIssue{Issue: Issue{Title: f.Title, Body: f.Body}, URL: f.URL}
+	}
+	return issues, nil
+}
+
+// SprintIssues builds one issue per top-level task: the task text as the
+// title and its sub-tasks as a checklist, checked where complete
+func SprintIssues(sprint *SprintState, sprintNum int) []Issue {
+	var issues []Issue
+	for _, task := range sprint.Tasks {
+		var body strings.Builder
+		body.WriteString(fmt.Sprintf("Task %d of sprint %d (`%s`).\n", task.Index+1, sprintNum, filepath.Base(sprint.FilePath)))
+		if task.Skipped {
+			body.WriteString("\nSkipped by `agate next --keep-going`: it kept failing review.\n")
+		}
+		if len(task.SubTasks) > 0 {
+			body.WriteString("\n")
+		}
+		for _, sub := range task.SubTasks {
+			box := "[ ]"
+			if sub.Checked {
+				box = "[x]"
+			}
+			body.WriteString(fmt.Sprintf("- %s %s: %s\n", box, sub.Skill, sub.Text))
+		}
+		issues = append(issues, Issue{Title: task.Text, Body: body.String()})
+	}
+	return issues
+}
+
+// ExportedIssue is an issue created by ExportIssues and its URL
+type ExportedIssue struct {
+	Issue
+	URL string
+	// Existing is set when the issue was found from an earlier export
+	// instead of being created
+	Existing bool
+}
+
+// ExportIssues creates a GitHub issue for each top-level task of sprint
+// sprintNum (0 for the current sprint). The sprint file is only read. A
+// task whose issue already exists, from an earlier or interrupted export,
+// is not created again, so re-running is safe once GitHub's search has
+// indexed the earlier issues. On a failure the issues handled so far are
+// returned with the error.
+func ExportIssues(proj *project.Project, sprintNum int, repo string, client IssueClient) ([]ExportedIssue, error) {
+	sprintPath, sprintNum, err := resolveSprint(proj, sprintNum)
+	if err != nil {
+		return nil, err
+	}
+	sprint, err := ParseSprint(sprintPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sprint: %w", err)
+	}
+
+	var exported []ExportedIssue
+	for _, issue := range SprintIssues(sprint, sprintNum) {
+		existing, err := findExportedIssue(client, repo, issue)
+		if err != nil {
+			return exported, fmt.Errorf("failed to look up issue %q: %w", issue.Title, err)
+		}
+		if existing != nil {
+			exported = append(exported, *existing)
+			continue
+		}
+		url, err := client.CreateIssue(repo, issue)
+		if err != nil {
+			return exported, fmt.Errorf("failed to create issue %q: %w", issue.Title, err)
+		}
+		exported = append(exported, ExportedIssue{Issue: issue, URL: url})
+	}
+	return exported, nil
+}
+
+// titleSearchQuery returns a GitHub search query for issues whose title
+// contains title. The title is quoted as a phrase so words like "is:open"
+// or "-draft" in it aren't read as qualifiers; search syntax has no escape
+// for a quote inside a phrase, so quotes become spaces, which the search
+// ignores like other punctuation.
+func titleSearchQuery(title string) string {
+	return `"` + strings.ReplaceAll(title, `"`, " ") + `" in:title`
+}
+
+// findExportedIssue returns the issue an earlier export created for issue's
+// task, or nil. It must have the same title and the same first body line,
+// which names the task's number and sprint file, so a same-named task in
+// another sprint isn't mistaken for it.
+func findExportedIssue(client IssueClient, repo string, issue Issue) (*ExportedIssue, error) {
+	found, err := client.SearchIssues(repo, issue.Title)
+	if err != nil {
+		return nil, err
+	}
+	header, _, _ := strings.Cut(issue.Body, "\n")
+	for _, f := range found {
+		first, _, _ := strings.Cut(strings.ReplaceAll(f.Body, "\r\n", "\n"), "\n")
+		if f.Title == issue.Title && strings.TrimSpace(first) == header {
+			f.Existing = true
+			return &f, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,160 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// fakeIssueClient records the issues it is asked to create, and finds the
+// ones it created successfully
+type fakeIssueClient struct {
+	repo    string
+	issues  []Issue
+	created []ExportedIssue
+	failAt  int // 1-based call that fails; 0 never fails
+}
+
+func (f *fakeIssueClient) CreateIssue(repo string, issue Issue) (string, error) {
+	f.repo = repo
+	f.issues = append(f.issues, issue)
+	if len(f.issues) == f.failAt {
+		return "", fmt.Errorf("rate limited")
+	}
+	url := fmt.Sprintf("https://github.com/%s/issues/%d", repo, len(f.issues))
+	f.created = append(f.created, ExportedIssue{Issue: issue, URL: url})
+	return url, nil
+}
+
+func (f *fakeIssueClient) SearchIssues(repo string, title string) ([]ExportedIssue, error) {
+	var found []ExportedIssue
+	for _, c := range f.created {
+		if strings.Contains(c.Title, title) {
+			found = append(found, c)
+		}
+	}
+	return found, nil
+}
+
+func TestExportIssues_Payloads(t *testing.T) {
+	sprint := "# Sprint 1\n\n" +
+		"- [x] Add the parser\n  - [x] go-coder: Write the parser\n  - [x] _reviewer: Review the parser\n\n" +
+		"- [ ] ❌ Add the CLI\n  - [x] go-coder: Wire up flags\n  - [ ] _reviewer: Review the CLI\n"
+	tmpDir := setupExecutionProject(t, map[string]string{"01-initial.md": sprint})
+	sprintPath := filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md")
+
+	client := &fakeIssueClient{}
+	exported, err := ExportIssues(project.New(tmpDir), 0, "acme/widgets", client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Issue{
+		{
+			Title: "Add the parser",
+			Body:  "Task 1 of sprint 1 (`01-initial.md`).\n\n- [x] go-coder: Write the parser\n- [x] _reviewer: Review the parser\n",
+		},
+		{
+			Title: "Add the CLI",
+			Body:  "Task 2 of sprint 1 (`01-initial.md`).\n\n- [x] go-coder: Wire up flags\n- [ ] _reviewer: Review the CLI\n",
+		},
+	}
+	if len(client.issues) != len(want) {
+		t.Fatalf("expected %d issues, got %d: %+v", len(want), len(client.issues), client.issues)
+	}
+	for i := range want {
+		if client.issues[i] != want[i] {
+			t.Errorf("issue %d:\ngot  %+v\nwant %+v", i+1, client.issues[i], want[i])
+		}
+	}
+	if client.repo != "acme/widgets" {
+		t.Errorf("expected repo acme/widgets, got %q", client.repo)
+	}
+	if len(exported) != 2 || exported[1].URL != "https://github.com/acme/widgets/issues/2" {
+		t.Errorf("expected exported issues with URLs, got %+v", exported)
+	}
+	if data, _ := os.ReadFile(sprintPath); string(data) != sprint {
+		t.Errorf("expected the sprint file untouched, got:\n%s", data)
+	}
+}
+
+func TestExportIssues_StopsOnFailure(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] One\n  - [ ] go-coder: A\n\n- [ ] Two\n  - [ ] go-coder: B\n\n- [ ] Three\n  - [ ] go-coder: C\n",
+	})
+
+	client := &fakeIssueClient{failAt: 2}
+	exported, err := ExportIssues(project.New(tmpDir), 1, "", client)
+	if err == nil {
+		t.Fatal("expected an error from the failing issue")
+	}
+	if len(exported) != 1 || exported[0].Title != "One" {
+		t.Errorf("expected the issue created before the failure, got %+v", exported)
+	}
+	if len(client.issues) != 2 {
+		t.Errorf("expected no issues after the failure, got %d calls", len(client.issues))
+	}
+
+	if _, err := ExportIssues(project.New(tmpDir), 9, "", &fakeIssueClient{}); err == nil {
+		t.Error("expected an error for a missing sprint")
+	}
+}
+
+// TestExportIssues_ResumesWithoutDuplicates verifies re-running an export
+// that failed partway creates only the missing issues, and that a task with
+// the same title in another sprint still gets its own issue.
+func TestExportIssues_ResumesWithoutDuplicates(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] One\n  - [ ] go-coder: A\n\n- [ ] Two\n  - [ ] go-coder: B\n\n- [ ] Three\n  - [ ] go-coder: C\n",
+		"02-next.md":    "# Sprint 2\n\n- [ ] One\n  - [ ] go-coder: A again\n",
+	})
+
+	client := &fakeIssueClient{failAt: 2}
+	if _, err := ExportIssues(project.New(tmpDir), 1, "acme/widgets", client); err == nil {
+		t.Fatal("expected an error from the failing issue")
+	}
+	client.failAt = 0
+
+	exported, err := ExportIssues(project.New(tmpDir), 1, "acme/widgets", client)
+	if err != nil {
+		t.Fatalf("unexpected error on re-run: %v", err)
+	}
+	if len(exported) != 3 || !exported[0].Existing || exported[1].Existing || exported[2].Existing {
+		t.Fatalf("expected the first issue found and the rest created, got %+v", exported)
+	}
+	if exported[0].URL != "https://github.com/acme/widgets/issues/1" {
+		t.Errorf("expected the existing issue's URL, got %q", exported[0].URL)
+	}
+	if len(client.created) != 3 {
+		t.Errorf("expected 3 issues in total, got %+v", client.created)
+	}
+
+	if exported, err = ExportIssues(project.New(tmpDir), 1, "acme/widgets", client); err != nil || len(client.created) != 3 {
+		t.Errorf("expected a third run to create nothing, got %d issues (%v)", len(client.created), err)
+	}
+
+	exported, err = ExportIssues(project.New(tmpDir), 2, "acme/widgets", client)
+	if err != nil || len(exported) != 1 || exported[0].Existing {
+		t.Errorf("expected sprint 2's \"One\" to be created, got %+v (%v)", exported, err)
+	}
+}
+
+func TestTitleSearchQuery(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Add CLI", `"Add CLI" in:title`},
+		{"Close is:open issues -draft", `"Close is:open issues -draft" in:title`},
+		{`Support "quoted" args`, `"Support  quoted  args" in:title`},
+	}
+	for _, tt := range tests {
+		if got := titleSearchQuery(tt.title); got != tt.want {
+			t.Errorf("titleSearchQuery(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package workflow
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// journalName is the append-only log of sprint checkbox mutations. It lives
+// in the sprints directory and is hidden, so sprint listings (which only
+// look at .md files) never see it.
+const journalName = ".journal.jsonl"
+
+// Mutation operations recorded in the journal
+const (
+	OpCheck   = "check"   // A task or sub-task was checked
+	OpUncheck = "uncheck" // A task or sub-task was unchecked
+	OpFailure = "failure" // A ❌ was added to a task
+)
+
+// ErrNothingToUndo is returned by Undo when the journal is empty
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// JournalEntry records one line-level change to a sprint file
+type JournalEntry struct {
+	File   string `json:"file"` // Sprint file name within the sprints directory
+	Line   int    `json:"line"` // 1-based line number
+	Op     string `json:"op"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// JournalPath returns the mutation journal path for a sprints directory
+func JournalPath(sprintsDir string) string {
+	return filepath.Join(sprintsDir, journalName)
+}
+
+// lineAt returns the 1-based line lineNum of content, or "" if out of range
+func lineAt(content string, lineNum int) string {
+	lines := strings.Split(content, "\n")
+	if lineNum < 1 || lineNum > len(lines) {
+		return ""
+	}
+	return lines[lineNum-1]
+}
+
+// recordMutation journals the change to lineNum from before to its current
+// content. The sprint file is already written, so a journal failure only
+// warns rather than failing the step.
+func (s *SprintState) recordMutation(op string, lineNum int, before string) {
+	after := lineAt(s.Content, lineNum)
+	if after == before || s.FilePath == "" {
+		return
+	}
+	entry := JournalEntry{
+		File:   filepath.Base(s.FilePath),
+		Line:   lineNum,
+		Op:     op,
+		Before: before,
+		After:  after,
+	}
+	if err := appendJournal(JournalPath(filepath.Dir(s.FilePath)), entry); err != nil {
+		s.reporter().Warn(fmt.Sprintf("Warning: failed to journal sprint change: %v", err))
+	}
+}
+
+// appendJournal appends one JSON line to the journal at path
+func appendJournal(path string, entry JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readJournal returns the journal entries at path, oldest first
+func readJournal(path string) ([]JournalEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid journal entry on line %d: %w", n, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// writeJournal replaces the journal at path with entries
+func writeJournal(path string, entries []JournalEntry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// Undo reverts the most recent journaled mutation in sprintsDir and removes
+// it from the journal. It refuses if the line no longer matches what the
+// mutation wrote, since the file has been edited since.
+func Undo(sprintsDir string) (*JournalEntry, error) {
+	path := JournalPath(sprintsDir)
+	entries, err := readJournal(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, ErrNothingToUndo
+	}
+	last := entries[len(entries)-1]
+
+	sprintPath := filepath.Join(sprintsDir, last.File)
+	content, err := os.ReadFile(sprintPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sprint: %w", err)
+	}
+	lines := strings.Split(string(content), "\n")
+	if last.Line < 1 || last.Line > len(lines) || lines[last.Line-1] != last.After {
+		return nil, fmt.Errorf("%s line %d has changed since the last %s; edit it by hand", last.File, last.Line, last.Op)
+	}
+
+	lines[last.Line-1] = last.Before
+	if err := os.WriteFile(sprintPath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write sprint: %w", err)
+	}
+	if err := writeJournal(path, entries[:len(entries)-1]); err != nil {
+		return nil, fmt.Errorf("failed to update journal: %w", err)
+	}
+	return &last, nil
+}
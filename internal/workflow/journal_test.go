@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeJournalSprint(t *testing.T, content string) *SprintState {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "01-initial.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sprint, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sprint
+}
+
+func TestUndo_CheckThenUndoRestoresFile(t *testing.T) {
+	original := "# Sprint 1\n\n- [ ] Build parser\n  - [x] go-coder: Write parser\n  - [ ] _reviewer: Review parser\n"
+	sprint := writeJournalSprint(t, original)
+	sprintsDir := filepath.Dir(sprint.FilePath)
+
+	if err := sprint.CheckSubTask(0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := sprint.CheckTask(0); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := Undo(sprintsDir)
+	if err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if entry.Op != OpCheck || entry.Line != 3 || entry.Before != "- [ ] Build parser" {
+		t.Errorf("expected the task check to be undone first, got %+v", entry)
+	}
+	if _, err := Undo(sprintsDir); err != nil {
+		t.Fatalf("second Undo failed: %v", err)
+	}
+
+	data, err := os.ReadFile(sprint.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != original {
+		t.Errorf("expected original content after undo, got:\n%s", data)
+	}
+	if _, err := Undo(sprintsDir); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected ErrNothingToUndo, got %v", err)
+	}
+}
+
+func TestRecordMutation_WarnsThroughReporter(t *testing.T) {
+	sprint := writeJournalSprint(t, "# Sprint 1\n\n- [ ] Build parser\n  - [ ] go-coder: Write parser\n")
+	// A directory in the journal's place makes the append fail
+	if err := os.Mkdir(JournalPath(filepath.Dir(sprint.FilePath)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	report := &recordingReporter{}
+	sprint.Reporter = report
+
+	if err := sprint.CheckSubTask(0, 0); err != nil {
+		t.Fatalf("a journal failure should not fail the check: %v", err)
+	}
+	if _, ok := report.find("warn", "failed to journal sprint change"); !ok {
+		t.Errorf("expected a journal warning, got %+v", report.messages)
+	}
+}
+
+func TestUndo_Failure(t *testing.T) {
+	original := "# Sprint 1\n\n- [ ] Build parser\n  - [x] go-coder: Write parser\n  - [x] _reviewer: Review parser\n"
+	sprint := writeJournalSprint(t, original)
+
+	if err := sprint.UncheckSubTask(0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := sprint.AddFailure(0); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, op := range []string{OpFailure, OpUncheck} {
+		entry, err := Undo(filepath.Dir(sprint.FilePath))
+		if err != nil {
+			t.Fatalf("Undo failed: %v", err)
+		}
+		if entry.Op != op {
+			t.Errorf("expected %s to be undone, got %s", op, entry.Op)
+		}
+	}
+	data, err := os.ReadFile(sprint.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != original {
+		t.Errorf("expected original content after undo, got:\n%s", data)
+	}
+}
+
+func TestUndo_Table(t *testing.T) {
+	original := "# Sprint 1\n\n" +
+		"| Task | Skill | Status |\n" +
+		"|------|-------|--------|\n" +
+		"| Set up project | | todo |\n" +
+		"| Create go.mod | go-coder | todo |\n"
+	sprint := writeJournalSprint(t, original)
+
+	if err := sprint.CheckSubTask(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Undo(filepath.Dir(sprint.FilePath)); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	data, err := os.ReadFile(sprint.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != original {
+		t.Errorf("expected original content after undo, got:\n%s", data)
+	}
+}
+
+func TestUndo_RefusesEditedLine(t *testing.T) {
+	sprint := writeJournalSprint(t, "# Sprint 1\n\n- [ ] Build parser\n  - [ ] go-coder: Write parser\n")
+	if err := sprint.CheckSubTask(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	edited := "# Sprint 1\n\n- [ ] Build parser\n  - [x] go-coder: Write the parser\n"
+	if err := os.WriteFile(sprint.FilePath, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Undo(filepath.Dir(sprint.FilePath)); err == nil || !strings.Contains(err.Error(), "has changed") {
+		t.Errorf("expected changed-line error, got %v", err)
+	}
+	data, err := os.ReadFile(sprint.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != edited {
+		t.Errorf("expected edited file untouched, got:\n%s", data)
+	}
+}
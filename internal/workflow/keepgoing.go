@@ -0,0 +1,54 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// skipTask marks a task that can't get past review as skipped (⏭) so the
+// sprint moves on to its next task, for --keep-going
+func skipTask(sprint *SprintState, task *Task, why string, report Reporter) (*Result, error) {
+	if err := sprint.AddSkipMarker(task.Index); err != nil {
+		return nil, fmt.Errorf("failed to mark task skipped: %w", err)
+	}
+	report.Warn(fmt.Sprintf("⏭ Skipping task %q: %s", NormalizeTaskText(task.Text), why))
+	return &Result{
+		Message:  fmt.Sprintf("Skipped task %d (%s) after it %s. Moving on (--keep-going).", task.Index+1, NormalizeTaskText(task.Text), why),
+		MoreWork: true,
+		ExitCode: ExitMoreWork,
+	}, nil
+}
+
+// holdForSkipped turns a done exit into human-needed while the sprint
+// still has skipped tasks, so --keep-going never ends on a clean exit
+func holdForSkipped(sprint *SprintState, exitCode int) int {
+	if exitCode == ExitDone && len(sprint.SkippedTasks()) > 0 {
+		return ExitHumanNeeded
+	}
+	return exitCode
+}
+
+// skippedSummary lists a sprint's skipped tasks with their last review
+// failure, or returns "" if none were skipped
+func skippedSummary(sprint *SprintState) string {
+	skipped := sprint.SkippedTasks()
+	if len(skipped) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⏭ Skipped %d task%s (--keep-going); they need a human:", len(skipped), plural(len(skipped))))
+	for _, task := range skipped {
+		line := fmt.Sprintf("\n  - Task %d: %s", task.Index+1, NormalizeTaskText(task.Text))
+		if task.FailureCount > 0 {
+			line += fmt.Sprintf(" (failed review %d time%s)", task.FailureCount, plural(task.FailureCount))
+		}
+		for _, sub := range task.SubTasks {
+			if sub.FailureReason != "" {
+				line += ": " + sub.FailureReason
+				break
+			}
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
@@ -2,11 +2,14 @@ package workflow
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,26 +29,73 @@ func (e *HumanNeededError) Error() string {
 	return e.Message
 }
 
+// AutoAgent is the PreferredAgent that tries each available agent in turn
+// until one runs a sub-task successfully
+const AutoAgent = "auto"
+
 // NextOptions contains options for the Next workflow
 type NextOptions struct {
 	// StreamOutput enables streaming agent output to this writer
 	StreamOutput io.Writer
-	// PreferredAgent overrides automatic agent selection
+	// Events, if set, receives each invocation's file_written and done
+	// events (see agent.ExecuteOptions.Events)
+	Events *agent.EventWriter
+	// PreferredAgent overrides automatic agent selection. AutoAgent picks
+	// as usual but falls back through the other available agents when a
+	// sub-task's agent fails to run.
 	PreferredAgent string
+	// ModelOptions sets the model and reasoning effort of the agents that
+	// support them (codex)
+	ModelOptions agent.ModelOptions
+	// TaskNumber targets a specific top-level task (1-based) instead of the
+	// first incomplete one (0 = no target)
+	TaskNumber int
+	// PhaseOnly runs at most one planning phase and never executes sprint
+	// tasks, even once planning is complete
+	PhaseOnly bool
+	// NoRecovery surfaces sub-task execution errors as-is instead of
+	// running the _recover agent and retrying
+	NoRecovery bool
+	// DoubleReview runs reviewer sub-tasks on two agents in parallel and
+	// only approves when both do
+	DoubleReview bool
+	// StrictReview fails any review whose response lacks a line that is
+	// just APPROVED (or SPRINT_COMPLETE), instead of accepting the token
+	// anywhere in the text
+	StrictReview bool
+	// StrictSkills stops for a human when a sub-task names a skill that
+	// doesn't exist, instead of warning and running it without guidance
+	StrictSkills bool
+	// KeepGoing marks a task that has exhausted its review retries and
+	// replan as skipped (⏭) and moves on, instead of stopping for a human
+	KeepGoing bool
+	// NoWriteFiles discards the files in an implementation's output instead
+	// of writing them, and skips the check that an implementation changed a
+	// file. Replay sets it for a dry run with the dummy agent.
+	NoWriteFiles bool
+	// Fresh regenerates the most recently produced planning artifact (see
+	// FreshTarget) instead of advancing to the next phase
+	Fresh bool
+	// Reporter receives the step's progress messages and warnings
+	// (default: StdoutReporter)
+	Reporter Reporter
 }
 
 // Next executes the next step in the workflow
-func Next(projectDir string) (*Result, error) {
-	return NextWithOptions(projectDir, NextOptions{})
+func Next(proj *project.Project) (*Result, error) {
+	return NextWithOptions(proj, NextOptions{})
 }
 
 // NextWithOptions executes the next step with options
-func NextWithOptions(projectDir string, opts NextOptions) (*Result, error) {
-	proj := project.New(projectDir)
+func NextWithOptions(proj *project.Project, opts NextOptions) (*Result, error) {
+	projectDir := proj.Dir
 
 	// Check for GOAL.md
 	if !proj.HasGoal() {
-		return nil, fmt.Errorf("GOAL.md not found. Create a GOAL.md file describing what you want to build")
+		return nil, &HumanNeededError{Message: "GOAL.md not found. Create a GOAL.md file describing what you want to build"}
+	}
+	if err := proj.CheckGoal(); err != nil {
+		return nil, &HumanNeededError{Message: err.Error()}
 	}
 
 	// Check for agents
@@ -53,18 +103,50 @@ func NextWithOptions(projectDir string, opts NextOptions) (*Result, error) {
 		return nil, fmt.Errorf("%w\n\n%s", err, agent.FormatInstallInstructions())
 	}
 
+	// Bound simultaneous agent runs; a bad config is reported where it's used
+	if cfg, err := proj.LoadConfig(); err == nil {
+		agent.SetMaxConcurrent(cfg.MaxConcurrentAgents)
+	}
+
 	// Use GetStatus for unified state detection
 	fsys := os.DirFS(projectDir)
-	status := GetStatus(fsys)
+	status := GetStatus(fsys, proj.StateDir())
+
+	if opts.TaskNumber < 0 {
+		return nil, fmt.Errorf("task number must be positive, got %d", opts.TaskNumber)
+	}
+	if opts.PhaseOnly && opts.TaskNumber > 0 {
+		return nil, fmt.Errorf("cannot target task %d with phase-only: phase-only never executes sprint tasks", opts.TaskNumber)
+	}
+
+	if opts.Fresh {
+		if opts.TaskNumber > 0 {
+			return nil, fmt.Errorf("cannot target task %d with fresh: fresh only regenerates planning artifacts", opts.TaskNumber)
+		}
+		return RegeneratePlanArtifact(proj, PlanOptions{
+			StreamOutput:   opts.StreamOutput,
+			Events:         opts.Events,
+			PreferredAgent: opts.PreferredAgent,
+			ModelOptions:   opts.ModelOptions,
+			Reporter:       opts.Reporter,
+		})
+	}
 
-	// Check if we're still in planning phases
-	if status.Phase != PhaseExecution {
+	// Check if we're still in planning phases (phase-only stays here even
+	// once planning is complete, and reports that instead of running a task)
+	if status.Phase != PhaseExecution || opts.PhaseOnly {
+		if opts.TaskNumber > 0 {
+			return nil, fmt.Errorf("cannot target task %d: project is still in the %s phase", opts.TaskNumber, status.Phase)
+		}
 		// Execute ONE planning phase
 		planOpts := PlanOptions{
 			StreamOutput:   opts.StreamOutput,
+			Events:         opts.Events,
 			PreferredAgent: opts.PreferredAgent,
+			ModelOptions:   opts.ModelOptions,
+			Reporter:       opts.Reporter,
 		}
-		return ExecutePlanPhase(projectDir, planOpts)
+		return ExecutePlanPhase(proj, planOpts)
 	}
 
 	// Use sprint info from GetStatus
@@ -72,6 +154,7 @@ func NextWithOptions(projectDir string, opts NextOptions) (*Result, error) {
 		return &Result{
 			Message:  "No sprint files found. Run 'agate next' to continue planning.",
 			MoreWork: true,
+			ExitCode: ExitMoreWork,
 		}, nil
 	}
 
@@ -83,57 +166,89 @@ func NextWithOptions(projectDir string, opts NextOptions) (*Result, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse sprint: %w", err)
 	}
+	sprint.Reporter = opts.reporter()
 
-	// Check if sprint is complete
-	if sprint.IsComplete() {
-		return assessGoalAndPlanNext(projectDir, proj, sprintNum, opts)
+	// Auto-check orphaned tasks (no subtasks, or all subtasks done but task
+	// unchecked) before deciding completion, so a sprint whose work is all
+	// done is closed instead of stalling on unchecked top-level boxes
+	if fixed := autoCheckOrphanedTasks(sprint, opts.reporter()); fixed > 0 {
+		sprint, err = sprint.reparse()
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-parse sprint: %w", err)
+		}
 	}
 
-	// Get the next sub-task to work on
-	subTask := sprint.GetNextSubTask()
-	if subTask == nil {
-		// Auto-check orphaned tasks (no subtasks, or all subtasks done but task unchecked)
-		fixed := autoCheckOrphanedTasks(sprint)
-		if fixed > 0 {
-			// Re-parse and retry once
-			sprint, err = ParseSprint(sprintPath)
-			if err != nil {
-				return nil, fmt.Errorf("failed to re-parse sprint: %w", err)
-			}
-			if sprint.IsComplete() {
-				return assessGoalAndPlanNext(projectDir, proj, sprintNum, opts)
+	// Escalate a sprint that keeps running without completing anything
+	if !sprint.IsComplete() {
+		cfg, err := proj.LoadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		if err := checkSprintProgress(proj.SprintsDir(), sprint, sprintNum, cfg.StallLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	var subTask *SubTask
+	var currentTask *Task
+	if opts.TaskNumber > 0 {
+		// Work on the requested task out of order
+		subTask, err = sprint.GetNextSubTaskForTask(opts.TaskNumber - 1)
+		if err != nil {
+			return nil, err
+		}
+		currentTask = &sprint.Tasks[subTask.ParentIndex]
+	} else {
+		// Check if sprint is complete
+		if sprint.IsComplete() {
+			result, err := assessGoalAndPlanNext(projectDir, proj, sprintNum, opts)
+			if err == nil {
+				if summary := skippedSummary(sprint); summary != "" {
+					result.Message += "\n\n" + summary
+				}
+				result.ExitCode = holdForSkipped(sprint, result.ExitCode)
 			}
-			subTask = sprint.GetNextSubTask()
+			return result, err
 		}
+
+		// Get the next sub-task to work on
+		subTask = sprint.GetNextSubTask()
 		if subTask == nil {
 			return &Result{
 				Message:  "No more tasks in current sprint.",
 				MoreWork: false,
+				ExitCode: ExitMoreWork, // The sprint isn't complete, so automation may retry
 			}, nil
 		}
-	}
 
-	// Get the parent task for context
-	currentTask := sprint.GetCurrentTask()
-	if currentTask == nil {
-		return nil, fmt.Errorf("no current task found")
+		// Get the parent task for context
+		currentTask = sprint.GetCurrentTask()
+		if currentTask == nil {
+			return nil, fmt.Errorf("no current task found")
+		}
 	}
 
 	// Create logger
-	logger := logging.NewLogger(projectDir, sprintNum)
+	logger := logging.NewLogger(proj, sprintNum)
 
 	// Check if task has exceeded review retry limit
 	if currentTask.FailureCount >= maxReviewRetries {
 		// If already replanned, give up
 		if currentTask.ReplanCount > 0 {
+			if opts.KeepGoing {
+				return skipTask(sprint, currentTask, fmt.Sprintf("failed review %d times even after replan", currentTask.FailureCount), opts.reporter())
+			}
 			return nil, &HumanNeededError{
 				Message: fmt.Sprintf("task %q has failed review %d times (max %d) even after replan, human intervention needed", currentTask.Text, currentTask.FailureCount, maxReviewRetries),
 			}
 		}
 		// Attempt replan
-		fmt.Println(logging.Yellow("⚠ Review failed too many times. Attempting sprint replan..."))
+		opts.reporter().Warn("⚠ Review failed too many times. Attempting sprint replan...")
 		result, err := attemptReplan(projectDir, proj, sprint, currentTask, logger, opts)
 		if err != nil {
+			if opts.KeepGoing {
+				return skipTask(sprint, currentTask, fmt.Sprintf("failed review %d times and replan failed: %v", currentTask.FailureCount, err), opts.reporter())
+			}
 			return nil, &HumanNeededError{
 				Message: fmt.Sprintf("task %q has failed review %d times and replan failed: %v", currentTask.Text, currentTask.FailureCount, err),
 			}
@@ -152,22 +267,31 @@ func NextWithOptions(projectDir string, opts NextOptions) (*Result, error) {
 
 // executeSubTask runs a single sub-task. isRecovery prevents recursive recovery attempts.
 func executeSubTask(projectDir string, proj *project.Project, sprint *SprintState, task *Task, subTask *SubTask, logger *logging.Logger, opts NextOptions, isRecovery bool) (*Result, error) {
-	// Determine which agent to use
-	agentName := opts.PreferredAgent
-	if agentName == "" {
-		agentName = selectAgentForSkill(subTask.Skill)
+	// A test gate is decided by the test commands, not by an agent
+	if subTask.Skill == testGateSkill {
+		return executeTestGate(projectDir, proj, sprint, task, subTask, logger, opts.reporter())
 	}
 
-	selectedAgent := agent.GetAgentByName(agentName)
-	if selectedAgent == nil || !selectedAgent.Available() {
-		// Fall back to first available
-		agents := agent.GetAvailableAgents()
-		if len(agents) == 0 {
-			return nil, agent.NoAgentsError{}
+	report := opts.reporter()
+
+	// Load skills for agent restrictions and context
+	skills := loadExecutionSkills(proj, report)
+	skill := project.GetSkillByName(skills, subTask.Skill)
+	phase := subTaskPhase(subTask.Skill, skill)
+	if warning := unknownSkillWarning(proj, subTask.Skill, skills); warning != "" {
+		if opts.StrictSkills {
+			return nil, &HumanNeededError{Message: warning + "; fix the sub-task's skill name or add the skill (--strict-skills)"}
 		}
-		selectedAgent = agents[0]
+		report.Warn("⚠ " + warning + "; the agent gets no skill guidance")
 	}
 
+	// Determine which agent to use; --agent wins over the sprint's default
+	selectedAgent, err := selectAgent(sprint.preferredAgent(opts.PreferredAgent), subTask.Skill, skills, report)
+	if err != nil {
+		return nil, err
+	}
+	selectedAgent = opts.ModelOptions.Apply(selectedAgent)
+
 	// Build context from project
 	designContent := ""
 	if designPath := filepath.Join(proj.DesignDir(), "overview.md"); fileExists(designPath) {
@@ -176,26 +300,46 @@ func executeSubTask(projectDir string, proj *project.Project, sprint *SprintStat
 		}
 	}
 
-	// Load skills for context
-	skills, _ := project.LoadSkills(proj.SkillsDir())
-	skillContent := getSkillContent(skills, subTask.Skill)
+	cfg, err := proj.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.TaskTimeout)
 	defer cancel()
 
-	// Build prompt based on skill type
-	prompt := buildSubTaskPrompt(task, subTask, designContent, skillContent, sprint)
-
-	// Get sprint number for display
+	// Get sprint number for display and prior-sprint context
 	sprintNum := ExtractSprintNum(filepath.Base(sprint.FilePath))
+	priorSprints := loadCompletedSprintSummaries(proj.SprintsDir(), sprintNum-1)
+
+	// Append the output of the skill's context commands to its guidelines
+	skill = withCommandContext(ctx, projectDir, skill)
+
+	// Build prompt based on skill type
+	promptData := PromptData{
+		Design: designContent,
+		Task:   PromptTask{Text: task.Text, SubTask: subTask.Text},
+		Skill:  PromptSkill{Name: subTask.Skill, Phase: phase},
+	}
+	if skill != nil {
+		promptData.Skill.Content = skill.Content
+	}
+	prompt := renderPrompt(proj.PromptsDir(), promptSubTask, promptData, buildSubTaskPrompt(task, subTask, designContent, skill, priorSprints, sprint), report)
 	taskSummary := TruncateText(subTask.Text, 50)
 
 	// Show progress bar before invocation so user sees where we are
-	progressBar := sprint.RenderProgressBar(sprintNum, task.Index, subTask.Index)
-	fmt.Printf("%s\n", progressBar)
+	progressBar := sprint.RenderProgressBar(sprintNum, task.Index, subTask.Index, liveProgressBar)
+	report.Info(progressBar)
+
+	// Implementation tasks write files from the output; record them in the log
+	var writeFiles func(string) []string
+	if phase == phaseImplement && !opts.NoWriteFiles {
+		writeFiles = func(output string) []string {
+			return parseAndWriteFiles(projectDir, cfg.Root, output, report)
+		}
+	}
 
-	// Execute with logging
-	execResult := agent.ExecuteWithLogging(ctx, selectedAgent, prompt, projectDir, agent.ExecuteOptions{
+	execOpts := agent.ExecuteOptions{
 		Logger:        logger,
 		Phase:         "implement",
 		Task:          subTask.Text,
@@ -203,83 +347,177 @@ func executeSubTask(projectDir string, proj *project.Project, sprint *SprintStat
 		Skill:         subTask.Skill,
 		PromptSummary: taskSummary,
 		StreamWriter:  opts.StreamOutput,
-	})
+		Events:        opts.Events,
+		WriteFiles:    writeFiles,
+		KeepRaw:       cfg.KeepRawResponses,
+		// Agents like codex may write files directly instead of emitting
+		// them; record those too, but not agate's own state
+		TrackWrites: phase == phaseImplement && !opts.NoWriteFiles,
+		TrackIgnore: []string{stateRel(proj, proj.DataDir())},
+		Warn:        report.Warn,
+	}
+
+	// Execute with logging, on two reviewers for --double-review
+	isReviewer := phase == phaseReview
+	var execResult agent.Result
+	var second agent.Agent
+	if opts.DoubleReview && isReviewer {
+		second = opts.ModelOptions.Apply(secondReviewer(selectedAgent, skill))
+		if second == nil {
+			report.Warn(fmt.Sprintf("⚠ No second agent available for double review; reviewing with %s only", selectedAgent.Name()))
+		}
+	}
+	if second != nil {
+		// Parallel output would interleave, so don't stream
+		execOpts.StreamWriter = nil
+		results := agent.NewMultiAgent([]agent.Agent{selectedAgent, second}).ExecuteAllWithLogging(ctx, prompt, projectDir, execOpts)
+		execResult = combineReviews(results, opts.StrictReview, report)
+	} else if opts.PreferredAgent == AutoAgent {
+		candidates := []agent.Agent{selectedAgent}
+		for _, a := range fallbackAgents(selectedAgent, skill) {
+			candidates = append(candidates, opts.ModelOptions.Apply(a))
+		}
+		execResult = agent.NewMultiAgent(candidates).ExecuteInOrderWithLogging(ctx, prompt, projectDir, execOpts)
+		if ran := agent.GetAgentByName(execResult.AgentName); ran != nil {
+			selectedAgent = ran
+		}
+	} else {
+		execResult = agent.ExecuteWithLogging(ctx, selectedAgent, prompt, projectDir, execOpts)
+	}
 
 	if execResult.Error != nil {
+		// Only a human can log an agent in; recovery would fail the same way
+		var authErr *agent.AuthError
+		if errors.As(execResult.Error, &authErr) {
+			return nil, fmt.Errorf("failed to execute sub-task: %w", execResult.Error)
+		}
 		if isRecovery {
 			return nil, fmt.Errorf("failed to execute sub-task (after recovery): %w", execResult.Error)
 		}
-		fmt.Println(logging.Yellow("⚠ Agent execution failed. Attempting recovery..."))
+		// On timeout, retry with the next agent in the escalation order
+		// rather than re-running the same slow agent
+		if errors.Is(execResult.Error, context.DeadlineExceeded) {
+			next := cfg.NextEscalationAgent(selectedAgent.Name(), func(name string) bool {
+				a := agent.GetAgentByName(name)
+				return a != nil && a.Available()
+			})
+			if next != "" {
+				report.Warn(fmt.Sprintf("⚠ %s timed out after %s. Retrying with %s...", selectedAgent.Name(), cfg.TaskTimeout, next))
+				escalated := opts
+				escalated.PreferredAgent = next
+				return executeSubTask(projectDir, proj, sprint, task, subTask, logger, escalated, true)
+			}
+		}
+		if opts.NoRecovery {
+			return nil, fmt.Errorf("failed to execute sub-task: %w", execResult.Error)
+		}
+		report.Warn("⚠ Agent execution failed. Attempting recovery...")
 		recoveryErr := attemptRecovery(projectDir, proj, task, subTask,
 			selectedAgent.Name(), execResult, logger, opts)
 		if recoveryErr != nil {
-			fmt.Printf("  %s\n", logging.Yellow(fmt.Sprintf("Recovery failed: %v", recoveryErr)))
+			report.Warn(fmt.Sprintf("  Recovery failed: %v", recoveryErr))
 			return nil, fmt.Errorf("failed to execute sub-task: %w", execResult.Error)
 		}
-		fmt.Println("  " + logging.Yellow("Recovery complete. Retrying original task..."))
+		report.Warn("  Recovery complete. Retrying original task...")
 		return executeSubTask(projectDir, proj, sprint, task, subTask, logger, opts, true)
 	}
 
-	// If this is an implementation task, parse and write files
-	if isImplementationSkill(subTask.Skill) {
-		filesWritten := parseAndWriteFiles(projectDir, execResult.Output)
-		if filesWritten > 0 {
-			fmt.Printf("  %s\n", logging.Green(fmt.Sprintf("Wrote %d file(s)", filesWritten)))
-		}
+	if n := len(execResult.FilesWritten); n > 0 {
+		report.Success(fmt.Sprintf("  Wrote %d file(s)", n))
 	}
 
-	// Check for review failure
-	isReviewer := subTask.Skill == "_reviewer" || strings.HasSuffix(subTask.Skill, "-reviewer")
-	if isReviewer && !isReviewApproved(execResult.Output) {
-		// Review failed - add ❌ to parent task and uncheck subtasks for retry
-		fmt.Println(logging.Yellow("⚠ Review failed. Adding failure marker and unchecking tasks for retry..."))
-
-		// Add failure emoji to the parent task
-		if err := sprint.AddFailure(task.Index); err != nil {
-			fmt.Printf("%s\n", logging.Yellow(fmt.Sprintf("Warning: failed to add failure marker: %v", err)))
-		}
+	// An implementation that changed no project file did nothing, whether
+	// the agent emits files or edits them directly; retry it rather than
+	// checking it off. Like a failed review, this counts toward
+	// maxReviewRetries. Verify-only sub-tasks aren't expected to change files.
+	if phase == phaseImplement && execResult.WritesTracked && len(execResult.FilesWritten) == 0 && !subTask.VerifyOnly {
+		report.Warn("⚠ Implementation changed no files. Adding failure marker and unchecking tasks for retry...")
+		recordSubTaskFailure(sprint, task, subTask, "the implementation changed no project files", report)
+		return &Result{
+			Message:  "Implementation changed no files. Tasks unchecked for retry. Run 'agate next' to try again.",
+			MoreWork: true,
+			ExitCode: ExitMoreWork,
+		}, nil
+	}
 
-		// Uncheck this subtask and all subsequent ones in this task
-		for i := subTask.Index; i < len(task.SubTasks); i++ {
-			if task.SubTasks[i].Checked {
-				if err := sprint.UncheckSubTask(task.Index, i); err != nil {
-					fmt.Printf("%s\n", logging.Yellow(fmt.Sprintf("Warning: failed to uncheck sub-task %d: %v", i, err)))
-				}
-			}
+	// Check for review failure
+	if approved, reason := reviewOutcome(execResult.Output, opts.StrictReview); isReviewer && !approved {
+		if opts.StrictReview && isAmbiguousReview(reason) {
+			report.Warn(fmt.Sprintf("⚠ Strict review: %s; treating it as a failure", reason))
 		}
-		// Re-parse sprint
-		sprint, _ = ParseSprint(sprint.FilePath)
+		// Review failed - add ❌ to parent task and uncheck subtasks for retry
+		report.Warn("⚠ Review failed. Adding failure marker and unchecking tasks for retry...")
+		recordSubTaskFailure(sprint, task, subTask, reason, report)
 		return &Result{
 			Message:  "Review failed. Tasks unchecked for retry. Run 'agate next' to try again.",
 			MoreWork: true,
+			ExitCode: ExitMoreWork,
 		}, nil
 	}
 
+	// Run post-implement hooks (e.g. the test suite); a failing hook counts
+	// as a review failure with the hook output as feedback
+	if phase == phaseImplement && len(cfg.Hooks.PostImplement) > 0 {
+		hookCtx, hookCancel := context.WithTimeout(context.Background(), cfg.TaskTimeout)
+		failure := runHooks(hookCtx, projectDir, "post_implement", cfg.Hooks.PostImplement, logger, subTask)
+		hookCancel()
+		if failure != nil {
+			report.Warn(fmt.Sprintf("⚠ Hook %q failed. Adding failure marker and unchecking tasks for retry...", failure.Command))
+			recordSubTaskFailure(sprint, task, subTask, failure.Reason(), report)
+			return &Result{
+				Message:  "Post-implement hook failed. Tasks unchecked for retry. Run 'agate next' to try again.",
+				MoreWork: true,
+				ExitCode: ExitMoreWork,
+			}, nil
+		}
+	}
+
+	return completeSubTask(sprint, task, subTask, report)
+}
+
+// completeSubTask checks off a sub-task that passed, and its task once every
+// sub-task is done, and reports the sprint's progress
+func completeSubTask(sprint *SprintState, task *Task, subTask *SubTask, report Reporter) (*Result, error) {
+	sprintNum := ExtractSprintNum(filepath.Base(sprint.FilePath))
+
 	// Mark the sub-task as complete
 	if err := sprint.CheckSubTask(task.Index, subTask.Index); err != nil {
 		return nil, fmt.Errorf("failed to mark sub-task complete: %w", err)
 	}
 
 	// Re-parse to get updated state
-	sprint, _ = ParseSprint(sprint.FilePath)
+	sprint, _ = sprint.reparse()
 
 	// Check if all sub-tasks for this task are complete
 	if sprint.AllSubTasksComplete(task.Index) {
 		if err := sprint.CheckTask(task.Index); err != nil {
-			fmt.Printf("%s\n", logging.Yellow(fmt.Sprintf("Warning: failed to mark task complete: %v", err)))
+			report.Warn(fmt.Sprintf("Warning: failed to mark task complete: %v", err))
 		}
-		fmt.Printf("%s\n", logging.Green(fmt.Sprintf("✓ Task complete: %s", task.Text)))
+		report.Success(fmt.Sprintf("✓ Task complete: %s", task.Text))
 	}
 
 	// Re-parse for final state
-	sprint, _ = ParseSprint(sprint.FilePath)
+	sprint, _ = sprint.reparse()
 
 	// Check progress
 	completed, total := sprint.GetOverallProgress()
 	if sprint.IsComplete() {
+		// Done unless a later sprint is already planned or the Definition
+		// of Done still needs its review; the goal assessment that runs
+		// both happens on the next step
+		exitCode := ExitDone
+		if sprint.DoDPending() || findSprintByNum(filepath.Dir(sprint.FilePath), sprintNum+1) != "" {
+			exitCode = ExitMoreWork
+		}
+		message := fmt.Sprintf("Sprint complete! All %d tasks done.", total)
+		if summary := skippedSummary(sprint); summary != "" {
+			skipped := len(sprint.SkippedTasks())
+			message = fmt.Sprintf("Sprint finished: %d of %d tasks done, %d skipped.\n\n%s", len(sprint.Tasks)-skipped, len(sprint.Tasks), skipped, summary)
+		}
 		return &Result{
-			Message:  fmt.Sprintf("Sprint complete! All %d tasks done.", total),
+			Message:  message,
 			MoreWork: true, // There might be more sprints
+			ExitCode: holdForSkipped(sprint, exitCode),
 		}, nil
 	}
 
@@ -290,9 +528,175 @@ func executeSubTask(projectDir string, proj *project.Project, sprint *SprintStat
 	return &Result{
 		Message:  fmt.Sprintf("Sub-task complete (%d%%). Run 'agate next' to continue.", pct),
 		MoreWork: true,
+		ExitCode: ExitMoreWork,
 	}, nil
 }
 
+// fallbackAgents returns the other available agents permitted for the skill,
+// in availability order, for --agent auto to try after first. The dummy
+// agent is never a fallback, since its output would pass for real work.
+func fallbackAgents(first agent.Agent, skill *project.Skill) []agent.Agent {
+	var agents []agent.Agent
+	for _, a := range agent.GetAvailableAgents() {
+		if a.Name() == first.Name() || a.Name() == "dummy" || !agentPermitted(skill, a.Name()) {
+			continue
+		}
+		agents = append(agents, a)
+	}
+	return agents
+}
+
+// secondReviewer picks an independent agent to review alongside first:
+// another available agent permitted for the skill, preferring one that runs
+// a different CLI (so claude isn't double-checked by haiku when codex is
+// installed). The dummy agent never counts. Returns nil if there is none.
+func secondReviewer(first agent.Agent, skill *project.Skill) agent.Agent {
+	sameCLI := func(a, b string) bool {
+		claude := func(name string) bool { return name == "claude" || name == "haiku" }
+		return a == b || (claude(a) && claude(b))
+	}
+	var fallback agent.Agent
+	for _, a := range agent.GetAvailableAgents() {
+		if a.Name() == first.Name() || a.Name() == "dummy" || !agentPermitted(skill, a.Name()) {
+			continue
+		}
+		if !sameCLI(a.Name(), first.Name()) {
+			return a
+		}
+		if fallback == nil {
+			fallback = a
+		}
+	}
+	return fallback
+}
+
+// combineReviews merges parallel review results into one whose output
+// starts with a single verdict: APPROVED only if every reviewer that ran
+// approved, otherwise ISSUES_FOUND with each dissenting reviewer's reason.
+// If no reviewer ran successfully, the first failure is returned. strict
+// applies --strict-review to each reviewer's response.
+func combineReviews(results []agent.Result, strict bool, report Reporter) agent.Result {
+	successful := agent.GetSuccessfulResults(results)
+	if len(successful) == 0 {
+		return results[0]
+	}
+
+	var names, issues []string
+	for _, r := range results {
+		if r.Error != nil {
+			report.Warn(fmt.Sprintf("⚠ %s review failed: %v", r.AgentName, r.Error))
+			continue
+		}
+		names = append(names, r.AgentName)
+		if approved, reason := reviewOutcome(r.Output, strict); !approved {
+			if reason == "" {
+				reason = "did not approve"
+			}
+			issues = append(issues, fmt.Sprintf("%s: %s", r.AgentName, reason))
+		}
+	}
+
+	verdict := "APPROVED"
+	if len(issues) > 0 {
+		verdict = "ISSUES_FOUND: " + strings.Join(issues, "; ")
+	}
+	return agent.Result{
+		AgentName: strings.Join(names, "+"),
+		Output:    verdict + "\n\n" + agent.MergeResults(successful),
+	}
+}
+
+// recordSubTaskFailure adds a ❌ to the parent task, records reason on the
+// sub-task line for the next attempt, and unchecks the sub-task and all
+// subsequent ones in the task for retry
+func recordSubTaskFailure(sprint *SprintState, task *Task, subTask *SubTask, reason string, report Reporter) {
+	if err := sprint.AddFailure(task.Index); err != nil {
+		report.Warn(fmt.Sprintf("Warning: failed to add failure marker: %v", err))
+	}
+
+	if reason != "" {
+		if err := sprint.AnnotateFailure(task.Index, subTask.Index, reason); err != nil {
+			report.Warn(fmt.Sprintf("Warning: failed to annotate failure: %v", err))
+		}
+	}
+
+	for i := subTask.Index; i < len(task.SubTasks); i++ {
+		if task.SubTasks[i].Checked {
+			if err := sprint.UncheckSubTask(task.Index, i); err != nil {
+				report.Warn(fmt.Sprintf("Warning: failed to uncheck sub-task %d: %v", i, err))
+			}
+		}
+	}
+}
+
+// selectAgent picks the agent for a skill: the preferred agent if given,
+// otherwise (or for AutoAgent) selectAgentForSkill's default, falling back
+// to the first available agent. If the skill's agents: list doesn't permit
+// that agent, the first permitted available agent is used instead.
+func selectAgent(preferred, skillName string, skills []project.Skill, report Reporter) (agent.Agent, error) {
+	selected, _, warning, err := chooseAgent(preferred, skillName, skills)
+	if warning != "" {
+		report.Warn("⚠ " + warning)
+	}
+	return selected, err
+}
+
+// chooseAgent implements selectAgent without printing. It also returns why
+// the agent was chosen, and a warning when a skill restriction overrode the
+// choice.
+func chooseAgent(preferred, skillName string, skills []project.Skill) (selected agent.Agent, reason, warning string, err error) {
+	agentName := preferred
+	reason = "requested with --agent"
+	if agentName == "" || agentName == AutoAgent {
+		agentName = selectAgentForSkill(skillName)
+		if strings.Contains(skillName, "coder") {
+			reason = "coder skills default to codex"
+		} else {
+			reason = "non-coder skills default to claude"
+		}
+	}
+
+	selected = agent.GetAgentByName(agentName)
+	if selected == nil || !selected.Available() {
+		// Fall back to first available
+		agents := agent.GetAvailableAgents()
+		if len(agents) == 0 {
+			return nil, "", "", agent.NoAgentsError{}
+		}
+		selected = agents[0]
+		reason = fmt.Sprintf("%s is not available, so the first available agent is used", agentName)
+	}
+
+	skill := project.GetSkillByName(skills, skillName)
+	if agentPermitted(skill, selected.Name()) {
+		return selected, reason, "", nil
+	}
+	restricted := strings.Join(skill.Metadata.Agents, ", ")
+	for _, a := range agent.GetAvailableAgents() {
+		if agentPermitted(skill, a.Name()) {
+			warning = fmt.Sprintf("Skill %s is restricted to [%s]; using %s instead of %s",
+				skillName, restricted, a.Name(), selected.Name())
+			return a, fmt.Sprintf("skill %s is restricted to [%s]", skillName, restricted), warning, nil
+		}
+	}
+	warning = fmt.Sprintf("Skill %s is restricted to [%s] but none is available; using %s",
+		skillName, restricted, selected.Name())
+	return selected, reason + fmt.Sprintf("; none of the skill's agents [%s] is available", restricted), warning, nil
+}
+
+// agentPermitted reports whether a skill's agents: list allows an agent.
+// The dummy agent is a test stand-in and is always allowed, and haiku runs
+// the claude CLI, so it may use any skill claude may.
+func agentPermitted(skill *project.Skill, name string) bool {
+	switch name {
+	case "dummy":
+		return true
+	case "haiku":
+		return project.CanAgentUseSkill(skill, name) || project.CanAgentUseSkill(skill, "claude")
+	}
+	return project.CanAgentUseSkill(skill, name)
+}
+
 func selectAgentForSkill(skill string) string {
 	// Prefer codex for implementation, claude for review/planning
 	if strings.Contains(skill, "coder") {
@@ -304,6 +708,64 @@ func selectAgentForSkill(skill string) string {
 	return "claude"
 }
 
+// unknownSkillWarning returns a warning if no loaded or built-in skill is
+// named name, suggesting the closest name. It returns "" for a known skill.
+func unknownSkillWarning(proj *project.Project, name string, skills []project.Skill) string {
+	var names []string
+	for _, list := range [][]project.Skill{skills, project.BuiltinSkills()} {
+		for _, s := range list {
+			if s.Name == name {
+				return ""
+			}
+			names = append(names, s.Name)
+		}
+	}
+	warning := fmt.Sprintf("Skill %s not found in %s", name, proj.StatePath("skills"))
+	if closest := closestSkill(name, names); closest != "" {
+		warning += fmt.Sprintf(" (did you mean %s?)", closest)
+	}
+	return warning
+}
+
+// closestSkill returns the name nearest to name by edit distance, or "" if
+// none is close enough to be a likely typo (a third of the name's length,
+// and at least 2 edits)
+func closestSkill(name string, names []string) string {
+	limit := len(name) / 3
+	if limit < 2 {
+		limit = 2
+	}
+	best, bestDist := "", limit+1
+	for _, candidate := range names {
+		if d := editDistance(name, candidate); d < bestDist {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
 func getSkillContent(skills []project.Skill, skillName string) string {
 	for _, s := range skills {
 		if s.Name == skillName {
@@ -313,7 +775,32 @@ func getSkillContent(skills []project.Skill, skillName string) string {
 	return ""
 }
 
-func buildSubTaskPrompt(task *Task, subTask *SubTask, designContent, skillContent string, sprint *SprintState) string {
+// Skill phases that change how a sub-task is prompted and handled
+const (
+	phaseImplement = "implement"
+	phaseReview    = "review"
+)
+
+// subTaskPhase returns the phase of the sub-task's skill from its phase:
+// metadata, falling back to name heuristics for skills that don't declare
+// one (or have no skill file)
+func subTaskPhase(skillName string, skill *project.Skill) string {
+	if skill != nil && skill.Metadata.Phase != "" {
+		return skill.Metadata.Phase
+	}
+	if isImplementationSkill(skillName) {
+		return phaseImplement
+	}
+	if strings.Contains(skillName, "reviewer") {
+		return phaseReview
+	}
+	return ""
+}
+
+// buildSubTaskPrompt builds the prompt for a sub-task. skill may be nil when
+// the sub-task names a skill without a file; its instructions follow the
+// skill's phase.
+func buildSubTaskPrompt(task *Task, subTask *SubTask, designContent string, skill *project.Skill, priorSprints []completedSprint, sprint *SprintState) string {
 	var sb strings.Builder
 
 	sb.WriteString("You are working on a software project.\n\n")
@@ -324,9 +811,16 @@ func buildSubTaskPrompt(task *Task, subTask *SubTask, designContent, skillConten
 		sb.WriteString("\n\n")
 	}
 
-	if skillContent != "" {
+	if summary := formatPriorSprints(priorSprints); summary != "" {
+		sb.WriteString("## Prior Sprints\n\n")
+		sb.WriteString("Earlier sprints already built the following. Build on this work rather than redoing or contradicting it.\n\n")
+		sb.WriteString(summary)
+		sb.WriteString("\n")
+	}
+
+	if skill != nil && skill.Content != "" {
 		sb.WriteString("## Skill Guidelines\n\n")
-		sb.WriteString(skillContent)
+		sb.WriteString(skill.Content)
 		sb.WriteString("\n\n")
 	}
 
@@ -334,9 +828,25 @@ func buildSubTaskPrompt(task *Task, subTask *SubTask, designContent, skillConten
 	sb.WriteString(fmt.Sprintf("**Main Task**: %s\n\n", task.Text))
 	sb.WriteString(fmt.Sprintf("**Sub-task**: %s\n\n", subTask.Text))
 
+	// Surface earlier review failures recorded on this task's sub-tasks
+	var reasons []string
+	for _, sub := range task.SubTasks {
+		if sub.FailureReason != "" {
+			reasons = append(reasons, sub.FailureReason)
+		}
+	}
+	if len(reasons) > 0 {
+		sb.WriteString("**Previous review failures** (address these):\n")
+		for _, r := range reasons {
+			sb.WriteString(fmt.Sprintf("- %s\n", r))
+		}
+		sb.WriteString("\n")
+	}
+
 	sb.WriteString("## Instructions\n\n")
 
-	if isImplementationSkill(subTask.Skill) {
+	switch subTaskPhase(subTask.Skill, skill) {
+	case phaseImplement:
 		sb.WriteString(`Complete the sub-task above. Output any files that should be created or modified.
 For each file, use this format:
 
@@ -345,18 +855,31 @@ For each file, use this format:
 file contents here
 ` + "```" + `
 
+To change part of an existing file, you may instead output a unified diff
+with ---/+++ headers naming the file:
+
+` + "```diff" + `
+--- a/path/to/file.ext
++++ b/path/to/file.ext
+@@ -10,3 +10,4 @@
+ unchanged line
+-old line
++new line
+` + "```" + `
+
 If no files need to be created, just describe what you did.
 `)
-	} else if strings.Contains(subTask.Skill, "reviewer") || subTask.Skill == "_reviewer" {
+	case phaseReview:
 		sb.WriteString(`Review the implementation for this task. Check that:
 1. The task requirements are met
 2. Code follows best practices
 3. No obvious bugs or issues
 
 If the implementation is good, respond with: APPROVED
-If there are issues, describe them.
+If there are issues, respond with: ISSUES_FOUND: <one-line summary>
+followed by a description of the issues.
 `)
-	} else {
+	default:
 		sb.WriteString("Complete the sub-task described above.\n")
 	}
 
@@ -367,17 +890,77 @@ func isImplementationSkill(skill string) bool {
 	return strings.Contains(skill, "coder") || skill == "implement"
 }
 
-// isReviewApproved checks if the review output contains APPROVED
-func isReviewApproved(output string) bool {
-	output = strings.ToUpper(output)
-	return strings.Contains(output, "APPROVED")
+// Review outcome tokens. A fail token anywhere wins over a pass token, so
+// "NOT APPROVED" is never read as "APPROVED".
+var (
+	reviewFailRe = regexp.MustCompile(`(?i)\b(ISSUES_FOUND|NOT[ _]APPROVED)\b\s*:?\s*(.*)$`)
+	reviewPassRe = regexp.MustCompile(`(?i)\b(APPROVED|SPRINT_COMPLETE)\b`)
+)
+
+// parseReviewOutcome interprets a reviewer response. APPROVED and
+// SPRINT_COMPLETE pass; ISSUES_FOUND and NOT APPROVED fail, with the rest of
+// that line as the reason. A response with no token fails with no reason.
+func parseReviewOutcome(output string) (approved bool, reason string) {
+	for _, line := range strings.Split(output, "\n") {
+		if m := reviewFailRe.FindStringSubmatch(line); m != nil {
+			return false, strings.TrimSpace(m[2])
+		}
+	}
+	return reviewPassRe.MatchString(output), ""
+}
+
+// Reasons strict review gives for failing a response with no fail token
+const (
+	reviewEmptyReason     = "empty review response"
+	reviewNoVerdictReason = "review response has no APPROVED or ISSUES_FOUND verdict"
+	reviewAmbiguousReason = "ambiguous review response (no line that is just APPROVED)"
+)
+
+// reviewOutcome interprets a reviewer response, strictly for --strict-review
+func reviewOutcome(output string, strict bool) (approved bool, reason string) {
+	if strict {
+		return parseStrictReviewOutcome(output)
+	}
+	return parseReviewOutcome(output)
+}
+
+// parseStrictReviewOutcome fails closed: the response passes only if it has
+// no fail token and some line, ignoring markdown emphasis and a trailing
+// period, is exactly APPROVED or SPRINT_COMPLETE. "approved - looks fine"
+// or a response that never decides is a failure.
+func parseStrictReviewOutcome(output string) (approved bool, reason string) {
+	if strings.TrimSpace(output) == "" {
+		return false, reviewEmptyReason
+	}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if m := reviewFailRe.FindStringSubmatch(line); m != nil {
+			return false, strings.TrimSpace(m[2])
+		}
+	}
+	for _, line := range lines {
+		token := strings.TrimSuffix(strings.Trim(strings.TrimSpace(line), "*_`"), ".")
+		if token == "APPROVED" || token == "SPRINT_COMPLETE" {
+			return true, ""
+		}
+	}
+	if !reviewPassRe.MatchString(output) {
+		return false, reviewNoVerdictReason
+	}
+	return false, reviewAmbiguousReason
+}
+
+// isAmbiguousReview reports whether a strict review failed for want of a
+// clear verdict rather than for issues the reviewer found
+func isAmbiguousReview(reason string) bool {
+	return reason == reviewEmptyReason || reason == reviewNoVerdictReason || reason == reviewAmbiguousReason
 }
 
 // fileExists is defined in plan.go
 
 // autoCheckOrphanedTasks checks top-level tasks that have no subtasks or
 // have all subtasks complete but are themselves unchecked. Returns the number fixed.
-func autoCheckOrphanedTasks(sprint *SprintState) int {
+func autoCheckOrphanedTasks(sprint *SprintState, report Reporter) int {
 	fixed := 0
 	for i := range sprint.Tasks {
 		task := &sprint.Tasks[i]
@@ -387,16 +970,40 @@ func autoCheckOrphanedTasks(sprint *SprintState) int {
 		// Task with no subtasks, or all subtasks already done
 		if len(task.SubTasks) == 0 || sprint.AllSubTasksComplete(task.Index) {
 			if err := sprint.CheckTask(task.Index); err != nil {
-				fmt.Printf("%s\n", logging.Yellow(fmt.Sprintf("Warning: failed to auto-check task %d: %v", i, err)))
+				report.Warn(fmt.Sprintf("Warning: failed to auto-check task %d: %v", i, err))
 				continue
 			}
-			fmt.Printf("%s\n", logging.Green(fmt.Sprintf("✓ Auto-checked orphaned task: %s", task.Text)))
+			report.Success(fmt.Sprintf("✓ Auto-checked orphaned task: %s", task.Text))
 			fixed++
 		}
 	}
 	return fixed
 }
 
+// loadExecutionSkills loads the project's skills for running a sub-task.
+// If the skills directory is missing or empty (e.g. deleted mid-run), the
+// built-in skills and the skills for the goal's language are regenerated
+// first, so the agent doesn't silently lose its guidance.
+func loadExecutionSkills(proj *project.Project, report Reporter) []project.Skill {
+	skills, err := project.LoadSkills(proj.SkillsDir())
+	if err == nil && len(skills) > 0 {
+		return skills
+	}
+
+	report.Warn("⚠ Skills directory is missing or empty; regenerating skills")
+	if _, err := project.EnsureBuiltinSkills(proj.SkillsDir()); err != nil {
+		report.Warn(fmt.Sprintf("Warning: failed to write built-in skills: %v", err))
+	}
+	if goal, err := project.ParseGoal(proj.GoalPath()); err == nil {
+		if err := project.WriteSkills(proj.SkillsDir(), project.GenerateSkills(goal.Language, goal.Type)); err != nil {
+			report.Warn(fmt.Sprintf("Warning: failed to write skills: %v", err))
+		}
+	}
+
+	skills, _ = project.LoadSkills(proj.SkillsDir())
+	return skills
+}
+
 // attemptRecovery invokes a Claude recovery agent to diagnose and fix the environment
 // after a task execution failure. Returns nil on success, error on failure.
 func attemptRecovery(projectDir string, proj *project.Project, task *Task, subTask *SubTask,
@@ -424,6 +1031,7 @@ func attemptRecovery(projectDir string, proj *project.Project, task *Task, subTa
 		Skill:         "_recover",
 		PromptSummary: "Recovery: " + TruncateText(subTask.Text, 40),
 		StreamWriter:  opts.StreamOutput,
+		Events:        opts.Events,
 	})
 
 	if recoveryResult.Error != nil {
@@ -462,7 +1070,21 @@ func buildRecoveryPrompt(failedAgentName string, execErr error, task *Task, subT
 
 // attemptReplan invokes a Claude replanner agent to rewrite the failing task's subtasks
 // when review has failed too many times. Returns nil error on success.
+// If the reviewer asked for a task to be moved instead (MOVE_TASK), the
+// tasks are reordered without running the replanner.
 func attemptReplan(projectDir string, proj *project.Project, sprint *SprintState, task *Task, logger *logging.Logger, opts NextOptions) (*Result, error) {
+	// Find the last reviewer log for feedback
+	sprintNum := ExtractSprintNum(filepath.Base(sprint.FilePath))
+	reviewerFeedback := ""
+	lastLog := findLastReviewerLog(proj, sprintNum)
+	if lastLog != "" {
+		reviewerFeedback = extractReviewerFeedback(lastLog)
+	}
+
+	if from, to, ok := parseMoveTask(reviewerFeedback, len(sprint.Tasks)); ok {
+		return reorderForReview(sprint, task, from, to, opts.reporter())
+	}
+
 	replanAgent := agent.GetAgentByName("claude")
 	if replanAgent == nil || !replanAgent.Available() {
 		return nil, fmt.Errorf("claude agent not available for replan")
@@ -480,14 +1102,6 @@ func attemptReplan(projectDir string, proj *project.Project, sprint *SprintState
 		}
 	}
 
-	// Find the last reviewer log for feedback
-	sprintNum := ExtractSprintNum(filepath.Base(sprint.FilePath))
-	reviewerFeedback := ""
-	lastLog := findLastReviewerLog(projectDir, sprintNum)
-	if lastLog != "" {
-		reviewerFeedback = extractReviewerFeedback(lastLog)
-	}
-
 	// Read current sprint content
 	sprintContent, err := os.ReadFile(sprint.FilePath)
 	if err != nil {
@@ -507,6 +1121,7 @@ func attemptReplan(projectDir string, proj *project.Project, sprint *SprintState
 		Skill:         "_replanner",
 		PromptSummary: "Replan: " + TruncateText(task.Text, 40),
 		StreamWriter:  opts.StreamOutput,
+		Events:        opts.Events,
 	})
 
 	if replanResult.Error != nil {
@@ -515,12 +1130,27 @@ func attemptReplan(projectDir string, proj *project.Project, sprint *SprintState
 
 	// Re-parse sprint from disk — the replanner agent edited the file directly,
 	// so the in-memory sprint.Content is stale and would clobber the replan.
-	sprint, err = ParseSprint(sprint.FilePath)
+	sprint, err = sprint.reparse()
 	if err != nil {
 		return nil, fmt.Errorf("failed to re-parse sprint after replan: %w", err)
 	}
 
-	// Find the task again by matching text (index may have shifted if subtasks changed)
+	if err := markReplanned(sprint, task, opts.reporter()); err != nil {
+		return nil, err
+	}
+
+	opts.reporter().Info("  Replan complete. Sprint file updated. Run 'agate next' to retry.")
+	return &Result{
+		Message:  "Sprint replanned. Run 'agate next' to retry the task.",
+		MoreWork: true,
+		ExitCode: ExitMoreWork,
+	}, nil
+}
+
+// markReplanned finds task again by its text (its index may have shifted),
+// clears its failure markers, and adds a replan marker, so a further run of
+// failures goes to a human instead of another replan
+func markReplanned(sprint *SprintState, task *Task, report Reporter) error {
 	var replanTask *Task
 	for i := range sprint.Tasks {
 		if NormalizeTaskText(sprint.Tasks[i].Text) == NormalizeTaskText(task.Text) {
@@ -529,27 +1159,61 @@ func attemptReplan(projectDir string, proj *project.Project, sprint *SprintState
 		}
 	}
 	if replanTask == nil {
-		return nil, fmt.Errorf("could not find task %q after replan", task.Text)
+		return fmt.Errorf("could not find task %q after replan", task.Text)
 	}
 
-	// Clear failure markers and add replan marker
 	if err := sprint.ClearFailures(replanTask.Index); err != nil {
-		fmt.Printf("%s\n", logging.Yellow(fmt.Sprintf("Warning: failed to clear failure markers: %v", err)))
+		report.Warn(fmt.Sprintf("Warning: failed to clear failure markers: %v", err))
 	}
 	if err := sprint.AddReplanMarker(replanTask.Index); err != nil {
-		fmt.Printf("%s\n", logging.Yellow(fmt.Sprintf("Warning: failed to add replan marker: %v", err)))
+		report.Warn(fmt.Sprintf("Warning: failed to add replan marker: %v", err))
+	}
+	return nil
+}
+
+// moveTaskDirective is how a reviewer asks for a task to be moved, e.g.
+// "MOVE_TASK: 3 TO 1" when task 3 must be done before task 1
+const moveTaskDirective = "MOVE_TASK"
+
+var moveTaskRe = regexp.MustCompile(`(?i)` + moveTaskDirective + `:\s*(?:task\s*)?(\d+)\s*(?:to|->)\s*(?:position\s*)?(\d+)`)
+
+// parseMoveTask finds a MOVE_TASK directive in reviewer feedback and
+// returns its 0-based task indexes, if they are valid for numTasks tasks
+func parseMoveTask(feedback string, numTasks int) (from, to int, ok bool) {
+	m := moveTaskRe.FindStringSubmatch(feedback)
+	if m == nil {
+		return 0, 0, false
+	}
+	from, _ = strconv.Atoi(m[1])
+	to, _ = strconv.Atoi(m[2])
+	if from < 1 || from > numTasks || to < 1 || to > numTasks || from == to {
+		return 0, 0, false
+	}
+	return from - 1, to - 1, true
+}
+
+// reorderForReview is the cheap replan for a reviewer's MOVE_TASK: it moves
+// the task and marks the failing task replanned
+func reorderForReview(sprint *SprintState, task *Task, from, to int, report Reporter) (*Result, error) {
+	movedText := sprint.Tasks[from].Text
+	if err := sprint.MoveTask(from, to); err != nil {
+		return nil, fmt.Errorf("failed to move task %d: %w", from+1, err)
+	}
+	if err := markReplanned(sprint, task, report); err != nil {
+		return nil, err
 	}
 
-	fmt.Println("  Replan complete. Sprint file updated. Run 'agate next' to retry.")
+	report.Info(fmt.Sprintf("  Reviewer flagged task order: moved task %d (%s) to position %d.", from+1, TruncateText(movedText, 40), to+1))
 	return &Result{
-		Message:  "Sprint replanned. Run 'agate next' to retry the task.",
+		Message:  fmt.Sprintf("Sprint reordered: task %d moved to position %d. Run 'agate next' to continue.", from+1, to+1),
 		MoreWork: true,
+		ExitCode: ExitMoreWork,
 	}, nil
 }
 
 // findLastReviewerLog finds the last reviewer log file for the given sprint
-func findLastReviewerLog(projectDir string, sprintNum int) string {
-	logs, err := logging.ListLogs(projectDir, sprintNum)
+func findLastReviewerLog(proj *project.Project, sprintNum int) string {
+	logs, err := logging.ListLogs(proj, sprintNum)
 	if err != nil || len(logs) == 0 {
 		return ""
 	}
@@ -564,42 +1228,47 @@ func findLastReviewerLog(projectDir string, sprintNum int) string {
 	return lastReviewerLog
 }
 
-// extractReviewerFeedback reads a log file and extracts the response content
+// extractReviewerFeedback reads a log file and extracts the reviewer's
+// response. It prefers the first fenced block in the response, falling back
+// to the whole response (minus fence lines) when no fenced block is present.
+// Lines containing ISSUES_FOUND are always preserved.
 func extractReviewerFeedback(logPath string) string {
 	content, err := os.ReadFile(logPath)
 	if err != nil {
 		return ""
 	}
+	response, ok := logging.ParseInvocationResponse(string(content))
+	if !ok {
+		return ""
+	}
 
-	// Find ## Response section and extract content between ``` blocks
-	lines := strings.Split(string(content), "\n")
-	inResponse := false
 	inCodeBlock := false
-	var feedback []string
+	blockDone := false
+	var block []string   // Lines inside the first fenced block
+	var section []string // All non-fence lines in the response
+	var issues []string  // ISSUES_FOUND lines outside the first fenced block
 
-	for _, line := range lines {
-		if strings.HasPrefix(line, "## Response") {
-			inResponse = true
-			continue
-		}
-		if inResponse && strings.HasPrefix(line, "## ") {
-			break // Next section
-		}
-		if inResponse {
-			if strings.HasPrefix(line, "```") {
-				if inCodeBlock {
-					break // End of response code block
-				}
-				inCodeBlock = true
-				continue
-			}
+	for _, line := range strings.Split(response, "\n") {
+		if strings.HasPrefix(line, "```") {
 			if inCodeBlock {
-				feedback = append(feedback, line)
+				blockDone = true
 			}
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+
+		section = append(section, line)
+		if inCodeBlock && !blockDone {
+			block = append(block, line)
+		} else if strings.Contains(line, "ISSUES_FOUND") || strings.Contains(line, moveTaskDirective) {
+			issues = append(issues, line)
 		}
 	}
 
-	return strings.Join(feedback, "\n")
+	if len(block) > 0 {
+		return strings.Join(append(issues, block...), "\n")
+	}
+	return strings.TrimSpace(strings.Join(section, "\n"))
 }
 
 // buildReplanPrompt constructs the prompt for the replanner agent
@@ -635,7 +1304,8 @@ func buildReplanPrompt(skillContent, designContent, sprintContent, reviewerFeedb
 
 	sb.WriteString("## Instructions\n\n")
 	sb.WriteString(fmt.Sprintf("Edit the sprint file at %s to fix the subtasks for task %d (%q). ", sprintPath, task.Index+1, task.Text))
-	sb.WriteString("Rewrite the subtasks so the task can succeed. Uncheck all subtasks in the rewritten task.\n")
+	sb.WriteString("Rewrite the subtasks so the task can succeed. Uncheck all subtasks in the rewritten task. ")
+	sb.WriteString(verifyOnlyRule + ".\n")
 
 	return sb.String()
 }
@@ -646,16 +1316,75 @@ type completedSprint struct {
 	Content string
 }
 
-// findSprintByNum scans sprintsDir for any .md file whose name starts with the
-// zero-padded sprint number prefix (e.g. "02-"). Returns the full path or "".
+// Size caps for the prior-sprint context in sub-task prompts
+const (
+	maxPriorSprintChars  = 800  // Per sprint summary
+	maxPriorSprintsChars = 3000 // All summaries together
+)
+
+// summarizeSprint condenses a sprint to its title and top-level tasks,
+// truncated to maxPriorSprintChars
+func summarizeSprint(cs completedSprint) string {
+	var sb strings.Builder
+	title := fmt.Sprintf("Sprint %d", cs.Num)
+	for _, line := range strings.Split(cs.Content, "\n") {
+		if strings.HasPrefix(line, "# ") {
+			title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+			break
+		}
+	}
+	sb.WriteString(fmt.Sprintf("### %s\n\n", title))
+
+	sprint, err := ParseSprintContent(cs.Content)
+	if err != nil || len(sprint.Tasks) == 0 {
+		sb.WriteString(strings.TrimSpace(cs.Content))
+	} else {
+		for _, task := range sprint.Tasks {
+			status := "done"
+			if !task.Checked {
+				status = "not done"
+			}
+			sb.WriteString(fmt.Sprintf("- %s (%s)\n", NormalizeTaskText(task.Text), status))
+		}
+	}
+
+	return TruncateText(strings.TrimSpace(sb.String()), maxPriorSprintChars) + "\n"
+}
+
+// formatPriorSprints summarizes completed sprints for a sub-task prompt.
+// When the summaries exceed maxPriorSprintsChars the oldest are dropped,
+// since the most recent sprints are the most relevant.
+func formatPriorSprints(sprints []completedSprint) string {
+	var summaries []string
+	size, omitted := 0, 0
+	for i := len(sprints) - 1; i >= 0; i-- {
+		summary := summarizeSprint(sprints[i])
+		if size+len(summary) > maxPriorSprintsChars {
+			omitted = i + 1
+			break
+		}
+		summaries = append([]string{summary}, summaries...)
+		size += len(summary)
+	}
+
+	var sb strings.Builder
+	if omitted > 0 {
+		sb.WriteString(fmt.Sprintf("_(%d earlier sprint%s omitted)_\n\n", omitted, plural(omitted)))
+	}
+	sb.WriteString(strings.Join(summaries, "\n"))
+	return sb.String()
+}
+
+// findSprintByNum scans sprintsDir for any .md file whose numeric prefix is
+// num (e.g. "02-" or "100-"). Returns the full path or "".
 func findSprintByNum(sprintsDir string, num int) string {
-	prefix := fmt.Sprintf("%02d-", num)
 	entries, err := os.ReadDir(sprintsDir)
 	if err != nil {
 		return ""
 	}
+	// Compare the whole numeric prefix, so sprint 1 never matches 010-x.md
 	for _, e := range entries {
-		if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") && strings.HasPrefix(e.Name(), prefix) {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") && ExtractSprintNum(e.Name()) == num {
 			return filepath.Join(sprintsDir, e.Name())
 		}
 	}
@@ -690,8 +1419,32 @@ func loadCompletedSprintSummaries(sprintsDir string, upToNum int) []completedSpr
 	return results
 }
 
+// sprintGoalLine returns the one-line objective of a sprint: the first line
+// under its Goal heading, else its title
+func sprintGoalLine(content string) string {
+	title := ""
+	inGoal := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			if title == "" && strings.HasPrefix(trimmed, "# ") {
+				title = heading
+			}
+			inGoal = strings.Contains(strings.ToLower(heading), "goal")
+			continue
+		}
+		if inGoal && trimmed != "" {
+			return trimmed
+		}
+	}
+	return title
+}
+
 // buildNextSprintPrompt constructs the prompt for the assess-and-plan-next agent call.
-func buildNextSprintPrompt(goalContent, designContent string, completedSprints []completedSprint, skillNames []string, outputPath string) string {
+// Only the last fullSprints completed sprints are included in full; older
+// ones are cut to their goal line to keep the prompt small.
+func buildNextSprintPrompt(goalContent, designContent string, completedSprints []completedSprint, skillNames []string, outputPath string, minTasks, maxTasks, fullSprints int) string {
 	var sb strings.Builder
 
 	sb.WriteString("You are a project manager assessing whether a project goal is fully met, or planning the next sprint.\n\n")
@@ -706,9 +1459,31 @@ func buildNextSprintPrompt(goalContent, designContent string, completedSprints [
 		sb.WriteString("\n\n")
 	}
 
+	requirements := (&project.Goal{Content: goalContent}).Requirements()
+	if len(requirements) > 0 {
+		sb.WriteString("## Requirements Checklist\n\n")
+		sb.WriteString("The goal lists these requirements. Each must be addressed by a completed sprint before the goal is met:\n\n")
+		for _, req := range requirements {
+			sb.WriteString(fmt.Sprintf("- [ ] %s\n", req))
+		}
+		sb.WriteString("\n")
+	}
+
 	sb.WriteString("## Completed Sprints\n\n")
-	for _, cs := range completedSprints {
+	summarized := len(completedSprints) - fullSprints
+	if summarized > 0 {
+		sb.WriteString(fmt.Sprintf("_(Sprints %d-%d are shown by goal only; later sprints are in full)_\n\n", completedSprints[0].Num, completedSprints[summarized-1].Num))
+	}
+	for i, cs := range completedSprints {
 		sb.WriteString(fmt.Sprintf("### Sprint %d\n\n", cs.Num))
+		if i < summarized {
+			goal := sprintGoalLine(cs.Content)
+			if goal == "" {
+				goal = "(no goal recorded)"
+			}
+			sb.WriteString(fmt.Sprintf("Goal: %s\n\n", goal))
+			continue
+		}
 		sb.WriteString(cs.Content)
 		sb.WriteString("\n\n")
 	}
@@ -728,6 +1503,9 @@ func buildNextSprintPrompt(goalContent, designContent string, completedSprints [
 	sb.WriteString("Look at the goal and the completed sprints above. Decide:\n\n")
 	sb.WriteString("1. If the goal is **fully met** by the completed sprints, respond with exactly:\n")
 	sb.WriteString("   GOAL_COMPLETE\n\n")
+	if len(requirements) > 0 {
+		sb.WriteString("   Only respond GOAL_COMPLETE after confirming every item in the Requirements Checklist is addressed by the completed sprints. If any item is not, plan the next sprint around it.\n\n")
+	}
 	sb.WriteString("2. If more work is needed, write the next sprint document to this file path:\n")
 	sb.WriteString(fmt.Sprintf("   %s\n\n", outputPath))
 	sb.WriteString("The sprint document should use nested task checkboxes with skill assignments:\n\n")
@@ -736,7 +1514,8 @@ func buildNextSprintPrompt(goalContent, designContent string, completedSprints [
   - [ ] _reviewer: Validate correctness
 `, coderSkill))
 	sb.WriteString("\n")
-	sb.WriteString("Keep sprints lean: 2-4 top-level tasks. Each task has ONE coder sub-task and ONE _reviewer sub-task. The coder writes implementation AND tests together.\n\n")
+	sb.WriteString(fmt.Sprintf("Keep sprints lean: %s top-level tasks. Each task has ONE coder sub-task and ONE _reviewer sub-task. The coder writes implementation AND tests together.\n\n", taskCountRange(minTasks, maxTasks)))
+	sb.WriteString(verifyOnlyRule + ".\n\n")
 	if len(availableSkills) > 0 {
 		sb.WriteString(fmt.Sprintf("Available skills: %s\n\n", strings.Join(availableSkills, ", ")))
 	}
@@ -746,16 +1525,31 @@ func buildNextSprintPrompt(goalContent, designContent string, completedSprints [
 }
 
 // assessGoalAndPlanNext checks if the goal is met after a sprint completes.
-// If a next sprint already exists, returns MoreWork. Otherwise calls an agent
-// that either confirms GOAL_COMPLETE or writes the next sprint file.
+// If a next sprint already exists, returns MoreWork. Otherwise it first
+// verifies the sprint's Definition of Done (re-opening tasks if unmet), then
+// calls an agent that either confirms GOAL_COMPLETE or writes the next sprint file.
 func assessGoalAndPlanNext(projectDir string, proj *project.Project, completedSprintNum int, opts NextOptions) (*Result, error) {
 	nextNum := completedSprintNum + 1
 
+	// Gate on the sprint's Definition of Done before declaring it complete,
+	// even when the next sprint was already planned by hand
+	if sprintPath := findSprintByNum(proj.SprintsDir(), completedSprintNum); sprintPath != "" {
+		sprint, err := ParseSprint(sprintPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sprint: %w", err)
+		}
+		sprint.Reporter = opts.reporter()
+		if result, err := verifyDefinitionOfDone(projectDir, proj, sprint, completedSprintNum, opts); err != nil || result != nil {
+			return result, err
+		}
+	}
+
 	// If next sprint already exists, just continue
 	if findSprintByNum(proj.SprintsDir(), nextNum) != "" {
 		return &Result{
 			Message:  fmt.Sprintf("Sprint %d complete! Run 'agate next' to start sprint %d.", completedSprintNum, nextNum),
 			MoreWork: true,
+			ExitCode: ExitMoreWork,
 		}, nil
 	}
 
@@ -776,36 +1570,36 @@ func assessGoalAndPlanNext(projectDir string, proj *project.Project, completedSp
 	// Load completed sprint summaries
 	completed := loadCompletedSprintSummaries(proj.SprintsDir(), completedSprintNum)
 
-	// Load skill names (filter out builtins except _reviewer)
 	skills, _ := project.LoadSkills(proj.SkillsDir())
-	var skillNames []string
-	for _, s := range skills {
-		if !strings.HasPrefix(s.Name, "_") || s.Name == "_reviewer" {
-			skillNames = append(skillNames, s.Name)
-		}
-	}
+	skillNames := plannerSkillNames(skills)
 
 	// Build output path
-	outputPath := filepath.Join(proj.SprintsDir(), fmt.Sprintf("%02d-next.md", nextNum))
+	outputPath := filepath.Join(proj.SprintsDir(), FormatSprintFilename(nextNum, "next"))
+
+	cfg, err := proj.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
 
 	// Build prompt
-	prompt := buildNextSprintPrompt(string(goalContent), designContent, completed, skillNames, outputPath)
+	prompt := renderPrompt(proj.PromptsDir(), promptNextSprint, PromptData{
+		Goal:       string(goalContent),
+		Design:     designContent,
+		OutputPath: outputPath,
+		Skills:     skillNames,
+	}, buildNextSprintPrompt(string(goalContent), designContent, completed, skillNames, outputPath, cfg.SprintMinTasks, cfg.SprintMaxTasks, cfg.AssessContextSprints), opts.reporter())
 
 	// Select agent (prefer claude via _planner)
-	agentName := opts.PreferredAgent
-	if agentName == "" {
-		agentName = selectAgentForSkill("_planner")
+	selectedAgent, err := selectAgent(opts.PreferredAgent, "_planner", skills, opts.reporter())
+	if err != nil {
+		return nil, err
 	}
-	selectedAgent := agent.GetAgentByName(agentName)
-	if selectedAgent == nil || !selectedAgent.Available() {
-		agents := agent.GetAvailableAgents()
-		if len(agents) == 0 {
-			return nil, agent.NoAgentsError{}
-		}
-		selectedAgent = agents[0]
+	selectedAgent = opts.ModelOptions.Apply(selectedAgent)
+	if note := safePlanningNote(selectedAgent, outputPath); note != "" {
+		prompt += note + "If the goal is already fully met, respond with only GOAL_COMPLETE instead.\n"
 	}
 
-	logger := logging.NewLogger(projectDir, completedSprintNum)
+	logger := logging.NewLogger(proj, completedSprintNum)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
@@ -817,6 +1611,8 @@ func assessGoalAndPlanNext(projectDir string, proj *project.Project, completedSp
 		Skill:         "_planner",
 		PromptSummary: "Assessing goal completion",
 		StreamWriter:  opts.StreamOutput,
+		Events:        opts.Events,
+		SafeMode:      true, // Planning only needs the sprint document
 	})
 
 	if execResult.Error != nil {
@@ -828,37 +1624,122 @@ func assessGoalAndPlanNext(projectDir string, proj *project.Project, completedSp
 		return &Result{
 			Message:  fmt.Sprintf("Sprint %d complete. All sprints done — goal is fully met.", completedSprintNum),
 			MoreWork: false,
+			ExitCode: ExitDone,
 		}, nil
 	}
 
 	// Validate the new sprint file was written
+	if err := savePlannerOutput(outputPath, execResult.Output); err != nil {
+		return nil, fmt.Errorf("failed to write next sprint: %w", err)
+	}
 	if err := validateMarkdownContent(outputPath); err != nil {
 		return nil, fmt.Errorf("agent did not write a valid next sprint: %w", err)
 	}
 
+	// A new sprint starts: drop logs that fall outside the retention limits
+	autoPruneLogs(proj, cfg, nextNum, opts.reporter())
+
 	return &Result{
 		Message:  fmt.Sprintf("Sprint %d complete! Next sprint planned. Run 'agate next' to continue.", completedSprintNum),
 		MoreWork: true,
+		ExitCode: ExitMoreWork,
 	}, nil
 }
 
-func parseAndWriteFiles(projectDir string, content string) int {
-	// Simple parser for "### File: path" format
+// plannerSkillNames returns the skill names a planned sprint may use: the
+// project skills, plus _reviewer of the built-ins
+func plannerSkillNames(skills []project.Skill) []string {
+	var names []string
+	for _, s := range skills {
+		if !strings.HasPrefix(s.Name, "_") || s.Name == "_reviewer" {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+// autoPruneLogs prunes sprint logs per the log_keep_sprints and log_max_age
+// config, if either is set. Failures only warn; logs are never worth failing
+// a run over.
+func autoPruneLogs(proj *project.Project, cfg *project.Config, currentSprint int, report Reporter) {
+	if cfg.LogKeepSprints <= 0 && cfg.LogMaxAge <= 0 {
+		return
+	}
+	removed, err := logging.PruneLogs(proj, logging.PruneOptions{
+		CurrentSprint: currentSprint,
+		KeepSprints:   cfg.LogKeepSprints,
+		MaxAge:        cfg.LogMaxAge,
+	})
+	if err != nil {
+		report.Warn(fmt.Sprintf("Warning: failed to prune logs: %v", err))
+	}
+	if len(removed) > 0 {
+		report.Info(logging.Dim(fmt.Sprintf("Pruned logs of %d old sprint(s)", len(removed))))
+	}
+}
+
+// resolveOutputPath places a file path from agent output under root (a
+// project-relative directory, "" for the project itself) and returns it
+// relative to the project directory. Absolute paths and paths that escape
+// the project directory are rejected.
+func resolveOutputPath(root, file string) (string, error) {
+	file = filepath.FromSlash(file)
+	if filepath.IsAbs(file) {
+		return "", fmt.Errorf("absolute path not allowed: %s", file)
+	}
+	rel := filepath.Clean(filepath.Join(root, file))
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("path escapes the project directory: %s", file)
+	}
+	return rel, nil
+}
+
+// writablePath resolves file like resolveOutputPath and also rejects paths
+// excluded by .agateignore
+func writablePath(root string, ignore *project.Ignore, file string) (string, error) {
+	rel, err := resolveOutputPath(root, file)
+	if err != nil {
+		return "", err
+	}
+	if ignore.Match(rel, false) {
+		return "", fmt.Errorf("%s is excluded by %s", filepath.ToSlash(rel), project.IgnoreFileName)
+	}
+	return rel, nil
+}
+
+// parseAndWriteFiles writes the "### File: path" blocks in content, with
+// paths relative to root inside projectDir, and returns the paths written
+// (relative to projectDir). Fenced unified diffs (```diff, or unlabeled
+// blocks with ---/+++ headers) are applied to existing files instead.
+// Paths excluded by the project's .agateignore are skipped.
+func parseAndWriteFiles(projectDir, root, content string, report Reporter) []string {
+	ignore, err := project.New(projectDir).LoadIgnore()
+	if err != nil {
+		report.Warn(fmt.Sprintf("  Warning: failed to read %s: %v", project.IgnoreFileName, err))
+	}
+
 	lines := strings.Split(content, "\n")
 	var currentFile string
 	var currentContent []string
 	inCodeBlock := false
-	filesWritten := 0
+	var blockLang string
+	var block []string
+	var filesWritten []string
 
 	writeCurrentFile := func() {
 		if currentFile != "" && len(currentContent) > 0 {
-			path := filepath.Join(projectDir, currentFile)
-			dir := filepath.Dir(path)
-			os.MkdirAll(dir, 0755)
-			content := strings.Join(currentContent, "\n")
-			if err := os.WriteFile(path, []byte(content), 0644); err == nil {
-				filesWritten++
-				fmt.Printf("  %s\n", logging.Green(fmt.Sprintf("Wrote: %s", currentFile)))
+			rel, err := writablePath(root, ignore, currentFile)
+			if err != nil {
+				report.Warn(fmt.Sprintf("  Skipped: %v", err))
+			} else {
+				path := filepath.Join(projectDir, rel)
+				os.MkdirAll(filepath.Dir(path), 0755)
+				content := strings.Join(currentContent, "\n")
+				if err := os.WriteFile(path, []byte(content), 0644); err == nil {
+					rel = filepath.ToSlash(rel)
+					filesWritten = append(filesWritten, rel)
+					report.Success(fmt.Sprintf("  Wrote: %s", rel))
+				}
 			}
 		}
 		currentFile = ""
@@ -873,22 +1754,31 @@ func parseAndWriteFiles(projectDir string, content string) int {
 			continue
 		}
 
-		if currentFile != "" {
-			if strings.HasPrefix(line, "```") {
-				if inCodeBlock {
-					inCodeBlock = false
-				} else {
-					inCodeBlock = true
-				}
+		if strings.HasPrefix(line, "```") {
+			if !inCodeBlock {
+				inCodeBlock = true
+				blockLang = strings.TrimSpace(strings.TrimPrefix(line, "```"))
+				block = nil
 				continue
 			}
-
-			if inCodeBlock {
-				currentContent = append(currentContent, line)
+			inCodeBlock = false
+			if isDiffBlock(blockLang, currentFile, block) {
+				filesWritten = append(filesWritten, applyDiffBlock(projectDir, root, ignore, currentFile, block, report)...)
+			} else if currentFile != "" {
+				currentContent = append(currentContent, block...)
 			}
+			continue
+		}
+
+		if inCodeBlock {
+			block = append(block, line)
 		}
 	}
 
+	// An unterminated block still counts as file contents
+	if inCodeBlock && currentFile != "" && !isDiffBlock(blockLang, currentFile, block) {
+		currentContent = append(currentContent, block...)
+	}
 	writeCurrentFile()
 	return filesWritten
 }
@@ -1,12 +1,21 @@
 package workflow
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/strongdm/agate/internal/agent"
+	"github.com/strongdm/agate/internal/logging"
 	"github.com/strongdm/agate/internal/project"
 )
 
@@ -127,6 +136,21 @@ func TestBuildReplanPrompt_IncludesContext(t *testing.T) {
 	}
 }
 
+// TestPlannerPrompts_DescribeVerifyOnly verifies every prompt that writes
+// sub-tasks tells the planner about the verify-only marker.
+func TestPlannerPrompts_DescribeVerifyOnly(t *testing.T) {
+	prompts := map[string]string{
+		"sprints":     buildSprintsPromptWithContext(&project.Goal{Content: "goal"}, "design", "", "out.md", []string{"go-coder"}, 2, 4),
+		"next sprint": buildNextSprintPrompt("goal", "", nil, []string{"go-coder"}, "out.md", 2, 4, project.DefaultAssessContextSprints),
+		"replan":      buildReplanPrompt("", "", "# Sprint 1\n", "", &Task{Text: "Task", FailureCount: 3}, "out.md"),
+	}
+	for name, prompt := range prompts {
+		if !strings.Contains(prompt, verifyOnlyMarker) {
+			t.Errorf("%s prompt should describe the %s marker", name, verifyOnlyMarker)
+		}
+	}
+}
+
 func TestBuildReplanPrompt_NoDesignOrFeedback(t *testing.T) {
 	task := &Task{Index: 0, Text: "Task", FailureCount: 3}
 
@@ -169,7 +193,7 @@ func TestFindLastReviewerLog(t *testing.T) {
 		}
 	}
 
-	result := findLastReviewerLog(tmpDir, 1)
+	result := findLastReviewerLog(project.New(tmpDir), 1)
 
 	if result == "" {
 		t.Fatal("expected to find a reviewer log")
@@ -181,9 +205,74 @@ func TestFindLastReviewerLog(t *testing.T) {
 	}
 }
 
+// TestAttemptReplan_MoveTask verifies a reviewer's MOVE_TASK reorders the
+// sprint without running the replanner agent.
+func TestAttemptReplan_MoveTask(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] ❌❌❌ Add handler\n  - [ ] go-coder: Write handler\n  - [ ] _reviewer: Review handler\n\n- [ ] Add store\n  - [ ] go-coder: Write store\n",
+	})
+	logsDir := filepath.Join(tmpDir, ".ai", "logs", "sprint-001")
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	review := logging.FormatInvocation(&logging.Invocation{
+		Skill:    "_reviewer",
+		Response: "ISSUES_FOUND: the handler needs the store, which comes later\nMOVE_TASK: 2 TO 1\n",
+		Status:   "success",
+	})
+	if err := os.WriteFile(filepath.Join(logsDir, "002-implement-01-_reviewer-claude.md"), []byte(review), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// No claude on PATH: the reorder must not need the replanner
+	t.Setenv("PATH", t.TempDir())
+
+	sprint, err := ParseSprint(filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := attemptReplan(tmpDir, project.New(tmpDir), sprint, &sprint.Tasks[0], logging.NewLogger(project.New(tmpDir), 1), NextOptions{})
+	if err != nil {
+		t.Fatalf("attemptReplan failed: %v", err)
+	}
+	if result.ExitCode != ExitMoreWork || !strings.Contains(result.Message, "reordered") {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	sprint, err = ParseSprint(sprint.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sprint.Tasks[0].Text != "Add store" || sprint.Tasks[1].Text != "Add handler" {
+		t.Fatalf("expected Add store first, got %q then %q", sprint.Tasks[0].Text, sprint.Tasks[1].Text)
+	}
+	if handler := sprint.Tasks[1]; handler.FailureCount != 0 || handler.ReplanCount != 1 {
+		t.Errorf("expected the failing task's ❌ cleared and a 🔄 added, got %d and %d", handler.FailureCount, handler.ReplanCount)
+	}
+}
+
+func TestParseMoveTask(t *testing.T) {
+	tests := []struct {
+		feedback string
+		from, to int
+		ok       bool
+	}{
+		{"ISSUES_FOUND\nMOVE_TASK: 3 TO 1", 2, 0, true},
+		{"move_task: task 1 -> 2", 0, 1, true},
+		{"MOVE_TASK: 4 TO 1", 0, 0, false}, // Out of range
+		{"MOVE_TASK: 2 TO 2", 0, 0, false},
+		{"ISSUES_FOUND: tests fail", 0, 0, false},
+	}
+	for _, tt := range tests {
+		from, to, ok := parseMoveTask(tt.feedback, 3)
+		if ok != tt.ok || from != tt.from || to != tt.to {
+			t.Errorf("parseMoveTask(%q) = %d, %d, %v; want %d, %d, %v", tt.feedback, from, to, ok, tt.from, tt.to, tt.ok)
+		}
+	}
+}
+
 func TestFindLastReviewerLog_NoLogs(t *testing.T) {
 	tmpDir := t.TempDir()
-	result := findLastReviewerLog(tmpDir, 1)
+	result := findLastReviewerLog(project.New(tmpDir), 1)
 	if result != "" {
 		t.Errorf("expected empty string for no logs, got %s", result)
 	}
@@ -232,6 +321,94 @@ Some notes here.
 	}
 }
 
+// writeReviewerLog writes a reviewer invocation log with the logger's own
+// formatter, including stderr notes that are themselves fenced
+func writeReviewerLog(t *testing.T, response string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "review.md")
+	content := logging.FormatInvocation(&logging.Invocation{
+		Timestamp: time.Now(),
+		Sprint:    1,
+		Phase:     "implement",
+		Agent:     "claude",
+		Skill:     "_reviewer",
+		Prompt:    "Review the implementation",
+		Response:  response,
+		Status:    "success",
+		Notes:     "Some notes here.\n\n```\nstderr: warning\n```",
+	})
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExtractReviewerFeedback_Unfenced(t *testing.T) {
+	path := writeReviewerLog(t, "ISSUES_FOUND: missing error handling in parser.\nThe CLI exits 0 on failure.")
+
+	feedback := extractReviewerFeedback(path)
+
+	if !strings.Contains(feedback, "ISSUES_FOUND: missing error handling") {
+		t.Errorf("feedback should contain ISSUES_FOUND line, got %q", feedback)
+	}
+	if !strings.Contains(feedback, "exits 0 on failure") {
+		t.Errorf("feedback should contain prose after ISSUES_FOUND, got %q", feedback)
+	}
+	if strings.Contains(feedback, "Some notes here") || strings.Contains(feedback, "Review the implementation") {
+		t.Errorf("feedback should only include the Response section, got %q", feedback)
+	}
+}
+
+func TestExtractReviewerFeedback_Mixed(t *testing.T) {
+	response := "ISSUES_FOUND: tests fail to compile.\n\n```\nundefined: parseArgs\n```\n\nTrailing commentary."
+	path := writeReviewerLog(t, response)
+
+	feedback := extractReviewerFeedback(path)
+
+	if !strings.Contains(feedback, "ISSUES_FOUND: tests fail to compile.") {
+		t.Errorf("feedback should preserve ISSUES_FOUND outside the fence, got %q", feedback)
+	}
+	if !strings.Contains(feedback, "undefined: parseArgs") {
+		t.Errorf("feedback should contain fenced content, got %q", feedback)
+	}
+	if strings.Contains(feedback, "```") {
+		t.Errorf("feedback should not contain fence markers, got %q", feedback)
+	}
+	if strings.Contains(feedback, "Trailing commentary") {
+		t.Errorf("feedback should prefer the fenced block over other prose, got %q", feedback)
+	}
+}
+
+// TestExtractReviewerFeedback_FenceAfterProse verifies a code fence in the
+// reviewer's answer doesn't cut the feedback off: the log's own fence
+// around the response is not the first fenced block.
+func TestExtractReviewerFeedback_FenceAfterProse(t *testing.T) {
+	response := "ISSUES_FOUND: the parser panics on empty input.\n\n" +
+		"## Details\n\nReproduce with:\n\n```go\nParse(\"\")\n```\n\nAdd a guard before indexing."
+	path := writeReviewerLog(t, response)
+
+	feedback := extractReviewerFeedback(path)
+
+	for _, want := range []string{"ISSUES_FOUND: the parser panics on empty input.", `Parse("")`} {
+		if !strings.Contains(feedback, want) {
+			t.Errorf("feedback should contain %q, got %q", want, feedback)
+		}
+	}
+	if strings.Contains(feedback, "stderr: warning") || strings.Contains(feedback, "Review the implementation") {
+		t.Errorf("feedback should only include the response, got %q", feedback)
+	}
+}
+
+func TestExtractReviewerFeedback_HeadingsInResponse(t *testing.T) {
+	path := writeReviewerLog(t, "ISSUES_FOUND: two problems.\n\n## Problem 1\n\nNo tests.\n\n## Problem 2\n\nNo docs.")
+
+	feedback := extractReviewerFeedback(path)
+
+	if !strings.Contains(feedback, "No tests.") || !strings.Contains(feedback, "No docs.") {
+		t.Errorf("feedback should keep the response past its own headings, got %q", feedback)
+	}
+}
+
 func TestExtractReviewerFeedback_EmptyFile(t *testing.T) {
 	feedback := extractReviewerFeedback("/nonexistent/file.md")
 	if feedback != "" {
@@ -248,7 +425,7 @@ func TestBuildNextSprintPrompt_IncludesAllContext(t *testing.T) {
 	skills := []string{"go-coder", "_reviewer"}
 	outputPath := ".ai/sprints/02-next.md"
 
-	prompt := buildNextSprintPrompt(goal, design, sprints, skills, outputPath)
+	prompt := buildNextSprintPrompt(goal, design, sprints, skills, outputPath, project.DefaultSprintMinTasks, project.DefaultSprintMaxTasks, project.DefaultAssessContextSprints)
 
 	// Should include goal
 	if !strings.Contains(prompt, "Build a CLI tool") {
@@ -283,13 +460,40 @@ func TestBuildNextSprintPrompt_IncludesAllContext(t *testing.T) {
 }
 
 func TestBuildNextSprintPrompt_NoDesign(t *testing.T) {
-	prompt := buildNextSprintPrompt("goal", "", []completedSprint{{Num: 1, Content: "sprint 1"}}, nil, "out.md")
+	prompt := buildNextSprintPrompt("goal", "", []completedSprint{{Num: 1, Content: "sprint 1"}}, nil, "out.md", project.DefaultSprintMinTasks, project.DefaultSprintMaxTasks, project.DefaultAssessContextSprints)
 
 	if strings.Contains(prompt, "## Design") {
 		t.Error("prompt should not contain design section when design is empty")
 	}
 }
 
+func TestBuildNextSprintPrompt_RequirementsChecklist(t *testing.T) {
+	goal := "# Goal\n\nBuild a todo CLI.\n\n- Add and list todos\n- Persist todos to disk\n"
+	prompt := buildNextSprintPrompt(goal, "", []completedSprint{{Num: 1, Content: "sprint 1"}}, nil, "out.md", project.DefaultSprintMinTasks, project.DefaultSprintMaxTasks, project.DefaultAssessContextSprints)
+
+	if !strings.Contains(prompt, "## Requirements Checklist") {
+		t.Fatal("prompt should contain a requirements checklist")
+	}
+	for _, item := range []string{"- [ ] Add and list todos\n", "- [ ] Persist todos to disk\n"} {
+		if !strings.Contains(prompt, item) {
+			t.Errorf("checklist should contain %q", item)
+		}
+	}
+	if !strings.Contains(prompt, "confirming every item in the Requirements Checklist") {
+		t.Error("GOAL_COMPLETE instruction should require confirming the checklist")
+	}
+
+	prompt = buildNextSprintPrompt(goal+"\n## Non-goals\n\n- A web UI\n", "", nil, nil, "out.md", project.DefaultSprintMinTasks, project.DefaultSprintMaxTasks, project.DefaultAssessContextSprints)
+	if strings.Contains(prompt, "- [ ] A web UI") {
+		t.Error("checklist should not contain non-goal bullets")
+	}
+
+	prompt = buildNextSprintPrompt("Build a todo CLI.", "", nil, nil, "out.md", project.DefaultSprintMinTasks, project.DefaultSprintMaxTasks, project.DefaultAssessContextSprints)
+	if strings.Contains(prompt, "Requirements Checklist") {
+		t.Error("a goal without bullets should not get a checklist")
+	}
+}
+
 func TestBuildNextSprintPrompt_MultipleCompletedSprints(t *testing.T) {
 	sprints := []completedSprint{
 		{Num: 1, Content: "Sprint 1 content"},
@@ -297,7 +501,7 @@ func TestBuildNextSprintPrompt_MultipleCompletedSprints(t *testing.T) {
 		{Num: 3, Content: "Sprint 3 content"},
 	}
 
-	prompt := buildNextSprintPrompt("goal", "", sprints, nil, "out.md")
+	prompt := buildNextSprintPrompt("goal", "", sprints, nil, "out.md", project.DefaultSprintMinTasks, project.DefaultSprintMaxTasks, project.DefaultAssessContextSprints)
 
 	if !strings.Contains(prompt, "### Sprint 1") {
 		t.Error("prompt should contain sprint 1 heading")
@@ -310,6 +514,54 @@ func TestBuildNextSprintPrompt_MultipleCompletedSprints(t *testing.T) {
 	}
 }
 
+func TestBuildNextSprintPrompt_CapsFullSprints(t *testing.T) {
+	var sprints []completedSprint
+	for n := 1; n <= 5; n++ {
+		sprints = append(sprints, completedSprint{
+			Num:     n,
+			Content: fmt.Sprintf("# Sprint %d: Part %d\n\n## Goal\n\nShip part %d.\n\n## Tasks\n\n- [x] Task body %d\n", n, n, n, n),
+		})
+	}
+
+	prompt := buildNextSprintPrompt("goal", "", sprints, nil, "out.md", project.DefaultSprintMinTasks, project.DefaultSprintMaxTasks, 2)
+
+	for n := 1; n <= 3; n++ {
+		if !strings.Contains(prompt, fmt.Sprintf("### Sprint %d\n\nGoal: Ship part %d.", n, n)) {
+			t.Errorf("sprint %d should be summarized to its goal", n)
+		}
+		if strings.Contains(prompt, fmt.Sprintf("Task body %d", n)) {
+			t.Errorf("sprint %d should not be included in full", n)
+		}
+	}
+	for n := 4; n <= 5; n++ {
+		if !strings.Contains(prompt, fmt.Sprintf("Task body %d", n)) {
+			t.Errorf("sprint %d should be included in full", n)
+		}
+	}
+	if !strings.Contains(prompt, "Sprints 1-3 are shown by goal only") {
+		t.Error("prompt should say which sprints are summarized")
+	}
+}
+
+func TestSprintGoalLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"goal section", "# Sprint 1: Setup\n\n## Sprint Goal\n\nScaffold the CLI.\n", "Scaffold the CLI."},
+		{"title fallback", "# Sprint 2: Polish\n\n## Tasks\n\n- [x] Do it\n", "Sprint 2: Polish"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sprintGoalLine(tt.content); got != tt.want {
+				t.Errorf("sprintGoalLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFindSprintByNum(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -403,7 +655,7 @@ func TestFindCurrentSprintFS_PicksUpNewSprint(t *testing.T) {
 
 	// Before new sprint exists, FindCurrentSprintFS should return the last (complete) sprint
 	fsys := os.DirFS(tmpDir)
-	path, num := FindCurrentSprintFS(fsys)
+	path, num := FindCurrentSprintFS(fsys, project.DefaultStateDir)
 	if num != 1 {
 		t.Errorf("expected sprint 1 as current (last complete), got %d", num)
 	}
@@ -416,7 +668,7 @@ func TestFindCurrentSprintFS_PicksUpNewSprint(t *testing.T) {
 	os.WriteFile(filepath.Join(sprintsDir, "02-next.md"), []byte(sprint02), 0644)
 
 	// FindCurrentSprintFS should now find the incomplete sprint 02
-	path, num = FindCurrentSprintFS(fsys)
+	path, num = FindCurrentSprintFS(fsys, project.DefaultStateDir)
 	if num != 2 {
 		t.Errorf("expected sprint 2 as current (incomplete), got %d", num)
 	}
@@ -450,6 +702,80 @@ func TestFindSprintByNum_DifferentNamingPatterns(t *testing.T) {
 	}
 }
 
+// TestFindSprintByNum_ExactNumber verifies that the whole numeric prefix must
+// match, so looking for sprint 1 never finds 010-*.md (sprint 10).
+func TestFindSprintByNum_ExactNumber(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"010-later.md", "01-first.md", "100-big.md"} {
+		os.WriteFile(filepath.Join(tmpDir, name), []byte("content"), 0644)
+	}
+
+	tests := []struct {
+		num  int
+		want string
+	}{
+		{1, "01-first.md"},
+		{10, "010-later.md"},
+		{100, "100-big.md"},
+		{0, ""},
+		{2, ""},
+	}
+	for _, tt := range tests {
+		got := findSprintByNum(tmpDir, tt.num)
+		if tt.want == "" {
+			if got != "" {
+				t.Errorf("sprint %d: expected no match, got %s", tt.num, got)
+			}
+			continue
+		}
+		if filepath.Base(got) != tt.want {
+			t.Errorf("sprint %d: expected %s, got %q", tt.num, tt.want, got)
+		}
+	}
+}
+
+// TestFindSprintByNum_Unpadded verifies that padded and unpadded names both
+// resolve by number.
+func TestFindSprintByNum_Unpadded(t *testing.T) {
+	for _, name := range []string{"1-foo.md", "01-foo.md"} {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			os.WriteFile(filepath.Join(tmpDir, name), []byte("content"), 0644)
+			os.WriteFile(filepath.Join(tmpDir, "12-other.md"), []byte("content"), 0644)
+
+			got := findSprintByNum(tmpDir, 1)
+			if filepath.Base(got) != name {
+				t.Errorf("expected %s for sprint 1, got %q", name, got)
+			}
+		})
+	}
+}
+
+// TestFindCurrentSprintFS_PastNinetyNine verifies sprints are ordered by
+// number, so a complete sprint 100 follows sprint 99 rather than sorting
+// before it.
+func TestFindCurrentSprintFS_PastNinetyNine(t *testing.T) {
+	tmpDir := t.TempDir()
+	sprintsDir := filepath.Join(tmpDir, ".ai", "sprints")
+	os.MkdirAll(sprintsDir, 0755)
+
+	done := "# Sprint\n\n- [x] Task\n  - [x] go-coder: Code\n"
+	os.WriteFile(filepath.Join(sprintsDir, "99-old.md"), []byte(done), 0644)
+	os.WriteFile(filepath.Join(sprintsDir, "100-next.md"), []byte(done), 0644)
+
+	path, num := FindCurrentSprintFS(os.DirFS(tmpDir), project.DefaultStateDir)
+	if num != 100 || path != ".ai/sprints/100-next.md" {
+		t.Errorf("expected sprint 100 as current, got %d (%s)", num, path)
+	}
+
+	pending := "# Sprint\n\n- [ ] Task\n  - [ ] go-coder: Code\n"
+	os.WriteFile(filepath.Join(sprintsDir, "101-more.md"), []byte(pending), 0644)
+	path, num = FindCurrentSprintFS(os.DirFS(tmpDir), project.DefaultStateDir)
+	if num != 101 || path != ".ai/sprints/101-more.md" {
+		t.Errorf("expected sprint 101 as current, got %d (%s)", num, path)
+	}
+}
+
 // TestAssessGoalAndPlanNext_NextSprintAlreadyExists verifies the fast path
 // when the next sprint file already exists on disk.
 func TestAssessGoalAndPlanNext_NextSprintAlreadyExists(t *testing.T) {
@@ -477,3 +803,1108 @@ func TestAssessGoalAndPlanNext_NextSprintAlreadyExists(t *testing.T) {
 		t.Errorf("expected message to mention sprint 2, got: %s", result.Message)
 	}
 }
+
+// setupExecutionProject creates a project in the execution phase with the
+// given sprint files (filename -> content) under .ai/sprints.
+func setupExecutionProject(t *testing.T, sprints map[string]string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	proj := project.New(tmpDir)
+	if err := proj.EnsureDirectories(); err != nil {
+		t.Fatalf("failed to create directories: %v", err)
+	}
+	files := map[string]string{
+		"GOAL.md":                 "# Goal\n\nBuild a CLI in Go.",
+		".ai/interview.md":        "# Interview\n\n- [x] All questions answered\n",
+		".ai/design/overview.md":  "# Design\n",
+		".ai/design/decisions.md": "# Decisions\n",
+	}
+	for name, content := range sprints {
+		files[filepath.Join(".ai", "sprints", name)] = content
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return tmpDir
+}
+
+// TestNext_KeepGoingSkipsStuckTask verifies --keep-going marks a task that
+// failed review even after replan as skipped and moves on to the next task
+// instead of stopping for a human.
+func TestNext_KeepGoingSkipsStuckTask(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] ❌❌❌🔄 Stuck task\n  - [ ] go-coder: Work <!-- fail: tests still fail -->\n\n- [ ] Next task\n  - [ ] go-coder: More work\n",
+	})
+	sprintPath := filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md")
+
+	if _, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"}); err == nil {
+		t.Fatal("expected a human-needed error without --keep-going")
+	}
+
+	result, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy", KeepGoing: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ExitCode != ExitMoreWork || !strings.Contains(result.Message, "Skipped task 1") {
+		t.Errorf("expected the stuck task to be skipped, got %d: %s", result.ExitCode, result.Message)
+	}
+	sprint, err := ParseSprint(sprintPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sprint.Tasks[0].Skipped {
+		t.Fatal("expected task 1 to be marked ⏭")
+	}
+
+	// The next step works on task 2, finishing the sprint with a skip
+	result, err = NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy", KeepGoing: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ExitCode != ExitHumanNeeded || strings.Contains(result.Message, "All 2 tasks done") {
+		t.Errorf("expected a skipped task to hold the run for a human, got %d: %s", result.ExitCode, result.Message)
+	}
+	if !strings.Contains(result.Message, "1 of 2 tasks done, 1 skipped") || !strings.Contains(result.Message, "⏭ Skipped 1 task") {
+		t.Errorf("expected the skipped summary in the completion message, got:\n%s", result.Message)
+	}
+	sprint, _ = ParseSprint(sprintPath)
+	if !sprint.Tasks[1].SubTasks[0].Checked {
+		t.Error("expected task 2 to be worked on after the skip")
+	}
+	if sprint.Tasks[0].SubTasks[0].Checked {
+		t.Error("the skipped task should not be worked on")
+	}
+}
+
+// TestNext_KeepGoingSummarizesSkippedTasks verifies a sprint completed with
+// skipped tasks lists them, with their last failure, in the final message.
+func TestNext_KeepGoingSummarizesSkippedTasks(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] ❌❌❌🔄⏭ Stuck task\n  - [ ] go-coder: Work <!-- fail: tests still fail -->\n\n- [x] Done task\n  - [x] go-coder: Work\n",
+	})
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "echo GOAL_COMPLETE\n")
+	t.Setenv("PATH", bin)
+
+	result, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "claude", KeepGoing: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ExitCode != ExitHumanNeeded {
+		t.Errorf("expected skipped tasks to hold the finished run for a human, got %d: %s", result.ExitCode, result.Message)
+	}
+	want := "⏭ Skipped 1 task (--keep-going); they need a human:\n  - Task 1: Stuck task (failed review 3 times): tests still fail"
+	if !strings.Contains(result.Message, want) {
+		t.Errorf("expected skipped summary %q, got:\n%s", want, result.Message)
+	}
+}
+
+// TestNext_OrphanedTasksCloseSprint verifies a sprint whose sub-tasks are all
+// checked but whose top-level boxes are not is treated as complete.
+func TestNext_OrphanedTasksCloseSprint(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [x] First task\n  - [x] go-coder: Work\n\n- [ ] Orphaned task\n  - [x] go-coder: Do work\n  - [x] _reviewer: Review work\n",
+		"02-next.md":    "# Sprint 2\n\n- [ ] Next task\n  - [ ] go-coder: More work\n",
+	})
+
+	result, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Message, "Sprint 1 complete") {
+		t.Errorf("expected sprint 1 to be treated as complete, got: %s", result.Message)
+	}
+
+	sprint, err := ParseSprint(filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md"))
+	if err != nil {
+		t.Fatalf("failed to parse sprint: %v", err)
+	}
+	if !sprint.IsComplete() {
+		t.Error("expected orphaned task to be auto-checked on disk")
+	}
+}
+
+// TestNext_StreamsJSONEvents verifies an EventWriter stream gets chunk
+// events, a file_written event per written file, and a done event instead
+// of the raw agent output, even when the stream wraps the EventWriter.
+func TestNext_StreamsJSONEvents(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [ ] go-coder: Write code\n",
+	})
+
+	var stream bytes.Buffer
+	writer := agent.NewEventWriter(&stream)
+	opts := NextOptions{PreferredAgent: "dummy", StreamOutput: io.MultiWriter(writer), Events: writer, Reporter: &recordingReporter{}}
+	if _, err := NextWithOptions(project.New(tmpDir), opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var types []string
+	var events []agent.StreamEvent
+	scanner := bufio.NewScanner(&stream)
+	for scanner.Scan() {
+		var event agent.StreamEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("stream line is not a JSON event: %q", scanner.Text())
+		}
+		events = append(events, event)
+		types = append(types, event.Type)
+	}
+	want := []string{agent.EventChunk, agent.EventFileWritten, agent.EventDone}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected events %v, got %v", want, types)
+	}
+	if events[0].Bytes == 0 {
+		t.Error("expected the chunk event to carry a byte count")
+	}
+	if events[1].Path != "main.go" {
+		t.Errorf("expected main.go to be written, got %q", events[1].Path)
+	}
+	if events[2].Status != "success" {
+		t.Errorf("expected status success, got %q", events[2].Status)
+	}
+}
+
+// writeStubScript writes an executable shell script named name into dir
+func writeStubScript(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestExecuteSubTask_TimeoutEscalates verifies a timed-out sub-task is
+// retried with the next agent in the escalation order.
+func TestExecuteSubTask_TimeoutEscalates(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [ ] go-coder: Write code\n",
+	})
+	config := "task_timeout: 500ms\nescalation_order: [codex, claude]\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".ai", "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sleepPath, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep not available")
+	}
+	bin := t.TempDir()
+	marker := filepath.Join(bin, "claude-ran")
+	writeStubScript(t, bin, "codex", "exec "+sleepPath+" 10\n")
+	writeStubScript(t, bin, "claude", "echo ran > "+marker+"\nprintf '### File: main.go\\n```go\\npackage main\\n```\\n'\n")
+	t.Setenv("PATH", bin)
+
+	proj := project.New(tmpDir)
+	sprint, err := ParseSprint(filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := &sprint.Tasks[0]
+	logger := logging.NewLogger(project.New(tmpDir), 1)
+
+	result, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[0], logger, NextOptions{PreferredAgent: "codex"}, false)
+	if err != nil {
+		t.Fatalf("expected escalated retry to succeed, got: %v", err)
+	}
+	if !result.MoreWork {
+		t.Error("expected MoreWork after sub-task")
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("expected claude to run after codex timed out")
+	}
+
+	sprint, err = ParseSprint(sprint.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sprint.Tasks[0].SubTasks[0].Checked {
+		t.Error("expected sub-task to be checked after escalated retry")
+	}
+}
+
+// TestExecuteSubTask_SprintDefaultAgent verifies a sprint's default_agent
+// frontmatter picks the agent when --agent isn't given, and --agent wins.
+func TestExecuteSubTask_SprintDefaultAgent(t *testing.T) {
+	tests := []struct {
+		name      string
+		preferred string
+		want      string
+	}{
+		{"pinned", "", "claude"},
+		{"agent flag wins", "codex", "codex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := setupExecutionProject(t, map[string]string{
+				// go-coder would default to codex
+				"01-initial.md": "---\ndefault_agent: claude\n---\n# Sprint 1\n\n- [ ] First task\n  - [ ] go-coder: Write code\n",
+			})
+			bin := t.TempDir()
+			ran := filepath.Join(bin, "ran")
+			writeStubScript(t, bin, "claude", "echo claude > "+ran+"\necho OK\n")
+			writeStubScript(t, bin, "codex", "echo codex > "+ran+"\necho OK\n")
+			t.Setenv("PATH", bin)
+
+			proj := project.New(tmpDir)
+			sprint, err := ParseSprint(filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			task := &sprint.Tasks[0]
+			logger := logging.NewLogger(project.New(tmpDir), 1)
+			opts := NextOptions{PreferredAgent: tt.preferred, NoRecovery: true}
+			if _, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[0], logger, opts, false); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := os.ReadFile(ran)
+			if err != nil {
+				t.Fatal("expected an agent to run")
+			}
+			if strings.TrimSpace(string(got)) != tt.want {
+				t.Errorf("expected %s to run, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestExecuteSubTask_PriorSprintsInPrompt verifies sub-task prompts summarize
+// what earlier sprints built, and that sprint 1 prompts have no such section.
+func TestExecuteSubTask_PriorSprintsInPrompt(t *testing.T) {
+	bin := t.TempDir()
+	promptFile := filepath.Join(bin, "prompt.txt")
+	writeStubScript(t, bin, "claude", "printf '%s\\n' \"$@\" > "+promptFile+"\necho done\n")
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	runFirstSubTask := func(t *testing.T, tmpDir, sprintFile string) string {
+		t.Helper()
+		proj := project.New(tmpDir)
+		sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), sprintFile))
+		if err != nil {
+			t.Fatal(err)
+		}
+		task := &sprint.Tasks[0]
+		logger := logging.NewLogger(project.New(tmpDir), ExtractSprintNum(sprintFile))
+		if _, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[0], logger, NextOptions{PreferredAgent: "claude"}, false); err != nil {
+			t.Fatalf("executeSubTask failed: %v", err)
+		}
+		prompt, err := os.ReadFile(promptFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(prompt)
+	}
+
+	t.Run("multi-sprint", func(t *testing.T) {
+		tmpDir := setupExecutionProject(t, map[string]string{
+			"01-initial.md": "# Sprint 1: Parser\n\n- [x] Build the tokenizer\n  - [x] go-coder: Write tokenizer\n",
+			"02-next.md":    "# Sprint 2: Evaluator\n\n- [x] ❌ Evaluate expressions\n  - [x] go-coder: Write evaluator\n",
+			"03-next.md":    "# Sprint 3: CLI\n\n- [ ] Add REPL\n  - [ ] go-coder: Write REPL\n",
+		})
+		prompt := runFirstSubTask(t, tmpDir, "03-next.md")
+
+		for _, want := range []string{"## Prior Sprints", "### Sprint 1: Parser", "- Build the tokenizer (done)", "### Sprint 2: Evaluator", "- Evaluate expressions (done)"} {
+			if !strings.Contains(prompt, want) {
+				t.Errorf("expected %q in prompt:\n%s", want, prompt)
+			}
+		}
+		if strings.Contains(prompt, "Sprint 3: CLI") {
+			t.Error("current sprint should not be listed as a prior sprint")
+		}
+	})
+
+	t.Run("sprint 1", func(t *testing.T) {
+		tmpDir := setupExecutionProject(t, map[string]string{
+			"01-initial.md": "# Sprint 1: Parser\n\n- [ ] Build the tokenizer\n  - [ ] go-coder: Write tokenizer\n",
+		})
+		prompt := runFirstSubTask(t, tmpDir, "01-initial.md")
+
+		if strings.Contains(prompt, "## Prior Sprints") {
+			t.Errorf("expected no prior sprints section for sprint 1:\n%s", prompt)
+		}
+	})
+}
+
+func TestFormatPriorSprints_CapsSize(t *testing.T) {
+	var sprints []completedSprint
+	for i := 1; i <= 10; i++ {
+		content := fmt.Sprintf("# Sprint %d\n\n", i)
+		for j := 0; j < 20; j++ {
+			content += fmt.Sprintf("- [x] Task %d of sprint %d with a fairly long description\n  - [x] go-coder: Do it\n", j, i)
+		}
+		sprints = append(sprints, completedSprint{Num: i, Content: content})
+	}
+
+	got := formatPriorSprints(sprints)
+	if len(got) > maxPriorSprintsChars+100 {
+		t.Errorf("expected summaries capped near %d chars, got %d", maxPriorSprintsChars, len(got))
+	}
+	if !strings.Contains(got, "### Sprint 10") {
+		t.Error("expected the most recent sprint to be kept")
+	}
+	if strings.Contains(got, "### Sprint 1\n") || !strings.Contains(got, "earlier sprints omitted") {
+		t.Errorf("expected the oldest sprints to be dropped with a note:\n%s", got)
+	}
+}
+
+// TestNext_PhaseOnlyStopsBeforeExecution verifies --phase-only generates the
+// sprint plan and then never runs an implementation sub-task.
+func TestNext_PhaseOnlyStopsBeforeExecution(t *testing.T) {
+	tmpDir := setupExecutionProject(t, nil)
+
+	bin := t.TempDir()
+	calls := filepath.Join(bin, "calls")
+	writeStubScript(t, bin, "claude", `echo call >> `+calls+`
+path=$(printf '%s\n' "$@" | sed -n 's/.*directly to this file path: //p' | head -n 1)
+if [ -n "$path" ]; then
+	printf '# Sprint 1\n\n- [ ] Build\n  - [ ] go-coder: Write code\n  - [ ] _reviewer: Review code\n' > "$path"
+fi
+echo done
+`)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	opts := NextOptions{PreferredAgent: "claude", PhaseOnly: true}
+
+	if _, err := NextWithOptions(project.New(tmpDir), opts); err != nil {
+		t.Fatalf("sprint phase failed: %v", err)
+	}
+	if phase := GetCurrentPlanPhase(project.New(tmpDir)); phase != PhaseExecution {
+		t.Fatalf("expected sprint plan to be generated, phase is %s", phase)
+	}
+
+	result, err := NextWithOptions(project.New(tmpDir), opts)
+	if err != nil {
+		t.Fatalf("phase-only step failed: %v", err)
+	}
+	if !strings.Contains(result.Message, "Planning complete") {
+		t.Errorf("expected planning complete message, got %q", result.Message)
+	}
+
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "call"); n != 1 {
+		t.Errorf("expected only the sprint planning call, got %d agent calls", n)
+	}
+	sprint, err := ParseSprint(filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sprint.Tasks[0].SubTasks[0].Checked {
+		t.Error("expected no sub-task to run with phase-only")
+	}
+}
+
+func TestNext_PhaseOnlyRejectsTask(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Build\n  - [ ] go-coder: Write code\n",
+	})
+	_, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy", PhaseOnly: true, TaskNumber: 1})
+	if err == nil || !strings.Contains(err.Error(), "phase-only") {
+		t.Errorf("expected phase-only/task conflict error, got: %v", err)
+	}
+}
+
+func TestNext_RejectsNegativeTask(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Build\n  - [ ] go-coder: Write code\n",
+	})
+	_, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy", TaskNumber: -1})
+	if err == nil || !strings.Contains(err.Error(), "must be positive") {
+		t.Errorf("expected a task number error, got: %v", err)
+	}
+}
+
+// TestExecuteSubTask_NoRecovery verifies a failing sub-task surfaces the
+// agent's error without invoking the recovery agent.
+func TestExecuteSubTask_NoRecovery(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [ ] go-coder: Write code\n",
+	})
+
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "echo 'disk quota exceeded' >&2\nexit 1\n")
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	_, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "claude", NoRecovery: true})
+	if err == nil {
+		t.Fatal("expected sub-task error")
+	}
+	if !strings.Contains(err.Error(), "disk quota exceeded") {
+		t.Errorf("expected original agent error, got: %v", err)
+	}
+
+	logs, err := filepath.Glob(filepath.Join(tmpDir, ".ai", "logs", "*", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 {
+		t.Errorf("expected only the failed sub-task log, got %v", logs)
+	}
+	for _, l := range logs {
+		if strings.Contains(filepath.Base(l), "-recover-") {
+			t.Errorf("expected no recovery log, found %s", l)
+		}
+	}
+}
+
+func TestSelectAgent_SkillRestriction(t *testing.T) {
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "echo OK\n")
+	writeStubScript(t, bin, "codex", "echo OK\n")
+	t.Setenv("PATH", bin)
+
+	skills := []project.Skill{
+		{Name: "go-coder", Metadata: project.SkillMetadata{Agents: []string{"claude"}}},
+		{Name: "py-coder", Metadata: project.SkillMetadata{Agents: []string{"claude", "codex"}}},
+	}
+	tests := []struct {
+		name      string
+		preferred string
+		skill     string
+		want      string
+	}{
+		{"claude-only skill overrides codex", "codex", "go-coder", "claude"},
+		{"claude-only skill overrides default", "", "go-coder", "claude"},
+		{"permitted agent is kept", "codex", "py-coder", "codex"},
+		{"unknown skill is unrestricted", "codex", "rust-coder", "codex"},
+		{"haiku may use claude skills", "haiku", "go-coder", "haiku"},
+		{"dummy is always allowed", "dummy", "go-coder", "dummy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectAgent(tt.preferred, tt.skill, skills, StdoutReporter{})
+			if err != nil {
+				t.Fatalf("selectAgent failed: %v", err)
+			}
+			if got.Name() != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got.Name())
+			}
+		})
+	}
+}
+
+func TestUnknownSkillWarning(t *testing.T) {
+	skills := []project.Skill{{Name: "gocoder"}, {Name: "go-reviewer"}}
+
+	if w := unknownSkillWarning(project.New(""), "gocoder", skills); w != "" {
+		t.Errorf("expected no warning for an exact match, got %q", w)
+	}
+	if w := unknownSkillWarning(project.New(""), "_reviewer", skills); w != "" {
+		t.Errorf("expected no warning for a built-in skill, got %q", w)
+	}
+
+	w := unknownSkillWarning(project.New(""), "go-coder", skills)
+	if !strings.Contains(w, "go-coder not found") || !strings.Contains(w, "did you mean gocoder?") {
+		t.Errorf("expected a warning suggesting gocoder, got %q", w)
+	}
+
+	w = unknownSkillWarning(project.New(""), "terraform-planner", skills)
+	if w == "" || strings.Contains(w, "did you mean") {
+		t.Errorf("expected a warning without a suggestion, got %q", w)
+	}
+}
+
+func TestClosestSkill(t *testing.T) {
+	names := []string{"go-coder", "go-reviewer", "py-coder"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"go-coder", "go-coder"},
+		{"gocoder", "go-coder"},
+		{"go-reveiwer", "go-reviewer"},
+		{"py-cder", "py-coder"},
+		{"rust", ""},
+	}
+	for _, tt := range tests {
+		if got := closestSkill(tt.name, names); got != tt.want {
+			t.Errorf("closestSkill(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestExecuteSubTask_SkillRestrictionForcesAgent verifies a sub-task whose
+// skill only allows claude runs on claude even when codex is requested.
+func TestExecuteSubTask_SkillRestrictionForcesAgent(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [ ] go-coder: Write code\n",
+	})
+	skill := project.FormatSkillWithFrontmatter(project.SkillMetadata{
+		Name:    "go-coder",
+		Agents:  []string{"claude"},
+		Phase:   "implement",
+		Version: 1,
+	}, "# Go Coder\n")
+	proj := project.New(tmpDir)
+	if err := os.MkdirAll(proj.SkillsDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(proj.SkillsDir(), "go-coder.md"), []byte(skill), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "echo ran > "+filepath.Join(bin, "claude-ran")+"\necho OK\n")
+	writeStubScript(t, bin, "codex", "echo ran > "+filepath.Join(bin, "codex-ran")+"\necho OK\n")
+	t.Setenv("PATH", bin)
+
+	sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := &sprint.Tasks[0]
+	logger := logging.NewLogger(project.New(tmpDir), 1)
+
+	if _, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[0], logger, NextOptions{PreferredAgent: "codex"}, false); err != nil {
+		t.Fatalf("executeSubTask failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(bin, "claude-ran")); err != nil {
+		t.Error("expected claude to run the claude-only skill")
+	}
+	if _, err := os.Stat(filepath.Join(bin, "codex-ran")); err == nil {
+		t.Error("expected codex not to run")
+	}
+}
+
+// TestExecuteSubTask_RegeneratesMissingSkills verifies a skills directory
+// deleted mid-run is regenerated before the prompt is built, so the agent
+// still gets the skill's guidance.
+func TestExecuteSubTask_RegeneratesMissingSkills(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [ ] go-coder: Write code\n",
+	})
+	proj := project.New(tmpDir)
+	if err := os.RemoveAll(proj.SkillsDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	bin := t.TempDir()
+	prompt := filepath.Join(bin, "prompt")
+	writeStubScript(t, bin, "claude", `printf '%s\n' "$@" > `+prompt+"\necho OK\n")
+	t.Setenv("PATH", bin)
+
+	sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := &sprint.Tasks[0]
+	logger := logging.NewLogger(project.New(tmpDir), 1)
+
+	if _, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[0], logger, NextOptions{PreferredAgent: "claude"}, false); err != nil {
+		t.Fatalf("executeSubTask failed: %v", err)
+	}
+
+	for _, name := range []string{"go-coder.md", "_reviewer.md"} {
+		if _, err := os.Stat(filepath.Join(proj.SkillsDir(), name)); err != nil {
+			t.Errorf("expected %s to be regenerated: %v", name, err)
+		}
+	}
+	data, err := os.ReadFile(prompt)
+	if err != nil {
+		t.Fatalf("agent did not run: %v", err)
+	}
+	skills, _ := project.LoadSkills(proj.SkillsDir())
+	coder := project.GetSkillByName(skills, "go-coder")
+	if coder == nil {
+		t.Fatal("go-coder skill not regenerated")
+	}
+	if firstLine := strings.SplitN(strings.TrimSpace(coder.Content), "\n", 2)[0]; !strings.Contains(string(data), firstLine) {
+		t.Errorf("prompt should include the regenerated go-coder guidance %q", firstLine)
+	}
+}
+
+// TestExecuteSubTask_ReviewPhaseSkillFailsReview verifies a custom skill
+// declared as phase: review is treated as a reviewer even though its name
+// doesn't contain "reviewer".
+func TestExecuteSubTask_ReviewPhaseSkillFailsReview(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [x] go-coder: Write code\n  - [ ] auditor: Audit code\n",
+	})
+	skill := project.FormatSkillWithFrontmatter(project.SkillMetadata{
+		Name:    "auditor",
+		Phase:   "review",
+		Version: 1,
+	}, "# Auditor\n")
+	proj := project.New(tmpDir)
+	if err := os.MkdirAll(proj.SkillsDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(proj.SkillsDir(), "auditor.md"), []byte(skill), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bin := t.TempDir()
+	promptFile := filepath.Join(bin, "prompt")
+	writeStubScript(t, bin, "claude", "printf '%s\\n' \"$@\" > "+promptFile+"\necho 'ISSUES_FOUND: no error handling'\n")
+	t.Setenv("PATH", bin)
+
+	sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := &sprint.Tasks[0]
+	logger := logging.NewLogger(project.New(tmpDir), 1)
+
+	result, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[1], logger, NextOptions{PreferredAgent: "claude"}, false)
+	if err != nil {
+		t.Fatalf("executeSubTask failed: %v", err)
+	}
+	if !strings.Contains(result.Message, "Review failed") {
+		t.Errorf("expected review failure, got: %s", result.Message)
+	}
+	prompt, err := os.ReadFile(promptFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(prompt), "respond with: ISSUES_FOUND") {
+		t.Errorf("expected reviewer instructions in prompt:\n%s", prompt)
+	}
+	content, err := os.ReadFile(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "no error handling") {
+		t.Errorf("expected failure reason recorded:\n%s", content)
+	}
+}
+
+// runDoubleReview runs the review sub-task of a one-task sprint with claude
+// and codex stubs that print the given responses
+func runDoubleReview(t *testing.T, claudeSays, codexSays string) (*Result, *SprintState) {
+	t.Helper()
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [x] go-coder: Write code\n  - [ ] _reviewer: Review code\n",
+	})
+	// A reviewer skill open to any agent, so codex can be the second reviewer
+	proj := project.New(tmpDir)
+	reviewer := project.FormatSkillWithFrontmatter(project.SkillMetadata{
+		Name:    "_reviewer",
+		Phase:   "review",
+		Version: 1,
+	}, "# Reviewer\n")
+	if err := os.WriteFile(filepath.Join(proj.SkillsDir(), "_reviewer.md"), []byte(reviewer), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "echo '"+claudeSays+"'\n")
+	writeStubScript(t, bin, "codex", "echo '"+codexSays+"'\n")
+	t.Setenv("PATH", bin)
+
+	sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := &sprint.Tasks[0]
+	logger := logging.NewLogger(project.New(tmpDir), 1)
+
+	result, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[1], logger, NextOptions{PreferredAgent: "claude", DoubleReview: true}, false)
+	if err != nil {
+		t.Fatalf("executeSubTask failed: %v", err)
+	}
+	sprint, err = ParseSprint(sprint.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, sprint
+}
+
+func TestExecuteSubTask_DoubleReviewDisagreementFails(t *testing.T) {
+	result, sprint := runDoubleReview(t, "APPROVED", "ISSUES_FOUND: no tests for the parser")
+
+	if !strings.Contains(result.Message, "Review failed") {
+		t.Errorf("expected review failure, got %q", result.Message)
+	}
+	task := sprint.Tasks[0]
+	if task.FailureCount != 1 {
+		t.Errorf("expected one failure marker, got %d", task.FailureCount)
+	}
+	if task.SubTasks[1].Checked {
+		t.Error("expected review sub-task to stay unchecked")
+	}
+	if reason := task.SubTasks[1].FailureReason; reason != "codex: no tests for the parser" {
+		t.Errorf("expected codex's feedback as the failure reason, got %q", reason)
+	}
+}
+
+func TestExecuteSubTask_DoubleReviewBothApprove(t *testing.T) {
+	result, sprint := runDoubleReview(t, "APPROVED", "Looks good. APPROVED")
+
+	if strings.Contains(result.Message, "Review failed") {
+		t.Errorf("expected approval, got %q", result.Message)
+	}
+	if !sprint.Tasks[0].Checked {
+		t.Error("expected task to be complete once both reviewers approve")
+	}
+}
+
+func TestCombineReviews(t *testing.T) {
+	results := []agent.Result{
+		{AgentName: "claude", Output: "NOT APPROVED: CLI ignores --verbose"},
+		{AgentName: "codex", Error: errors.New("codex crashed")},
+	}
+	combined := combineReviews(results, false, StdoutReporter{})
+	if approved, reason := parseReviewOutcome(combined.Output); approved || reason != "claude: CLI ignores --verbose" {
+		t.Errorf("unexpected outcome (%v, %q)", approved, reason)
+	}
+
+	// A reviewer that errored doesn't block approval by the other
+	results[0].Output = "APPROVED"
+	if approved, _ := parseReviewOutcome(combineReviews(results, false, StdoutReporter{}).Output); !approved {
+		t.Error("expected approval when the only successful reviewer approves")
+	}
+
+	// With no successful reviewer the first failure is surfaced
+	failed := []agent.Result{{AgentName: "claude", Error: errors.New("timeout")}, {AgentName: "codex", Error: errors.New("crash")}}
+	if combineReviews(failed, false, StdoutReporter{}).Error == nil {
+		t.Error("expected an error when every reviewer failed")
+	}
+}
+
+// TestExecuteSubTask_AutoAgentFallsBack verifies --agent auto retries a
+// sub-task on the next available agent when the first fails to run, and
+// logs both attempts
+func TestExecuteSubTask_AutoAgentFallsBack(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [ ] go-coder: Write code\n  - [ ] _reviewer: Review code\n",
+	})
+	bin := t.TempDir()
+	writeStubScript(t, bin, "codex", "echo 'not authenticated' >&2\nexit 1\n")
+	writeStubScript(t, bin, "claude", "printf '### File: main.go\\n```go\\npackage main\\n```\\n'\n")
+	t.Setenv("PATH", bin)
+
+	proj := project.New(tmpDir)
+	sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := &sprint.Tasks[0]
+	logger := logging.NewLogger(project.New(tmpDir), 1)
+
+	report := &recordingReporter{}
+	result, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[0], logger, NextOptions{PreferredAgent: AutoAgent, NoRecovery: true, Reporter: report}, false)
+	if err != nil {
+		t.Fatalf("expected fallback to succeed, got: %v", err)
+	}
+	if !result.MoreWork {
+		t.Error("expected MoreWork after sub-task")
+	}
+	if _, ok := report.find("warn", "codex failed"); !ok {
+		t.Errorf("expected the fallback warning through the reporter, got %+v", report.messages)
+	}
+
+	sprint, err = ParseSprint(sprint.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sprint.Tasks[0].SubTasks[0].Checked {
+		t.Error("expected sub-task to be checked after fallback")
+	}
+
+	logs, err := logging.ListLogs(project.New(tmpDir), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 logged attempts, got %v", logs)
+	}
+	if !strings.HasSuffix(logs[0], "-codex.md") || !strings.HasSuffix(logs[1], "-claude.md") {
+		t.Errorf("expected codex then claude attempts, got %v", logs)
+	}
+}
+
+// TestExecuteSubTask_AutoAgentAllFail verifies the sub-task fails with every
+// agent's error once no fallback succeeds
+func TestExecuteSubTask_AutoAgentAllFail(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [ ] go-coder: Write code\n",
+	})
+	bin := t.TempDir()
+	writeStubScript(t, bin, "codex", "exit 1\n")
+	writeStubScript(t, bin, "claude", "exit 1\n")
+	t.Setenv("PATH", bin)
+
+	proj := project.New(tmpDir)
+	sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := &sprint.Tasks[0]
+
+	_, err = executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[0], logging.NewLogger(project.New(tmpDir), 1), NextOptions{PreferredAgent: AutoAgent, NoRecovery: true}, false)
+	if err == nil || !strings.Contains(err.Error(), "all agents failed: codex:") {
+		t.Errorf("expected combined failure, got: %v", err)
+	}
+}
+
+// TestAutoPruneLogs verifies configured retention prunes old sprint logs
+// when a sprint starts, and does nothing when unset
+func TestAutoPruneLogs(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, num := range []int{1, 2, 3} {
+		if err := os.MkdirAll(logging.GetLogsDir(project.New(tmpDir), num), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	autoPruneLogs(project.New(tmpDir), project.DefaultConfig(), 3, StdoutReporter{})
+	if _, err := os.Stat(logging.GetLogsDir(project.New(tmpDir), 1)); err != nil {
+		t.Fatalf("expected no pruning without config: %v", err)
+	}
+
+	cfg := project.DefaultConfig()
+	cfg.LogKeepSprints = 2
+	autoPruneLogs(project.New(tmpDir), cfg, 3, StdoutReporter{})
+	if _, err := os.Stat(logging.GetLogsDir(project.New(tmpDir), 1)); !os.IsNotExist(err) {
+		t.Errorf("expected sprint 1 logs pruned, got %v", err)
+	}
+	for _, num := range []int{2, 3} {
+		if _, err := os.Stat(logging.GetLogsDir(project.New(tmpDir), num)); err != nil {
+			t.Errorf("expected sprint %d logs kept: %v", num, err)
+		}
+	}
+}
+
+// TestExecuteSubTask_StrictReviewFailsAmbiguous verifies --strict-review
+// fails a review that only mentions approval in prose, recording why
+func TestExecuteSubTask_StrictReviewFailsAmbiguous(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [x] go-coder: Write code\n  - [ ] _reviewer: Review code\n",
+	})
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "echo 'I have approved most of this, but not sure about the tests.'\n")
+	t.Setenv("PATH", bin)
+
+	run := func(strict bool) (*Result, *SprintState) {
+		t.Helper()
+		proj := project.New(tmpDir)
+		sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		task := &sprint.Tasks[0]
+		result, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[1], logging.NewLogger(project.New(tmpDir), 1), NextOptions{PreferredAgent: "claude", StrictReview: strict}, false)
+		if err != nil {
+			t.Fatalf("executeSubTask failed: %v", err)
+		}
+		sprint, err = ParseSprint(sprint.FilePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result, sprint
+	}
+
+	result, sprint := run(true)
+	if !strings.Contains(result.Message, "Review failed") {
+		t.Errorf("expected strict review to fail, got: %s", result.Message)
+	}
+	if reason := sprint.Tasks[0].SubTasks[1].FailureReason; reason != reviewAmbiguousReason {
+		t.Errorf("expected ambiguity recorded as the failure reason, got %q", reason)
+	}
+
+	// The same response passes without --strict-review
+	if err := os.WriteFile(sprint.FilePath, []byte("# Sprint 1\n\n- [ ] First task\n  - [x] go-coder: Write code\n  - [ ] _reviewer: Review code\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, _ = run(false)
+	if strings.Contains(result.Message, "Review failed") {
+		t.Errorf("expected lenient review to pass, got: %s", result.Message)
+	}
+}
+
+// TestExecuteSubTask_NoOpImplementationNotCompleted verifies an
+// implementation sub-task whose agent changes no project file is failed
+// for retry instead of being checked off, while one whose agent edits a
+// file directly, as codex does, is checked off.
+func TestExecuteSubTask_NoOpImplementationNotCompleted(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      string
+		wantChecked bool
+	}{
+		{"no-op", "echo 'All done!'\n", false},
+		{"direct edit", "echo 'package main' > main.go\necho 'All done!'\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := setupExecutionProject(t, map[string]string{
+				"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [ ] go-coder: Write code\n  - [ ] _reviewer: Review code\n",
+			})
+			bin := t.TempDir()
+			writeStubScript(t, bin, "codex", tt.script)
+			t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+			proj := project.New(tmpDir)
+			sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			task := &sprint.Tasks[0]
+			logger := logging.NewLogger(project.New(tmpDir), 1)
+
+			result, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[0], logger, NextOptions{PreferredAgent: "codex", Reporter: &recordingReporter{}}, false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.ExitCode != ExitMoreWork {
+				t.Errorf("expected exit code %d, got %d", ExitMoreWork, result.ExitCode)
+			}
+
+			sprint, err = ParseSprint(sprint.FilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sprint.Tasks[0].SubTasks[0].Checked; got != tt.wantChecked {
+				t.Errorf("expected sub-task checked=%v, got %v", tt.wantChecked, got)
+			}
+			wantFailures := 1
+			if tt.wantChecked {
+				wantFailures = 0
+			}
+			if got := sprint.Tasks[0].FailureCount; got != wantFailures {
+				t.Errorf("expected %d failure marker(s), got %d", wantFailures, got)
+			}
+		})
+	}
+}
+
+// TestExecuteSubTask_VerifyOnlyChangesNoFiles verifies a sub-task marked
+// verify-only is checked off even though its agent changes no files, and
+// that the marker survives a failure annotation.
+func TestExecuteSubTask_VerifyOnlyChangesNoFiles(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [ ] go-coder: Run the tests <!-- verify-only -->\n  - [ ] _reviewer: Review code\n",
+	})
+	bin := t.TempDir()
+	writeStubScript(t, bin, "codex", "echo 'All tests pass'\n")
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	proj := project.New(tmpDir)
+	sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := &sprint.Tasks[0]
+	if !task.SubTasks[0].VerifyOnly || task.SubTasks[0].Text != "Run the tests" {
+		t.Fatalf("expected a verify-only sub-task, got %+v", task.SubTasks[0])
+	}
+
+	result, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[0], logging.NewLogger(project.New(tmpDir), 1), NextOptions{PreferredAgent: "codex", Reporter: &recordingReporter{}}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ExitCode != ExitMoreWork {
+		t.Errorf("expected exit code %d, got %d", ExitMoreWork, result.ExitCode)
+	}
+	sprint, err = ParseSprint(sprint.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sprint.Tasks[0].SubTasks[0].Checked || sprint.Tasks[0].FailureCount != 0 {
+		t.Errorf("expected the verify-only sub-task checked with no failure, got %+v", sprint.Tasks[0])
+	}
+
+	if err := sprint.AnnotateFailure(0, 0, "tests fail"); err != nil {
+		t.Fatal(err)
+	}
+	sprint, err = ParseSprint(sprint.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := sprint.Tasks[0].SubTasks[0]; !st.VerifyOnly || st.FailureReason != "tests fail" {
+		t.Errorf("expected the marker and annotation to coexist, got %+v", st)
+	}
+}
+
+// TestNext_NoOpImplementationRetriesStop verifies an implementation that
+// never changes a file counts toward the review retry limit, so the run
+// stops for a human instead of retrying forever.
+func TestNext_NoOpImplementationRetriesStop(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [ ] go-coder: Write code\n  - [ ] _reviewer: Review code\n",
+	})
+	bin := t.TempDir()
+	writeStubScript(t, bin, "codex", "echo 'All done!'\n")
+	writeStubScript(t, bin, "claude", "echo 'No replan.'\n")
+	t.Setenv("PATH", bin)
+
+	opts := NextOptions{PreferredAgent: "codex", NoRecovery: true, Reporter: &recordingReporter{}}
+	for step := 0; step < 2*(maxReviewRetries+2); step++ {
+		result, err := NextWithOptions(project.New(tmpDir), opts)
+		var human *HumanNeededError
+		if errors.As(err, &human) {
+			sprint, err := ParseSprint(filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Reaching maxReviewRetries triggers a replan, which marks 🔄
+			if sprint.Tasks[0].ReplanCount != 1 {
+				t.Errorf("expected the retry limit to trigger a replan, got:\n%s", sprint.Content)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("step %d: unexpected error: %v", step, err)
+		}
+		if result.ExitCode != ExitMoreWork {
+			t.Fatalf("step %d: expected more work, got %d: %s", step, result.ExitCode, result.Message)
+		}
+	}
+	t.Fatal("expected the retries to stop for a human")
+}
+
+// TestExecuteSubTask_StrictSkillsStopsOnUnknownSkill verifies a sub-task
+// naming an unknown skill only warns by default, but with --strict-skills
+// stops for a human, suggesting the closest skill, without running an agent.
+func TestExecuteSubTask_StrictSkillsStopsOnUnknownSkill(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		t.Run(fmt.Sprintf("strict=%v", strict), func(t *testing.T) {
+			tmpDir := setupExecutionProject(t, map[string]string{
+				"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [ ] gocoder: Write code\n",
+			})
+			proj := project.New(tmpDir)
+			skill := project.FormatSkillWithFrontmatter(project.SkillMetadata{Name: "go-coder", Phase: "implement", Version: 1}, "# Go Coder\n")
+			if err := os.MkdirAll(proj.SkillsDir(), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(proj.SkillsDir(), "go-coder.md"), []byte(skill), 0644); err != nil {
+				t.Fatal(err)
+			}
+			bin := t.TempDir()
+			ran := filepath.Join(bin, "claude-ran")
+			writeStubScript(t, bin, "claude", "echo ran > "+ran+"\nprintf '### File: main.go\\n```go\\npackage main\\n```\\n'\n")
+			t.Setenv("PATH", bin)
+
+			sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			task := &sprint.Tasks[0]
+			report := &recordingReporter{}
+			opts := NextOptions{PreferredAgent: "claude", StrictSkills: strict, Reporter: report}
+
+			_, err = executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[0], logging.NewLogger(project.New(tmpDir), 1), opts, false)
+			_, statErr := os.Stat(ran)
+			if !strict {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if _, ok := report.find("warn", "did you mean go-coder?"); !ok {
+					t.Error("expected a warning suggesting go-coder")
+				}
+				if statErr != nil {
+					t.Error("expected the agent to run")
+				}
+				return
+			}
+			var human *HumanNeededError
+			if !errors.As(err, &human) || !strings.Contains(human.Message, "did you mean go-coder?") {
+				t.Fatalf("expected HumanNeededError suggesting go-coder, got %v", err)
+			}
+			if statErr == nil {
+				t.Error("expected no agent to run")
+			}
+		})
+	}
+}
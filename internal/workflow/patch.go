@@ -0,0 +1,289 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// filePatch is one file's section of a unified diff
+type filePatch struct {
+	OldPath string // "" when the diff has no file headers
+	NewPath string
+	Hunks   []diffHunk
+}
+
+// diffHunk is a single @@ section: body lines keep their ' ', '-' or '+'
+// prefix (an empty line is an empty context line)
+type diffHunk struct {
+	OldStart int // 1-based line in the original file
+	Lines    []string
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+\d+(?:,\d+)? @@`)
+
+// devNull is the path diffs use for a file that doesn't exist on one side
+const devNull = "/dev/null"
+
+// isDiffBlock reports whether a fenced block should be applied as a unified
+// diff: it is labeled diff or patch, or it is unlabeled and has ---/+++ file
+// headers and a hunk. Blocks for .diff/.patch files are file contents.
+func isDiffBlock(lang, file string, block []string) bool {
+	if ext := filepath.Ext(file); ext == ".diff" || ext == ".patch" {
+		return false
+	}
+	switch lang {
+	case "diff", "patch":
+		return true
+	case "":
+	default:
+		return false
+	}
+	hasHeader, hasHunk := false, false
+	for i, line := range block {
+		if strings.HasPrefix(line, "--- ") && i+1 < len(block) && strings.HasPrefix(block[i+1], "+++ ") {
+			hasHeader = true
+		}
+		if hunkHeaderRe.MatchString(line) {
+			hasHunk = true
+		}
+	}
+	return hasHeader && hasHunk
+}
+
+// parseUnifiedDiff parses the lines of a unified diff into per-file
+// patches. Hunk line counts are ignored because agents often get them
+// wrong; a hunk runs until the next hunk or file header.
+func parseUnifiedDiff(lines []string) ([]filePatch, error) {
+	var patches []filePatch
+	var current *filePatch
+	var hunk *diffHunk
+
+	finishHunk := func() {
+		if hunk != nil {
+			// Blank lines trailing a hunk are usually padding, not context
+			for len(hunk.Lines) > 0 && hunk.Lines[len(hunk.Lines)-1] == "" {
+				hunk.Lines = hunk.Lines[:len(hunk.Lines)-1]
+			}
+			current.Hunks = append(current.Hunks, *hunk)
+			hunk = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") {
+			finishHunk()
+			patches = append(patches, filePatch{
+				OldPath: diffPath(strings.TrimPrefix(line, "--- ")),
+				NewPath: diffPath(strings.TrimPrefix(lines[i+1], "+++ ")),
+			})
+			current = &patches[len(patches)-1]
+			i++
+			continue
+		}
+		if m := hunkHeaderRe.FindStringSubmatch(line); m != nil {
+			if current == nil {
+				patches = append(patches, filePatch{})
+				current = &patches[len(patches)-1]
+			}
+			finishHunk()
+			start, _ := strconv.Atoi(m[1])
+			hunk = &diffHunk{OldStart: start}
+			continue
+		}
+		if hunk == nil {
+			continue // diff --git, index, mode lines and commentary
+		}
+		switch {
+		case line == "":
+			hunk.Lines = append(hunk.Lines, line) // Context line with its trailing space trimmed
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		case strings.ContainsRune(" -+", rune(line[0])):
+			hunk.Lines = append(hunk.Lines, line)
+		default:
+			return nil, fmt.Errorf("unexpected line in hunk: %q", line)
+		}
+	}
+	if current != nil {
+		finishHunk()
+	}
+
+	for _, p := range patches {
+		if len(p.Hunks) == 0 {
+			return nil, fmt.Errorf("no hunks for %s", p.NewPath)
+		}
+	}
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("no hunks found")
+	}
+	return patches, nil
+}
+
+// diffPath strips the timestamp and a/ or b/ prefix from a diff file header
+func diffPath(header string) string {
+	path, _, _ := strings.Cut(header, "\t")
+	path = strings.TrimSpace(path)
+	if path == devNull {
+		return path
+	}
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		path = path[2:]
+	}
+	return path
+}
+
+// applyHunks applies hunks to content in order. Each hunk's context and
+// removed lines must match exactly; they are looked for at the hunk's line
+// number first and then at the nearest offset, as patch does. Nothing is
+// applied unless every hunk matches.
+func applyHunks(content string, hunks []diffHunk) (string, error) {
+	var lines []string
+	trailingNewline := content == "" || strings.HasSuffix(content, "\n")
+	if content != "" {
+		lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+
+	var out []string
+	pos := 0 // First line of lines not yet copied to out
+	for i, h := range hunks {
+		var old, replacement []string
+		for _, l := range h.Lines {
+			if l == "" {
+				l = " "
+			}
+			switch l[0] {
+			case ' ':
+				old = append(old, l[1:])
+				replacement = append(replacement, l[1:])
+			case '-':
+				old = append(old, l[1:])
+			case '+':
+				replacement = append(replacement, l[1:])
+			}
+		}
+
+		at := findLines(lines, old, h.OldStart-1, pos)
+		if at < 0 {
+			return "", fmt.Errorf("hunk %d (line %d): context does not match", i+1, h.OldStart)
+		}
+		out = append(out, lines[pos:at]...)
+		out = append(out, replacement...)
+		pos = at + len(old)
+	}
+	out = append(out, lines[pos:]...)
+
+	result := strings.Join(out, "\n")
+	if trailingNewline && len(out) > 0 {
+		result += "\n"
+	}
+	return result, nil
+}
+
+// findLines returns the index at or after min where want occurs in lines,
+// preferring the occurrence nearest to hint, or -1 if there is none
+func findLines(lines, want []string, hint, min int) int {
+	last := len(lines) - len(want)
+	if last < min {
+		return -1
+	}
+	if hint < min {
+		hint = min
+	}
+	if hint > last {
+		hint = last
+	}
+	matches := func(at int) bool {
+		for j, w := range want {
+			if lines[at+j] != w {
+				return false
+			}
+		}
+		return true
+	}
+	for d := 0; hint-d >= min || hint+d <= last; d++ {
+		if hint+d <= last && matches(hint+d) {
+			return hint + d
+		}
+		if d > 0 && hint-d >= min && matches(hint-d) {
+			return hint - d
+		}
+	}
+	return -1
+}
+
+// applyDiffBlock applies a fenced unified diff from agent output. Paths
+// come from the diff's file headers, or file (the enclosing "### File:"
+// path) when the diff has one file; they are relative to root. A patch
+// that fails is reported and skipped, leaving its file unchanged. Returns
+// the project-relative paths written.
+func applyDiffBlock(projectDir, root string, ignore *project.Ignore, file string, block []string, report Reporter) []string {
+	patches, err := parseUnifiedDiff(block)
+	if err != nil {
+		report.Warn(fmt.Sprintf("  Skipped diff: %v", err))
+		return nil
+	}
+
+	var written []string
+	for _, p := range patches {
+		target := p.NewPath
+		if target == "" || target == devNull {
+			target = p.OldPath
+		}
+		if file != "" && len(patches) == 1 {
+			target = file
+		}
+		if err := applyFilePatch(projectDir, root, ignore, target, p); err != nil {
+			report.Warn(fmt.Sprintf("  Skipped patch for %s: %v", target, err))
+			continue
+		}
+		rel, _ := resolveOutputPath(root, target)
+		rel = filepath.ToSlash(rel)
+		written = append(written, rel)
+		report.Success(fmt.Sprintf("  Patched: %s", rel))
+	}
+	return written
+}
+
+// applyFilePatch applies one file's hunks to target (relative to root)
+func applyFilePatch(projectDir, root string, ignore *project.Ignore, target string, p filePatch) error {
+	if target == "" || target == devNull {
+		return fmt.Errorf("diff has no file path")
+	}
+	if p.NewPath == devNull {
+		return fmt.Errorf("deleting files is not supported")
+	}
+	rel, err := writablePath(root, ignore, target)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(projectDir, rel)
+
+	original := ""
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil && p.OldPath == devNull:
+		return fmt.Errorf("file already exists")
+	case err == nil:
+		original = string(data)
+	case os.IsNotExist(err) && p.OldPath == devNull:
+		// New file: the hunk is all additions
+	default:
+		return err
+	}
+
+	patched, err := applyHunks(original, p.Hunks)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(patched), 0644)
+}
@@ -0,0 +1,168 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const patchOriginal = "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n"
+
+func TestParseAndWriteFiles_AppliesDiff(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte(patchOriginal), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := "I updated the greeting.\n\n```diff\n" +
+		"--- a/main.go\n" +
+		"+++ b/main.go\n" +
+		"@@ -5,3 +5,4 @@\n" +
+		" func main() {\n" +
+		"-\tfmt.Println(\"hello\")\n" +
+		"+\tfmt.Println(\"hello, world\")\n" +
+		"+\tfmt.Println(\"bye\")\n" +
+		" }\n" +
+		"```\n"
+
+	files := parseAndWriteFiles(dir, "", output, StdoutReporter{})
+
+	if strings.Join(files, ",") != "main.go" {
+		t.Errorf("unexpected files: %v", files)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello, world\")\n\tfmt.Println(\"bye\")\n}\n"
+	if string(data) != want {
+		t.Errorf("unexpected patched content:\n%s", data)
+	}
+}
+
+func TestParseAndWriteFiles_DiffContextMismatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(path, []byte(patchOriginal), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := "### File: main.go\n```diff\n" +
+		"@@ -5,3 +5,3 @@\n" +
+		" func main() {\n" +
+		"-\tfmt.Println(\"goodbye\")\n" +
+		"+\tfmt.Println(\"hi\")\n" +
+		" }\n" +
+		"```\n\n" +
+		"### File: README.md\n```markdown\n# Hello\n```\n"
+
+	files := parseAndWriteFiles(dir, "", output, StdoutReporter{})
+
+	// The mismatched patch is skipped without stopping later files
+	if strings.Join(files, ",") != "README.md" {
+		t.Errorf("unexpected files: %v", files)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != patchOriginal {
+		t.Errorf("expected file unchanged after mismatch, got:\n%s", data)
+	}
+}
+
+func TestParseAndWriteFiles_UnlabeledDiffAndNewFile(t *testing.T) {
+	dir := t.TempDir()
+	output := "```\n" +
+		"--- /dev/null\n" +
+		"+++ b/internal/app/app.go\n" +
+		"@@ -0,0 +1,3 @@\n" +
+		"+package app\n" +
+		"+\n" +
+		"+const Name = \"app\"\n" +
+		"```\n"
+
+	files := parseAndWriteFiles(dir, filepath.Join("services", "api"), output, StdoutReporter{})
+
+	if strings.Join(files, ",") != "services/api/internal/app/app.go" {
+		t.Errorf("unexpected files: %v", files)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "services", "api", "internal", "app", "app.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "package app\n\nconst Name = \"app\"\n" {
+		t.Errorf("unexpected new file content:\n%s", data)
+	}
+}
+
+func TestParseAndWriteFiles_DiffFileContentsNotApplied(t *testing.T) {
+	dir := t.TempDir()
+	output := "### File: fix.patch\n```diff\n--- a/x.go\n+++ b/x.go\n@@ -1 +1 @@\n-a\n+b\n```\n"
+
+	files := parseAndWriteFiles(dir, "", output, StdoutReporter{})
+
+	if strings.Join(files, ",") != "fix.patch" {
+		t.Errorf("expected the patch file itself to be written, got %v", files)
+	}
+}
+
+func TestApplyHunks(t *testing.T) {
+	content := "a\nb\nc\nd\ne\nf\n"
+	tests := []struct {
+		name    string
+		hunks   []diffHunk
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "exact position",
+			hunks: []diffHunk{{OldStart: 2, Lines: []string{" b", "-c", "+C", " d"}}},
+			want:  "a\nb\nC\nd\ne\nf\n",
+		},
+		{
+			name:  "offset line number",
+			hunks: []diffHunk{{OldStart: 1, Lines: []string{" d", "-e", "+E"}}},
+			want:  "a\nb\nc\nd\nE\nf\n",
+		},
+		{
+			name: "two hunks",
+			hunks: []diffHunk{
+				{OldStart: 1, Lines: []string{"-a", "+A"}},
+				{OldStart: 6, Lines: []string{" e", "-f", "+F", "+g"}},
+			},
+			want: "A\nb\nc\nd\ne\nF\ng\n",
+		},
+		{
+			name:  "empty context line",
+			hunks: []diffHunk{{OldStart: 1, Lines: []string{"-a", "+a", "+"}}},
+			want:  "a\n\nb\nc\nd\ne\nf\n",
+		},
+		{
+			name:    "context mismatch",
+			hunks:   []diffHunk{{OldStart: 2, Lines: []string{" b", "-x", "+y"}}},
+			wantErr: true,
+		},
+		{
+			name: "second hunk mismatch applies nothing",
+			hunks: []diffHunk{
+				{OldStart: 1, Lines: []string{"-a", "+A"}},
+				{OldStart: 3, Lines: []string{"-z"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyHunks(content, tt.hunks)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,14 +18,25 @@ import (
 type Result struct {
 	Message  string
 	MoreWork bool
+	// ExitCode is the exit code the step's outcome calls for: ExitDone,
+	// ExitMoreWork, or ExitHumanNeeded
+	ExitCode int
 }
 
 // PlanOptions contains options for the Plan workflow
 type PlanOptions struct {
 	// StreamOutput enables streaming agent output to this writer
 	StreamOutput io.Writer
+	// Events, if set, receives each invocation's file_written and done
+	// events (see agent.ExecuteOptions.Events)
+	Events *agent.EventWriter
 	// PreferredAgent overrides automatic agent selection
 	PreferredAgent string
+	// ModelOptions sets the model and reasoning effort of the agents that
+	// support them (codex)
+	ModelOptions agent.ModelOptions
+	// Reporter receives the phase's warnings (stdout if nil)
+	Reporter Reporter
 }
 
 // PlanPhase represents the current planning phase
@@ -40,15 +51,15 @@ const (
 )
 
 // InterviewPath returns the path to the interview file
-func InterviewPath(projectDir string) string {
-	return filepath.Join(projectDir, ".ai", "interview.md")
+func InterviewPath(proj *project.Project) string {
+	return proj.InterviewPath()
 }
 
 // GetCurrentPlanPhase determines the current planning phase based on existing files
 // Uses GetStatus(fs.FS) for detection
-func GetCurrentPlanPhase(projectDir string) PlanPhase {
-	fsys := os.DirFS(projectDir)
-	result := GetStatus(fsys)
+func GetCurrentPlanPhase(proj *project.Project) PlanPhase {
+	fsys := os.DirFS(proj.Dir)
+	result := GetStatus(fsys, proj.StateDir())
 	return result.Phase
 }
 
@@ -71,12 +82,24 @@ func GetNextPlanAction(phase PlanPhase) string {
 }
 
 // ExecutePlanPhase executes a single planning phase and returns
-func ExecutePlanPhase(projectDir string, opts PlanOptions) (*Result, error) {
-	proj := project.New(projectDir)
+func ExecutePlanPhase(proj *project.Project, opts PlanOptions) (*Result, error) {
+	projectDir := proj.Dir
 
 	// Check for GOAL.md
 	if !proj.HasGoal() {
-		return nil, fmt.Errorf("GOAL.md not found. Create a GOAL.md file describing what you want to build")
+		return nil, &HumanNeededError{Message: "GOAL.md not found. Create a GOAL.md file describing what you want to build"}
+	}
+	if err := proj.CheckGoal(); err != nil {
+		return nil, &HumanNeededError{Message: err.Error()}
+	}
+
+	// Catch an empty or template goal before it produces a garbage plan
+	goal, err := project.ParseGoal(proj.GoalPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GOAL.md: %w", err)
+	}
+	if err := goal.Validate(); err != nil {
+		return nil, &HumanNeededError{Message: err.Error()}
 	}
 
 	// Ensure directories exist
@@ -89,7 +112,7 @@ func ExecutePlanPhase(projectDir string, opts PlanOptions) (*Result, error) {
 		return nil, fmt.Errorf("%w\n\n%s", err, agent.FormatInstallInstructions())
 	}
 
-	phase := GetCurrentPlanPhase(projectDir)
+	phase := GetCurrentPlanPhase(proj)
 
 	switch phase {
 	case PhaseInterview:
@@ -104,6 +127,7 @@ func ExecutePlanPhase(projectDir string, opts PlanOptions) (*Result, error) {
 		return &Result{
 			Message:  "Planning complete. Run 'agate next' to execute sprint tasks.",
 			MoreWork: true,
+			ExitCode: ExitMoreWork,
 		}, nil
 	}
 
@@ -114,32 +138,71 @@ func getSelectedAgent(opts PlanOptions) agent.Agent {
 	if opts.PreferredAgent != "" {
 		a := agent.GetAgentByName(opts.PreferredAgent)
 		if a != nil && a.Available() {
-			return a
+			return opts.ModelOptions.Apply(a)
 		}
 	}
 	agents := agent.GetAvailableAgents()
 	if len(agents) > 0 {
-		return agents[0]
+		return opts.ModelOptions.Apply(agents[0])
 	}
 	return nil
 }
 
+// selectInterviewAgent picks the agent for interview question generation.
+// The interview is configured independently of the implementation agent:
+// interview_agent from config wins, then --agent, then claude, then the
+// first available agent.
+func selectInterviewAgent(cfg *project.Config, opts PlanOptions) agent.Agent {
+	if cfg != nil && cfg.InterviewAgent != "" {
+		a := agent.GetAgentByName(cfg.InterviewAgent)
+		if a != nil && a.Available() {
+			return opts.ModelOptions.Apply(a)
+		}
+		fmt.Printf("%s\n", logging.Yellow(fmt.Sprintf("Warning: configured interview agent %q is not available", cfg.InterviewAgent)))
+	}
+	if opts.PreferredAgent != "" {
+		a := agent.GetAgentByName(opts.PreferredAgent)
+		if a != nil && a.Available() {
+			return opts.ModelOptions.Apply(a)
+		}
+	}
+	// Claude is best at generating clarifying questions
+	if a := agent.GetAgentByName("claude"); a != nil && a.Available() {
+		return opts.ModelOptions.Apply(a)
+	}
+	return getSelectedAgent(opts)
+}
+
 func executeInterviewPhase(projectDir string, proj *project.Project, opts PlanOptions) (*Result, error) {
-	interviewPath := InterviewPath(projectDir)
+	interviewPath := InterviewPath(proj)
 
 	// Check if interview exists and is pending answers
 	if fileExists(interviewPath) {
 		content, err := os.ReadFile(interviewPath)
+		if err == nil && !logging.HasInterviewCompletionMarker(string(content)) {
+			// Without a completion box the user could never advance past the interview
+			fixed := logging.AppendInterviewCompletionLine(string(content))
+			if err := os.WriteFile(interviewPath, []byte(fixed), 0644); err != nil {
+				return nil, fmt.Errorf("interview file has no completion checkbox and adding one failed: %w\nAdd this line to %s and check it when done:\n  %s", err, interviewPath, logging.InterviewCompletionLine)
+			}
+			return &Result{
+				Message:  fmt.Sprintf("Interview file was missing its completion checkbox; added one at the bottom of:\n  %s\n\nAnswer the questions, check the completion box, then run 'agate next' again.", interviewPath),
+				MoreWork: true,
+				ExitCode: ExitHumanNeeded,
+			}, nil
+		}
 		if err == nil && !logging.ParseInterviewStatus(string(content)) {
 			return &Result{
 				Message:  fmt.Sprintf("Interview questions pending. Please answer the questions in:\n  %s\n\nCheck the completion box at the bottom when done, then run 'agate next' again.", interviewPath),
 				MoreWork: true,
+				ExitCode: ExitHumanNeeded,
 			}, nil
 		}
 		// Interview already complete, move to next phase
 		return &Result{
 			Message:  "Interview complete. Run 'agate next' to generate design.",
 			MoreWork: true,
+			ExitCode: ExitMoreWork,
 		}, nil
 	}
 
@@ -150,36 +213,51 @@ func executeInterviewPhase(projectDir string, proj *project.Project, opts PlanOp
 	}
 
 	// Get agent and logger
-	selectedAgent := getSelectedAgent(opts)
+	cfg, err := proj.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	selectedAgent := selectInterviewAgent(cfg, opts)
 	if selectedAgent == nil {
 		return nil, agent.NoAgentsError{}
 	}
 
-	logger := logging.NewLogger(projectDir, 0)
+	logger := logging.NewLogger(proj, 0)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
-	// Generate interview questions
-	interviewPrompt := buildInterviewPrompt(goal)
-	execResult := agent.ExecuteWithLogging(ctx, selectedAgent, interviewPrompt, projectDir, agent.ExecuteOptions{
-		Logger:        logger,
-		Phase:         "interview",
-		Task:          "Generate project interview questions",
-		TaskIndex:     0,
-		Skill:         "_interviewer",
-		PromptSummary: "Generating interview questions",
-		StreamWriter:  opts.StreamOutput,
-		SafeMode:      true, // Planning phase - no file writes needed
-	})
+	// Generate interview questions, re-asking with a format reminder when
+	// the agent's answer has none we can parse
+	var questions []logging.InterviewQuestion
+	for attempt := 1; attempt <= maxInterviewAttempts; attempt++ {
+		interviewPrompt := renderPrompt(proj.PromptsDir(), promptInterview, PromptData{Goal: goal.Content}, buildInterviewPrompt(goal), opts.reporter())
+		if attempt > 1 {
+			fmt.Printf("%s\n", logging.Yellow(fmt.Sprintf("No interview questions found in the response; retrying (%d/%d)...", attempt, maxInterviewAttempts)))
+			interviewPrompt += interviewFormatReminder
+		}
+		execResult := agent.ExecuteWithLogging(ctx, selectedAgent, interviewPrompt, projectDir, agent.ExecuteOptions{
+			Logger:        logger,
+			Phase:         "interview",
+			Task:          "Generate project interview questions",
+			TaskIndex:     0,
+			Skill:         "_interviewer",
+			PromptSummary: "Generating interview questions",
+			StreamWriter:  opts.StreamOutput,
+			Events:        opts.Events,
+			SafeMode:      true, // Planning phase - no file writes needed
+		})
+
+		if execResult.Error != nil {
+			return nil, fmt.Errorf("failed to generate interview: %w", execResult.Error)
+		}
 
-	if execResult.Error != nil {
-		return nil, fmt.Errorf("failed to generate interview: %w", execResult.Error)
+		questions = parseInterviewQuestionsFromResponse(execResult.Output)
+		if len(questions) > 0 {
+			break
+		}
 	}
-
-	// Parse and write interview questions
-	questions := parseInterviewQuestionsFromResponse(execResult.Output)
 	if len(questions) == 0 {
-		return nil, fmt.Errorf("no interview questions generated")
+		return nil, fmt.Errorf("no interview questions generated after %d attempts", maxInterviewAttempts)
 	}
 
 	interviewContent := logging.FormatInterview(questions)
@@ -190,6 +268,7 @@ func executeInterviewPhase(projectDir string, proj *project.Project, opts PlanOp
 	return &Result{
 		Message:  fmt.Sprintf("Interview questions generated. Please answer the questions in:\n  %s\n\nCheck the completion box when done, then run 'agate next' again.", interviewPath),
 		MoreWork: true,
+		ExitCode: ExitHumanNeeded,
 	}, nil
 }
 
@@ -201,7 +280,7 @@ func executeDesignPhase(projectDir string, proj *project.Project, opts PlanOptio
 	}
 
 	// Load interview answers
-	interviewPath := InterviewPath(projectDir)
+	interviewPath := InterviewPath(proj)
 	var interviewAnswers map[string]string
 	if content, err := os.ReadFile(interviewPath); err == nil {
 		interviewAnswers = logging.ParseInterviewAnswers(string(content))
@@ -214,13 +293,17 @@ func executeDesignPhase(projectDir string, proj *project.Project, opts PlanOptio
 		return nil, agent.NoAgentsError{}
 	}
 
-	logger := logging.NewLogger(projectDir, 0)
+	logger := logging.NewLogger(proj, 0)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
 	// Generate design overview
 	overviewPath := filepath.Join(proj.DesignDir(), "overview.md")
-	designPrompt := buildDesignPromptWithContext(goal, interviewContext, overviewPath)
+	designPrompt := renderPrompt(proj.PromptsDir(), promptDesign, PromptData{
+		Goal:       goal.Content,
+		Interview:  interviewContext,
+		OutputPath: overviewPath,
+	}, buildDesignPromptWithContext(goal, interviewContext, overviewPath), opts.reporter()) + safePlanningNote(selectedAgent, overviewPath)
 	execResult := agent.ExecuteWithLogging(ctx, selectedAgent, designPrompt, projectDir, agent.ExecuteOptions{
 		Logger:        logger,
 		Phase:         "design",
@@ -229,11 +312,16 @@ func executeDesignPhase(projectDir string, proj *project.Project, opts PlanOptio
 		Skill:         "_planner",
 		PromptSummary: "Generating design overview",
 		StreamWriter:  opts.StreamOutput,
+		Events:        opts.Events,
+		SafeMode:      true, // Planning only needs the document, not file access
 	})
 
 	if execResult.Error != nil {
 		return nil, fmt.Errorf("failed to generate design: %w", execResult.Error)
 	}
+	if err := savePlannerOutput(overviewPath, execResult.Output); err != nil {
+		return nil, fmt.Errorf("failed to write design: %w", err)
+	}
 
 	// Verify the agent wrote valid content
 	if err := validateMarkdownContent(overviewPath); err != nil {
@@ -243,6 +331,7 @@ func executeDesignPhase(projectDir string, proj *project.Project, opts PlanOptio
 	return &Result{
 		Message:  "Design overview generated. Run 'agate next' to generate technical decisions.",
 		MoreWork: true,
+		ExitCode: ExitMoreWork,
 	}, nil
 }
 
@@ -266,13 +355,17 @@ func executeDecisionsPhase(projectDir string, proj *project.Project, opts PlanOp
 		return nil, agent.NoAgentsError{}
 	}
 
-	logger := logging.NewLogger(projectDir, 0)
+	logger := logging.NewLogger(proj, 0)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
 	// Generate decisions
 	decisionsPath := filepath.Join(proj.DesignDir(), "decisions.md")
-	decisionsPrompt := buildDecisionsPrompt(goal, string(designContent), decisionsPath)
+	decisionsPrompt := renderPrompt(proj.PromptsDir(), promptDecisions, PromptData{
+		Goal:       goal.Content,
+		Design:     string(designContent),
+		OutputPath: decisionsPath,
+	}, buildDecisionsPrompt(goal, string(designContent), decisionsPath), opts.reporter()) + safePlanningNote(selectedAgent, decisionsPath)
 	execResult := agent.ExecuteWithLogging(ctx, selectedAgent, decisionsPrompt, projectDir, agent.ExecuteOptions{
 		Logger:        logger,
 		Phase:         "decisions",
@@ -281,11 +374,16 @@ func executeDecisionsPhase(projectDir string, proj *project.Project, opts PlanOp
 		Skill:         "_planner",
 		PromptSummary: "Generating technical decisions",
 		StreamWriter:  opts.StreamOutput,
+		Events:        opts.Events,
+		SafeMode:      true,
 	})
 
 	if execResult.Error != nil {
 		return nil, fmt.Errorf("failed to generate decisions: %w", execResult.Error)
 	}
+	if err := savePlannerOutput(decisionsPath, execResult.Output); err != nil {
+		return nil, fmt.Errorf("failed to write decisions: %w", err)
+	}
 
 	// Verify the agent wrote valid content
 	if err := validateMarkdownContent(decisionsPath); err != nil {
@@ -295,6 +393,7 @@ func executeDecisionsPhase(projectDir string, proj *project.Project, opts PlanOp
 	return &Result{
 		Message:  "Technical decisions generated. Run 'agate next' to generate sprint plan.",
 		MoreWork: true,
+		ExitCode: ExitMoreWork,
 	}, nil
 }
 
@@ -306,7 +405,7 @@ func executeSprintPhase(projectDir string, proj *project.Project, opts PlanOptio
 	}
 
 	// Load interview answers for context
-	interviewPath := InterviewPath(projectDir)
+	interviewPath := InterviewPath(proj)
 	var interviewAnswers map[string]string
 	if content, err := os.ReadFile(interviewPath); err == nil {
 		interviewAnswers = logging.ParseInterviewAnswers(string(content))
@@ -320,13 +419,18 @@ func executeSprintPhase(projectDir string, proj *project.Project, opts PlanOptio
 		return nil, fmt.Errorf("failed to read design: %w", err)
 	}
 
+	cfg, err := proj.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
 	// Get agent
 	selectedAgent := getSelectedAgent(opts)
 	if selectedAgent == nil {
 		return nil, agent.NoAgentsError{}
 	}
 
-	logger := logging.NewLogger(projectDir, 0)
+	logger := logging.NewLogger(proj, 0)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
@@ -341,9 +445,19 @@ func executeSprintPhase(projectDir string, proj *project.Project, opts PlanOptio
 		skillNames = append(skillNames, s.Name)
 	}
 
-	// Generate sprint plan
+	// Generate sprint plan into a temp file that is renamed into place only
+	// once valid, so an interrupted generation never leaves a half-written
+	// sprint that phase detection would treat as the plan
 	sprintPath := filepath.Join(proj.SprintsDir(), "01-initial.md")
-	sprintPrompt := buildSprintsPromptWithContext(goal, string(designContent), interviewContext, sprintPath, skillNames)
+	tmpPath := sprintPath + ".tmp"
+	os.Remove(tmpPath) // Discard leftovers from an interrupted run
+	sprintPrompt := renderPrompt(proj.PromptsDir(), promptSprints, PromptData{
+		Goal:       goal.Content,
+		Design:     string(designContent),
+		Interview:  interviewContext,
+		OutputPath: tmpPath,
+		Skills:     skillNames,
+	}, buildSprintsPromptWithContext(goal, string(designContent), interviewContext, tmpPath, skillNames, cfg.SprintMinTasks, cfg.SprintMaxTasks), opts.reporter()) + safePlanningNote(selectedAgent, tmpPath)
 	execResult := agent.ExecuteWithLogging(ctx, selectedAgent, sprintPrompt, projectDir, agent.ExecuteOptions{
 		Logger:        logger,
 		Phase:         "sprint_plan",
@@ -352,23 +466,56 @@ func executeSprintPhase(projectDir string, proj *project.Project, opts PlanOptio
 		Skill:         "_planner",
 		PromptSummary: "Generating sprint plan",
 		StreamWriter:  opts.StreamOutput,
+		Events:        opts.Events,
+		SafeMode:      true,
 	})
 
 	if execResult.Error != nil {
 		return nil, fmt.Errorf("failed to generate sprint: %w", execResult.Error)
 	}
+	if err := savePlannerOutput(tmpPath, execResult.Output); err != nil {
+		return nil, fmt.Errorf("failed to write sprint: %w", err)
+	}
 
-	// Verify the agent wrote valid content
-	if err := validateMarkdownContent(sprintPath); err != nil {
+	// Verify the agent wrote a complete sprint using only skills that
+	// exist, then move it into place
+	if err := checkSprintSkills(tmpPath, skills); err != nil {
+		return nil, err
+	}
+	if err := commitSprintFile(tmpPath, sprintPath); err != nil {
 		return nil, err
 	}
 
 	return &Result{
 		Message:  "Sprint plan generated. Run 'agate next' to start implementation.",
 		MoreWork: true,
+		ExitCode: ExitMoreWork,
 	}, nil
 }
 
+// safePlanningNote tells an agent that runs planning in safe mode, where it
+// can't write files, to answer with the document instead. Agents without a
+// safe mode run as usual and get no note.
+func safePlanningNote(a agent.Agent, outputPath string) string {
+	if _, ok := a.(agent.SafeModeAgent); !ok {
+		return ""
+	}
+	return fmt.Sprintf("\nNOTE: This run cannot write files. Respond with only the complete document, starting with its # heading and with no commentary; it will be saved to %s.\n", outputPath)
+}
+
+// savePlannerOutput writes a planner's response to path when the agent
+// didn't write the file itself, as in safe mode. The caller validates the
+// file either way.
+func savePlannerOutput(path, output string) error {
+	if fileExists(path) || strings.TrimSpace(output) == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(output), 0644)
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
@@ -409,6 +556,105 @@ func validateMarkdownContent(path string) error {
 	return nil
 }
 
+// validateSprintContent checks that sprint markdown is complete enough to
+// execute: at least one task, and every task has sub-tasks with skills.
+// A truncated write usually fails these checks.
+func validateSprintContent(content string) error {
+	sprint, err := ParseSprintContent(content)
+	if err != nil {
+		return err
+	}
+	if len(sprint.Tasks) == 0 {
+		return fmt.Errorf("sprint has no tasks")
+	}
+	for _, task := range sprint.Tasks {
+		if len(task.SubTasks) == 0 {
+			return fmt.Errorf("task %d (%s) has no sub-tasks", task.Index+1, TruncateText(task.Text, 40))
+		}
+		for _, sub := range task.SubTasks {
+			if sub.Skill == "" || sub.Text == "" {
+				return fmt.Errorf("task %d has a sub-task without a skill or description", task.Index+1)
+			}
+		}
+	}
+	return nil
+}
+
+// commitSprintFile validates the sprint an agent wrote to tmpPath and
+// atomically renames it to sprintPath. On failure sprintPath is untouched.
+func commitSprintFile(tmpPath, sprintPath string) error {
+	if err := validateMarkdownContent(tmpPath); err != nil {
+		return err
+	}
+	content, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return err
+	}
+	if err := validateSprintContent(string(content)); err != nil {
+		return fmt.Errorf("agent wrote an incomplete sprint to %s: %w - retry with 'agate next'", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, sprintPath); err != nil {
+		return fmt.Errorf("failed to move sprint into place: %w", err)
+	}
+	return nil
+}
+
+// checkSprintSkills verifies that every sub-task in the sprint at path names
+// one of the generated skills or a built-in skill, so a skill the planner
+// made up fails here instead of running with no skill content. A sprint
+// that doesn't parse is left for commitSprintFile to report.
+func checkSprintSkills(path string, skills []project.Skill) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	sprint, err := ParseSprintContent(string(content))
+	if err != nil {
+		return nil
+	}
+
+	known := map[string]bool{testGateSkill: true}
+	for _, s := range skills {
+		known[s.Name] = true
+	}
+	for _, s := range project.BuiltinSkills() {
+		known[s.Name] = true
+	}
+	names := sortedKeys(known)
+
+	var unknown []string
+	seen := make(map[string]bool)
+	for _, task := range sprint.Tasks {
+		for _, sub := range task.SubTasks {
+			if sub.Skill == "" || known[sub.Skill] || seen[sub.Skill] {
+				continue
+			}
+			seen[sub.Skill] = true
+			desc := fmt.Sprintf("%q (task %d)", sub.Skill, task.Index+1)
+			if closest := closestSkill(sub.Skill, names); closest != "" {
+				desc += fmt.Sprintf(", did you mean %s?", closest)
+			}
+			unknown = append(unknown, desc)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	return fmt.Errorf("sprint plan in %s references unknown skills: %s; available skills: %s - retry with 'agate next'", path, strings.Join(unknown, "; "), strings.Join(names, ", "))
+}
+
+// maxInterviewAttempts is how many times the interview prompt is sent before
+// giving up on getting parseable questions
+const maxInterviewAttempts = 3
+
+// interviewFormatReminder is appended to the interview prompt on retries
+const interviewFormatReminder = `
+IMPORTANT: Your previous response could not be parsed. Output ONLY the
+questions, each starting with a line that begins exactly with "QUESTION:",
+followed by the question text and a "TYPE:" line. Do not use headings,
+numbered lists, or any other format.
+`
+
 func buildInterviewPrompt(goal *project.Goal) string {
 	return fmt.Sprintf(`You are a software architect preparing to design a project. Based on the following goal, generate 3-5 clarifying questions that would help you create a better design.
 
@@ -428,14 +674,19 @@ Generate questions that:
 Format each question as:
 QUESTION: [Brief title]
 [The full question text]
+TYPE: [text, single, or multi]
 OPTIONS: [If applicable, comma-separated options]
 
 Example:
 QUESTION: Authentication Method
 What authentication method should be used for user login?
+TYPE: single
 OPTIONS: JWT tokens, Session cookies, OAuth 2.0, API keys
 
-Only include OPTIONS if there are specific choices to pick from.
+Use TYPE text for free-form answers, single when exactly one option can apply,
+and multi when several options may be chosen.
+Only include OPTIONS if there are specific choices to pick from; on a text
+question they are shown as suggestions.
 `, goal.Content)
 }
 
@@ -461,6 +712,8 @@ func parseInterviewQuestionsFromResponse(response string) []logging.InterviewQue
 			currentQuestion = &logging.InterviewQuestion{
 				Title: title,
 			}
+		} else if strings.HasPrefix(stripped, "TYPE:") && currentQuestion != nil {
+			currentQuestion.Kind = logging.ParseQuestionKind(strings.TrimPrefix(stripped, "TYPE:"))
 		} else if strings.HasPrefix(stripped, "OPTIONS:") && currentQuestion != nil {
 			optStr := strings.TrimSpace(strings.TrimPrefix(stripped, "OPTIONS:"))
 			opts := strings.Split(optStr, ",")
@@ -532,7 +785,15 @@ func findSkillByPattern(skillNames []string, pattern string, fallback string) st
 	return fallback
 }
 
-func buildSprintsPromptWithContext(goal *project.Goal, design string, interviewContext string, outputPath string, skillNames []string) string {
+// taskCountRange formats sprint sizing bounds for a prompt, e.g. "2-4"
+func taskCountRange(minTasks, maxTasks int) string {
+	if minTasks == maxTasks {
+		return fmt.Sprintf("%d", minTasks)
+	}
+	return fmt.Sprintf("%d-%d", minTasks, maxTasks)
+}
+
+func buildSprintsPromptWithContext(goal *project.Goal, design string, interviewContext string, outputPath string, skillNames []string, minTasks, maxTasks int) string {
 	// Build dynamic skill references from actual generated skills
 	coderSkill := findSkillByPattern(skillNames, "coder", "coder")
 
@@ -584,16 +845,17 @@ Focus on getting a minimal working version. Use markdown formatting with NESTED
 %s
 
 CRITICAL sprint sizing rules:
-- Keep sprints lean: aim for 2-4 top-level tasks. Each task should be a meaningful chunk of work, NOT a single function or file
+- Keep sprints lean: aim for %s top-level tasks. Each task should be a meaningful chunk of work, NOT a single function or file
 - Each task has exactly ONE coder sub-task and ONE _reviewer sub-task. Do NOT add separate code review, test-writing, or design sub-tasks
 - The coder writes implementation AND tests together in one sub-task
 - Sub-tasks format: "- [ ] skill-name: description"
 - End each task with exactly ONE "_reviewer" sub-task for validation
+- %s
 - Available skills: %s
 
 IMPORTANT: Write the complete sprint document directly to this file path: %s
 Do not create any other files. Do not output any summary or commentary. Just write the sprint plan to that exact path.
-`, goal.Content, interviewContext, design, examples, availableSkills, outputPath)
+`, goal.Content, interviewContext, design, examples, taskCountRange(minTasks, maxTasks), verifyOnlyRule, availableSkills, outputPath)
 }
 
 func buildDecisionsPrompt(goal *project.Goal, design string, outputPath string) string {
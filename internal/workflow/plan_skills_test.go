@@ -50,7 +50,7 @@ func TestBuildSprintsPromptSkillNames(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			prompt := buildSprintsPromptWithContext(goal, "design doc", "", "/tmp/sprint.md", tt.skills)
+			prompt := buildSprintsPromptWithContext(goal, "design doc", "", "/tmp/sprint.md", tt.skills, project.DefaultSprintMinTasks, project.DefaultSprintMaxTasks)
 
 			// Should contain the expected coder in examples
 			if !strings.Contains(prompt, fmt.Sprintf("  - [ ] %s:", tt.wantCoder)) {
@@ -81,3 +81,25 @@ func TestBuildSprintsPromptSkillNames(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildSprintsPrompt_CustomTaskBounds(t *testing.T) {
+	goal := &project.Goal{Content: "Build a sample app"}
+
+	prompt := buildSprintsPromptWithContext(goal, "design doc", "", "/tmp/sprint.md", []string{"go-coder"}, 3, 6)
+	if !strings.Contains(prompt, "aim for 3-6 top-level tasks") {
+		t.Errorf("prompt should contain custom task bounds, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "2-4") {
+		t.Error("prompt should not contain the default bounds")
+	}
+
+	prompt = buildSprintsPromptWithContext(goal, "design doc", "", "/tmp/sprint.md", []string{"go-coder"}, 1, 1)
+	if !strings.Contains(prompt, "aim for 1 top-level tasks") {
+		t.Errorf("prompt should contain a single task count, got:\n%s", prompt)
+	}
+
+	next := buildNextSprintPrompt("goal", "", nil, []string{"go-coder"}, "out.md", 5, 8, project.DefaultAssessContextSprints)
+	if !strings.Contains(next, "Keep sprints lean: 5-8 top-level tasks") {
+		t.Errorf("next sprint prompt should contain custom task bounds, got:\n%s", next)
+	}
+}
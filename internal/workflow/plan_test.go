@@ -0,0 +1,493 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestSelectInterviewAgent_PrefersConfiguredAgent(t *testing.T) {
+	cfg := &project.Config{InterviewAgent: "dummy"}
+
+	// The configured interview agent wins over the general --agent choice
+	selected := selectInterviewAgent(cfg, PlanOptions{PreferredAgent: "haiku"})
+	if selected == nil {
+		t.Fatal("expected an agent to be selected")
+	}
+	if selected.Name() != "dummy" {
+		t.Errorf("expected configured interview agent 'dummy', got %q", selected.Name())
+	}
+}
+
+func TestSelectInterviewAgent_FallsBackToPreferred(t *testing.T) {
+	// Unknown configured agent falls through to the --agent choice
+	cfg := &project.Config{InterviewAgent: "nonexistent"}
+
+	selected := selectInterviewAgent(cfg, PlanOptions{PreferredAgent: "dummy"})
+	if selected == nil || selected.Name() != "dummy" {
+		t.Errorf("expected fallback to preferred agent 'dummy', got %v", selected)
+	}
+}
+
+func TestParseInterviewQuestionsFromResponse_Type(t *testing.T) {
+	response := `QUESTION: Database
+Which database?
+TYPE: single
+OPTIONS: Postgres, MySQL
+
+**QUESTION: Features**
+Which features?
+TYPE: multi-choice
+OPTIONS: Auth, Logging
+
+QUESTION: Deployment
+Where will it run?
+TYPE: text`
+
+	questions := parseInterviewQuestionsFromResponse(response)
+	if len(questions) != 3 {
+		t.Fatalf("expected 3 questions, got %d", len(questions))
+	}
+
+	want := []string{logging.QuestionSingle, logging.QuestionMulti, logging.QuestionText}
+	for i, q := range questions {
+		if q.Kind != want[i] {
+			t.Errorf("question %d (%s): expected kind %q, got %q", i, q.Title, want[i], q.Kind)
+		}
+		if strings.Contains(q.Question, "TYPE:") {
+			t.Errorf("question %d text should not include the TYPE line: %q", i, q.Question)
+		}
+	}
+}
+
+func TestExecuteInterviewPhase_MissingCompletionLine(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "GOAL.md"), []byte("Build a CLI in Go."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	proj := project.New(dir)
+	if err := proj.EnsureDirectories(); err != nil {
+		t.Fatal(err)
+	}
+
+	interviewPath := InterviewPath(project.New(dir))
+	original := "# Project Interview\n\n### Q1: Database\n\nWhich DB?\n\n> Answer: Postgres\n"
+	if err := os.WriteFile(interviewPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := executeInterviewPhase(dir, proj, PlanOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Message, "missing its completion checkbox") {
+		t.Errorf("expected message about missing checkbox, got: %s", result.Message)
+	}
+	if result.ExitCode != ExitHumanNeeded {
+		t.Errorf("expected exit %d while answers are pending, got %d", ExitHumanNeeded, result.ExitCode)
+	}
+
+	content, err := os.ReadFile(interviewPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), original) {
+		t.Error("expected existing answers to be preserved")
+	}
+	if !logging.HasInterviewCompletionMarker(string(content)) {
+		t.Errorf("expected completion checkbox to be appended:\n%s", content)
+	}
+
+	// Once the user checks the box the interview is complete
+	checked := strings.Replace(string(content), "- [ ] All questions answered", "- [x] All questions answered", 1)
+	if err := os.WriteFile(interviewPath, []byte(checked), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err = executeInterviewPhase(dir, proj, PlanOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Message, "Interview complete") {
+		t.Errorf("expected interview complete, got: %s", result.Message)
+	}
+	if result.ExitCode != ExitMoreWork {
+		t.Errorf("expected exit %d after interview, got %d", ExitMoreWork, result.ExitCode)
+	}
+}
+
+// setupInterviewRetry creates a goal-only project and a claude stub that
+// answers in prose for its first badCalls calls, then in the question format.
+// It returns the project dir and the file the stub appends each prompt to.
+func setupInterviewRetry(t *testing.T, badCalls int) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "GOAL.md"), []byte("Build a CLI in Go."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := project.New(dir).EnsureDirectories(); err != nil {
+		t.Fatal(err)
+	}
+
+	bin := t.TempDir()
+	prompts := filepath.Join(bin, "prompts")
+	count := filepath.Join(bin, "count")
+	writeStubScript(t, bin, "claude", `printf '%s\n---\n' "$@" >> `+prompts+`
+echo x >> `+count+`
+if [ "$(wc -l < `+count+`)" -le `+fmt.Sprint(badCalls)+` ]; then
+	echo "Sure! Here are some thoughts about your project."
+else
+	printf 'QUESTION: Storage\nWhere should data live?\nTYPE: single\nOPTIONS: SQLite, Files\n'
+fi
+`)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir, prompts
+}
+
+func TestExecuteInterviewPhase_RetriesUnparseableResponse(t *testing.T) {
+	dir, prompts := setupInterviewRetry(t, 1)
+
+	result, err := executeInterviewPhase(dir, project.New(dir), PlanOptions{PreferredAgent: "claude"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Message, "Interview questions generated") {
+		t.Errorf("unexpected message: %s", result.Message)
+	}
+
+	content, err := os.ReadFile(InterviewPath(project.New(dir)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "Storage") {
+		t.Errorf("expected question from retry in interview:\n%s", content)
+	}
+
+	sent, err := os.ReadFile(prompts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(sent), "Based on the following goal"); n != 2 {
+		t.Errorf("expected 2 interview prompts, got %d", n)
+	}
+	if n := strings.Count(string(sent), "could not be parsed"); n != 1 {
+		t.Errorf("expected the format reminder only on the retry, got %d", n)
+	}
+}
+
+func TestExecuteInterviewPhase_FailsAfterRetries(t *testing.T) {
+	dir, prompts := setupInterviewRetry(t, maxInterviewAttempts)
+
+	_, err := executeInterviewPhase(dir, project.New(dir), PlanOptions{PreferredAgent: "claude"})
+	if err == nil || !strings.Contains(err.Error(), "no interview questions generated") {
+		t.Fatalf("expected no-questions error, got %v", err)
+	}
+	if fileExists(InterviewPath(project.New(dir))) {
+		t.Error("interview file should not be written")
+	}
+
+	sent, err := os.ReadFile(prompts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(sent), "Based on the following goal"); n != maxInterviewAttempts {
+		t.Errorf("expected %d interview prompts, got %d", maxInterviewAttempts, n)
+	}
+}
+
+func TestValidateSprintContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "complete",
+			content: "# Sprint 1\n\n- [ ] Build it\n  - [ ] go-coder: Write code\n  - [ ] _reviewer: Review code\n",
+		},
+		{
+			name:    "no tasks",
+			content: "# Sprint 1\n\n## Goal\n\nBuild it.\n",
+			wantErr: "no tasks",
+		},
+		{
+			name:    "truncated after task line",
+			content: "# Sprint 1\n\n- [ ] Build it\n  - [ ] go-coder: Write code\n- [ ] Test it\n",
+			wantErr: "task 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSprintContent(tt.content)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestExecuteSprintPhase_InterruptedWriteLeavesNoSprint simulates an agent
+// that dies partway through writing the sprint plan: the partial plan must
+// not appear in the sprints dir, so the project stays in the sprint phase.
+func TestExecuteSprintPhase_InterruptedWriteLeavesNoSprint(t *testing.T) {
+	tmpDir := setupExecutionProject(t, nil)
+
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", `path=$(printf '%s\n' "$@" | sed -n 's/.*directly to this file path: //p' | head -n 1)
+printf '# Sprint 1\n\n## Tasks\n\n- [ ] Set up project\n' > "$path"
+exit 1
+`)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	proj := project.New(tmpDir)
+	if _, err := executeSprintPhase(tmpDir, proj, PlanOptions{PreferredAgent: "claude"}); err == nil {
+		t.Fatal("expected error from interrupted sprint generation")
+	}
+
+	sprintPath := filepath.Join(proj.SprintsDir(), "01-initial.md")
+	if _, err := os.Stat(sprintPath); !os.IsNotExist(err) {
+		t.Errorf("expected no sprint file after interrupted write, stat err: %v", err)
+	}
+	if _, err := os.Stat(sprintPath + ".tmp"); err != nil {
+		t.Fatalf("expected the stub to have written the temp file: %v", err)
+	}
+	if phase := GetCurrentPlanPhase(project.New(tmpDir)); phase != PhaseSprint {
+		t.Errorf("expected phase %s, got %s", PhaseSprint, phase)
+	}
+}
+
+// TestPlanningPhases_UseSafeMode verifies design, decisions, and sprint
+// planning run a SafeModeAgent without skipping permissions, and save its
+// response since it can't write the file itself.
+func TestPlanningPhases_UseSafeMode(t *testing.T) {
+	phases := []struct {
+		name string
+		file string
+		run  func(string, *project.Project, PlanOptions) (*Result, error)
+	}{
+		{"design", filepath.Join(".ai", "design", "overview.md"), executeDesignPhase},
+		{"decisions", filepath.Join(".ai", "design", "decisions.md"), executeDecisionsPhase},
+		{"sprint", filepath.Join(".ai", "sprints", "01-initial.md"), executeSprintPhase},
+	}
+	for _, phase := range phases {
+		t.Run(phase.name, func(t *testing.T) {
+			tmpDir := setupExecutionProject(t, nil)
+			if phase.name == "design" || phase.name == "decisions" {
+				os.Remove(filepath.Join(tmpDir, phase.file))
+			}
+
+			bin := t.TempDir()
+			args := filepath.Join(bin, "args")
+			// A reply that is a valid document for every phase
+			writeStubScript(t, bin, "claude", `printf '%s\n' "$@" > `+args+`
+printf '# Sprint 1\n\n- [ ] Set up\n  - [ ] go-coder: Init module\n'
+`)
+			t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+			if _, err := phase.run(tmpDir, project.New(tmpDir), PlanOptions{PreferredAgent: "claude"}); err != nil {
+				t.Fatalf("phase failed: %v", err)
+			}
+			recorded, err := os.ReadFile(args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(recorded), "--dangerously-skip-permissions") {
+				t.Error("expected the safe execution path, got --dangerously-skip-permissions")
+			}
+			if !strings.Contains(string(recorded), "This run cannot write files") {
+				t.Error("expected the prompt to ask for the document in the response")
+			}
+			content, err := os.ReadFile(filepath.Join(tmpDir, phase.file))
+			if err != nil || !strings.HasPrefix(string(content), "# Sprint 1") {
+				t.Errorf("expected the response saved to %s, got %q (%v)", phase.file, content, err)
+			}
+		})
+	}
+}
+
+func TestCommitSprintFile(t *testing.T) {
+	dir := t.TempDir()
+	sprintPath := filepath.Join(dir, "01-initial.md")
+	tmpPath := sprintPath + ".tmp"
+
+	// A partial write is rejected and never reaches the final path
+	if err := os.WriteFile(tmpPath, []byte("# Sprint 1\n\n- [ ] Set up project\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := commitSprintFile(tmpPath, sprintPath); err == nil {
+		t.Fatal("expected error for partial sprint")
+	}
+	if _, err := os.Stat(sprintPath); !os.IsNotExist(err) {
+		t.Errorf("expected no sprint file, stat err: %v", err)
+	}
+
+	// A complete write is moved into place
+	complete := "# Sprint 1\n\n- [ ] Set up project\n  - [ ] go-coder: Write code\n  - [ ] _reviewer: Review code\n"
+	if err := os.WriteFile(tmpPath, []byte(complete), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := commitSprintFile(tmpPath, sprintPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(sprintPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != complete {
+		t.Errorf("unexpected sprint content:\n%s", content)
+	}
+	if _, err := os.Stat(tmpPath); !os.IsNotExist(err) {
+		t.Error("expected temp file to be gone after rename")
+	}
+}
+
+func TestExecutePlanPhase_RejectsTemplateGoal(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "GOAL.md"), []byte(project.GoalTemplate), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ExecutePlanPhase(project.New(tmpDir), PlanOptions{PreferredAgent: "dummy"})
+	var humanErr *HumanNeededError
+	if !errors.As(err, &humanErr) {
+		t.Fatalf("expected HumanNeededError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "GOAL.md looks unedited") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(project.New(tmpDir).InterviewPath()); !os.IsNotExist(err) {
+		t.Errorf("expected no interview to be written, stat err: %v", err)
+	}
+}
+
+func TestNextWithOptions_GoalIsDirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, "GOAL.md"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"})
+	var humanErr *HumanNeededError
+	if !errors.As(err, &humanErr) {
+		t.Fatalf("expected HumanNeededError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "GOAL.md is a directory") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestExecuteSprintPhase_RejectsUnknownSkill verifies a sprint plan whose
+// sub-tasks name a skill that was neither generated nor built in fails with
+// the unknown skills listed, and never becomes the sprint.
+func TestExecuteSprintPhase_RejectsUnknownSkill(t *testing.T) {
+	tmpDir := setupExecutionProject(t, nil)
+
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", `printf '# Sprint 1\n\n- [ ] Set up\n  - [ ] go-codr: Init module\n  - [ ] bogus-skill: Do magic\n  - [ ] _reviewer: Review it\n'
+`)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	proj := project.New(tmpDir)
+	_, err := executeSprintPhase(tmpDir, proj, PlanOptions{PreferredAgent: "claude"})
+	if err == nil {
+		t.Fatal("expected error for a sprint referencing unknown skills")
+	}
+	for _, want := range []string{`"go-codr" (task 1), did you mean go-coder?`, `"bogus-skill" (task 1)`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got: %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), `"_reviewer"`) {
+		t.Errorf("expected the built-in _reviewer skill to be accepted, got: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(proj.SprintsDir(), "01-initial.md")); !os.IsNotExist(err) {
+		t.Errorf("expected no sprint file after a rejected plan, stat err: %v", err)
+	}
+	if phase := GetCurrentPlanPhase(project.New(tmpDir)); phase != PhaseSprint {
+		t.Errorf("expected phase %s, got %s", PhaseSprint, phase)
+	}
+}
+
+func TestCheckSprintSkills(t *testing.T) {
+	skills := []project.Skill{{Name: "go-coder"}}
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "generated and built-in skills",
+			content: "# Sprint 1\n\n- [ ] Build it\n  - [ ] go-coder: Write code\n  - [ ] _reviewer: Review code\n  - [ ] _test-gate: Run tests\n",
+		},
+		{
+			name:    "unknown skill",
+			content: "# Sprint 1\n\n- [ ] Build it\n  - [ ] go-coder: Write code\n- [ ] Ship it\n  - [ ] deployer: Deploy\n",
+			wantErr: `unknown skills: "deployer" (task 2)`,
+		},
+		{
+			name:    "unparseable sprint is left to commitSprintFile",
+			content: "I wrote the sprint plan for you.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "01-initial.md.tmp")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			err := checkSprintSkills(path, skills)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestNext_DummyAgentPlansAndCompletesSprint runs the built-in dummy agent
+// from sprint planning through the last sub-task, so its canned sprint must
+// pass the plan-time skill check and every sub-task must complete.
+func TestNext_DummyAgentPlansAndCompletesSprint(t *testing.T) {
+	tmpDir := setupExecutionProject(t, nil)
+	opts := NextOptions{PreferredAgent: "dummy", Reporter: &recordingReporter{}}
+
+	var result *Result
+	for step := 0; step < 20; step++ {
+		var err error
+		result, err = NextWithOptions(project.New(tmpDir), opts)
+		if err != nil {
+			t.Fatalf("step %d failed: %v", step+1, err)
+		}
+		if result.ExitCode == ExitDone {
+			break
+		}
+	}
+	if result.ExitCode != ExitDone {
+		t.Fatalf("expected the sprint to finish within 20 steps, last result: %+v", result)
+	}
+
+	sprint, err := ParseSprint(filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sprint.IsComplete() {
+		t.Error("expected the dummy sprint to be complete")
+	}
+}
@@ -0,0 +1,69 @@
+package workflow
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// Prompts that can be overridden with a <name>.tmpl file in the prompts
+// directory (.ai/prompts/, under the state dir)
+const (
+	promptInterview  = "interview"
+	promptDesign     = "design"
+	promptDecisions  = "decisions"
+	promptSprints    = "sprints"
+	promptSubTask    = "subtask"
+	promptNextSprint = "next-sprint"
+)
+
+// PromptData is the data a prompt template is executed with. Fields that
+// don't apply to a prompt are empty.
+type PromptData struct {
+	Goal       string      // GOAL.md content
+	Design     string      // Design overview
+	Interview  string      // Formatted interview answers (planning prompts)
+	OutputPath string      // File the agent must write (planning prompts)
+	Skills     []string    // Skill names available to sprint plans
+	Task       PromptTask  // Current task (sub-task prompt)
+	Skill      PromptSkill // Sub-task's skill (sub-task prompt)
+	Default    string      // The built-in prompt, so a template can extend it
+}
+
+// PromptTask describes the task a sub-task prompt is for
+type PromptTask struct {
+	Text    string // Main task text
+	SubTask string // Sub-task text
+}
+
+// PromptSkill describes the skill a sub-task runs with
+type PromptSkill struct {
+	Name    string
+	Phase   string // implement, review, ... or "" if unknown
+	Content string // Skill guidelines
+}
+
+// renderPrompt returns the prompt for name: the override template in
+// promptsDir executed with data if one exists, else builtin. A template
+// that fails to parse or execute is reported as a warning and falls back
+// too, so a typo never blocks a run.
+func renderPrompt(promptsDir, name string, data PromptData, builtin string, report Reporter) string {
+	path := filepath.Join(promptsDir, name+".tmpl")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return builtin
+	}
+
+	data.Default = builtin
+	tmpl, err := template.New(name).Parse(string(content))
+	if err == nil {
+		var buf bytes.Buffer
+		if err = tmpl.Execute(&buf, data); err == nil {
+			return buf.String()
+		}
+	}
+	report.Warn(fmt.Sprintf("Warning: ignoring prompt template %s: %v", path, err))
+	return builtin
+}
@@ -0,0 +1,108 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestRenderPrompt_BuiltinWithoutOverride(t *testing.T) {
+	got := renderPrompt(t.TempDir(), promptDesign, PromptData{Goal: "Build a CLI"}, "built-in prompt", &recordingReporter{})
+	if got != "built-in prompt" {
+		t.Errorf("expected built-in prompt, got %q", got)
+	}
+}
+
+func TestRenderPrompt_Override(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := "Goal: {{.Goal}}\nWrite to {{.OutputPath}} using {{range .Skills}}{{.}} {{end}}\n---\n{{.Default}}"
+	if err := os.WriteFile(filepath.Join(dir, "sprints.tmpl"), []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := renderPrompt(dir, promptSprints, PromptData{
+		Goal:       "Build a CLI",
+		OutputPath: "out.md",
+		Skills:     []string{"go-coder", "_reviewer"},
+	}, "built-in prompt", &recordingReporter{})
+
+	want := "Goal: Build a CLI\nWrite to out.md using go-coder _reviewer \n---\nbuilt-in prompt"
+	if got != want {
+		t.Errorf("renderPrompt() = %q, want %q", got, want)
+	}
+
+	// Other prompts are unaffected by the sprints override
+	if got := renderPrompt(dir, promptDesign, PromptData{}, "design prompt", &recordingReporter{}); got != "design prompt" {
+		t.Errorf("expected design built-in, got %q", got)
+	}
+}
+
+func TestRenderPrompt_BrokenTemplateFallsBack(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"design.tmpl":    "{{.Goal",          // Parse error
+		"decisions.tmpl": "{{.NoSuchField}}", // Execute error
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report := &recordingReporter{}
+	if got := renderPrompt(dir, promptDesign, PromptData{}, "built-in", report); got != "built-in" {
+		t.Errorf("expected fallback on parse error, got %q", got)
+	}
+	if got := renderPrompt(dir, promptDecisions, PromptData{}, "built-in", report); got != "built-in" {
+		t.Errorf("expected fallback on execute error, got %q", got)
+	}
+	for _, name := range []string{"design.tmpl", "decisions.tmpl"} {
+		if _, ok := report.find("warn", "ignoring prompt template "+filepath.Join(dir, name)); !ok {
+			t.Errorf("expected a warning for %s, got %+v", name, report.messages)
+		}
+	}
+}
+
+// TestExecuteSubTask_PromptOverride verifies a subtask.tmpl override is what
+// the agent receives
+func TestExecuteSubTask_PromptOverride(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Build parser\n  - [ ] go-coder: Write parser\n",
+	})
+	proj := project.New(tmpDir)
+	if err := os.MkdirAll(proj.PromptsDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := "CUSTOM {{.Task.Text}} / {{.Task.SubTask}} as {{.Skill.Name}} ({{.Skill.Phase}})\n"
+	if err := os.WriteFile(filepath.Join(proj.PromptsDir(), "subtask.tmpl"), []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bin := t.TempDir()
+	promptFile := filepath.Join(bin, "prompt")
+	writeStubScript(t, bin, "claude", "printf '%s\\n' \"$@\" > "+promptFile+"\necho done\n")
+	t.Setenv("PATH", bin)
+
+	sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := &sprint.Tasks[0]
+	if _, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[0], logging.NewLogger(project.New(tmpDir), 1), NextOptions{PreferredAgent: "claude"}, false); err != nil {
+		t.Fatalf("executeSubTask failed: %v", err)
+	}
+
+	prompt, err := os.ReadFile(promptFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(prompt), "CUSTOM Build parser / Write parser as go-coder (implement)") {
+		t.Errorf("expected override prompt, got:\n%s", prompt)
+	}
+	if strings.Contains(string(prompt), "You are working on a software project") {
+		t.Error("built-in prompt should be replaced by the override")
+	}
+}
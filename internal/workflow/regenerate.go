@@ -0,0 +1,135 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/strongdm/agate/internal/agent"
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// RegenerateSprint rewrites sprint sprintNum from scratch, for a sprint file
+// mangled beyond parsing. The planner gets the goal, the design, and the
+// sprints before it, as when planning a next sprint. The old file is backed
+// up to the sprint drafts directory first, and is only replaced once the
+// new sprint is valid.
+func RegenerateSprint(proj *project.Project, sprintNum int, opts NextOptions) (*Result, error) {
+	if !proj.HasGoal() {
+		return nil, &HumanNeededError{Message: "GOAL.md not found. Create a GOAL.md file describing what you want to build"}
+	}
+
+	sprintPath := findSprintByNum(proj.SprintsDir(), sprintNum)
+	if sprintPath == "" {
+		return nil, fmt.Errorf("sprint %d not found", sprintNum)
+	}
+
+	backupPath, err := backupSprint(proj, sprintPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up sprint %d: %w", sprintNum, err)
+	}
+	fmt.Println(logging.Dim(fmt.Sprintf("Backed up %s to %s", filepath.Base(sprintPath), backupPath)))
+
+	goalContent, err := os.ReadFile(proj.GoalPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GOAL.md: %w", err)
+	}
+	designContent := ""
+	if content, err := os.ReadFile(filepath.Join(proj.DesignDir(), "overview.md")); err == nil {
+		designContent = string(content)
+	}
+	completed := loadCompletedSprintSummaries(proj.SprintsDir(), sprintNum-1)
+	skills, _ := project.LoadSkills(proj.SkillsDir())
+	skillNames := plannerSkillNames(skills)
+
+	cfg, err := proj.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Generate into a temp file, as for the first sprint, so a failed run
+	// leaves the old file in place
+	tmpPath := sprintPath + ".tmp"
+	os.Remove(tmpPath)
+	prompt := renderPrompt(proj.PromptsDir(), promptNextSprint, PromptData{
+		Goal:       string(goalContent),
+		Design:     designContent,
+		OutputPath: tmpPath,
+		Skills:     skillNames,
+	}, buildNextSprintPrompt(string(goalContent), designContent, completed, skillNames, tmpPath, cfg.SprintMinTasks, cfg.SprintMaxTasks, cfg.AssessContextSprints), opts.reporter())
+	prompt += fmt.Sprintf("\nNOTE: Sprint %d's file was corrupted and is being rewritten. Do not respond with GOAL_COMPLETE: write sprint %d to the file path above.\n", sprintNum, sprintNum)
+
+	selectedAgent, err := selectAgent(opts.PreferredAgent, "_planner", skills, opts.reporter())
+	if err != nil {
+		return nil, err
+	}
+	selectedAgent = opts.ModelOptions.Apply(selectedAgent)
+	prompt += safePlanningNote(selectedAgent, tmpPath)
+
+	logger := logging.NewLogger(proj, sprintNum)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	execResult := agent.ExecuteWithLogging(ctx, selectedAgent, prompt, proj.Dir, agent.ExecuteOptions{
+		Logger:        logger,
+		Phase:         "regenerate",
+		Task:          fmt.Sprintf("Regenerate sprint %d", sprintNum),
+		TaskIndex:     0,
+		Skill:         "_planner",
+		PromptSummary: fmt.Sprintf("Regenerating sprint %d", sprintNum),
+		StreamWriter:  opts.StreamOutput,
+		Events:        opts.Events,
+		SafeMode:      true,
+	})
+	if execResult.Error != nil {
+		return nil, fmt.Errorf("failed to regenerate sprint %d: %w", sprintNum, execResult.Error)
+	}
+
+	// An agent that answers with the sprint instead of writing it still
+	// counts; commitSprintFile validates either way
+	if err := savePlannerOutput(tmpPath, execResult.Output); err != nil {
+		return nil, fmt.Errorf("failed to write sprint: %w", err)
+	}
+	if err := commitSprintFile(tmpPath, sprintPath); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	return &Result{
+		Message:  fmt.Sprintf("Sprint %d regenerated (old version in %s). Run 'agate next' to continue.", sprintNum, backupPath),
+		MoreWork: true,
+		ExitCode: ExitMoreWork,
+	}, nil
+}
+
+// backupSprint copies a sprint file into the sprint drafts directory under
+// a timestamped name and returns the copy's path relative to the project
+func backupSprint(proj *project.Project, sprintPath string) (string, error) {
+	return backupToDrafts(proj, proj.SprintDraftsDir(), sprintPath)
+}
+
+// backupToDrafts copies a file into draftsDir as <name>.<timestamp>.md and
+// returns the copy's path relative to the project
+func backupToDrafts(proj *project.Project, draftsDir, path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(draftsDir, 0755); err != nil {
+		return "", err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(path), ".md")
+	backup := filepath.Join(draftsDir, fmt.Sprintf("%s.%s.md", base, time.Now().Format("20060102-150405")))
+	if err := os.WriteFile(backup, content, 0644); err != nil {
+		return "", err
+	}
+	if rel, err := filepath.Rel(proj.Dir, backup); err == nil {
+		return rel, nil
+	}
+	return backup, nil
+}
@@ -0,0 +1,136 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestRegenerateSprint_ReplacesBrokenSprint(t *testing.T) {
+	broken := "## Tasks\n- [x Set up\n  -[ ] go-coder Build\n<<<<<<< HEAD\n"
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [x] Set up\n  - [x] go-coder: Init module\n",
+		"02-next.md":    broken,
+	})
+	sprintPath := filepath.Join(tmpDir, ".ai", "sprints", "02-next.md")
+
+	result, err := RegenerateSprint(project.New(tmpDir), 2, NextOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("RegenerateSprint failed: %v", err)
+	}
+	if result.ExitCode != ExitMoreWork {
+		t.Errorf("expected exit code %d, got %d", ExitMoreWork, result.ExitCode)
+	}
+
+	sprint, err := ParseSprint(sprintPath)
+	if err != nil {
+		t.Fatalf("regenerated sprint does not parse: %v", err)
+	}
+	if len(sprint.Tasks) == 0 || len(sprint.Tasks[0].SubTasks) == 0 {
+		t.Fatalf("expected tasks with sub-tasks, got %+v", sprint.Tasks)
+	}
+	if _, err := os.Stat(sprintPath + ".tmp"); !os.IsNotExist(err) {
+		t.Error("expected the temp file to be moved into place")
+	}
+
+	// The mangled file is kept in the drafts directory
+	drafts, err := os.ReadDir(filepath.Join(tmpDir, ".ai", "sprints", ".drafts"))
+	if err != nil || len(drafts) != 1 {
+		t.Fatalf("expected one backup, got %v (%v)", drafts, err)
+	}
+	if !strings.HasPrefix(drafts[0].Name(), "02-next.") {
+		t.Errorf("unexpected backup name %s", drafts[0].Name())
+	}
+	backup, _ := os.ReadFile(filepath.Join(tmpDir, ".ai", "sprints", ".drafts", drafts[0].Name()))
+	if string(backup) != broken {
+		t.Errorf("expected the backup to hold the broken sprint, got %q", backup)
+	}
+
+	// The backup doesn't count as a sprint
+	if _, num := FindCurrentSprintFS(os.DirFS(tmpDir), project.DefaultStateDir); num != 2 {
+		t.Errorf("expected sprint 2 as current, got %d", num)
+	}
+}
+
+func TestRegenerateSprint_Missing(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Set up\n  - [ ] go-coder: Init module\n",
+	})
+	if _, err := RegenerateSprint(project.New(tmpDir), 3, NextOptions{PreferredAgent: "dummy"}); err == nil || !strings.Contains(err.Error(), "sprint 3 not found") {
+		t.Errorf("expected a not-found error, got %v", err)
+	}
+}
+
+// TestRegenerateSprint_InvalidOutputKeepsOld verifies a response that is not
+// a sprint leaves the old file in place.
+func TestRegenerateSprint_InvalidOutputKeepsOld(t *testing.T) {
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "echo 'Sorry, I cannot help with that.'\n")
+	t.Setenv("PATH", bin)
+
+	old := "# Sprint 1\n\nmangled\n"
+	tmpDir := setupExecutionProject(t, map[string]string{"01-initial.md": old})
+
+	if _, err := RegenerateSprint(project.New(tmpDir), 1, NextOptions{PreferredAgent: "claude"}); err == nil {
+		t.Fatal("expected an error for a response that is not a sprint")
+	}
+	content, _ := os.ReadFile(filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md"))
+	if string(content) != old {
+		t.Errorf("expected the old sprint to be kept, got %q", content)
+	}
+}
+
+// TestSprintPlanners_UseSafeMode verifies assessment and regeneration run a
+// SafeModeAgent without skipping permissions, and save its response since it
+// can't write the sprint itself.
+func TestSprintPlanners_UseSafeMode(t *testing.T) {
+	planners := []struct {
+		name string
+		file string
+		run  func(*project.Project) error
+	}{
+		{"assess", "02-next.md", func(proj *project.Project) error {
+			_, err := assessGoalAndPlanNext(proj.Dir, proj, 1, NextOptions{PreferredAgent: "claude"})
+			return err
+		}},
+		{"regenerate", "01-initial.md", func(proj *project.Project) error {
+			_, err := RegenerateSprint(proj, 1, NextOptions{PreferredAgent: "claude"})
+			return err
+		}},
+	}
+	for _, planner := range planners {
+		t.Run(planner.name, func(t *testing.T) {
+			tmpDir := setupExecutionProject(t, map[string]string{
+				"01-initial.md": "# Sprint 1\n\n- [x] Set up\n  - [x] go-coder: Init module\n",
+			})
+
+			bin := t.TempDir()
+			args := filepath.Join(bin, "args")
+			writeStubScript(t, bin, "claude", `printf '%s\n' "$@" > `+args+`
+printf '# Sprint 2\n\n- [ ] Build\n  - [ ] go-coder: Write code\n'
+`)
+			t.Setenv("PATH", bin)
+
+			if err := planner.run(project.New(tmpDir)); err != nil {
+				t.Fatalf("planner failed: %v", err)
+			}
+			recorded, err := os.ReadFile(args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(recorded), "--dangerously-skip-permissions") {
+				t.Error("expected the safe execution path, got --dangerously-skip-permissions")
+			}
+			if !strings.Contains(string(recorded), "This run cannot write files") {
+				t.Error("expected the prompt to ask for the sprint in the response")
+			}
+			content, err := os.ReadFile(filepath.Join(tmpDir, ".ai", "sprints", planner.file))
+			if err != nil || !strings.HasPrefix(string(content), "# Sprint 2") {
+				t.Errorf("expected the response saved to %s, got %q (%v)", planner.file, content, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package workflow
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// ReplayOptions contains options for replaying a sprint
+type ReplayOptions struct {
+	// SprintNum is the sprint to replay (required)
+	SprintNum int
+	// PreferredAgent selects the agent for every step (default: dummy).
+	// A dummy replay is dry: the files in its output are not written.
+	PreferredAgent string
+	// StreamOutput enables streaming agent output to this writer
+	StreamOutput io.Writer
+	// OnStep, if set, is called with each step's result as it completes
+	OnStep func(step int, result *Result)
+}
+
+// ReplayResult summarizes a replay and how it compares to the original run
+type ReplayResult struct {
+	SprintNum int
+	Steps     []string // Message of each Next step, in order
+	// BackupPath is the copy of the sprint file taken before the reset,
+	// relative to the project
+	BackupPath string
+
+	// Failed reviews (❌) and replans (🔄) recorded in the sprint before the
+	// reset and after the replay
+	OriginalFailures, ReplayFailures int
+	OriginalReplans, ReplayReplans   int
+}
+
+// Replay resets every checkbox and marker in a sprint and drives Next until
+// the sprint is complete again, for reproducing a run deterministically.
+// Every earlier sprint must be complete so Next works on the target sprint;
+// later sprints are never touched, and the replay stops as soon as the
+// target is complete rather than assessing the goal or planning onwards.
+// The sprint file is backed up to the sprint drafts directory before the
+// reset. Callers should hold the project's run-lock.
+func Replay(proj *project.Project, opts ReplayOptions) (*ReplayResult, error) {
+	projectDir := proj.Dir
+	sprintPath := findSprintByNum(proj.SprintsDir(), opts.SprintNum)
+	if sprintPath == "" {
+		return nil, fmt.Errorf("sprint %d not found", opts.SprintNum)
+	}
+
+	for _, s := range loadCompletedSprintSummaries(proj.SprintsDir(), opts.SprintNum-1) {
+		earlier, err := ParseSprintContent(s.Content)
+		if err != nil || !earlier.IsComplete() {
+			return nil, fmt.Errorf("sprint %d is not complete; replay needs every earlier sprint done", s.Num)
+		}
+	}
+
+	sprint, err := ParseSprint(sprintPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sprint: %w", err)
+	}
+	if len(sprint.Tasks) == 0 {
+		return nil, fmt.Errorf("sprint %d has no tasks to replay", opts.SprintNum)
+	}
+
+	result := &ReplayResult{SprintNum: opts.SprintNum}
+	original := sprint.CountMarkers()
+	result.OriginalFailures, result.OriginalReplans = original.Failures, original.Replans
+
+	backupPath, err := backupSprint(proj, sprintPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up sprint: %w", err)
+	}
+	result.BackupPath = backupPath
+	if err := sprint.ResetAll(); err != nil {
+		return result, fmt.Errorf("failed to reset sprint: %w", err)
+	}
+
+	agentName := opts.PreferredAgent
+	if agentName == "" {
+		agentName = "dummy"
+	}
+	// The dummy agent's canned files would overwrite the project's real ones
+	nextOpts := NextOptions{StreamOutput: opts.StreamOutput, PreferredAgent: agentName, NoWriteFiles: agentName == "dummy"}
+
+	// Every sub-task may fail review up to the retry limit and be replanned
+	_, total := sprint.GetOverallProgress()
+	maxSteps := total*(maxReviewRetries+2) + 1
+
+	for step := 1; ; step++ {
+		sprint, err = ParseSprint(sprintPath)
+		if err != nil {
+			return result, fmt.Errorf("failed to parse sprint: %w", err)
+		}
+		if sprint.IsComplete() {
+			break
+		}
+		if step > maxSteps {
+			return result, fmt.Errorf("sprint %d not complete after %d steps", opts.SprintNum, maxSteps)
+		}
+
+		status := GetStatus(os.DirFS(projectDir), proj.StateDir())
+		if filepath.Join(projectDir, status.CurrentSprintPath) != sprintPath {
+			return result, fmt.Errorf("current sprint is %s, not sprint %d", status.CurrentSprintPath, opts.SprintNum)
+		}
+
+		stepResult, err := NextWithOptions(proj, nextOpts)
+		if err != nil {
+			return result, fmt.Errorf("step %d: %w", step, err)
+		}
+		result.Steps = append(result.Steps, stepResult.Message)
+		if opts.OnStep != nil {
+			opts.OnStep(step, stepResult)
+		}
+	}
+
+	replayed := sprint.CountMarkers()
+	result.ReplayFailures, result.ReplayReplans = replayed.Failures, replayed.Replans
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/fsutil"
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestReplay_DummyAgentToCompletion(t *testing.T) {
+	sprint1 := "# Sprint 1\n\n" +
+		"- [x] ❌ Build parser\n" +
+		"  - [x] go-coder: Write parser <!-- fail: tests missing -->\n" +
+		"  - [x] _reviewer: Review parser\n" +
+		"- [x] Wire CLI\n" +
+		"  - [x] go-coder: Add command\n" +
+		"  - [x] _reviewer: Review command\n"
+	sprint2 := "# Sprint 2\n\n- [x] Polish\n  - [x] go-coder: Tidy up\n  - [x] _reviewer: Review\n"
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": sprint1,
+		"02-next.md":    sprint2,
+	})
+	mainPath := filepath.Join(tmpDir, "main.go")
+	if err := os.WriteFile(mainPath, []byte("package main // real code\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var steps []int
+	result, err := Replay(project.New(tmpDir), ReplayOptions{
+		SprintNum: 1,
+		OnStep:    func(step int, _ *Result) { steps = append(steps, step) },
+	})
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+
+	// One step per sub-task with the dummy agent approving every review
+	if len(result.Steps) != 4 || len(steps) != 4 {
+		t.Errorf("expected 4 steps, got %d: %v", len(result.Steps), result.Steps)
+	}
+	if !strings.Contains(result.Steps[len(result.Steps)-1], "Sprint complete") {
+		t.Errorf("expected last step to complete the sprint, got %q", result.Steps[len(result.Steps)-1])
+	}
+	if result.OriginalFailures != 1 || result.ReplayFailures != 0 {
+		t.Errorf("expected failures original 1 / replay 0, got %d / %d", result.OriginalFailures, result.ReplayFailures)
+	}
+
+	sprint, err := ParseSprint(filepath.Join(tmpDir, ".ai", "sprints", "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sprint.IsComplete() {
+		t.Error("expected sprint 1 to be complete after replay")
+	}
+	if sprint.Tasks[0].SubTasks[0].FailureReason != "" {
+		t.Error("expected the original failure annotation to be cleared")
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".ai", "sprints", "02-next.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != sprint2 {
+		t.Errorf("expected later sprint untouched, got:\n%s", data)
+	}
+	if sprints := fsutil.ListMarkdownFiles(filepath.Join(tmpDir, ".ai", "sprints")); len(sprints) != 2 {
+		t.Errorf("expected no new sprints to be planned, got %v", sprints)
+	}
+
+	// A dummy replay is dry, and the original sprint is kept
+	if data, _ := os.ReadFile(mainPath); string(data) != "package main // real code\n" {
+		t.Errorf("expected the dummy replay not to write project files, got:\n%s", data)
+	}
+	if data, err := os.ReadFile(filepath.Join(tmpDir, result.BackupPath)); err != nil || string(data) != sprint1 {
+		t.Errorf("expected the original sprint backed up, got %q (%v)", data, err)
+	}
+}
+
+func TestReplay_RequiresEarlierSprintsComplete(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Build\n  - [ ] go-coder: Write code\n",
+		"02-next.md":    "# Sprint 2\n\n- [x] Polish\n  - [x] go-coder: Tidy up\n",
+	})
+
+	_, err := Replay(project.New(tmpDir), ReplayOptions{SprintNum: 2})
+	if err == nil || !strings.Contains(err.Error(), "sprint 1 is not complete") {
+		t.Fatalf("expected error about incomplete sprint 1, got: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".ai", "sprints", "02-next.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "- [x] Polish") {
+		t.Error("expected sprint 2 not to be reset when replay is refused")
+	}
+}
+
+func TestReplay_SprintNotFound(t *testing.T) {
+	tmpDir := setupExecutionProject(t, nil)
+	if _, err := Replay(project.New(tmpDir), ReplayOptions{SprintNum: 3}); err == nil || !strings.Contains(err.Error(), "sprint 3 not found") {
+		t.Fatalf("expected not found error, got: %v", err)
+	}
+}
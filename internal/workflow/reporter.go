@@ -0,0 +1,62 @@
+package workflow
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/strongdm/agate/internal/logging"
+)
+
+// Reporter receives the progress messages, warnings, and successes a
+// workflow step emits as it runs. NextOptions.Reporter defaults to a
+// StdoutReporter; tests inject one that records the messages.
+type Reporter interface {
+	Info(msg string)
+	Warn(msg string)
+	Success(msg string)
+}
+
+// StdoutReporter writes messages to Out (stdout if nil), warnings in yellow
+// and successes in green
+type StdoutReporter struct {
+	Out io.Writer
+}
+
+func (r StdoutReporter) out() io.Writer {
+	if r.Out == nil {
+		return os.Stdout
+	}
+	return r.Out
+}
+
+// Info writes msg as-is
+func (r StdoutReporter) Info(msg string) {
+	fmt.Fprintln(r.out(), msg)
+}
+
+// Warn writes msg in yellow
+func (r StdoutReporter) Warn(msg string) {
+	fmt.Fprintln(r.out(), logging.Yellow(msg))
+}
+
+// Success writes msg in green
+func (r StdoutReporter) Success(msg string) {
+	fmt.Fprintln(r.out(), logging.Green(msg))
+}
+
+// reporter returns the step's Reporter, defaulting to stdout
+func (o NextOptions) reporter() Reporter {
+	if o.Reporter == nil {
+		return StdoutReporter{}
+	}
+	return o.Reporter
+}
+
+// reporter returns the phase's Reporter, defaulting to stdout
+func (o PlanOptions) reporter() Reporter {
+	if o.Reporter == nil {
+		return StdoutReporter{}
+	}
+	return o.Reporter
+}
@@ -0,0 +1,82 @@
+package workflow
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// reportedMessage is one message a recordingReporter received
+type reportedMessage struct {
+	Level string
+	Text  string
+}
+
+// recordingReporter records the messages a workflow step emits
+type recordingReporter struct {
+	mu       sync.Mutex
+	messages []reportedMessage
+}
+
+func (r *recordingReporter) record(level, msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, reportedMessage{Level: level, Text: msg})
+}
+
+func (r *recordingReporter) Info(msg string)    { r.record("info", msg) }
+func (r *recordingReporter) Warn(msg string)    { r.record("warn", msg) }
+func (r *recordingReporter) Success(msg string) { r.record("success", msg) }
+
+// find returns the first message at level containing text
+func (r *recordingReporter) find(level, text string) (reportedMessage, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range r.messages {
+		if m.Level == level && strings.Contains(m.Text, text) {
+			return m, true
+		}
+	}
+	return reportedMessage{}, false
+}
+
+// TestNext_ReportsThroughReporter verifies a dummy-agent sub-task run sends
+// its progress bar, file writes, and task completion to the Reporter.
+func TestNext_ReportsThroughReporter(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Build it\n  - [ ] go-coder: Write code\n",
+	})
+	rec := &recordingReporter{}
+
+	if _, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy", Reporter: rec}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []reportedMessage{
+		{"warn", "Skills directory is missing or empty"},
+		{"info", "Sprint 1 - Build it"},
+		{"success", "Wrote 1 file(s)"},
+		{"success", "✓ Task complete: Build it"},
+	} {
+		if _, ok := rec.find(want.Level, want.Text); !ok {
+			t.Errorf("expected a %s message containing %q, got %+v", want.Level, want.Text, rec.messages)
+		}
+	}
+}
+
+func TestStdoutReporter_WritesToOut(t *testing.T) {
+	var out bytes.Buffer
+	r := StdoutReporter{Out: &out}
+	r.Info("step one")
+	r.Warn("⚠ careful")
+	r.Success("✓ done")
+
+	for _, want := range []string{"step one\n", "⚠ careful", "✓ done"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in the output, got %q", want, out.String())
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package workflow
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,56 +19,97 @@ import (
 type RetroOptions struct {
 	// UserInput is optional user feedback to include in the retrospective
 	UserInput string
+	// MaxLogs caps how many of the sprint's most recent logs are summarized;
+	// 0 means DefaultRetroMaxLogs
+	MaxLogs int
+	// FailuresOnly summarizes only invocations whose status isn't success,
+	// so the analysis focuses on what went wrong
+	FailuresOnly bool
 }
 
+// DefaultRetroMaxLogs is how many of a sprint's most recent logs the
+// retrospective summarizes when RetroOptions.MaxLogs is unset
+const DefaultRetroMaxLogs = 100
+
+// retroSummaryLen is how many bytes of each log the retrospective includes
+const retroSummaryLen = 500
+
 // RunRetrospective runs a retrospective for the completed sprint
-func RunRetrospective(projectDir string, sprintNumber int) (*Result, error) {
-	return RunRetrospectiveWithOptions(projectDir, sprintNumber, RetroOptions{})
+func RunRetrospective(proj *project.Project, sprintNumber int) (*Result, error) {
+	return RunRetrospectiveWithOptions(proj, sprintNumber, RetroOptions{})
 }
 
 // RunRetrospectiveWithOptions runs a retrospective with options
-func RunRetrospectiveWithOptions(projectDir string, sprintNumber int, opts RetroOptions) (*Result, error) {
+func RunRetrospectiveWithOptions(proj *project.Project, sprintNumber int, opts RetroOptions) (*Result, error) {
 	fmt.Printf("Running retrospective for sprint %d...\n", sprintNumber)
 
 	// Check if retro already done for this sprint (by file existence)
-	retroPath := logging.GetRetroPath(projectDir, sprintNumber)
+	retroPath := logging.GetRetroPath(proj, sprintNumber)
 	if fileExists(retroPath) {
 		return &Result{
 			Message:  fmt.Sprintf("Retrospective already completed for sprint %d", sprintNumber),
 			MoreWork: false,
+			ExitCode: ExitDone,
 		}, nil
 	}
 
-	// Read all logs from the sprint
-	logs, err := logging.ListLogs(projectDir, sprintNumber)
+	maxLogs := opts.MaxLogs
+	if maxLogs <= 0 {
+		maxLogs = DefaultRetroMaxLogs
+	}
+
+	// Summarize the newest logs, reading only the head of each
+	var logSummaries []string
+	total := 0
+	err := logging.WalkLogs(proj, sprintNumber, func(logPath string) bool {
+		if opts.FailuresOnly && !logFailed(logPath) {
+			return true
+		}
+		total++
+		if len(logSummaries) >= maxLogs {
+			return true // Keep counting so the prompt can say what was left out
+		}
+		summary, err := readLogSummary(logPath)
+		if err != nil {
+			return true
+		}
+		logSummaries = append(logSummaries, fmt.Sprintf("### %s\n%s", filepath.Base(logPath), summary))
+		return true
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list logs: %w", err)
 	}
 
-	if len(logs) == 0 {
+	if total == 0 && opts.FailuresOnly {
+		return &Result{
+			Message:  fmt.Sprintf("No failed invocations found for sprint %d, skipping retrospective", sprintNumber),
+			MoreWork: false,
+			ExitCode: ExitDone,
+		}, nil
+	}
+	if total == 0 {
 		return &Result{
 			Message:  fmt.Sprintf("No logs found for sprint %d, skipping retrospective", sprintNumber),
 			MoreWork: false,
+			ExitCode: ExitDone,
 		}, nil
 	}
 
-	// Collect log summaries
-	var logSummaries []string
-	for _, logPath := range logs {
-		content, err := os.ReadFile(logPath)
-		if err != nil {
-			continue
-		}
-		// Just use first 500 chars of each log for summary
-		summary := string(content)
-		if len(summary) > 500 {
-			summary = summary[:500] + "..."
-		}
-		logSummaries = append(logSummaries, fmt.Sprintf("### %s\n%s", filepath.Base(logPath), summary))
+	// Present the summaries oldest first
+	for i, j := 0, len(logSummaries)-1; i < j; i, j = i+1, j-1 {
+		logSummaries[i], logSummaries[j] = logSummaries[j], logSummaries[i]
+	}
+	kind := "logs"
+	if opts.FailuresOnly {
+		kind = "failed invocation logs"
+	}
+	if total > len(logSummaries) {
+		logSummaries = append([]string{fmt.Sprintf("(Showing the %d most recent of %d %s.)", len(logSummaries), total, kind)}, logSummaries...)
+	} else if opts.FailuresOnly {
+		logSummaries = append([]string{"(Showing only failed invocation logs.)"}, logSummaries...)
 	}
 
 	// Load current skills
-	proj := project.New(projectDir)
 	skills, _ := project.LoadSkills(proj.SkillsDir())
 	var skillNames []string
 	for _, s := range skills {
@@ -125,7 +168,7 @@ END_SKILL_UPDATE
 Be specific and actionable. Focus on improvements that would prevent similar issues in future sprints.
 `, sprintNumber, strings.Join(logSummaries, "\n\n"), strings.Join(skillNames, ", "), userFeedback)
 
-	result, err := agents[0].Execute(ctx, prompt, projectDir)
+	result, err := agents[0].Execute(ctx, prompt, proj.Dir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run retrospective analysis: %w", err)
 	}
@@ -144,7 +187,7 @@ Be specific and actionable. Focus on improvements that would prevent similar iss
 
 	// Format and save the retrospective
 	retroContent := logging.FormatRetro(sprintNumber, result, skillUpdates)
-	if err := logging.EnsureRetrosDir(projectDir); err != nil {
+	if err := logging.EnsureRetrosDir(proj); err != nil {
 		fmt.Printf("%s\n", logging.Yellow(fmt.Sprintf("Warning: failed to create retros directory: %v", err)))
 	}
 	if err := os.WriteFile(retroPath, []byte(retroContent), 0644); err != nil {
@@ -156,9 +199,51 @@ Be specific and actionable. Focus on improvements that would prevent similar iss
 	return &Result{
 		Message:  fmt.Sprintf("Retrospective complete for sprint %d. Updated %d skills.", sprintNumber, len(skillUpdates)),
 		MoreWork: false,
+		ExitCode: ExitDone,
 	}, nil
 }
 
+// readLogSummary reads the first retroSummaryLen bytes of a log, adding
+// "..." if there is more, without loading the rest of the file
+func readLogSummary(logPath string) (string, error) {
+	f, err := os.Open(logPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, retroSummaryLen+1)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	if n > retroSummaryLen {
+		return string(buf[:retroSummaryLen]) + "...", nil
+	}
+	return string(buf[:n]), nil
+}
+
+// logFailed reports whether an invocation log records a status other than
+// success. Only the metadata at the top of the log is read.
+func logFailed(logPath string) bool {
+	f, err := os.Open(logPath)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	var meta strings.Builder
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if scanner.Text() == "## Prompt" {
+			break
+		}
+		meta.WriteString(scanner.Text() + "\n")
+	}
+	status, ok := logging.ParseInvocationStatus(meta.String())
+	return ok && status != "success"
+}
+
 // parseSkillUpdates extracts skill updates from the retrospective response
 func parseSkillUpdates(response string) map[string]string {
 	updates := make(map[string]string)
@@ -230,9 +315,9 @@ func applySkillUpdate(skillsDir, skillName, update string) error {
 
 // RunEvolution runs skill evolution at the start of a new sprint
 // It finds the most recent retrospective by scanning the retros directory
-func RunEvolution(projectDir string) error {
+func RunEvolution(proj *project.Project) error {
 	// Find the most recent retrospective by looking at retro files
-	retrosDir := filepath.Join(projectDir, ".ai", "retros")
+	retrosDir := proj.RetrosDir()
 	if _, err := os.Stat(retrosDir); err != nil {
 		return nil // No retros dir, nothing to evolve
 	}
@@ -0,0 +1,129 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// TestRunRetrospective_SummarizesNewestLogs verifies a sprint with many logs
+// only has its newest MaxLogs summarized, oldest first.
+func TestRunRetrospective_SummarizesNewestLogs(t *testing.T) {
+	tmpDir := t.TempDir()
+	logsDir := logging.GetLogsDir(project.New(tmpDir), 1)
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for seq := 1; seq <= 1200; seq++ {
+		name := fmt.Sprintf("%03d-implement-00-go-coder-claude.md", seq)
+		if err := os.WriteFile(filepath.Join(logsDir, name), []byte(fmt.Sprintf("log %d\n", seq)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The stub agent echoes the prompt, so the saved retro shows what was summarized
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "printf '%s\\n' \"$@\"\n")
+	t.Setenv("PATH", bin)
+
+	if _, err := RunRetrospectiveWithOptions(project.New(tmpDir), 1, RetroOptions{MaxLogs: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(logging.GetRetroPath(project.New(tmpDir), 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	retro := string(content)
+
+	if got := strings.Count(retro, "-implement-00-go-coder-claude.md"); got != 5 {
+		t.Errorf("expected 5 log summaries, got %d:\n%s", got, retro)
+	}
+	for _, seq := range []int{1, 999, 1195} {
+		if strings.Contains(retro, fmt.Sprintf("### %03d-", seq)) {
+			t.Errorf("expected log %d not to be summarized", seq)
+		}
+	}
+	first := strings.Index(retro, "### 1196-")
+	last := strings.Index(retro, "### 1200-")
+	if first < 0 || last < 0 || first > last {
+		t.Errorf("expected logs 1196-1200 summarized oldest first, got:\n%s", retro)
+	}
+	if !strings.Contains(retro, "Showing the 5 most recent of 1200 logs") {
+		t.Errorf("expected the prompt to note omitted logs, got:\n%s", retro)
+	}
+}
+
+// TestRunRetrospective_FailuresOnly verifies only logs whose status isn't
+// success feed the prompt when FailuresOnly is set.
+func TestRunRetrospective_FailuresOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	logsDir := logging.GetLogsDir(project.New(tmpDir), 1)
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i, status := range []string{"success", "error", "success", "error"} {
+		name := fmt.Sprintf("%03d-implement-00-go-coder-claude.md", i+1)
+		log := logging.FormatInvocation(&logging.Invocation{
+			Timestamp: time.Now(),
+			Sprint:    1,
+			Phase:     "implement",
+			Task:      fmt.Sprintf("task %d", i+1),
+			Status:    status,
+		})
+		if err := os.WriteFile(filepath.Join(logsDir, name), []byte(log), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "printf '%s\\n' \"$@\"\n")
+	t.Setenv("PATH", bin)
+
+	if _, err := RunRetrospectiveWithOptions(project.New(tmpDir), 1, RetroOptions{FailuresOnly: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(logging.GetRetroPath(project.New(tmpDir), 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	retro := string(content)
+	for _, want := range []string{"### 002-", "### 004-", "Showing only failed invocation logs"} {
+		if !strings.Contains(retro, want) {
+			t.Errorf("expected %q in the retro prompt, got:\n%s", want, retro)
+		}
+	}
+	for _, unwanted := range []string{"### 001-", "### 003-"} {
+		if strings.Contains(retro, unwanted) {
+			t.Errorf("expected successful log %q to be left out", unwanted)
+		}
+	}
+}
+
+// TestRunRetrospective_FailuresOnlyNoFailures verifies a sprint without
+// failed invocations skips the retrospective rather than running on nothing.
+func TestRunRetrospective_FailuresOnlyNoFailures(t *testing.T) {
+	tmpDir := t.TempDir()
+	logsDir := logging.GetLogsDir(project.New(tmpDir), 1)
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	log := logging.FormatInvocation(&logging.Invocation{Timestamp: time.Now(), Phase: "implement", Status: "success"})
+	if err := os.WriteFile(filepath.Join(logsDir, "001-implement-00-go-coder-claude.md"), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := RunRetrospectiveWithOptions(project.New(tmpDir), 1, RetroOptions{FailuresOnly: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(result.Message, "No failed invocations") {
+		t.Errorf("expected a skip message, got %q", result.Message)
+	}
+	if _, err := os.Stat(logging.GetRetroPath(project.New(tmpDir), 1)); err == nil {
+		t.Error("expected no retrospective to be written")
+	}
+}
@@ -0,0 +1,109 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// ShowSprint renders a sprint as a task tree with its progress bar.
+// sprintNum 0 selects the current sprint as reported by GetStatus.
+func ShowSprint(proj *project.Project, sprintNum int) (string, error) {
+	sprintPath, sprintNum, err := resolveSprint(proj, sprintNum)
+	if err != nil {
+		return "", err
+	}
+
+	sprint, err := ParseSprint(sprintPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse sprint: %w", err)
+	}
+	return FormatSprintTree(sprint, sprintNum), nil
+}
+
+// resolveSprint returns the path and number of sprint sprintNum, or of the
+// current sprint as reported by GetStatus when sprintNum is 0
+func resolveSprint(proj *project.Project, sprintNum int) (string, int, error) {
+	projectDir := proj.Dir
+	if sprintNum == 0 {
+		status := GetStatus(os.DirFS(projectDir), proj.StateDir())
+		if status.CurrentSprintPath == "" {
+			return "", 0, fmt.Errorf("no sprint files found in %s", proj.StatePath("sprints"))
+		}
+		return filepath.Join(projectDir, status.CurrentSprintPath), status.CurrentSprintNum, nil
+	}
+	sprintPath := findSprintByNum(proj.SprintsDir(), sprintNum)
+	if sprintPath == "" {
+		return "", 0, fmt.Errorf("sprint %d not found", sprintNum)
+	}
+	return sprintPath, sprintNum, nil
+}
+
+// FormatSprintTree renders each top-level task with its ❌/🔄 counts and ⏭
+// if skipped, and each sub-task's skill and checked state, followed by the
+// progress bar
+func FormatSprintTree(sprint *SprintState, sprintNum int) string {
+	var sb strings.Builder
+
+	sb.WriteString(logging.Bold(fmt.Sprintf("Sprint %d", sprintNum)))
+	sb.WriteString(logging.Dim(" -> " + filepath.Base(sprint.FilePath)))
+	sb.WriteString("\n\n")
+
+	box := func(checked bool) string {
+		if checked {
+			return logging.Green("[x]")
+		}
+		return logging.Dim("[ ]")
+	}
+
+	for _, task := range sprint.Tasks {
+		sb.WriteString(fmt.Sprintf("%s %s", box(task.Checked), task.Text))
+		var markers []string
+		if task.FailureCount > 0 {
+			markers = append(markers, fmt.Sprintf("❌ %d failed review%s", task.FailureCount, plural(task.FailureCount)))
+		}
+		if task.ReplanCount > 0 {
+			markers = append(markers, fmt.Sprintf("🔄 %d replan%s", task.ReplanCount, plural(task.ReplanCount)))
+		}
+		if task.Skipped {
+			markers = append(markers, "⏭ skipped")
+		}
+		if len(markers) > 0 {
+			sb.WriteString("  " + logging.Yellow(strings.Join(markers, ", ")))
+		}
+		sb.WriteString("\n")
+
+		for _, sub := range task.SubTasks {
+			sb.WriteString(fmt.Sprintf("    %s %s %s\n", box(sub.Checked), logging.Cyan(sub.Skill+":"), sub.Text))
+			if sub.FailureReason != "" {
+				sb.WriteString(fmt.Sprintf("        %s\n", logging.Dim("last failure: "+sub.FailureReason)))
+			}
+		}
+	}
+
+	if sprint.DefinitionOfDone != "" {
+		state := "not yet verified"
+		if sprint.DoDVerified {
+			state = "verified"
+		}
+		sb.WriteString(fmt.Sprintf("\nDefinition of Done: %s\n", state))
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(sprint.RenderProgressBar(sprintNum, -1, -1, ProgressBarOptions{MarkFailed: true}))
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// plural returns "s" unless n is 1
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
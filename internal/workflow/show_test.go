@@ -0,0 +1,58 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestShowSprint_RendersMarkerCounts(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [x] Set up project\n  - [x] go-coder: Create go.mod\n  - [x] _reviewer: Validate setup\n",
+		"02-parser.md": "# Sprint 2\n\n- [ ] ❌❌🔄 Build parser\n" +
+			"  - [x] go-coder: Write parser\n" +
+			"  - [ ] _reviewer: Review parser <!-- fail: edge cases untested -->\n\n" +
+			"- [ ] Add CLI\n  - [ ] go-coder: Wire up CLI\n",
+	})
+
+	out, err := ShowSprint(project.New(tmpDir), 0)
+	if err != nil {
+		t.Fatalf("ShowSprint failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"Sprint 2",
+		"Build parser",
+		"❌ 2 failed reviews",
+		"🔄 1 replan",
+		"go-coder:",
+		"Write parser",
+		"_reviewer:",
+		"last failure: edge cases untested",
+		"Add CLI",
+		"33%",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "Add CLI") && strings.ContainsAny(line, "❌🔄") {
+			t.Errorf("task without markers should not show counts: %q", line)
+		}
+	}
+
+	// An explicit sprint number selects that sprint
+	out, err = ShowSprint(project.New(tmpDir), 1)
+	if err != nil {
+		t.Fatalf("ShowSprint(project.New(1)) failed: %v", err)
+	}
+	if !strings.Contains(out, "Set up project") || strings.Contains(out, "Build parser") {
+		t.Errorf("expected sprint 1 tree, got:\n%s", out)
+	}
+
+	if _, err := ShowSprint(project.New(tmpDir), 9); err == nil {
+		t.Error("expected error for missing sprint")
+	}
+}
@@ -0,0 +1,77 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// contextCommandTimeout bounds each skill context command
+const contextCommandTimeout = 10 * time.Second
+
+// maxContextOutput caps the output kept from each context command
+const maxContextOutput = 4000
+
+// withCommandContext returns a copy of skill whose content ends with the
+// output of its context_commands, or skill itself if it has none. Commands
+// must pass project.IsAllowedContextCommand; they run in projectDir without
+// a shell or stdin, each bounded by contextCommandTimeout. A failing command
+// is reported in the content rather than failing the sub-task.
+func withCommandContext(ctx context.Context, projectDir string, skill *project.Skill) *project.Skill {
+	if skill == nil || len(skill.Metadata.ContextCommands) == 0 {
+		return skill
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n\n## Live Context\n")
+	for _, command := range skill.Metadata.ContextCommands {
+		sb.WriteString(fmt.Sprintf("\n$ %s\n", command))
+		if !project.IsAllowedContextCommand(command) {
+			sb.WriteString("(not run: command is not in the context command allowlist)\n")
+			continue
+		}
+		output, err := runContextCommand(ctx, projectDir, command)
+		sb.WriteString("```\n")
+		sb.WriteString(output)
+		if output != "" && !strings.HasSuffix(output, "\n") {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("```\n")
+		if err != nil {
+			sb.WriteString(fmt.Sprintf("(command failed: %v)\n", err))
+		}
+	}
+
+	withContext := *skill
+	withContext.Content = strings.TrimRight(skill.Content, "\n") + sb.String()
+	return &withContext
+}
+
+// runContextCommand runs an allowlisted command and returns its combined
+// output, truncated to maxContextOutput
+func runContextCommand(ctx context.Context, projectDir, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, contextCommandTimeout)
+	defer cancel()
+
+	args := strings.Fields(command)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = projectDir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", contextCommandTimeout)
+	}
+
+	output := out.String()
+	if len(output) > maxContextOutput {
+		output = output[:maxContextOutput] + "\n... (truncated)"
+	}
+	return output, err
+}
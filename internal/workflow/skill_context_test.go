@@ -0,0 +1,110 @@
+package workflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestWithCommandContext(t *testing.T) {
+	bin := t.TempDir()
+	writeStubScript(t, bin, "go", "echo \"stub go $*\"\n")
+	writeStubScript(t, bin, "rm", "echo removed > "+filepath.Join(bin, "rm-ran")+"\n")
+	writeStubScript(t, bin, "git", "echo 'not a repo' >&2\nexit 128\n")
+	t.Setenv("PATH", bin)
+
+	skill := &project.Skill{
+		Name:    "go-reviewer",
+		Content: "# Go Reviewer\n",
+		Metadata: project.SkillMetadata{
+			ContextCommands: []string{"go version", "rm -rf .", "git status"},
+		},
+	}
+	got := withCommandContext(context.Background(), t.TempDir(), skill)
+
+	for _, want := range []string{
+		"## Live Context",
+		"$ go version\n```\nstub go version\n```",
+		"$ rm -rf .\n(not run: command is not in the context command allowlist)",
+		"$ git status\n```\nnot a repo\n```\n(command failed: exit status 128)",
+	} {
+		if !strings.Contains(got.Content, want) {
+			t.Errorf("expected %q in content:\n%s", want, got.Content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(bin, "rm-ran")); err == nil {
+		t.Error("disallowed command should not run")
+	}
+	if skill.Content != "# Go Reviewer\n" {
+		t.Error("original skill should be unchanged")
+	}
+
+	plain := &project.Skill{Name: "go-coder", Content: "# Go Coder\n"}
+	if withCommandContext(context.Background(), t.TempDir(), plain) != plain {
+		t.Error("skill without context commands should be returned as is")
+	}
+}
+
+// TestExecuteSubTask_SkillContextInPrompt verifies context command output
+// is part of the prompt sent to the agent
+func TestExecuteSubTask_SkillContextInPrompt(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] First task\n  - [ ] go-reviewer: Review code\n",
+	})
+	skill := "---\nname: go-reviewer\nagents: [claude]\nphase: review\ncontext_commands: [go version]\n---\n\n# Go Reviewer\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".ai", "skills", "go-reviewer.md"), []byte(skill), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bin := t.TempDir()
+	promptFile := filepath.Join(bin, "prompt.txt")
+	writeStubScript(t, bin, "go", "echo go version go1.99 stub/amd64\n")
+	writeStubScript(t, bin, "claude", "printf '%s\\n' \"$@\" > "+promptFile+"\necho APPROVED\n")
+	t.Setenv("PATH", bin)
+
+	proj := project.New(tmpDir)
+	sprint, err := ParseSprint(filepath.Join(proj.SprintsDir(), "01-initial.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := &sprint.Tasks[0]
+	logger := logging.NewLogger(project.New(tmpDir), 1)
+	if _, err := executeSubTask(tmpDir, proj, sprint, task, &task.SubTasks[0], logger, NextOptions{PreferredAgent: "claude"}, false); err != nil {
+		t.Fatalf("executeSubTask failed: %v", err)
+	}
+
+	prompt, err := os.ReadFile(promptFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(prompt), "$ go version\n```\ngo version go1.99 stub/amd64\n```") {
+		t.Errorf("expected go version output in prompt:\n%s", prompt)
+	}
+}
+
+// TestWithCommandContext_RejectsUnsafeFlags verifies allowlisted commands
+// given a flag that writes files or runs a program are not run.
+func TestWithCommandContext_RejectsUnsafeFlags(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "written")
+	skill := &project.Skill{
+		Name:    "go-coder",
+		Content: "# Go Coder",
+		Metadata: project.SkillMetadata{ContextCommands: []string{
+			"git diff --output=" + out,
+			"go env -w GOFLAGS=-mod=mod",
+		}},
+	}
+	got := withCommandContext(context.Background(), dir, skill)
+	if n := strings.Count(got.Content, "(not run: command is not in the context command allowlist)"); n != 2 {
+		t.Errorf("expected both commands refused, got:\n%s", got.Content)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("expected git diff --output not to run, stat err: %v", err)
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
-	"path/filepath"
+	"path"
 	"regexp"
 	"sort"
 	"strings"
@@ -18,8 +18,9 @@ type Task struct {
 	Text         string
 	Checked      bool
 	LineNum      int
-	FailureCount int // Number of ❌ emojis before the task text
-	ReplanCount  int // Number of 🔄 emojis before the task text
+	FailureCount int  // Number of ❌ emojis before the task text
+	ReplanCount  int  // Number of 🔄 emojis before the task text
+	Skipped      bool // A ⏭ before the task text: skipped by --keep-going
 	SubTasks     []SubTask
 }
 
@@ -31,6 +32,11 @@ type SubTask struct {
 	Checked     bool
 	LineNum     int
 	ParentIndex int
+	// FailureReason is the reviewer's reason from a <!-- fail: ... --> annotation
+	FailureReason string
+	// VerifyOnly is set by a <!-- verify-only --> marker: the sub-task
+	// checks the work (e.g. runs the tests) and is not expected to change files
+	VerifyOnly bool
 }
 
 // SprintState represents the parsed state of a sprint file
@@ -38,6 +44,37 @@ type SprintState struct {
 	FilePath string
 	Tasks    []Task
 	Content  string
+	Format   SprintFormat
+	// DefinitionOfDone is the body of the "## Definition of Done" section
+	DefinitionOfDone string
+	// DoDVerified is true once a reviewer has confirmed the Definition of Done
+	DoDVerified bool
+	// DoDReopens counts the times a failed Definition of Done review has
+	// re-opened tasks
+	DoDReopens int
+	// DefaultAgent is the sprint's default_agent frontmatter: the agent its
+	// sub-tasks prefer when --agent isn't given
+	DefaultAgent string
+	// Reporter receives warnings from sprint updates, such as a failed
+	// journal write (stdout if nil)
+	Reporter Reporter
+}
+
+func (s *SprintState) reporter() Reporter {
+	if s.Reporter == nil {
+		return StdoutReporter{}
+	}
+	return s.Reporter
+}
+
+// reparse re-reads the sprint from its file, keeping its Reporter
+func (s *SprintState) reparse() (*SprintState, error) {
+	fresh, err := ParseSprint(s.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	fresh.Reporter = s.Reporter
+	return fresh, nil
 }
 
 // ParseSprint parses a sprint file with nested checkboxes
@@ -68,32 +105,51 @@ func ParseSprintFS(fsys fs.FS, path string) (*SprintState, error) {
 	return state, nil
 }
 
+// Nested checkbox lines: a top-level task captures its checkbox, marker
+// emojis, and text; a sub-task captures its checkbox, skill, and text
+var (
+	topLevelTaskRe = regexp.MustCompile(`^- \[([ xX])\] ((?:❌|🔄|⏭)*)\s*(.*)$`)
+	subTaskLineRe  = regexp.MustCompile(`^  - \[([ xX])\] ([^:]+): (.*)$`)
+)
+
 // ParseSprintContent parses sprint markdown content (pure, no filesystem access)
 func ParseSprintContent(content string) (*SprintState, error) {
 	state := &SprintState{
 		Content: content,
+		Format:  SprintFormatCheckbox,
+	}
+	parseSprintFrontmatter(state)
+	parseDefinitionOfDone(state)
+
+	// Table-format sprints map rows onto the same Task/SubTask model
+	if isTableSprint(content) {
+		parseSprintTable(state)
+		return state, nil
 	}
 
 	// Parse nested checkboxes
-	// Top-level: ^- \[([ xX])\] ((?:❌|🔄)*)\s*(.*)$ - captures checkbox, marker emojis, text
-	// Sub-task:  ^  - \[([ xX])\] ([^:]+): (.*)$
-	topLevelRe := regexp.MustCompile(`^- \[([ xX])\] ((?:❌|🔄)*)\s*(.*)$`)
-	subTaskRe := regexp.MustCompile(`^  - \[([ xX])\] ([^:]+): (.*)$`)
 
 	lines := strings.Split(content, "\n")
 	var currentTask *Task
 	taskIndex := 0
 
+	// Definition of Done criteria are a sprint gate, not tasks
+	dodStart, dodEnd, hasDoD := definitionOfDoneRange(lines)
+
 	for lineNum, line := range lines {
+		if hasDoD && lineNum >= dodStart && lineNum < dodEnd {
+			continue
+		}
+
 		// Check for top-level task
-		if matches := topLevelRe.FindStringSubmatch(line); matches != nil {
+		if matches := topLevelTaskRe.FindStringSubmatch(line); matches != nil {
 			// Save previous task if exists
 			if currentTask != nil {
 				state.Tasks = append(state.Tasks, *currentTask)
 			}
 
 			checked := strings.ToLower(matches[1]) == "x"
-			// Count ❌ and 🔄 emojis from the marker string
+			// Count ❌ and 🔄 emojis, and look for ⏭, in the marker string
 			markerStr := matches[2]
 			failureCount := strings.Count(markerStr, "❌")
 			replanCount := strings.Count(markerStr, "🔄")
@@ -104,6 +160,7 @@ func ParseSprintContent(content string) (*SprintState, error) {
 				LineNum:      lineNum + 1,
 				FailureCount: failureCount,
 				ReplanCount:  replanCount,
+				Skipped:      strings.Contains(markerStr, "⏭"),
 				SubTasks:     []SubTask{},
 			}
 			taskIndex++
@@ -112,15 +169,19 @@ func ParseSprintContent(content string) (*SprintState, error) {
 
 		// Check for sub-task (must have current task)
 		if currentTask != nil {
-			if matches := subTaskRe.FindStringSubmatch(line); matches != nil {
+			if matches := subTaskLineRe.FindStringSubmatch(line); matches != nil {
 				checked := strings.ToLower(matches[1]) == "x"
+				text, reason := splitFailureAnnotation(matches[3])
+				text, verifyOnly := splitVerifyOnly(text)
 				subTask := SubTask{
-					Index:       len(currentTask.SubTasks),
-					Skill:       strings.TrimSpace(matches[2]),
-					Text:        strings.TrimSpace(matches[3]),
-					Checked:     checked,
-					LineNum:     lineNum + 1,
-					ParentIndex: currentTask.Index,
+					Index:         len(currentTask.SubTasks),
+					Skill:         strings.TrimSpace(matches[2]),
+					Text:          text,
+					Checked:       checked,
+					LineNum:       lineNum + 1,
+					ParentIndex:   currentTask.Index,
+					FailureReason: reason,
+					VerifyOnly:    verifyOnly,
 				}
 				currentTask.SubTasks = append(currentTask.SubTasks, subTask)
 			}
@@ -135,10 +196,48 @@ func ParseSprintContent(content string) (*SprintState, error) {
 	return state, nil
 }
 
+// parseSprintFrontmatter reads the optional frontmatter block at the top of
+// a sprint file:
+//
+//	---
+//	default_agent: codex
+//	---
+func parseSprintFrontmatter(state *SprintState) {
+	if !strings.HasPrefix(state.Content, "---\n") {
+		return
+	}
+	endIdx := strings.Index(state.Content[4:], "\n---")
+	if endIdx == -1 {
+		return
+	}
+	for _, line := range strings.Split(state.Content[4:endIdx+4], "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "default_agent":
+			state.DefaultAgent = strings.TrimSpace(value)
+		}
+	}
+}
+
+// preferredAgent returns the agent the sprint's sub-tasks should prefer:
+// preferred (from --agent) if set, otherwise the sprint's default_agent.
+// With --agent auto the default_agent is tried first.
+func (s *SprintState) preferredAgent(preferred string) string {
+	if (preferred == "" || preferred == AutoAgent) && s.DefaultAgent != "" {
+		return s.DefaultAgent
+	}
+	return preferred
+}
+
 // FindCurrentSprintFS finds the current (first incomplete) sprint from an fs.FS
+// whose state lives in stateDir.
 // Returns the relative path and sprint number, or empty string and 0 if none found
-func FindCurrentSprintFS(fsys fs.FS) (string, int) {
-	entries, err := fs.ReadDir(fsys, filepath.Join(".ai", "sprints"))
+func FindCurrentSprintFS(fsys fs.FS, stateDir string) (string, int) {
+	sprintsDir := path.Join(stateDir, "sprints")
+	entries, err := fs.ReadDir(fsys, sprintsDir)
 	if err != nil {
 		return "", 0
 	}
@@ -151,13 +250,13 @@ func FindCurrentSprintFS(fsys fs.FS) (string, int) {
 		}
 	}
 
-	// Sort to get them in order
-	sort.Strings(sprintFiles)
+	// Sort by number, so sprint 100 comes after sprint 99
+	sortSprintFiles(sprintFiles)
 
 	// Find first incomplete sprint
 	for _, name := range sprintFiles {
-		path := ".ai/sprints/" + name
-		sprint, err := ParseSprintFS(fsys, path)
+		sprintPath := path.Join(sprintsDir, name)
+		sprint, err := ParseSprintFS(fsys, sprintPath)
 		if err != nil {
 			continue
 		}
@@ -166,24 +265,26 @@ func FindCurrentSprintFS(fsys fs.FS) (string, int) {
 		num := ExtractSprintNum(name)
 
 		if !sprint.IsComplete() {
-			return path, num
+			return sprintPath, num
 		}
 	}
 
 	// All complete or no sprints - return last one if exists
 	if len(sprintFiles) > 0 {
 		name := sprintFiles[len(sprintFiles)-1]
-		return ".ai/sprints/" + name, ExtractSprintNum(name)
+		return path.Join(sprintsDir, name), ExtractSprintNum(name)
 	}
 
 	return "", 0
 }
 
-// ExtractSprintNum extracts the sprint number from a filename like "01-initial.md"
+// sprintNumRe matches the leading digits of a sprint filename
+var sprintNumRe = regexp.MustCompile(`^(\d+)`)
+
+// ExtractSprintNum extracts the sprint number from a filename like
+// "01-initial.md"; padding is ignored, so "1-initial.md" is sprint 1 too
 func ExtractSprintNum(filename string) int {
-	// Try to extract leading digits
-	re := regexp.MustCompile(`^(\d+)`)
-	if matches := re.FindStringSubmatch(filename); matches != nil {
+	if matches := sprintNumRe.FindStringSubmatch(filename); matches != nil {
 		var num int
 		fmt.Sscanf(matches[1], "%d", &num)
 		return num
@@ -192,18 +293,32 @@ func ExtractSprintNum(filename string) int {
 }
 
 // FormatSprintFilename formats a sprint filename given a number and name.
-// Example: FormatSprintFilename(1, "initial") returns "01-initial.md"
+// The number is zero-padded to two digits and widens as needed.
+// Example: FormatSprintFilename(1, "initial") returns "01-initial.md";
+// FormatSprintFilename(100, "next") returns "100-next.md"
 func FormatSprintFilename(num int, name string) string {
 	return fmt.Sprintf("%02d-%s.md", num, name)
 }
 
+// sortSprintFiles sorts sprint file names by sprint number, then name.
+// Plain string order would put "100-x.md" before "99-x.md".
+func sortSprintFiles(names []string) {
+	sort.SliceStable(names, func(i, j int) bool {
+		ni, nj := ExtractSprintNum(names[i]), ExtractSprintNum(names[j])
+		if ni != nj {
+			return ni < nj
+		}
+		return names[i] < names[j]
+	})
+}
+
 // GetNextSubTask returns the next unchecked sub-task to work on
-// Returns nil if all tasks are complete
+// Returns nil if all tasks are complete or skipped
 func (s *SprintState) GetNextSubTask() *SubTask {
 	for i := range s.Tasks {
 		task := &s.Tasks[i]
-		// Skip completed top-level tasks
-		if task.Checked {
+		// Skip completed and skipped top-level tasks
+		if task.Checked || task.Skipped {
 			continue
 		}
 
@@ -218,20 +333,39 @@ func (s *SprintState) GetNextSubTask() *SubTask {
 	return nil
 }
 
-// GetCurrentTask returns the current top-level task being worked on
+// GetNextSubTaskForTask returns the first unchecked sub-task of the task at
+// taskIndex (0-based), for working on tasks out of order
+func (s *SprintState) GetNextSubTaskForTask(taskIndex int) (*SubTask, error) {
+	if taskIndex < 0 || taskIndex >= len(s.Tasks) {
+		return nil, fmt.Errorf("invalid task number %d (sprint has %d tasks)", taskIndex+1, len(s.Tasks))
+	}
+	task := &s.Tasks[taskIndex]
+	if task.Checked {
+		return nil, fmt.Errorf("task %d is already complete: %s", taskIndex+1, task.Text)
+	}
+	for j := range task.SubTasks {
+		if !task.SubTasks[j].Checked {
+			return &task.SubTasks[j], nil
+		}
+	}
+	return nil, fmt.Errorf("task %d has no unchecked sub-tasks: %s", taskIndex+1, task.Text)
+}
+
+// GetCurrentTask returns the current top-level task being worked on: the
+// first that is neither complete nor skipped
 func (s *SprintState) GetCurrentTask() *Task {
 	for i := range s.Tasks {
-		if !s.Tasks[i].Checked {
+		if !s.Tasks[i].Checked && !s.Tasks[i].Skipped {
 			return &s.Tasks[i]
 		}
 	}
 	return nil
 }
 
-// IsComplete returns true if all tasks and sub-tasks are checked
+// IsComplete returns true if all tasks are checked or skipped
 func (s *SprintState) IsComplete() bool {
 	for _, task := range s.Tasks {
-		if !task.Checked {
+		if !task.Checked && !task.Skipped {
 			return false
 		}
 	}
@@ -276,7 +410,16 @@ func (s *SprintState) CheckTask(taskIndex int) error {
 }
 
 // checkLineAt changes [ ] to [x] at a specific line number
+// (or marks the Status cell done for table sprints)
 func (s *SprintState) checkLineAt(lineNum int) error {
+	if s.Format == SprintFormatTable {
+		before := lineAt(s.Content, lineNum)
+		if err := s.setTableStatusAt(lineNum, true); err != nil {
+			return err
+		}
+		s.recordMutation(OpCheck, lineNum, before)
+		return nil
+	}
 	lines := strings.Split(s.Content, "\n")
 	if lineNum < 1 || lineNum > len(lines) {
 		return fmt.Errorf("invalid line number: %d", lineNum)
@@ -293,7 +436,11 @@ func (s *SprintState) checkLineAt(lineNum int) error {
 	s.Content = strings.Join(lines, "\n")
 
 	// Write back to file
-	return os.WriteFile(s.FilePath, []byte(s.Content), 0644)
+	if err := os.WriteFile(s.FilePath, []byte(s.Content), 0644); err != nil {
+		return err
+	}
+	s.recordMutation(OpCheck, lineNum, line)
+	return nil
 }
 
 // UncheckSubTask marks a sub-task as incomplete in the file (for review failures)
@@ -311,7 +458,16 @@ func (s *SprintState) UncheckSubTask(taskIndex, subTaskIndex int) error {
 }
 
 // uncheckLineAt changes [x] to [ ] at a specific line number
+// (or marks the Status cell todo for table sprints)
 func (s *SprintState) uncheckLineAt(lineNum int) error {
+	if s.Format == SprintFormatTable {
+		before := lineAt(s.Content, lineNum)
+		if err := s.setTableStatusAt(lineNum, false); err != nil {
+			return err
+		}
+		s.recordMutation(OpUncheck, lineNum, before)
+		return nil
+	}
 	lines := strings.Split(s.Content, "\n")
 	if lineNum < 1 || lineNum > len(lines) {
 		return fmt.Errorf("invalid line number: %d", lineNum)
@@ -331,7 +487,11 @@ func (s *SprintState) uncheckLineAt(lineNum int) error {
 	s.Content = strings.Join(lines, "\n")
 
 	// Write back to file
-	return os.WriteFile(s.FilePath, []byte(s.Content), 0644)
+	if err := os.WriteFile(s.FilePath, []byte(s.Content), 0644); err != nil {
+		return err
+	}
+	s.recordMutation(OpUncheck, lineNum, line)
+	return nil
 }
 
 // GetProgress returns completed/total counts
@@ -362,13 +522,7 @@ func (s *SprintState) GetSubTaskProgress() (completed, total int) {
 
 // TruncateText truncates text to maxLen characters with "..."
 func TruncateText(text string, maxLen int) string {
-	if len(text) <= maxLen {
-		return text
-	}
-	if maxLen <= 3 {
-		return "..."
-	}
-	return text[:maxLen-3] + "..."
+	return logging.TruncateRunes(text, maxLen)
 }
 
 // AddFailure increments the failure count (❌) on a top-level task
@@ -378,6 +532,16 @@ func (s *SprintState) AddFailure(taskIndex int) error {
 	}
 	task := &s.Tasks[taskIndex]
 
+	if s.Format == SprintFormatTable {
+		before := lineAt(s.Content, task.LineNum)
+		if err := s.setTableTaskMarkers(task, func(m string) string { return m + "❌" }); err != nil {
+			return err
+		}
+		task.FailureCount++
+		s.recordMutation(OpFailure, task.LineNum, before)
+		return nil
+	}
+
 	lines := strings.Split(s.Content, "\n")
 	if task.LineNum < 1 || task.LineNum > len(lines) {
 		return fmt.Errorf("invalid line number: %d", task.LineNum)
@@ -387,7 +551,7 @@ func (s *SprintState) AddFailure(taskIndex int) error {
 
 	// Pattern: - [x] ❌🔄❌ Task text OR - [ ] Task text
 	// We need to insert one ❌ after existing markers, before the task text
-	re := regexp.MustCompile(`^(- \[[ xX]\]) ((?:❌|🔄)*)(.*)$`)
+	re := regexp.MustCompile(`^(- \[[ xX]\]) ((?:❌|🔄|⏭)*)(.*)$`)
 	if matches := re.FindStringSubmatch(line); matches != nil {
 		// matches[1] = "- [x]" or "- [ ]"
 		// matches[2] = existing markers (may be empty)
@@ -402,7 +566,11 @@ func (s *SprintState) AddFailure(taskIndex int) error {
 	task.FailureCount++
 
 	// Write back to file
-	return os.WriteFile(s.FilePath, []byte(s.Content), 0644)
+	if err := os.WriteFile(s.FilePath, []byte(s.Content), 0644); err != nil {
+		return err
+	}
+	s.recordMutation(OpFailure, task.LineNum, line)
+	return nil
 }
 
 // AddReplanMarker adds a 🔄 marker to a top-level task
@@ -412,6 +580,14 @@ func (s *SprintState) AddReplanMarker(taskIndex int) error {
 	}
 	task := &s.Tasks[taskIndex]
 
+	if s.Format == SprintFormatTable {
+		if err := s.setTableTaskMarkers(task, func(m string) string { return m + "🔄" }); err != nil {
+			return err
+		}
+		task.ReplanCount++
+		return nil
+	}
+
 	lines := strings.Split(s.Content, "\n")
 	if task.LineNum < 1 || task.LineNum > len(lines) {
 		return fmt.Errorf("invalid line number: %d", task.LineNum)
@@ -419,7 +595,7 @@ func (s *SprintState) AddReplanMarker(taskIndex int) error {
 
 	line := lines[task.LineNum-1]
 
-	re := regexp.MustCompile(`^(- \[[ xX]\]) ((?:❌|🔄)*)(.*)$`)
+	re := regexp.MustCompile(`^(- \[[ xX]\]) ((?:❌|🔄|⏭)*)(.*)$`)
 	if matches := re.FindStringSubmatch(line); matches != nil {
 		newLine := matches[1] + " " + matches[2] + "🔄" + matches[3]
 		lines[task.LineNum-1] = newLine
@@ -433,6 +609,154 @@ func (s *SprintState) AddReplanMarker(taskIndex int) error {
 	return os.WriteFile(s.FilePath, []byte(s.Content), 0644)
 }
 
+// AddSkipMarker adds a ⏭ marker to a top-level task, so the sprint moves
+// on without it. A task that is already skipped is left as is.
+func (s *SprintState) AddSkipMarker(taskIndex int) error {
+	if taskIndex < 0 || taskIndex >= len(s.Tasks) {
+		return fmt.Errorf("invalid task index: %d", taskIndex)
+	}
+	task := &s.Tasks[taskIndex]
+	if task.Skipped {
+		return nil
+	}
+
+	if s.Format == SprintFormatTable {
+		if err := s.setTableTaskMarkers(task, func(m string) string { return m + "⏭" }); err != nil {
+			return err
+		}
+		task.Skipped = true
+		return nil
+	}
+
+	lines := strings.Split(s.Content, "\n")
+	if task.LineNum < 1 || task.LineNum > len(lines) {
+		return fmt.Errorf("invalid line number: %d", task.LineNum)
+	}
+
+	line := lines[task.LineNum-1]
+
+	re := regexp.MustCompile(`^(- \[[ xX]\]) ((?:❌|🔄|⏭)*)(.*)$`)
+	if matches := re.FindStringSubmatch(line); matches != nil {
+		lines[task.LineNum-1] = matches[1] + " " + matches[2] + "⏭" + matches[3]
+	} else {
+		return fmt.Errorf("could not parse task line: %s", line)
+	}
+
+	s.Content = strings.Join(lines, "\n")
+	task.Skipped = true
+
+	return os.WriteFile(s.FilePath, []byte(s.Content), 0644)
+}
+
+// SkippedTasks returns the tasks marked ⏭
+func (s *SprintState) SkippedTasks() []Task {
+	var skipped []Task
+	for _, task := range s.Tasks {
+		if task.Skipped {
+			skipped = append(skipped, task)
+		}
+	}
+	return skipped
+}
+
+// MarkerCounts totals the task markers across a sprint
+type MarkerCounts struct {
+	Failures int // ❌ failed reviews
+	Replans  int // 🔄 replans
+	Skipped  int // ⏭ tasks skipped by --keep-going
+}
+
+// CountMarkers totals the ❌, 🔄 and ⏭ markers across the sprint's tasks
+func (s *SprintState) CountMarkers() MarkerCounts {
+	var counts MarkerCounts
+	for _, task := range s.Tasks {
+		counts.Failures += task.FailureCount
+		counts.Replans += task.ReplanCount
+		if task.Skipped {
+			counts.Skipped++
+		}
+	}
+	return counts
+}
+
+// MoveTask moves top-level task from, with its sub-tasks and any lines up to
+// the next task, to position to, shifting the tasks in between, and writes
+// the file. Indexes are 0-based. The lines themselves are moved unchanged,
+// and the spacing between tasks stays where it was.
+func (s *SprintState) MoveTask(from, to int) error {
+	if s.Format == SprintFormatTable {
+		return fmt.Errorf("moving tasks is not supported in table sprints")
+	}
+	n := len(s.Tasks)
+	if from < 0 || from >= n {
+		return fmt.Errorf("invalid task index: %d", from)
+	}
+	if to < 0 || to >= n {
+		return fmt.Errorf("invalid task index: %d", to)
+	}
+	if from == to {
+		return nil
+	}
+
+	lines := strings.Split(s.Content, "\n")
+	start, end := s.Tasks[0].LineNum-1, lastTaskEnd(lines, s.Tasks[n-1])
+	if dodStart, _, ok := definitionOfDoneRange(lines); ok && dodStart > start && dodStart < end {
+		return fmt.Errorf("cannot move tasks: the Definition of Done section sits between tasks")
+	}
+
+	// Split each task's lines into its content and the blank lines after it
+	blocks := make([][]string, n)
+	gaps := make([][]string, n)
+	for i := range s.Tasks {
+		blockEnd := end
+		if i+1 < n {
+			blockEnd = s.Tasks[i+1].LineNum - 1
+		}
+		block := lines[s.Tasks[i].LineNum-1 : blockEnd]
+		contentEnd := len(block)
+		for contentEnd > 1 && strings.TrimSpace(block[contentEnd-1]) == "" {
+			contentEnd--
+		}
+		blocks[i], gaps[i] = block[:contentEnd], block[contentEnd:]
+	}
+
+	moved := blocks[from]
+	blocks = append(blocks[:from], blocks[from+1:]...)
+	blocks = append(blocks[:to], append([][]string{moved}, blocks[to:]...)...)
+
+	region := make([]string, 0, end-start)
+	for i, block := range blocks {
+		region = append(region, block...)
+		region = append(region, gaps[i]...)
+	}
+	newLines := append(append(append([]string{}, lines[:start]...), region...), lines[end:]...)
+	content := strings.Join(newLines, "\n")
+
+	parsed, err := ParseSprintContent(content)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.FilePath, []byte(content), 0644); err != nil {
+		return err
+	}
+	s.Content = content
+	s.Tasks = parsed.Tasks
+	return nil
+}
+
+// lastTaskEnd returns the line index just past the last task: after its
+// last sub-task and any indented lines that follow it
+func lastTaskEnd(lines []string, task Task) int {
+	end := task.LineNum
+	if len(task.SubTasks) > 0 {
+		end = task.SubTasks[len(task.SubTasks)-1].LineNum
+	}
+	for end < len(lines) && strings.TrimSpace(lines[end]) != "" && (strings.HasPrefix(lines[end], " ") || strings.HasPrefix(lines[end], "\t")) {
+		end++
+	}
+	return end
+}
+
 // ClearFailures removes all ❌ markers from a top-level task (keeps 🔄)
 func (s *SprintState) ClearFailures(taskIndex int) error {
 	if taskIndex < 0 || taskIndex >= len(s.Tasks) {
@@ -440,6 +764,14 @@ func (s *SprintState) ClearFailures(taskIndex int) error {
 	}
 	task := &s.Tasks[taskIndex]
 
+	if s.Format == SprintFormatTable {
+		if err := s.setTableTaskMarkers(task, func(m string) string { return strings.ReplaceAll(m, "❌", "") }); err != nil {
+			return err
+		}
+		task.FailureCount = 0
+		return nil
+	}
+
 	lines := strings.Split(s.Content, "\n")
 	if task.LineNum < 1 || task.LineNum > len(lines) {
 		return fmt.Errorf("invalid line number: %d", task.LineNum)
@@ -447,7 +779,7 @@ func (s *SprintState) ClearFailures(taskIndex int) error {
 
 	line := lines[task.LineNum-1]
 
-	re := regexp.MustCompile(`^(- \[[ xX]\]) ((?:❌|🔄)*)\s*(.*)$`)
+	re := regexp.MustCompile(`^(- \[[ xX]\]) ((?:❌|🔄|⏭)*)\s*(.*)$`)
 	if matches := re.FindStringSubmatch(line); matches != nil {
 		// Remove ❌ but keep 🔄
 		markers := strings.ReplaceAll(matches[2], "❌", "")
@@ -468,6 +800,186 @@ func (s *SprintState) ClearFailures(taskIndex int) error {
 	return os.WriteFile(s.FilePath, []byte(s.Content), 0644)
 }
 
+// failureAnnotationRe matches a trailing <!-- fail: reason --> annotation
+var failureAnnotationRe = regexp.MustCompile(`\s*<!--\s*fail:\s*(.*?)\s*-->\s*$`)
+
+// verifyOnlyMarker marks a sub-task that is not expected to change files
+const verifyOnlyMarker = "<!-- verify-only -->"
+
+// verifyOnlyRule tells planners when a sub-task needs the verify-only
+// marker, since a coder sub-task that changes no files otherwise fails
+const verifyOnlyRule = "A coder sub-task that changes no project files fails and is retried. If a sub-task only checks existing work (e.g. running the tests), end its line with " + verifyOnlyMarker
+
+// splitVerifyOnly removes a verify-only marker from sub-task text and
+// reports whether it was there
+func splitVerifyOnly(text string) (string, bool) {
+	if !strings.Contains(text, verifyOnlyMarker) {
+		return text, false
+	}
+	return strings.TrimSpace(strings.Replace(text, verifyOnlyMarker, "", 1)), true
+}
+
+// cellText returns the sub-task text as written in a table cell, with its
+// verify-only marker but without a failure annotation
+func (st *SubTask) cellText() string {
+	if st.VerifyOnly {
+		return st.Text + " " + verifyOnlyMarker
+	}
+	return st.Text
+}
+
+// splitFailureAnnotation separates a trailing failure annotation from
+// sub-task text, returning the trimmed text and the reason (if any)
+func splitFailureAnnotation(text string) (string, string) {
+	if m := failureAnnotationRe.FindStringSubmatchIndex(text); m != nil {
+		return strings.TrimSpace(text[:m[0]]), text[m[2]:m[3]]
+	}
+	return strings.TrimSpace(text), ""
+}
+
+// sanitizeFailureReason flattens a reason onto one line and makes it safe to
+// embed in an HTML comment
+func sanitizeFailureReason(reason string) string {
+	reason = strings.Join(strings.Fields(reason), " ")
+	reason = strings.ReplaceAll(reason, "-->", "->")
+	reason = strings.ReplaceAll(reason, "|", "/") // Keep table rows intact
+	return TruncateText(reason, 200)
+}
+
+// AnnotateFailure records a review failure reason on a sub-task line as a
+// trailing <!-- fail: reason --> comment, replacing any earlier annotation
+func (s *SprintState) AnnotateFailure(taskIndex, subTaskIndex int, reason string) error {
+	if taskIndex < 0 || taskIndex >= len(s.Tasks) {
+		return fmt.Errorf("invalid task index: %d", taskIndex)
+	}
+	task := &s.Tasks[taskIndex]
+	if subTaskIndex < 0 || subTaskIndex >= len(task.SubTasks) {
+		return fmt.Errorf("invalid sub-task index: %d", subTaskIndex)
+	}
+	subTask := &task.SubTasks[subTaskIndex]
+
+	reason = sanitizeFailureReason(reason)
+	if reason == "" {
+		return nil
+	}
+	annotation := "<!-- fail: " + reason + " -->"
+
+	if s.Format == SprintFormatTable {
+		if err := s.setTableTaskCell(subTask.LineNum, subTask.cellText()+" "+annotation); err != nil {
+			return err
+		}
+		subTask.FailureReason = reason
+		return nil
+	}
+
+	lines := strings.Split(s.Content, "\n")
+	if subTask.LineNum < 1 || subTask.LineNum > len(lines) {
+		return fmt.Errorf("invalid line number: %d", subTask.LineNum)
+	}
+	line := failureAnnotationRe.ReplaceAllString(lines[subTask.LineNum-1], "")
+	lines[subTask.LineNum-1] = strings.TrimRight(line, " ") + " " + annotation
+	s.Content = strings.Join(lines, "\n")
+	subTask.FailureReason = reason
+
+	return os.WriteFile(s.FilePath, []byte(s.Content), 0644)
+}
+
+// ResetAll returns the sprint to its unstarted state: every task and
+// sub-task unchecked, ❌/🔄/⏭ markers and failure annotations removed, and
+// the Definition of Done verification and re-open count cleared
+func (s *SprintState) ResetAll() error {
+	for i := range s.Tasks {
+		task := &s.Tasks[i]
+		if err := s.uncheckLineAt(task.LineNum); err != nil {
+			return err
+		}
+		if task.FailureCount > 0 || task.ReplanCount > 0 || task.Skipped {
+			if err := s.clearTaskMarkers(task); err != nil {
+				return err
+			}
+		}
+		task.Checked = false
+		task.FailureCount = 0
+		task.ReplanCount = 0
+		task.Skipped = false
+
+		for j := range task.SubTasks {
+			subTask := &task.SubTasks[j]
+			if err := s.uncheckLineAt(subTask.LineNum); err != nil {
+				return err
+			}
+			if subTask.FailureReason != "" {
+				if err := s.clearFailureAnnotation(subTask); err != nil {
+					return err
+				}
+			}
+			subTask.Checked = false
+			subTask.FailureReason = ""
+		}
+	}
+
+	if !s.DoDVerified && s.DoDReopens == 0 {
+		return nil
+	}
+	// Removing the marker lines shifts the lines after it, so re-parse
+	lines := strings.Split(s.Content, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if trimmed := strings.TrimSpace(line); trimmed != dodVerifiedMarker && !dodReopensRe.MatchString(trimmed) {
+			kept = append(kept, line)
+		}
+	}
+	content := strings.Join(kept, "\n")
+	if err := os.WriteFile(s.FilePath, []byte(content), 0644); err != nil {
+		return err
+	}
+	reset, err := ParseSprintContent(content)
+	if err != nil {
+		return err
+	}
+	reset.FilePath = s.FilePath
+	*s = *reset
+	return nil
+}
+
+// clearTaskMarkers removes all ❌, 🔄 and ⏭ markers from a top-level task
+func (s *SprintState) clearTaskMarkers(task *Task) error {
+	if s.Format == SprintFormatTable {
+		return s.setTableTaskMarkers(task, func(string) string { return "" })
+	}
+
+	lines := strings.Split(s.Content, "\n")
+	if task.LineNum < 1 || task.LineNum > len(lines) {
+		return fmt.Errorf("invalid line number: %d", task.LineNum)
+	}
+
+	re := regexp.MustCompile(`^(- \[[ xX]\]) ((?:❌|🔄|⏭)*)\s*(.*)$`)
+	matches := re.FindStringSubmatch(lines[task.LineNum-1])
+	if matches == nil {
+		return fmt.Errorf("could not parse task line: %s", lines[task.LineNum-1])
+	}
+	lines[task.LineNum-1] = matches[1] + " " + matches[3]
+	s.Content = strings.Join(lines, "\n")
+
+	return os.WriteFile(s.FilePath, []byte(s.Content), 0644)
+}
+
+// clearFailureAnnotation removes a sub-task's <!-- fail: ... --> annotation
+func (s *SprintState) clearFailureAnnotation(subTask *SubTask) error {
+	if s.Format == SprintFormatTable {
+		return s.setTableTaskCell(subTask.LineNum, subTask.cellText())
+	}
+
+	lines := strings.Split(s.Content, "\n")
+	if subTask.LineNum < 1 || subTask.LineNum > len(lines) {
+		return fmt.Errorf("invalid line number: %d", subTask.LineNum)
+	}
+	lines[subTask.LineNum-1] = failureAnnotationRe.ReplaceAllString(lines[subTask.LineNum-1], "")
+	s.Content = strings.Join(lines, "\n")
+
+	return os.WriteFile(s.FilePath, []byte(s.Content), 0644)
+}
+
 // NormalizeTaskText strips checkbox, ❌/🔄 emojis, and normalizes whitespace for task matching
 func NormalizeTaskText(text string) string {
 	// Remove any leading/trailing whitespace
@@ -548,10 +1060,40 @@ func (s *SprintState) GetOverallProgress() (completed, total int) {
 	return completed, total
 }
 
+// ProgressBarOptions controls optional RenderProgressBar behavior
+type ProgressBarOptions struct {
+	// HalfCreditRunning counts the running sub-task as half done in the
+	// percentage
+	HalfCreditRunning bool
+	// MarkFailed shows the unchecked sub-tasks of tasks that have failed
+	// review (❌) as "!" instead of "."
+	MarkFailed bool
+}
+
+// liveProgressBar is how the bar shown while a sub-task runs is rendered
+var liveProgressBar = ProgressBarOptions{HalfCreditRunning: true, MarkFailed: true}
+
+// progressPercent returns the percentage done of total items, counting each
+// of the running items as half done
+func progressPercent(completed, running, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return (2*completed + running) * 100 / (2 * total)
+}
+
+// isUncheckedSubTask reports whether the indices name an unchecked sub-task
+func (s *SprintState) isUncheckedSubTask(taskIdx, subIdx int) bool {
+	if taskIdx < 0 || taskIdx >= len(s.Tasks) || subIdx < 0 || subIdx >= len(s.Tasks[taskIdx].SubTasks) {
+		return false
+	}
+	return !s.Tasks[taskIdx].SubTasks[subIdx].Checked
+}
+
 // RenderProgressBar renders visual progress as [xo. ... .....] format
 // runningTaskIdx and runningSubIdx indicate which subtask is currently running (-1 for none)
 // Returns a string like "[xo. ... .....] 45% Sprint 1 - Task name"
-func (s *SprintState) RenderProgressBar(sprintNum int, runningTaskIdx, runningSubIdx int) string {
+func (s *SprintState) RenderProgressBar(sprintNum int, runningTaskIdx, runningSubIdx int, opts ProgressBarOptions) string {
 	var segments []string
 	var singleChars []string
 
@@ -568,6 +1110,8 @@ func (s *SprintState) RenderProgressBar(sprintNum int, runningTaskIdx, runningSu
 			return logging.Green(string(ch))
 		case 'o':
 			return logging.Yellow(string(ch))
+		case '!':
+			return logging.Red(string(ch))
 		default:
 			return logging.Dim(string(ch))
 		}
@@ -591,6 +1135,8 @@ func (s *SprintState) RenderProgressBar(sprintNum int, runningTaskIdx, runningSu
 				chars = append(chars, colorChar('x', task.Index, sub.Index))
 			} else if task.Index == runningTaskIdx && sub.Index == runningSubIdx {
 				chars = append(chars, colorChar('o', task.Index, sub.Index))
+			} else if opts.MarkFailed && task.FailureCount > 0 {
+				chars = append(chars, colorChar('!', task.Index, sub.Index))
 			} else {
 				chars = append(chars, colorChar('.', task.Index, sub.Index))
 			}
@@ -603,10 +1149,11 @@ func (s *SprintState) RenderProgressBar(sprintNum int, runningTaskIdx, runningSu
 
 	// Compute percentage from all subtask-level items
 	completed, total := s.GetOverallProgress()
-	pct := 0
-	if total > 0 {
-		pct = completed * 100 / total
+	running := 0
+	if opts.HalfCreditRunning && s.isUncheckedSubTask(runningTaskIdx, runningSubIdx) {
+		running = 1
 	}
+	pct := progressPercent(completed, running, total)
 
 	// Add context: percentage, sprint number, and current task name
 	currentTask := s.GetCurrentTask()
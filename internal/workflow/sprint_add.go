@@ -0,0 +1,105 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// AddedSprint describes a sprint written by AddSprint
+type AddedSprint struct {
+	Num  int
+	Path string
+	// Warning is set when the sprint's heading names a different number,
+	// e.g. one an existing sprint already uses
+	Warning string
+}
+
+// sprintHeadingRe matches a "# Sprint N: Title" heading; the number and
+// title are both optional
+var sprintHeadingRe = regexp.MustCompile(`(?m)^#\s+(?:Sprint\s+(\d+)\s*[:.\-–—]?\s*)?(.*)$`)
+
+// maxSlugLen bounds the title part of a sprint filename
+const maxSlugLen = 40
+
+// AddSprint validates hand-written sprint markdown and writes it as the next
+// sprint after the highest-numbered one, named from its heading
+func AddSprint(proj *project.Project, content string) (*AddedSprint, error) {
+	if err := validateSprintContent(content); err != nil {
+		return nil, fmt.Errorf("not a valid sprint: %w", err)
+	}
+
+	num := highestSprintNum(proj.SprintsDir()) + 1
+
+	headingNum, title := 0, ""
+	if m := sprintHeadingRe.FindStringSubmatch(content); m != nil {
+		fmt.Sscanf(m[1], "%d", &headingNum)
+		title = m[2]
+	}
+	slug := sprintSlug(title)
+	if slug == "" {
+		slug = "manual"
+	}
+
+	added := &AddedSprint{Num: num, Path: filepath.Join(proj.SprintsDir(), FormatSprintFilename(num, slug))}
+	if headingNum != 0 && headingNum != num {
+		if existing := findSprintByNum(proj.SprintsDir(), headingNum); existing != "" {
+			added.Warning = fmt.Sprintf("the heading says sprint %d, which overlaps %s; added as sprint %d", headingNum, filepath.Base(existing), num)
+		} else {
+			added.Warning = fmt.Sprintf("the heading says sprint %d; added as sprint %d", headingNum, num)
+		}
+	}
+
+	if err := os.MkdirAll(proj.SprintsDir(), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create sprints directory: %w", err)
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if err := os.WriteFile(added.Path, []byte(content), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write sprint: %w", err)
+	}
+	return added, nil
+}
+
+// highestSprintNum returns the largest sprint number in sprintsDir, or 0
+func highestSprintNum(sprintsDir string) int {
+	entries, err := os.ReadDir(sprintsDir)
+	if err != nil {
+		return 0
+	}
+	highest := 0
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") {
+			if num := ExtractSprintNum(e.Name()); num > highest {
+				highest = num
+			}
+		}
+	}
+	return highest
+}
+
+// sprintSlug turns a sprint title into a lowercase, hyphenated filename part
+func sprintSlug(title string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+		if sb.Len() >= maxSlugLen {
+			break
+		}
+	}
+	return strings.TrimRight(sb.String(), "-")
+}
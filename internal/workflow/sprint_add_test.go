@@ -0,0 +1,18 @@
+package workflow
+
+import "testing"
+
+func TestSprintSlug(t *testing.T) {
+	tests := map[string]string{
+		"Add Caching!":             "add-caching",
+		"  Auth / sessions (v2)  ": "auth-sessions-v2",
+		"":                         "",
+		"✨":                        "",
+		"a very long sprint title that keeps going and going": "a-very-long-sprint-title-that-keeps-goin",
+	}
+	for title, want := range tests {
+		if got := sprintSlug(title); got != want {
+			t.Errorf("sprintSlug(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// SprintFormat identifies how tasks are written in a sprint file
+type SprintFormat string
+
+const (
+	SprintFormatCheckbox SprintFormat = "checkbox" // Nested "- [ ]" lists (default)
+	SprintFormatTable    SprintFormat = "table"    // GFM table with Task, Skill, Status columns
+)
+
+// Table sprints use one row per task. Rows with an empty Skill cell are
+// top-level tasks; rows with a skill are sub-tasks of the preceding task:
+//
+//	| Task             | Skill        | Status |
+//	|------------------|--------------|--------|
+//	| Set up project   |              | [ ]    |
+//	| Create go.mod    | _implementer | done   |
+//	| Validate setup   | _reviewer    | todo   |
+//
+// Pipes inside cells are not supported.
+
+// tableColumns holds the column positions of a sprint table header
+type tableColumns struct {
+	task, skill, status int
+}
+
+var tableSeparatorRe = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+
+// splitTableRow returns the trimmed cells of a markdown table row,
+// or nil if line is not a table row
+func splitTableRow(line string) []string {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "|") {
+		return nil
+	}
+	trimmed = strings.TrimPrefix(trimmed, "|")
+	trimmed = strings.TrimSuffix(trimmed, "|")
+	cells := strings.Split(trimmed, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// parseTableHeader returns the column layout if cells form a sprint table header
+func parseTableHeader(cells []string) (tableColumns, bool) {
+	cols := tableColumns{task: -1, skill: -1, status: -1}
+	for i, c := range cells {
+		switch strings.ToLower(c) {
+		case "task":
+			cols.task = i
+		case "skill":
+			cols.skill = i
+		case "status":
+			cols.status = i
+		}
+	}
+	return cols, cols.task >= 0 && cols.skill >= 0 && cols.status >= 0
+}
+
+// isTableSprint reports whether content uses the table format: it has a
+// Task/Skill/Status table and no top-level checkbox tasks
+func isTableSprint(content string) bool {
+	lines := strings.Split(content, "\n")
+	dodStart, dodEnd, hasDoD := definitionOfDoneRange(lines)
+	hasTable := false
+	for i, line := range lines {
+		if hasDoD && i >= dodStart && i < dodEnd {
+			continue
+		}
+		if strings.HasPrefix(line, "- [") {
+			return false
+		}
+		if _, ok := parseTableHeader(splitTableRow(line)); ok && i+1 < len(lines) && tableSeparatorRe.MatchString(strings.TrimSpace(lines[i+1])) {
+			hasTable = true
+		}
+	}
+	return hasTable
+}
+
+// tableStatusChecked reports whether a Status cell means the task is done
+func tableStatusChecked(status string) bool {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "[x]", "x", "done", "complete", "completed", "✅":
+		return true
+	}
+	return false
+}
+
+// parseSprintTable parses a table-format sprint into the same Task/SubTask
+// model as checkbox sprints
+func parseSprintTable(state *SprintState) {
+	state.Format = SprintFormatTable
+
+	lines := strings.Split(state.Content, "\n")
+	var cols tableColumns
+	inTable := false
+	var currentTask *Task
+	taskIndex := 0
+
+	for lineNum, line := range lines {
+		cells := splitTableRow(line)
+		if cells == nil {
+			inTable = false
+			continue
+		}
+		if c, ok := parseTableHeader(cells); ok {
+			cols = c
+			inTable = true
+			continue
+		}
+		if !inTable || tableSeparatorRe.MatchString(strings.TrimSpace(line)) {
+			continue
+		}
+
+		cell := func(i int) string {
+			if i < len(cells) {
+				return cells[i]
+			}
+			return ""
+		}
+		text := cell(cols.task)
+		skill := cell(cols.skill)
+		checked := tableStatusChecked(cell(cols.status))
+		if text == "" {
+			continue
+		}
+
+		if skill == "" || skill == "-" {
+			if currentTask != nil {
+				state.Tasks = append(state.Tasks, *currentTask)
+			}
+			markers := text[:len(text)-len(strings.TrimLeft(text, "❌🔄⏭"))]
+			currentTask = &Task{
+				Index:        taskIndex,
+				Text:         strings.TrimSpace(text[len(markers):]),
+				Checked:      checked,
+				LineNum:      lineNum + 1,
+				FailureCount: strings.Count(markers, "❌"),
+				ReplanCount:  strings.Count(markers, "🔄"),
+				Skipped:      strings.Contains(markers, "⏭"),
+				SubTasks:     []SubTask{},
+			}
+			taskIndex++
+			continue
+		}
+
+		if currentTask != nil {
+			text, reason := splitFailureAnnotation(text)
+			text, verifyOnly := splitVerifyOnly(text)
+			currentTask.SubTasks = append(currentTask.SubTasks, SubTask{
+				Index:         len(currentTask.SubTasks),
+				Skill:         skill,
+				Text:          text,
+				Checked:       checked,
+				LineNum:       lineNum + 1,
+				ParentIndex:   currentTask.Index,
+				FailureReason: reason,
+				VerifyOnly:    verifyOnly,
+			})
+		}
+	}
+
+	if currentTask != nil {
+		state.Tasks = append(state.Tasks, *currentTask)
+	}
+}
+
+// tableColumnsAt returns the column layout of the table containing lineNum
+func tableColumnsAt(lines []string, lineNum int) (tableColumns, bool) {
+	for i := lineNum - 1; i >= 0; i-- {
+		cells := splitTableRow(lines[i])
+		if cells == nil {
+			break
+		}
+		if cols, ok := parseTableHeader(cells); ok {
+			return cols, true
+		}
+	}
+	return tableColumns{}, false
+}
+
+// setTableCell replaces cell col in a table row, preserving the other cells
+func setTableCell(line string, col int, value string) (string, bool) {
+	indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+	body := strings.TrimSpace(line)
+	leading := strings.HasPrefix(body, "|")
+	trailing := strings.HasSuffix(body, "|") && len(body) > 1
+	body = strings.TrimSuffix(strings.TrimPrefix(body, "|"), "|")
+
+	parts := strings.Split(body, "|")
+	if col < 0 || col >= len(parts) {
+		return line, false
+	}
+	parts[col] = " " + value + " "
+
+	out := indent
+	if leading {
+		out += "|"
+	}
+	out += strings.Join(parts, "|")
+	if trailing {
+		out += "|"
+	}
+	return out, true
+}
+
+// setTableStatusAt updates the Status cell of the row at lineNum, keeping
+// the cell's style ("[ ]"/"[x]" checkboxes or "todo"/"done" words)
+func (s *SprintState) setTableStatusAt(lineNum int, checked bool) error {
+	lines := strings.Split(s.Content, "\n")
+	if lineNum < 1 || lineNum > len(lines) {
+		return fmt.Errorf("invalid line number: %d", lineNum)
+	}
+	cols, ok := tableColumnsAt(lines, lineNum)
+	if !ok {
+		return fmt.Errorf("no table header found for line %d", lineNum)
+	}
+
+	line := lines[lineNum-1]
+	cells := splitTableRow(line)
+	current := ""
+	if cols.status < len(cells) {
+		current = cells[cols.status]
+	}
+	if tableStatusChecked(current) == checked {
+		return nil // Already in the requested state
+	}
+
+	value := "todo"
+	if checked {
+		value = "done"
+	}
+	if strings.HasPrefix(current, "[") || current == "" {
+		value = "[ ]"
+		if checked {
+			value = "[x]"
+		}
+	}
+
+	newLine, ok := setTableCell(line, cols.status, value)
+	if !ok {
+		return fmt.Errorf("could not update status cell on line %d", lineNum)
+	}
+	lines[lineNum-1] = newLine
+	s.Content = strings.Join(lines, "\n")
+
+	return os.WriteFile(s.FilePath, []byte(s.Content), 0644)
+}
+
+// setTableTaskCell replaces the Task cell of the row at lineNum
+func (s *SprintState) setTableTaskCell(lineNum int, value string) error {
+	lines := strings.Split(s.Content, "\n")
+	if lineNum < 1 || lineNum > len(lines) {
+		return fmt.Errorf("invalid line number: %d", lineNum)
+	}
+	cols, ok := tableColumnsAt(lines, lineNum)
+	if !ok {
+		return fmt.Errorf("no table header found for line %d", lineNum)
+	}
+	newLine, ok := setTableCell(lines[lineNum-1], cols.task, value)
+	if !ok {
+		return fmt.Errorf("could not update task cell on line %d", lineNum)
+	}
+	lines[lineNum-1] = newLine
+	s.Content = strings.Join(lines, "\n")
+
+	return os.WriteFile(s.FilePath, []byte(s.Content), 0644)
+}
+
+// setTableTaskMarkers rewrites the ❌/🔄/⏭ markers at the start of a table
+// task's Task cell using update
+func (s *SprintState) setTableTaskMarkers(task *Task, update func(markers string) string) error {
+	lines := strings.Split(s.Content, "\n")
+	if task.LineNum < 1 || task.LineNum > len(lines) {
+		return fmt.Errorf("invalid line number: %d", task.LineNum)
+	}
+	cols, ok := tableColumnsAt(lines, task.LineNum)
+	if !ok {
+		return fmt.Errorf("no table header found for line %d", task.LineNum)
+	}
+
+	line := lines[task.LineNum-1]
+	cells := splitTableRow(line)
+	if cols.task >= len(cells) {
+		return fmt.Errorf("could not parse task row: %s", line)
+	}
+	text := cells[cols.task]
+	markers := text[:len(text)-len(strings.TrimLeft(text, "❌🔄⏭"))]
+	rest := strings.TrimSpace(text[len(markers):])
+
+	newMarkers := update(markers)
+	value := rest
+	if newMarkers != "" {
+		value = newMarkers + " " + rest
+	}
+	newLine, ok := setTableCell(line, cols.task, value)
+	if !ok {
+		return fmt.Errorf("could not parse task row: %s", line)
+	}
+	lines[task.LineNum-1] = newLine
+	s.Content = strings.Join(lines, "\n")
+
+	return os.WriteFile(s.FilePath, []byte(s.Content), 0644)
+}
@@ -0,0 +1,137 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const tableSprint = `# Sprint 1: Setup
+
+| Task | Skill | Status |
+|------|-------|--------|
+| Set up project | | [ ] |
+| Create go.mod | _implementer | done |
+| Validate setup | _reviewer | todo |
+| ❌ Add CLI | - | [ ] |
+| Wire cobra root | _implementer | [ ] |
+`
+
+func TestParseSprintContent_Table(t *testing.T) {
+	sprint, err := ParseSprintContent(tableSprint)
+	if err != nil {
+		t.Fatalf("ParseSprintContent failed: %v", err)
+	}
+
+	if sprint.Format != SprintFormatTable {
+		t.Errorf("expected table format, got %s", sprint.Format)
+	}
+	if len(sprint.Tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(sprint.Tasks))
+	}
+
+	first := sprint.Tasks[0]
+	if first.Text != "Set up project" || first.Checked || first.LineNum != 5 {
+		t.Errorf("unexpected first task: %+v", first)
+	}
+	if len(first.SubTasks) != 2 {
+		t.Fatalf("expected 2 sub-tasks, got %d", len(first.SubTasks))
+	}
+	if sub := first.SubTasks[0]; sub.Skill != "_implementer" || sub.Text != "Create go.mod" || !sub.Checked {
+		t.Errorf("unexpected first sub-task: %+v", sub)
+	}
+	if sub := first.SubTasks[1]; sub.Skill != "_reviewer" || sub.Checked {
+		t.Errorf("unexpected second sub-task: %+v", sub)
+	}
+
+	second := sprint.Tasks[1]
+	if second.Text != "Add CLI" || second.FailureCount != 1 {
+		t.Errorf("expected markers stripped and counted, got %+v", second)
+	}
+
+	next := sprint.GetNextSubTask()
+	if next == nil || next.Text != "Validate setup" {
+		t.Errorf("expected next sub-task 'Validate setup', got %+v", next)
+	}
+}
+
+func TestParseSprintContent_CheckboxUnchanged(t *testing.T) {
+	// A checkbox sprint that happens to contain a table stays checkbox format
+	content := `# Sprint 1
+
+| Task | Skill | Status |
+|------|-------|--------|
+| reference | only | table |
+
+- [ ] Task one
+  - [ ] _implementer: Do it
+`
+	sprint, err := ParseSprintContent(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sprint.Format != SprintFormatCheckbox {
+		t.Errorf("expected checkbox format, got %s", sprint.Format)
+	}
+	if len(sprint.Tasks) != 1 || len(sprint.Tasks[0].SubTasks) != 1 {
+		t.Errorf("expected 1 task with 1 sub-task, got %+v", sprint.Tasks)
+	}
+}
+
+func TestSprintTable_StatusCellUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "01-setup.md")
+	if err := os.WriteFile(path, []byte(tableSprint), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sprint, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Word-style status cell
+	if err := sprint.CheckSubTask(0, 1); err != nil {
+		t.Fatalf("CheckSubTask failed: %v", err)
+	}
+	// Checkbox-style status cell
+	if err := sprint.CheckTask(0); err != nil {
+		t.Fatalf("CheckTask failed: %v", err)
+	}
+	if err := sprint.AddFailure(1); err != nil {
+		t.Fatalf("AddFailure failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		"| Validate setup | _reviewer | done |",
+		"| Set up project | | [x] |",
+		"| ❌❌ Add CLI | - | [ ] |",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in:\n%s", want, content)
+		}
+	}
+
+	reparsed, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reparsed.Tasks[0].Checked || !reparsed.Tasks[0].SubTasks[1].Checked {
+		t.Error("expected checked state to survive a re-parse")
+	}
+	if reparsed.Tasks[1].FailureCount != 2 {
+		t.Errorf("expected failure count 2, got %d", reparsed.Tasks[1].FailureCount)
+	}
+
+	if err := reparsed.UncheckSubTask(0, 1); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(reparsed.Content, "| Validate setup | _reviewer | todo |") {
+		t.Errorf("expected status reset to todo:\n%s", reparsed.Content)
+	}
+}
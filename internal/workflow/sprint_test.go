@@ -0,0 +1,517 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestAnnotateFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "01-initial.md")
+	content := "# Sprint 1\n\n- [ ] ❌ Build parser\n  - [x] go-coder: Write parser\n  - [ ] _reviewer: Review parser\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sprint, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sprint.AnnotateFailure(0, 1, "tests missing\nfor --> edge cases"); err != nil {
+		t.Fatalf("AnnotateFailure failed: %v", err)
+	}
+	// A second failure replaces the first annotation
+	if err := sprint.AnnotateFailure(0, 1, "error paths untested"); err != nil {
+		t.Fatalf("AnnotateFailure failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "  - [ ] _reviewer: Review parser <!-- fail: error paths untested -->"
+	if !strings.Contains(string(data), want) {
+		t.Errorf("expected %q in:\n%s", want, data)
+	}
+	if strings.Count(string(data), "<!-- fail:") != 1 {
+		t.Errorf("expected a single annotation, got:\n%s", data)
+	}
+
+	reparsed, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub := reparsed.Tasks[0].SubTasks[1]
+	if sub.Text != "Review parser" {
+		t.Errorf("annotation should be stripped from text, got %q", sub.Text)
+	}
+	if sub.FailureReason != "error paths untested" {
+		t.Errorf("expected failure reason, got %q", sub.FailureReason)
+	}
+}
+
+func TestAnnotateFailure_Sanitizes(t *testing.T) {
+	if got := sanitizeFailureReason("a\n  b --> c | d"); got != "a b -> c / d" {
+		t.Errorf("unexpected sanitized reason %q", got)
+	}
+}
+
+func TestBuildSubTaskPrompt_IncludesFailureReason(t *testing.T) {
+	sprint, err := ParseSprintContent("# Sprint 1\n\n- [ ] ❌ Build parser\n  - [ ] go-coder: Write parser\n  - [ ] _reviewer: Review parser <!-- fail: tests missing for empty input -->\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := &sprint.Tasks[0]
+
+	prompt := buildSubTaskPrompt(task, &task.SubTasks[0], "", nil, nil, sprint)
+
+	if !strings.Contains(prompt, "Previous review failures") || !strings.Contains(prompt, "- tests missing for empty input") {
+		t.Errorf("expected failure reason in prompt:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "<!--") {
+		t.Error("prompt should not contain raw annotation markup")
+	}
+}
+
+func TestBuildSubTaskPrompt_BranchesOnSkillPhase(t *testing.T) {
+	sprint, err := ParseSprintContent("# Sprint 1\n\n- [ ] Build parser\n  - [ ] schema-writer: Write schema\n  - [ ] auditor: Audit parser\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := &sprint.Tasks[0]
+	writer, auditor := &task.SubTasks[0], &task.SubTasks[1]
+
+	reviewSkill := &project.Skill{Name: "auditor", Metadata: project.SkillMetadata{Name: "auditor", Phase: "review"}, Content: "Audit carefully."}
+	prompt := buildSubTaskPrompt(task, auditor, "", reviewSkill, nil, sprint)
+	if !strings.Contains(prompt, "If the implementation is good, respond with: APPROVED") {
+		t.Errorf("review-phase skill should get reviewer instructions:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "Audit carefully.") {
+		t.Error("prompt should include the skill content")
+	}
+
+	// Without metadata the name heuristics apply, and "auditor" is generic
+	prompt = buildSubTaskPrompt(task, auditor, "", nil, nil, sprint)
+	if !strings.Contains(prompt, "Complete the sub-task described above.") {
+		t.Errorf("skill without phase should get generic instructions:\n%s", prompt)
+	}
+
+	implementSkill := &project.Skill{Name: "schema-writer", Metadata: project.SkillMetadata{Name: "schema-writer", Phase: "implement"}}
+	prompt = buildSubTaskPrompt(task, writer, "", implementSkill, nil, sprint)
+	if !strings.Contains(prompt, "### File: path/to/file.ext") {
+		t.Errorf("implement-phase skill should get file output instructions:\n%s", prompt)
+	}
+}
+
+func TestSubTaskPhase(t *testing.T) {
+	tests := []struct {
+		name  string
+		skill *project.Skill
+		want  string
+	}{
+		{"go-coder", nil, "implement"},
+		{"_reviewer", nil, "review"},
+		{"security-reviewer", nil, "review"},
+		{"auditor", nil, ""},
+		{"auditor", &project.Skill{Metadata: project.SkillMetadata{Phase: "review"}}, "review"},
+		{"go-coder", &project.Skill{Metadata: project.SkillMetadata{Phase: "reference"}}, "reference"},
+		{"go-coder", &project.Skill{}, "implement"},
+	}
+	for _, tt := range tests {
+		if got := subTaskPhase(tt.name, tt.skill); got != tt.want {
+			t.Errorf("subTaskPhase(%q, %+v) = %q, want %q", tt.name, tt.skill, got, tt.want)
+		}
+	}
+}
+
+func TestParseReviewOutcome(t *testing.T) {
+	tests := []struct {
+		name         string
+		output       string
+		wantApproved bool
+		wantReason   string
+	}{
+		{"approved", "Looks good.\nAPPROVED", true, ""},
+		{"approved lowercase", "approved - all requirements met", true, ""},
+		{"sprint complete", "Checked every goal.\nSPRINT_COMPLETE", true, ""},
+		{"issues found", "Looked at the code.\nISSUES_FOUND: no tests for the parser\nMore detail here.", false, "no tests for the parser"},
+		{"issues found without colon", "ISSUES_FOUND missing error handling", false, "missing error handling"},
+		{"not approved", "NOT APPROVED: the CLI ignores --verbose", false, "the CLI ignores --verbose"},
+		{"not approved underscore", "NOT_APPROVED", false, ""},
+		{"fail token wins over pass token", "APPROVED the structure, but\nISSUES_FOUND: tests fail", false, "tests fail"},
+		{"no token", "NEEDS WORK", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			approved, reason := parseReviewOutcome(tt.output)
+			if approved != tt.wantApproved || reason != tt.wantReason {
+				t.Errorf("parseReviewOutcome(%q) = (%v, %q), want (%v, %q)", tt.output, approved, reason, tt.wantApproved, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestParseStrictReviewOutcome(t *testing.T) {
+	tests := []struct {
+		name         string
+		output       string
+		wantApproved bool
+		wantReason   string
+	}{
+		{"explicit approved", "Looks good.\nAPPROVED", true, ""},
+		{"bold approved", "All checks pass.\n\n**APPROVED**\n", true, ""},
+		{"sprint complete", "SPRINT_COMPLETE.", true, ""},
+		{"empty", "", false, reviewEmptyReason},
+		{"whitespace only", "  \n\n", false, reviewEmptyReason},
+		{"approved in prose", "approved - all requirements met", false, reviewAmbiguousReason},
+		{"lowercase token", "Looks fine.\napproved", false, reviewAmbiguousReason},
+		{"no verdict", "The code is mostly fine, a few nits.", false, reviewNoVerdictReason},
+		{"issues found", "APPROVED\nISSUES_FOUND: tests fail", false, "tests fail"},
+		{"not approved", "NOT_APPROVED", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			approved, reason := parseStrictReviewOutcome(tt.output)
+			if approved != tt.wantApproved || reason != tt.wantReason {
+				t.Errorf("parseStrictReviewOutcome(%q) = (%v, %q), want (%v, %q)", tt.output, approved, reason, tt.wantApproved, tt.wantReason)
+			}
+		})
+	}
+
+	// The default mode still accepts the token anywhere
+	if approved, _ := reviewOutcome("approved - all requirements met", false); !approved {
+		t.Error("expected lenient mode to approve")
+	}
+}
+
+func TestGetNextSubTaskForTask(t *testing.T) {
+	sprint, err := ParseSprintContent("# Sprint 1\n\n- [x] Done task\n  - [x] go-coder: Work\n\n- [ ] Open task\n  - [x] go-coder: Write code\n  - [ ] _reviewer: Review code\n\n- [ ] Later task\n  - [ ] go-coder: More work\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := sprint.GetNextSubTaskForTask(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sub.Text != "More work" || sub.ParentIndex != 2 {
+		t.Errorf("expected task 3's sub-task, got %+v", sub)
+	}
+
+	sub, err = sprint.GetNextSubTaskForTask(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sub.Skill != "_reviewer" {
+		t.Errorf("expected first unchecked sub-task, got %+v", sub)
+	}
+
+	if _, err := sprint.GetNextSubTaskForTask(0); err == nil || !strings.Contains(err.Error(), "already complete") {
+		t.Errorf("expected already complete error, got %v", err)
+	}
+	for _, i := range []int{-1, 3} {
+		if _, err := sprint.GetNextSubTaskForTask(i); err == nil || !strings.Contains(err.Error(), "invalid task number") {
+			t.Errorf("index %d: expected invalid task error, got %v", i, err)
+		}
+	}
+}
+
+func TestResetAll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "01-initial.md")
+	content := "# Sprint 1\n\n" +
+		"- [x] ❌🔄❌ Build parser\n" +
+		"  - [x] go-coder: Write parser <!-- fail: tests missing -->\n" +
+		"  - [X] _reviewer: Review parser\n" +
+		"- [x] Wire CLI\n" +
+		"  - [x] go-coder: Add command\n" +
+		"\n## Definition of Done\n" + dodVerifiedMarker + "\n- Parser handles errors\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sprint, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sprint.ResetAll(); err != nil {
+		t.Fatalf("ResetAll failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Sprint 1\n\n" +
+		"- [ ] Build parser\n" +
+		"  - [ ] go-coder: Write parser\n" +
+		"  - [ ] _reviewer: Review parser\n" +
+		"- [ ] Wire CLI\n" +
+		"  - [ ] go-coder: Add command\n" +
+		"\n## Definition of Done\n- Parser handles errors\n"
+	if string(data) != want {
+		t.Errorf("unexpected content after reset:\n%s\nwant:\n%s", data, want)
+	}
+
+	// The in-memory state matches the file
+	if sprint.DoDVerified {
+		t.Error("expected DoD verification to be cleared")
+	}
+	task := sprint.Tasks[0]
+	if task.Checked || task.FailureCount != 0 || task.ReplanCount != 0 {
+		t.Errorf("expected task 1 reset, got checked=%v ❌=%d 🔄=%d", task.Checked, task.FailureCount, task.ReplanCount)
+	}
+	if sub := task.SubTasks[0]; sub.Checked || sub.FailureReason != "" {
+		t.Errorf("expected sub-task reset, got checked=%v reason=%q", sub.Checked, sub.FailureReason)
+	}
+	if next := sprint.GetNextSubTask(); next == nil || next.Text != "Write parser" {
+		t.Errorf("expected first sub-task next, got %+v", next)
+	}
+}
+
+func TestResetAll_Table(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "01-initial.md")
+	content := "# Sprint 1\n\n" +
+		"| Task | Skill | Status |\n" +
+		"|------|-------|--------|\n" +
+		"| ❌ Set up project | | [x] |\n" +
+		"| Create go.mod <!-- fail: wrong module path --> | go-coder | done |\n" +
+		"| Validate setup | _reviewer | done |\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sprint, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sprint.ResetAll(); err != nil {
+		t.Fatalf("ResetAll failed: %v", err)
+	}
+
+	reparsed, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := reparsed.Tasks[0]
+	if task.Checked || task.FailureCount != 0 {
+		t.Errorf("expected task reset, got checked=%v ❌=%d", task.Checked, task.FailureCount)
+	}
+	for _, sub := range task.SubTasks {
+		if sub.Checked || sub.FailureReason != "" {
+			t.Errorf("expected sub-task %q reset, got checked=%v reason=%q", sub.Text, sub.Checked, sub.FailureReason)
+		}
+	}
+}
+
+func TestTruncateText_Multibyte(t *testing.T) {
+	got := TruncateText("添加配置文件解析功能", 6)
+	if got != "添加配..." {
+		t.Errorf("unexpected truncation %q", got)
+	}
+}
+
+func TestFormatSprintFilename(t *testing.T) {
+	tests := []struct {
+		num  int
+		want string
+	}{
+		{1, "01-initial.md"},
+		{10, "10-initial.md"},
+		{100, "100-initial.md"},
+	}
+	for _, tt := range tests {
+		got := FormatSprintFilename(tt.num, "initial")
+		if got != tt.want {
+			t.Errorf("FormatSprintFilename(%d) = %q, want %q", tt.num, got, tt.want)
+		}
+		if n := ExtractSprintNum(got); n != tt.num {
+			t.Errorf("ExtractSprintNum(%q) = %d, want %d", got, n, tt.num)
+		}
+	}
+}
+
+func TestProgressPercent_HalfCredit(t *testing.T) {
+	tests := []struct {
+		completed, running, total, want int
+	}{
+		{0, 0, 0, 0},
+		{1, 0, 4, 25},
+		{1, 1, 4, 37},
+		{0, 1, 2, 25},
+		{3, 1, 4, 87},
+		{4, 0, 4, 100},
+	}
+	for _, tt := range tests {
+		if got := progressPercent(tt.completed, tt.running, tt.total); got != tt.want {
+			t.Errorf("progressPercent(%d, %d, %d) = %d, want %d", tt.completed, tt.running, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestRenderProgressBar_Options(t *testing.T) {
+	logging.SetTheme(logging.ThemeMono)
+	t.Cleanup(func() { logging.SetTheme(logging.ThemeDark) })
+
+	path := filepath.Join(t.TempDir(), "01-initial.md")
+	content := "# Sprint 1\n\n" +
+		"- [ ] ❌ Build parser\n  - [x] go-coder: Write parser\n  - [ ] _reviewer: Review parser\n\n" +
+		"- [ ] Add CLI\n  - [ ] go-coder: Write CLI\n  - [ ] _reviewer: Review CLI\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sprint, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain := sprint.RenderProgressBar(1, 1, 0, ProgressBarOptions{})
+	if !strings.HasPrefix(plain, "[x. o.] 25%") {
+		t.Errorf("unexpected default bar %q", plain)
+	}
+
+	bar := sprint.RenderProgressBar(1, 1, 0, ProgressBarOptions{HalfCreditRunning: true, MarkFailed: true})
+	if !strings.HasPrefix(bar, "[x! o.] 37%") {
+		t.Errorf("expected failed glyph and half credit, got %q", bar)
+	}
+
+	// Nothing running: no half credit, and the failed task still shows "!"
+	idle := sprint.RenderProgressBar(1, -1, -1, ProgressBarOptions{HalfCreditRunning: true, MarkFailed: true})
+	if !strings.HasPrefix(idle, "[x! ..] 25%") {
+		t.Errorf("unexpected idle bar %q", idle)
+	}
+}
+
+func TestParseSprintContent_DefaultAgent(t *testing.T) {
+	content := "---\ndefault_agent: codex\n---\n# Sprint 2\n\n- [ ] Task\n  - [ ] go-coder: Work\n"
+	sprint, err := ParseSprintContent(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sprint.DefaultAgent != "codex" {
+		t.Errorf("expected default agent codex, got %q", sprint.DefaultAgent)
+	}
+	if len(sprint.Tasks) != 1 || len(sprint.Tasks[0].SubTasks) != 1 {
+		t.Errorf("frontmatter disturbed task parsing: %+v", sprint.Tasks)
+	}
+	if got := sprint.preferredAgent(""); got != "codex" {
+		t.Errorf("expected codex without --agent, got %q", got)
+	}
+	if got := sprint.preferredAgent("claude"); got != "claude" {
+		t.Errorf("expected --agent to win, got %q", got)
+	}
+}
+
+// moveSprint has blank-line spacing, a note under task 2, and trailing
+// sections that must stay put
+const moveSprint = `# Sprint 1
+
+## Tasks
+
+- [x] Set up
+  - [x] go-coder: Init module
+
+- [ ] ❌❌ Add handler
+  - [ ] go-coder: Write handler
+  - [ ] _reviewer: Review handler
+  Note: needs the store
+
+- [ ] Add store
+  - [ ] go-coder: Write store
+
+## Definition of Done
+
+- [ ] Tests pass
+`
+
+func writeMoveSprint(t *testing.T) *SprintState {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "01-initial.md")
+	if err := os.WriteFile(path, []byte(moveSprint), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sprint, err := ParseSprint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sprint
+}
+
+func TestMoveTask_Up(t *testing.T) {
+	sprint := writeMoveSprint(t)
+	if err := sprint.MoveTask(2, 1); err != nil {
+		t.Fatalf("MoveTask failed: %v", err)
+	}
+
+	want := `# Sprint 1
+
+## Tasks
+
+- [x] Set up
+  - [x] go-coder: Init module
+
+- [ ] Add store
+  - [ ] go-coder: Write store
+
+- [ ] ❌❌ Add handler
+  - [ ] go-coder: Write handler
+  - [ ] _reviewer: Review handler
+  Note: needs the store
+
+## Definition of Done
+
+- [ ] Tests pass
+`
+	if sprint.Content != want {
+		t.Errorf("unexpected content:\n%s", sprint.Content)
+	}
+	onDisk, err := ParseSprint(sprint.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onDisk.Content != want {
+		t.Errorf("file not updated:\n%s", onDisk.Content)
+	}
+	if sprint.Tasks[1].Text != "Add store" || len(sprint.Tasks[1].SubTasks) != 1 {
+		t.Errorf("expected Add store with its sub-task at index 1, got %+v", sprint.Tasks[1])
+	}
+	handler := sprint.Tasks[2]
+	if handler.Text != "Add handler" || handler.FailureCount != 2 || len(handler.SubTasks) != 2 || handler.SubTasks[1].Skill != "_reviewer" {
+		t.Errorf("expected Add handler to keep its markers and sub-tasks, got %+v", handler)
+	}
+}
+
+func TestMoveTask_Down(t *testing.T) {
+	sprint := writeMoveSprint(t)
+	if err := sprint.MoveTask(0, 2); err != nil {
+		t.Fatalf("MoveTask failed: %v", err)
+	}
+
+	var texts []string
+	for _, task := range sprint.Tasks {
+		texts = append(texts, task.Text)
+	}
+	if got := strings.Join(texts, ", "); got != "Add handler, Add store, Set up" {
+		t.Errorf("unexpected order: %s", got)
+	}
+	setUp := sprint.Tasks[2]
+	if !setUp.Checked || len(setUp.SubTasks) != 1 || setUp.SubTasks[0].Text != "Init module" {
+		t.Errorf("expected Set up to keep its sub-task, got %+v", setUp)
+	}
+	if !strings.Contains(sprint.Content, "  - [x] go-coder: Init module\n\n## Definition of Done") {
+		t.Errorf("expected the sections after the tasks to stay put:\n%s", sprint.Content)
+	}
+	if sprint.DefinitionOfDone != "- [ ] Tests pass" {
+		t.Errorf("expected the Definition of Done to survive, got %q", sprint.DefinitionOfDone)
+	}
+}
+
+func TestMoveTask_InvalidIndex(t *testing.T) {
+	sprint := writeMoveSprint(t)
+	if err := sprint.MoveTask(0, 3); err == nil {
+		t.Error("expected an error for an out-of-range index")
+	}
+}
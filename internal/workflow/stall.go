@@ -0,0 +1,92 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// progressName is the hidden marker in the sprints directory that tracks
+// whether the current sprint is still gaining completed sub-tasks
+const progressName = ".progress"
+
+// sprintProgress is the content of the progress marker
+type sprintProgress struct {
+	Sprint  int `json:"sprint"`
+	Last    int `json:"last"`    // Sub-tasks completed at the previous invocation
+	Stalled int `json:"stalled"` // Invocations in a row with Last unchanged
+}
+
+// checkSprintProgress records the sprint's completed sub-task count for this
+// invocation and returns a HumanNeededError once limit invocations in a row
+// have passed without it changing. A count that drops, as when a replan or a
+// reopened Definition of Done unchecks sub-tasks, is a change: the retry
+// and replan paths are making progress of their own, so redoing those
+// sub-tasks doesn't count as a stall. The count resets after escalating so
+// a human fix gets a fresh budget.
+func checkSprintProgress(sprintsDir string, sprint *SprintState, sprintNum, limit int) error {
+	progress := nextProgress(sprintsDir, sprint, sprintNum)
+
+	var stallErr error
+	if stallsOut(progress, limit) {
+		completed, total := sprint.GetOverallProgress()
+		stallErr = &HumanNeededError{Message: stallSummary(sprint, sprintNum, progress.Stalled, completed, total)}
+		progress.Stalled = 0
+	}
+
+	if data, err := json.Marshal(progress); err == nil {
+		os.WriteFile(filepath.Join(sprintsDir, progressName), data, 0644)
+	}
+	return stallErr
+}
+
+// nextProgress returns the progress marker as this invocation would record
+// it, without writing it
+func nextProgress(sprintsDir string, sprint *SprintState, sprintNum int) sprintProgress {
+	completed, _ := sprint.GetOverallProgress()
+
+	var progress sprintProgress
+	if data, err := os.ReadFile(filepath.Join(sprintsDir, progressName)); err == nil {
+		json.Unmarshal(data, &progress)
+	}
+	switch {
+	case progress.Sprint != sprintNum:
+		progress = sprintProgress{Sprint: sprintNum, Last: completed}
+	case completed != progress.Last:
+		progress.Last = completed
+		progress.Stalled = 0
+	default:
+		progress.Stalled++
+	}
+	return progress
+}
+
+// stallsOut reports whether progress has reached the stall limit
+func stallsOut(progress sprintProgress, limit int) bool {
+	return limit > 0 && progress.Stalled >= limit
+}
+
+// stallSummary describes a stalled sprint and its unfinished tasks
+func stallSummary(sprint *SprintState, sprintNum, stalled, completed, total int) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("sprint %d has made no progress in %d runs (%d/%d sub-tasks done), human intervention needed.\nStuck tasks:", sprintNum, stalled, completed, total))
+	for _, task := range sprint.Tasks {
+		if task.Checked {
+			continue
+		}
+		line := fmt.Sprintf("\n  - %s", NormalizeTaskText(task.Text))
+		if task.FailureCount > 0 {
+			line += fmt.Sprintf(" (failed review %d time%s)", task.FailureCount, plural(task.FailureCount))
+		}
+		for _, sub := range task.SubTasks {
+			if sub.FailureReason != "" {
+				line += ": " + sub.FailureReason
+				break
+			}
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
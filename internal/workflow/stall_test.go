@@ -0,0 +1,115 @@
+package workflow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+func TestNext_StalledSprintEscalates(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Build parser\n  - [x] go-coder: Write parser\n  - [ ] _reviewer: Review parser\n",
+	})
+	if err := os.WriteFile(filepath.Join(tmpDir, ".ai", "config.yaml"), []byte("stall_limit: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	writeStubScript(t, bin, "claude", "echo 'ISSUES_FOUND: parser drops the last token'\n")
+	t.Setenv("PATH", bin)
+	opts := NextOptions{PreferredAgent: "claude"}
+
+	// Each review fails, so the completed count never beats its first value
+	for i := 1; i <= 2; i++ {
+		result, err := NextWithOptions(project.New(tmpDir), opts)
+		if err != nil {
+			t.Fatalf("run %d: unexpected error: %v", i, err)
+		}
+		if !strings.Contains(result.Message, "Review failed") {
+			t.Fatalf("run %d: expected review failure, got: %s", i, result.Message)
+		}
+	}
+
+	_, err := NextWithOptions(project.New(tmpDir), opts)
+	var humanErr *HumanNeededError
+	if !errors.As(err, &humanErr) {
+		t.Fatalf("expected HumanNeededError on the stalled run, got %v", err)
+	}
+	for _, want := range []string{"no progress in 2 runs", "1/2 sub-tasks done", "Build parser (failed review 2 times): parser drops the last token"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in stall summary, got:\n%v", want, err)
+		}
+	}
+
+	// Escalating resets the count, so the next run proceeds
+	if _, err := NextWithOptions(project.New(tmpDir), opts); err != nil {
+		t.Errorf("expected run after escalation to proceed, got %v", err)
+	}
+}
+
+func TestCheckSprintProgress(t *testing.T) {
+	dir := t.TempDir()
+	sprintWith := func(done int) *SprintState {
+		content := "# Sprint 1\n\n- [ ] Task\n"
+		for i := 0; i < 3; i++ {
+			box := " "
+			if i < done {
+				box = "x"
+			}
+			content += "  - [" + box + "] go-coder: Step\n"
+		}
+		sprint, err := ParseSprintContent(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sprint
+	}
+
+	// Any change resets the count, including falling back
+	for i, done := range []int{0, 1, 0, 1, 2, 1, 2, 2, 2} {
+		if err := checkSprintProgress(dir, sprintWith(done), 1, 3); err != nil {
+			t.Fatalf("check %d: unexpected stall: %v", i, err)
+		}
+	}
+	if err := checkSprintProgress(dir, sprintWith(2), 1, 3); err == nil {
+		t.Fatal("expected stall after 3 runs with 2 completed")
+	}
+
+	// A new sprint starts fresh
+	if err := checkSprintProgress(dir, sprintWith(0), 2, 1); err != nil {
+		t.Errorf("expected new sprint to reset progress, got %v", err)
+	}
+}
+
+// TestCheckSprintProgress_ReviewFailRedoApprove replays the completed counts
+// a task sees when its review fails, a replan unchecks its sub-tasks, and
+// the redo is approved: unchecking is not a stall, so it never escalates.
+func TestCheckSprintProgress_ReviewFailRedoApprove(t *testing.T) {
+	dir := t.TempDir()
+	sprintWith := func(done int) *SprintState {
+		content := "# Sprint 1\n\n- [ ] Task\n"
+		for i, skill := range []string{"go-coder", "go-coder", "_reviewer", "go-coder"} {
+			box := " "
+			if i < done {
+				box = "x"
+			}
+			content += "  - [" + box + "] " + skill + ": Step\n"
+		}
+		sprint, err := ParseSprintContent(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sprint
+	}
+
+	// Implement twice, fail review twice, replan (unchecked), implement
+	// twice, approve, finish
+	for i, done := range []int{0, 1, 2, 2, 2, 0, 1, 2, 3} {
+		if err := checkSprintProgress(dir, sprintWith(done), 1, 3); err != nil {
+			t.Fatalf("step %d: unexpected stall: %v", i+1, err)
+		}
+	}
+}
@@ -2,14 +2,18 @@ package workflow
 
 import (
 	"io/fs"
-	"path/filepath"
 
 	"github.com/strongdm/agate/internal/fsutil"
 	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
 )
 
 // StatusResult captures the detected workflow state from a filesystem
 type StatusResult struct {
+	// StateDir is the state directory the status was read from, relative
+	// to the project (e.g. ".ai")
+	StateDir string
+
 	// Goal
 	HasGoal bool
 
@@ -23,7 +27,7 @@ type StatusResult struct {
 	// Design
 	HasDesignOverview  bool
 	HasDesignDecisions bool
-	DesignFiles        []string
+	DesignFiles        []string // every design doc, gates and supplementary
 
 	// Skills
 	Skills []string
@@ -34,10 +38,11 @@ type StatusResult struct {
 	Sprint            *SprintState // parsed sprint with checkbox states
 }
 
-// GetStatus detects workflow state from an abstract filesystem.
+// GetStatus detects workflow state from an abstract filesystem rooted at the
+// project, whose state lives in stateDir (e.g. ".ai").
 // This function contains NO os.* calls - it only uses fs.FS operations.
-func GetStatus(fsys fs.FS) StatusResult {
-	result := StatusResult{}
+func GetStatus(fsys fs.FS, stateDir string) StatusResult {
+	result := StatusResult{StateDir: stateDir}
 
 	// Check for GOAL.md
 	result.HasGoal = fsExists(fsys, "GOAL.md")
@@ -47,7 +52,7 @@ func GetStatus(fsys fs.FS) StatusResult {
 	}
 
 	// Check interview status
-	interviewPath := filepath.Join(".ai", "interview.md")
+	interviewPath := result.statePath("interview.md")
 	result.InterviewExists = fsExists(fsys, interviewPath)
 	if result.InterviewExists {
 		content, err := fs.ReadFile(fsys, interviewPath)
@@ -57,15 +62,15 @@ func GetStatus(fsys fs.FS) StatusResult {
 	}
 
 	// Check design files
-	result.HasDesignOverview = fsExists(fsys, filepath.Join(".ai", "design", "overview.md"))
-	result.HasDesignDecisions = fsExists(fsys, filepath.Join(".ai", "design", "decisions.md"))
-	result.DesignFiles = fsutil.ListMarkdownFilesFS(fsys, filepath.Join(".ai", "design"))
+	result.HasDesignOverview = fsExists(fsys, result.statePath("design", "overview.md"))
+	result.HasDesignDecisions = fsExists(fsys, result.statePath("design", "decisions.md"))
+	result.DesignFiles = fsutil.ListMarkdownFilesFS(fsys, result.statePath("design"))
 
 	// Check skills
-	result.Skills = fsutil.ListMarkdownFilesFS(fsys, filepath.Join(".ai", "skills"))
+	result.Skills = fsutil.ListMarkdownFilesFS(fsys, result.statePath("skills"))
 
 	// Find current sprint
-	sprintPath, sprintNum := FindCurrentSprintFS(fsys)
+	sprintPath, sprintNum := FindCurrentSprintFS(fsys, stateDir)
 	result.CurrentSprintPath = sprintPath
 	result.CurrentSprintNum = sprintNum
 
@@ -83,7 +88,45 @@ func GetStatus(fsys fs.FS) StatusResult {
 	return result
 }
 
-// derivePhase determines the current workflow phase from detected state
+// designGateFiles are the design docs the planning phases require, in the
+// order they are written. Other files in the design dir are supplementary:
+// status lists them, but they never decide the phase.
+var designGateFiles = []string{"overview.md", "decisions.md"}
+
+// isDesignGate reports whether name is one of designGateFiles
+func isDesignGate(name string) bool {
+	for _, gate := range designGateFiles {
+		if name == gate {
+			return true
+		}
+	}
+	return false
+}
+
+// supplementaryDesignFiles returns the design files that aren't gates
+func (r StatusResult) supplementaryDesignFiles() []string {
+	var files []string
+	for _, f := range r.DesignFiles {
+		if !isDesignGate(f) {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// hasDesignGate reports whether the gate file name exists
+func (r StatusResult) hasDesignGate(name string) bool {
+	switch name {
+	case "overview.md":
+		return r.HasDesignOverview
+	case "decisions.md":
+		return r.HasDesignDecisions
+	}
+	return false
+}
+
+// derivePhase determines the current workflow phase from detected state.
+// Only the design gate files count; supplementary design docs don't.
 func derivePhase(r StatusResult) PlanPhase {
 	// No goal = can't proceed
 	if !r.HasGoal {
@@ -113,6 +156,11 @@ func derivePhase(r StatusResult) PlanPhase {
 	return PhaseExecution
 }
 
+// statePath joins elem onto the status's state dir, slash-separated
+func (r StatusResult) statePath(elem ...string) string {
+	return (&project.Project{AIDir: r.StateDir}).StatePath(elem...)
+}
+
 // fsExists checks if a path exists in the filesystem
 func fsExists(fsys fs.FS, path string) bool {
 	_, err := fs.Stat(fsys, path)
@@ -2,8 +2,11 @@ package workflow
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"testing/fstest"
+
+	"github.com/strongdm/agate/internal/project"
 )
 
 // Test catalog: This file documents ALL possible workflow states
@@ -13,7 +16,7 @@ func TestGetStatus_NoGoal(t *testing.T) {
 	// Empty filesystem - no GOAL.md
 	fsys := fstest.MapFS{}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	if result.HasGoal {
 		t.Error("expected HasGoal=false for empty filesystem")
@@ -29,7 +32,7 @@ func TestGetStatus_GoalOnly(t *testing.T) {
 		"GOAL.md": &fstest.MapFile{Data: []byte("# My Project\n\nBuild something cool.")},
 	}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	if !result.HasGoal {
 		t.Error("expected HasGoal=true")
@@ -60,7 +63,7 @@ What is the scope?
 `)},
 	}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	if !result.InterviewExists {
 		t.Error("expected InterviewExists=true")
@@ -91,7 +94,7 @@ What is the scope?
 `)},
 	}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	if !result.InterviewExists {
 		t.Error("expected InterviewExists=true")
@@ -117,7 +120,7 @@ Status: COMPLETE
 `)},
 	}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	if !result.InterviewComplete {
 		t.Error("expected InterviewComplete=true for legacy Status: COMPLETE format")
@@ -134,7 +137,7 @@ func TestGetStatus_DesignPhase(t *testing.T) {
 		".ai/interview.md": &fstest.MapFile{Data: []byte("- [x] All questions answered")},
 	}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	if result.Phase != PhaseDesign {
 		t.Errorf("expected Phase=design, got %s", result.Phase)
@@ -152,7 +155,7 @@ func TestGetStatus_DecisionsPhase(t *testing.T) {
 		".ai/design/overview.md":  &fstest.MapFile{Data: []byte("# Design Overview\n\n...")},
 	}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	if !result.HasDesignOverview {
 		t.Error("expected HasDesignOverview=true")
@@ -177,7 +180,7 @@ func TestGetStatus_SprintPhase(t *testing.T) {
 		".ai/design/decisions.md":  &fstest.MapFile{Data: []byte("# Technical Decisions")},
 	}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	if !result.HasDesignDecisions {
 		t.Error("expected HasDesignDecisions=true")
@@ -204,7 +207,7 @@ func TestGetStatus_ExecutionPhase(t *testing.T) {
 `)},
 	}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	if result.Phase != PhaseExecution {
 		t.Errorf("expected Phase=execution, got %s", result.Phase)
@@ -241,7 +244,7 @@ func TestGetStatus_SprintProgress(t *testing.T) {
 `)},
 	}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	if result.Sprint == nil {
 		t.Fatal("expected Sprint to be parsed")
@@ -278,7 +281,7 @@ func TestGetStatus_SprintComplete(t *testing.T) {
 `)},
 	}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	if result.Sprint == nil {
 		t.Fatal("expected Sprint to be parsed")
@@ -313,7 +316,7 @@ func TestGetStatus_MultiSprintFirstIncomplete(t *testing.T) {
 `)},
 	}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	// Should pick sprint 2 as it's the first incomplete
 	if result.CurrentSprintPath != ".ai/sprints/02-features.md" {
@@ -333,7 +336,7 @@ func TestGetStatus_Skills(t *testing.T) {
 		".ai/skills/go-coder.md":    &fstest.MapFile{Data: []byte("# Go coder skill")},
 	}
 
-	result := GetStatus(fsys)
+	result := GetStatus(fsys, project.DefaultStateDir)
 
 	if len(result.Skills) != 3 {
 		t.Errorf("expected 3 skills, got %d: %v", len(result.Skills), result.Skills)
@@ -597,3 +600,118 @@ func TestAddReplanMarker(t *testing.T) {
 		t.Errorf("expected FailureCount=2 preserved, got %d", updated.Tasks[0].FailureCount)
 	}
 }
+
+func TestAddSkipMarker(t *testing.T) {
+	content := `# Sprint 1
+
+## Tasks
+
+- [ ] ❌❌❌🔄 Stuck task
+  - [ ] go-coder: Fix bugs
+
+- [ ] Next task
+  - [ ] go-coder: Do work
+`
+
+	tmpFile := t.TempDir() + "/sprint.md"
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sprint, err := ParseSprint(tmpFile)
+	if err != nil {
+		t.Fatalf("ParseSprint failed: %v", err)
+	}
+
+	if err := sprint.AddSkipMarker(0); err != nil {
+		t.Fatalf("AddSkipMarker failed: %v", err)
+	}
+	if err := sprint.AddSkipMarker(0); err != nil {
+		t.Fatalf("second AddSkipMarker failed: %v", err)
+	}
+
+	updated, err := ParseSprint(tmpFile)
+	if err != nil {
+		t.Fatalf("ParseSprint failed: %v", err)
+	}
+	if !strings.Contains(updated.Content, "- [ ] ❌❌❌🔄⏭ Stuck task\n") {
+		t.Errorf("expected a single ⏭ after the other markers, got:\n%s", updated.Content)
+	}
+	task := updated.Tasks[0]
+	if !task.Skipped || task.FailureCount != 3 || task.ReplanCount != 1 || task.Text != "Stuck task" {
+		t.Errorf("unexpected skipped task: %+v", task)
+	}
+
+	if sub := updated.GetNextSubTask(); sub == nil || sub.ParentIndex != 1 {
+		t.Errorf("expected the next sub-task to come from task 2, got %+v", sub)
+	}
+	if cur := updated.GetCurrentTask(); cur == nil || cur.Index != 1 {
+		t.Errorf("expected task 2 to be current, got %+v", cur)
+	}
+	if updated.IsComplete() {
+		t.Error("sprint should not be complete while task 2 is open")
+	}
+	if err := updated.CheckTask(1); err != nil {
+		t.Fatal(err)
+	}
+	if updated, err = ParseSprint(tmpFile); err != nil {
+		t.Fatal(err)
+	}
+	if !updated.IsComplete() {
+		t.Error("a sprint whose tasks are all done or skipped should be complete")
+	}
+
+	// Resetting the sprint clears the skip along with the other markers
+	if err := updated.ResetAll(); err != nil {
+		t.Fatal(err)
+	}
+	reset, _ := ParseSprint(tmpFile)
+	if reset.Tasks[0].Skipped || strings.Contains(reset.Content, "⏭") {
+		t.Errorf("expected ResetAll to clear ⏭, got:\n%s", reset.Content)
+	}
+}
+
+func TestAddSkipMarker_Table(t *testing.T) {
+	content := "# Sprint 1\n\n| Task | Skill | Status |\n|------|-------|--------|\n| ❌ Stuck task | - | [ ] |\n| Fix it | go-coder | [ ] |\n"
+	tmpFile := t.TempDir() + "/sprint.md"
+	if err := os.WriteFile(tmpFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sprint, err := ParseSprint(tmpFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sprint.AddSkipMarker(0); err != nil {
+		t.Fatalf("AddSkipMarker failed: %v", err)
+	}
+	updated, _ := ParseSprint(tmpFile)
+	if len(updated.Tasks) != 1 || !updated.Tasks[0].Skipped || updated.Tasks[0].FailureCount != 1 {
+		t.Errorf("expected a skipped table task with its ❌ kept, got %+v\n%s", updated.Tasks, updated.Content)
+	}
+}
+
+func TestGetStatus_CustomStateDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"GOAL.md":                                  &fstest.MapFile{Data: []byte("# Goal")},
+		"build/agate-state/interview.md":           &fstest.MapFile{Data: []byte("- [x] All questions answered")},
+		"build/agate-state/design/overview.md":     &fstest.MapFile{Data: []byte("# Overview")},
+		"build/agate-state/design/decisions.md":    &fstest.MapFile{Data: []byte("# Decisions")},
+		"build/agate-state/sprints/01-initial.md":  &fstest.MapFile{Data: []byte("# Sprint 1\n\n- [ ] Task one\n")},
+		".ai/sprints/01-ignored.md":                &fstest.MapFile{Data: []byte("# Stale\n\n- [x] Done\n")},
+		"build/agate-state/skills/_implementer.md": &fstest.MapFile{Data: []byte("# Skill")},
+	}
+
+	result := GetStatus(fsys, "build/agate-state")
+
+	if !result.InterviewComplete || !result.HasDesignOverview || !result.HasDesignDecisions {
+		t.Errorf("expected planning files under custom state dir to be found: %+v", result)
+	}
+	if result.Phase != PhaseExecution {
+		t.Errorf("expected Phase=execution, got %s", result.Phase)
+	}
+	if result.CurrentSprintPath != "build/agate-state/sprints/01-initial.md" {
+		t.Errorf("expected sprint under custom state dir, got %q", result.CurrentSprintPath)
+	}
+	if len(result.Skills) != 1 {
+		t.Errorf("expected 1 skill, got %v", result.Skills)
+	}
+}
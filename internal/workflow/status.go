@@ -7,26 +7,34 @@ import (
 	"strings"
 
 	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
 )
 
 // StatusWithResult generates the status output and returns the StatusResult.
 // This allows callers to use GetExitCode on the result.
-func StatusWithResult(projectDir string) (string, StatusResult, error) {
-	fsys := os.DirFS(projectDir)
-	result := GetStatus(fsys)
-	output, err := formatStatus(projectDir, result)
+func StatusWithResult(proj *project.Project) (string, StatusResult, error) {
+	fsys := os.DirFS(proj.Dir)
+	result := GetStatus(fsys, proj.StateDir())
+	output, err := formatStatus(proj, result)
 	return output, result, err
 }
 
+// StatusPlainWithResult is StatusWithResult with plain output: ASCII-only,
+// no color codes, and fixed "key: value" columns, for piping into other tools.
+func StatusPlainWithResult(proj *project.Project) (string, StatusResult) {
+	result := GetStatus(os.DirFS(proj.Dir), proj.StateDir())
+	return formatStatusPlain(proj, result), result
+}
+
 // Status generates the status output from markdown files.
 // Uses GetStatus(fs.FS) for detection, then formats the output.
-func Status(projectDir string) (string, error) {
-	output, _, err := StatusWithResult(projectDir)
+func Status(proj *project.Project) (string, error) {
+	output, _, err := StatusWithResult(proj)
 	return output, err
 }
 
-func formatStatus(projectDir string, result StatusResult) (string, error) {
-	projectName := filepath.Base(projectDir)
+func formatStatus(proj *project.Project, result StatusResult) (string, error) {
+	projectName := filepath.Base(proj.Dir)
 
 	var sb strings.Builder
 
@@ -58,34 +66,48 @@ func formatStatus(projectDir string, result StatusResult) (string, error) {
 			sb.WriteString(fmt.Sprintf("%s %s\n", logging.Bold("INTERVIEW"), logging.Green("+ complete")))
 		} else {
 			sb.WriteString(fmt.Sprintf("%s %s\n", logging.Bold("INTERVIEW"), logging.Yellow("+ awaiting answers")))
-			sb.WriteString(fmt.Sprintf("         %s\n", logging.Dim("-> .ai/interview.md")))
+			sb.WriteString(fmt.Sprintf("         %s\n", logging.Dim("-> "+proj.StatePath("interview.md"))))
 		}
 	} else if result.Phase == PhaseInterview {
 		sb.WriteString(fmt.Sprintf("%s %s\n", logging.Bold("INTERVIEW"), logging.Yellow("(pending)")))
 	}
 
-	// Design status
-	if result.HasDesignOverview {
+	// Design status: the gate files decide the phase, so they are listed
+	// first, missing ones included; supplementary docs follow
+	switch {
+	case result.HasDesignOverview && result.HasDesignDecisions:
 		sb.WriteString(fmt.Sprintf("%s   %s\n", logging.Bold("DESIGN"), logging.Green("+ complete")))
-		for _, f := range result.DesignFiles {
-			sb.WriteString(fmt.Sprintf("         %s\n", logging.Dim(fmt.Sprintf("-> .ai/design/%s", f))))
-		}
-	} else if result.Phase == PhaseDesign || result.Phase == PhaseDecisions || result.Phase == PhaseSprint || result.Phase == PhaseExecution {
+	case result.HasDesignOverview:
+		sb.WriteString(fmt.Sprintf("%s   %s\n", logging.Bold("DESIGN"), logging.Yellow("+ decisions pending")))
+	case result.Phase != PhaseInterview || len(result.DesignFiles) > 0:
 		sb.WriteString(fmt.Sprintf("%s   %s\n", logging.Bold("DESIGN"), logging.Yellow("(pending)")))
 	}
+	if result.HasDesignOverview || len(result.DesignFiles) > 0 {
+		for _, f := range designGateFiles {
+			path := proj.StatePath("design", f)
+			if result.hasDesignGate(f) {
+				sb.WriteString(fmt.Sprintf("         %s\n", logging.Dim("-> "+path+" (required)")))
+			} else {
+				sb.WriteString(fmt.Sprintf("         %s %s\n", logging.Dim("-> "+path), logging.Yellow("(required, missing)")))
+			}
+		}
+		for _, f := range result.supplementaryDesignFiles() {
+			sb.WriteString(fmt.Sprintf("         %s\n", logging.Dim("-> "+proj.StatePath("design", f)+" (supplementary)")))
+		}
+	}
 
 	// Skills status
 	if len(result.Skills) > 0 {
 		sb.WriteString(fmt.Sprintf("%s   %s\n", logging.Bold("SKILLS"), logging.Green(fmt.Sprintf("+ %d generated", len(result.Skills)))))
 		for _, s := range result.Skills {
-			sb.WriteString(fmt.Sprintf("         %s\n", logging.Dim(fmt.Sprintf("-> .ai/skills/%s", s))))
+			sb.WriteString(fmt.Sprintf("         %s\n", logging.Dim("-> "+proj.StatePath("skills", s))))
 		}
 	}
 
 	// Sprint status
 	if result.Sprint != nil {
 		// Show visual progress bar
-		progressBar := result.Sprint.RenderProgressBar(result.CurrentSprintNum, -1, -1)
+		progressBar := result.Sprint.RenderProgressBar(result.CurrentSprintNum, -1, -1, ProgressBarOptions{MarkFailed: true})
 		sb.WriteString(fmt.Sprintf("%s   %s\n", logging.Bold("SPRINT"), progressBar))
 
 		// Show next sub-task if not complete
@@ -110,6 +132,90 @@ func formatStatus(projectDir string, result StatusResult) (string, error) {
 	return sb.String(), nil
 }
 
+// formatStatusPlain renders status as one "key: value" line per fact with
+// values aligned in a single column. Indented "file:" lines list the files
+// behind the preceding key; design docs are listed as "gate:" (required,
+// with "missing" when absent) or "extra:" (supplementary) instead.
+func formatStatusPlain(proj *project.Project, result StatusResult) string {
+	var sb strings.Builder
+	line := func(key, value string) {
+		sb.WriteString(fmt.Sprintf("%-11s %s\n", key+":", value))
+	}
+	fileAs := func(kind, path string) {
+		sb.WriteString(fmt.Sprintf("  %-9s %s\n", kind+":", path))
+	}
+	file := func(path string) {
+		fileAs("file", path)
+	}
+
+	line("project", filepath.Base(proj.Dir))
+	if !result.HasGoal {
+		line("goal", "missing")
+		line("next", getNextActionFromResult(result))
+		return sb.String()
+	}
+	line("goal", "present")
+	line("phase", string(result.Phase))
+
+	switch {
+	case result.InterviewExists && result.InterviewComplete:
+		line("interview", "complete")
+	case result.InterviewExists:
+		line("interview", "awaiting answers")
+		file(proj.StatePath("interview.md"))
+	case result.Phase == PhaseInterview:
+		line("interview", "pending")
+	default:
+		line("interview", "none")
+	}
+
+	switch {
+	case result.HasDesignOverview && result.HasDesignDecisions:
+		line("design", "complete")
+	case result.HasDesignOverview:
+		line("design", "decisions pending")
+	default:
+		line("design", "pending")
+	}
+	if result.HasDesignOverview || len(result.DesignFiles) > 0 {
+		for _, f := range designGateFiles {
+			path := proj.StatePath("design", f)
+			if !result.hasDesignGate(f) {
+				path += " (missing)"
+			}
+			fileAs("gate", path)
+		}
+		for _, f := range result.supplementaryDesignFiles() {
+			fileAs("extra", proj.StatePath("design", f))
+		}
+	}
+
+	line("skills", fmt.Sprintf("%d", len(result.Skills)))
+	for _, s := range result.Skills {
+		file(proj.StatePath("skills", s))
+	}
+
+	if result.Sprint != nil {
+		completed, total := result.Sprint.GetOverallProgress()
+		pct := 0
+		if total > 0 {
+			pct = completed * 100 / total
+		}
+		line("sprint", fmt.Sprintf("%d (%d/%d sub-tasks, %d%%)", result.CurrentSprintNum, completed, total, pct))
+		file(result.CurrentSprintPath)
+		if !result.Sprint.IsComplete() {
+			if nextSub := result.Sprint.GetNextSubTask(); nextSub != nil {
+				line("next_task", fmt.Sprintf("[%s] %s", nextSub.Skill, nextSub.Text))
+			}
+		}
+	} else {
+		line("sprint", "pending")
+	}
+
+	line("next", getNextActionFromResult(result))
+	return sb.String()
+}
+
 // getNextActionFromResult derives the next action from StatusResult
 func getNextActionFromResult(result StatusResult) string {
 	if !result.HasGoal {
@@ -120,7 +226,7 @@ func getNextActionFromResult(result StatusResult) string {
 	if result.Phase != PhaseExecution {
 		// Special case: interview exists but not complete
 		if result.Phase == PhaseInterview && result.InterviewExists && !result.InterviewComplete {
-			return "Answer questions in .ai/interview.md, then check completion box"
+			return "Answer questions in " + result.statePath("interview.md") + ", then check completion box"
 		}
 		return fmt.Sprintf("agate next (%s)", GetNextPlanAction(result.Phase))
 	}
@@ -0,0 +1,110 @@
+package workflow
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// TestFormatStatusPlain_Golden compares plain status output for the fixture
+// project in testdata/status/project. Run with -update to regenerate.
+func TestFormatStatusPlain_Golden(t *testing.T) {
+	dir := filepath.Join("testdata", "status", "project")
+	got, result := StatusPlainWithResult(project.New(dir))
+	if result.Phase != PhaseExecution {
+		t.Fatalf("fixture should be in the execution phase, got %s", result.Phase)
+	}
+
+	golden := filepath.Join("testdata", "status", "plain.golden")
+	if *updateGolden {
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("plain status mismatch\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+
+	for i := 0; i < len(got); i++ {
+		if got[i] > 0x7f || got[i] == 0x1b {
+			t.Fatalf("plain output contains non-ASCII or escape byte at %d: %q", i, got)
+		}
+	}
+}
+
+func TestFormatStatusPlain_NoGoal(t *testing.T) {
+	got, _ := StatusPlainWithResult(project.New(t.TempDir()))
+	want := "goal:       missing\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("expected %q in:\n%s", want, got)
+	}
+}
+
+// TestFormatStatus_SupplementaryDesignFiles verifies that extra design docs
+// are listed apart from the overview.md and decisions.md gates, and that
+// they don't move the phase past the missing decisions gate.
+func TestFormatStatus_SupplementaryDesignFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"GOAL.md":                            "# Goal\n\nBuild a CLI in Go.\n",
+		filepath.Join(".ai", "interview.md"): "# Interview\n\n- [x] All questions answered\n",
+		filepath.Join(".ai", "design", "overview.md"): "# Design\n",
+		filepath.Join(".ai", "design", "api.md"):      "# API\n",
+		filepath.Join(".ai", "design", "storage.md"):  "# Storage\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, result, err := StatusWithResult(project.New(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Phase != PhaseDecisions {
+		t.Fatalf("expected phase %s with decisions.md missing, got %s", PhaseDecisions, result.Phase)
+	}
+	for _, want := range []string{
+		"decisions pending",
+		".ai/design/overview.md (required)",
+		".ai/design/decisions.md",
+		"(required, missing)",
+		".ai/design/api.md (supplementary)",
+		".ai/design/storage.md (supplementary)",
+		"Next: agate next (" + GetNextPlanAction(PhaseDecisions) + ")",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in status:\n%s", want, got)
+		}
+	}
+	for _, line := range strings.Split(got, "\n") {
+		if strings.Contains(line, "DESIGN") && strings.Contains(line, "+ complete") {
+			t.Errorf("expected the design not to show as complete: %q", line)
+		}
+	}
+
+	plain, _ := StatusPlainWithResult(project.New(dir))
+	want := "design:     decisions pending\n" +
+		"  gate:     .ai/design/overview.md\n" +
+		"  gate:     .ai/design/decisions.md (missing)\n" +
+		"  extra:    .ai/design/api.md\n" +
+		"  extra:    .ai/design/storage.md\n"
+	if !strings.Contains(plain, want) {
+		t.Errorf("expected %q in plain status:\n%s", want, plain)
+	}
+}
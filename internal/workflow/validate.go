@@ -0,0 +1,241 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// ValidationIssue is one problem found in the project state
+type ValidationIssue struct {
+	File    string // Project-relative path, with forward slashes
+	Line    int    // 1-based line, or 0 for the whole file
+	Message string
+}
+
+// String formats the issue as file:line: message
+func (i ValidationIssue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", i.File, i.Line, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.File, i.Message)
+}
+
+// checkboxLikeRe matches any list item that starts with a checkbox, so
+// lines the sprint parser would silently skip can be reported
+var checkboxLikeRe = regexp.MustCompile(`^\s*[-*+]\s*\[[^\]]*\]`)
+
+// Validate checks the whole project state: GOAL.md is present and filled
+// in, every sprint parses into well-formed nested tasks whose skills exist,
+// sprint numbers are unique and contiguous, and skill files lint clean.
+// Issues are ordered by file, then line.
+func Validate(proj *project.Project) []ValidationIssue {
+	var issues []ValidationIssue
+
+	issues = append(issues, ValidateGoal(proj)...)
+
+	skillNames, skillIssues := validateSkills(proj)
+	issues = append(issues, skillIssues...)
+	issues = append(issues, ValidateSprints(proj, skillNames)...)
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// ValidateGoal checks GOAL.md is a readable file that isn't empty or the
+// unedited template
+func ValidateGoal(proj *project.Project) []ValidationIssue {
+	if err := proj.CheckGoal(); err != nil {
+		return []ValidationIssue{{File: "GOAL.md", Message: err.Error()}}
+	}
+	goal, err := project.ParseGoal(proj.GoalPath())
+	if err != nil {
+		return []ValidationIssue{{File: "GOAL.md", Message: err.Error()}}
+	}
+	if err := goal.Validate(); err != nil {
+		return []ValidationIssue{{File: "GOAL.md", Message: err.Error()}}
+	}
+	return nil
+}
+
+// validateSkills lints the skill files and returns the names sub-tasks may
+// reference: the skill files plus the built-in skills and _test-gate
+func validateSkills(proj *project.Project) (map[string]bool, []ValidationIssue) {
+	names := make(map[string]bool)
+	for _, b := range project.BuiltinSkills() {
+		names[b.Name] = true
+	}
+	names[testGateSkill] = true
+
+	results, err := project.LintSkills(proj.SkillsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return names, nil
+		}
+		return names, []ValidationIssue{{File: stateRel(proj, proj.SkillsDir()), Message: err.Error()}}
+	}
+
+	var issues []ValidationIssue
+	for _, r := range results {
+		names[strings.TrimSuffix(r.File, ".md")] = true
+		for _, e := range r.Errors {
+			issues = append(issues, ValidationIssue{File: stateRel(proj, filepath.Join(proj.SkillsDir(), r.File)), Message: e.Error()})
+		}
+	}
+	return names, issues
+}
+
+// ValidateSprints checks every sprint file: its name starts with a unique
+// sprint number, numbers run from 1 without gaps, and its tasks are well
+// formed. skills lists the skill names sub-tasks may reference; nil skips
+// that check.
+func ValidateSprints(proj *project.Project, skills map[string]bool) []ValidationIssue {
+	entries, err := os.ReadDir(proj.SprintsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return []ValidationIssue{{File: stateRel(proj, proj.SprintsDir()), Message: err.Error()}}
+	}
+
+	var issues []ValidationIssue
+	byNum := make(map[int][]string)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
+			continue
+		}
+		path := filepath.Join(proj.SprintsDir(), e.Name())
+		rel := stateRel(proj, path)
+
+		num := ExtractSprintNum(e.Name())
+		if num <= 0 {
+			issues = append(issues, ValidationIssue{File: rel, Message: "file name must start with the sprint number, e.g. 01-initial.md"})
+		} else {
+			byNum[num] = append(byNum[num], rel)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			issues = append(issues, ValidationIssue{File: rel, Message: err.Error()})
+			continue
+		}
+		for _, issue := range checkSprintContent(string(content), skills) {
+			issue.File = rel
+			issues = append(issues, issue)
+		}
+	}
+
+	// Sprint numbers must be unique and run 1..N
+	var nums []int
+	for num := range byNum {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	for i, num := range nums {
+		files := byNum[num]
+		sort.Strings(files)
+		for _, dup := range files[1:] {
+			issues = append(issues, ValidationIssue{File: dup, Message: fmt.Sprintf("duplicate sprint number %d (also %s)", num, files[0])})
+		}
+		prev := 0
+		if i > 0 {
+			prev = nums[i-1]
+		}
+		if num != prev+1 {
+			issues = append(issues, ValidationIssue{File: files[0], Message: fmt.Sprintf("sprint %d is missing before sprint %d", prev+1, num)})
+		}
+	}
+	return issues
+}
+
+// checkSprintContent reports structural problems in one sprint's content.
+// File is left empty for the caller to fill in.
+func checkSprintContent(content string, skills map[string]bool) []ValidationIssue {
+	sprint, err := ParseSprintContent(content)
+	if err != nil {
+		return []ValidationIssue{{Message: err.Error()}}
+	}
+
+	var issues []ValidationIssue
+	if sprint.Format == SprintFormatCheckbox {
+		issues = append(issues, checkCheckboxLines(content)...)
+	}
+	if len(sprint.Tasks) == 0 {
+		issues = append(issues, ValidationIssue{Message: "sprint has no tasks"})
+	}
+	for _, task := range sprint.Tasks {
+		if len(task.SubTasks) == 0 {
+			issues = append(issues, ValidationIssue{Line: task.LineNum, Message: fmt.Sprintf("task %q has no sub-tasks", TruncateText(task.Text, 40))})
+		}
+		for _, sub := range task.SubTasks {
+			if skills != nil && sub.Skill != "" && !skills[sub.Skill] {
+				msg := fmt.Sprintf("unknown skill %q (no %s.md in skills)", sub.Skill, sub.Skill)
+				if closest := closestSkill(sub.Skill, sortedKeys(skills)); closest != "" {
+					msg += fmt.Sprintf("; did you mean %s?", closest)
+				}
+				issues = append(issues, ValidationIssue{Line: sub.LineNum, Message: msg})
+			}
+		}
+	}
+	return issues
+}
+
+// checkCheckboxLines reports checkbox lines the nested-checkbox parser
+// would skip: wrong indentation or box syntax, sub-tasks with no skill, and
+// sub-tasks before the first task
+func checkCheckboxLines(content string) []ValidationIssue {
+	lines := strings.Split(content, "\n")
+	dodStart, dodEnd, hasDoD := definitionOfDoneRange(lines)
+
+	var issues []ValidationIssue
+	inTask := false
+	for i, line := range lines {
+		if hasDoD && i >= dodStart && i < dodEnd {
+			continue
+		}
+		switch {
+		case topLevelTaskRe.MatchString(line):
+			inTask = true
+		case subTaskLineRe.MatchString(line):
+			if !inTask {
+				issues = append(issues, ValidationIssue{Line: i + 1, Message: "sub-task appears before any top-level task"})
+			}
+		case checkboxLikeRe.MatchString(line):
+			msg := "malformed checkbox; use '- [ ] task' or '  - [ ] skill: description' (two-space indent)"
+			if strings.HasPrefix(line, "  - [") {
+				msg = "sub-task needs a skill: '  - [ ] skill: description'"
+			}
+			issues = append(issues, ValidationIssue{Line: i + 1, Message: msg})
+		}
+	}
+	return issues
+}
+
+// stateRel returns path relative to the project directory, with forward
+// slashes, for reporting
+func stateRel(proj *project.Project, path string) string {
+	if rel, err := filepath.Rel(proj.Dir, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
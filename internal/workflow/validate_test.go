@@ -0,0 +1,109 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// writeValidateFixture writes files (relative to a new project dir) and
+// returns the dir
+func writeValidateFixture(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func validSkill(name string) string {
+	return project.FormatSkillWithFrontmatter(project.SkillMetadata{
+		Name:    name,
+		Agents:  []string{"claude"},
+		Phase:   "implement",
+		Version: 1,
+	}, "# "+name+"\n")
+}
+
+func TestValidate_CleanProject(t *testing.T) {
+	dir := writeValidateFixture(t, map[string]string{
+		"GOAL.md":                 "# Goal\n\nBuild a task tracker CLI in Go.\n",
+		".ai/skills/go-coder.md":  validSkill("go-coder"),
+		".ai/sprints/01-setup.md": "# Sprint 1\n\n- [x] Setup\n  - [x] go-coder: Scaffold\n  - [x] _reviewer: Review\n",
+		".ai/sprints/02-core.md":  "# Sprint 2\n\n- [ ] Core\n  - [ ] go-coder: Build\n  - [ ] _reviewer: Review\n",
+		".ai/sprints/.progress":   "{}",
+	})
+
+	if issues := Validate(project.New(dir)); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+}
+
+func TestValidate_BrokenProject(t *testing.T) {
+	dir := writeValidateFixture(t, map[string]string{
+		"GOAL.md":                project.GoalTemplate,
+		".ai/skills/go-coder.md": validSkill("go-coder"),
+		".ai/skills/broken.md":   "no frontmatter\n",
+		".ai/sprints/01-setup.md": "# Sprint 1\n\n" +
+			"  - [ ] go-coder: Orphan sub-task\n" + // line 3
+			"- [ ] Setup\n" +
+			"  - [ ] go-coder: Scaffold\n" +
+			"  - [ ] py-coder: Wrong language\n" + // line 6
+			"    - [ ] go-coder: Too deep\n" + // line 7
+			"- [] Bad box\n" + // line 8
+			"  - [ ] missing skill separator\n" + // line 9
+			"- [ ] Empty task\n", // line 10
+		".ai/sprints/01-again.md": "# Sprint 1 again\n\n- [ ] Task\n  - [ ] go-coder: Work\n",
+		".ai/sprints/03-later.md": "# Sprint 3\n\n- [ ] Task\n  - [ ] go-coder: Work\n",
+		".ai/sprints/notes.md":    "# Notes\n\nJust prose.\n",
+	})
+
+	var got []string
+	for _, issue := range Validate(project.New(dir)) {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		".ai/sprints/01-setup.md:3: sub-task appears before any top-level task",
+		`.ai/sprints/01-setup.md:6: unknown skill "py-coder" (no py-coder.md in skills); did you mean go-coder?`,
+		".ai/sprints/01-setup.md:7: malformed checkbox",
+		".ai/sprints/01-setup.md:8: malformed checkbox",
+		".ai/sprints/01-setup.md:9: sub-task needs a skill",
+		`.ai/sprints/01-setup.md:10: task "Empty task" has no sub-tasks`,
+		".ai/sprints/01-setup.md: duplicate sprint number 1 (also .ai/sprints/01-again.md)",
+		".ai/sprints/03-later.md: sprint 2 is missing before sprint 3",
+		".ai/sprints/notes.md: file name must start with the sprint number",
+		".ai/sprints/notes.md: sprint has no tasks",
+		".ai/skills/broken.md: missing frontmatter block",
+		"GOAL.md: GOAL.md looks unedited",
+	}
+	all := strings.Join(got, "\n")
+	for _, w := range want {
+		if !strings.Contains(all, w) {
+			t.Errorf("missing issue %q in:\n%s", w, all)
+		}
+	}
+	if strings.Contains(all, "01-setup.md:5") {
+		t.Errorf("valid sub-task line should not be reported:\n%s", all)
+	}
+}
+
+func TestValidate_GoalDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "GOAL.md"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	issues := Validate(project.New(dir))
+	if len(issues) != 1 || !strings.Contains(issues[0].String(), "GOAL.md is a directory") {
+		t.Errorf("expected one GOAL.md directory issue, got %v", issues)
+	}
+}
@@ -0,0 +1,225 @@
+package workflow
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/strongdm/agate/internal/logging"
+	"github.com/strongdm/agate/internal/project"
+)
+
+// Clock abstracts time so the watcher can be driven deterministically in tests
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// realClock is the wall-clock implementation of Clock
+type realClock struct{}
+
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// RealClock returns a Clock backed by the system time
+func RealClock() Clock {
+	return realClock{}
+}
+
+// Default polling settings for watch mode
+const (
+	DefaultWatchInterval = 500 * time.Millisecond
+	DefaultWatchDebounce = 1 * time.Second
+)
+
+// Watcher polls GOAL.md and .ai/design/*.md modification times and reports
+// when they change. It uses fs.FS and an injectable Clock so it can be tested
+// without touching the real filesystem or sleeping.
+type Watcher struct {
+	fsys      fs.FS
+	designDir string // Slash-separated design dir within fsys
+	clock     Clock
+	Interval  time.Duration // How often to poll for changes
+	Debounce  time.Duration // How long files must be quiet before a change is reported
+	last      map[string]time.Time
+}
+
+// NewWatcher creates a watcher over the given project filesystem, whose
+// state lives in stateDir
+func NewWatcher(fsys fs.FS, stateDir string, clock Clock) *Watcher {
+	w := &Watcher{
+		fsys:      fsys,
+		designDir: path.Join(stateDir, "design"),
+		clock:     clock,
+		Interval:  DefaultWatchInterval,
+		Debounce:  DefaultWatchDebounce,
+	}
+	w.Mark()
+	return w
+}
+
+// Mark records the current modification times as the baseline.
+// Call it after running a step so files written by the step itself
+// don't trigger another run.
+func (w *Watcher) Mark() {
+	w.last = w.snapshot()
+}
+
+// WaitForChange blocks until a watched file changes and then stays unchanged
+// for the debounce period. Returns false if stop is closed first.
+func (w *Watcher) WaitForChange(stop <-chan struct{}) bool {
+	for {
+		if stopped(stop) {
+			return false
+		}
+		w.clock.Sleep(w.Interval)
+		current := w.snapshot()
+		if sameSnapshot(current, w.last) {
+			continue
+		}
+
+		// Debounce: wait until no further edits arrive for the debounce period
+		w.last = current
+		quietSince := w.clock.Now()
+		for w.clock.Now().Sub(quietSince) < w.Debounce {
+			if stopped(stop) {
+				return false
+			}
+			w.clock.Sleep(w.Interval)
+			next := w.snapshot()
+			if !sameSnapshot(next, w.last) {
+				w.last = next
+				quietSince = w.clock.Now()
+			}
+		}
+		return true
+	}
+}
+
+// snapshot collects modification times for all watched files
+func (w *Watcher) snapshot() map[string]time.Time {
+	snap := make(map[string]time.Time)
+	if info, err := fs.Stat(w.fsys, "GOAL.md"); err == nil {
+		snap["GOAL.md"] = info.ModTime()
+	}
+	designDir := w.designDir
+	entries, err := fs.ReadDir(w.fsys, designDir)
+	if err != nil {
+		return snap
+	}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".md" {
+			continue
+		}
+		p := path.Join(designDir, e.Name())
+		if info, err := fs.Stat(w.fsys, p); err == nil {
+			snap[p] = info.ModTime()
+		}
+	}
+	return snap
+}
+
+// WatchLoop runs step once, then reruns it each time the watcher reports a
+// change, until step returns ExitDone, ExitError, or ExitHumanNeeded, or stop
+// is closed. Returns the last exit code produced by step.
+func WatchLoop(w *Watcher, step func() int, stop <-chan struct{}) int {
+	code := step()
+	for code != ExitDone && code != ExitError && code != ExitHumanNeeded {
+		w.Mark()
+		if !w.WaitForChange(stop) {
+			break
+		}
+		code = step()
+	}
+	return code
+}
+
+// DefaultStatusWatchInterval is how often status --watch redraws
+const DefaultStatusWatchInterval = 5 * time.Second
+
+// StatusWatcher redraws the project status on an interval, re-running
+// GetStatus each tick, for monitoring a run from another terminal
+type StatusWatcher struct {
+	proj     *project.Project
+	fsys     fs.FS
+	clock    Clock
+	Interval time.Duration
+	// Plain renders the --plain status instead of the default one
+	Plain bool
+	// Clear, if set, is called before each redraw to draw in place (e.g.
+	// SplitView.Clear on a TTY); otherwise each tick is printed below the last
+	Clear func()
+}
+
+// NewStatusWatcher creates a status watcher over the project filesystem
+func NewStatusWatcher(proj *project.Project, fsys fs.FS, clock Clock) *StatusWatcher {
+	return &StatusWatcher{
+		proj:     proj,
+		fsys:     fsys,
+		clock:    clock,
+		Interval: DefaultStatusWatchInterval,
+	}
+}
+
+// Run renders the status to out each interval until the project is
+// complete or stop (which may be nil) is closed. Returns the exit code of
+// the last status.
+func (w *StatusWatcher) Run(out io.Writer, stop <-chan struct{}) int {
+	for {
+		result := GetStatus(w.fsys, w.proj.StateDir())
+		var output string
+		if w.Plain {
+			output = formatStatusPlain(w.proj, result)
+		} else {
+			output, _ = formatStatus(w.proj, result)
+		}
+
+		if w.Clear != nil {
+			w.Clear()
+		}
+		fmt.Fprint(out, output)
+		code := GetExitCode(result)
+		if code == ExitDone {
+			return code
+		}
+		footer := fmt.Sprintf("Updated %s, refreshing every %s (Ctrl-C to stop)", w.clock.Now().Format("15:04:05"), w.Interval)
+		if !w.Plain {
+			footer = logging.Dim(footer)
+		}
+		fmt.Fprintf(out, "\n%s\n", footer)
+		if w.Clear == nil {
+			fmt.Fprintln(out)
+		}
+
+		if stopped(stop) {
+			return code
+		}
+		w.clock.Sleep(w.Interval)
+		if stopped(stop) {
+			return code
+		}
+	}
+}
+
+func sameSnapshot(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || !bv.Equal(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func stopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
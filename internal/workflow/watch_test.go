@@ -0,0 +1,184 @@
+package workflow
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// fakeClock advances time on Sleep and invokes onSleep so tests can
+// mutate the filesystem at specific points in the polling loop.
+type fakeClock struct {
+	now     time.Time
+	sleeps  int
+	onSleep func(n int)
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.now = c.now.Add(d)
+	c.sleeps++
+	if c.onSleep != nil {
+		c.onSleep(c.sleeps)
+	}
+}
+
+func TestWatchLoop_ChangeTriggersOneRerun(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"GOAL.md":                &fstest.MapFile{Data: []byte("# Goal"), ModTime: base},
+		".ai/design/overview.md": &fstest.MapFile{Data: []byte("# Design"), ModTime: base},
+	}
+
+	clock := &fakeClock{now: base}
+	// Simulate a burst of rapid edits: GOAL.md on the 2nd and 3rd poll,
+	// overview.md on the 4th. The debounce should coalesce them.
+	clock.onSleep = func(n int) {
+		switch n {
+		case 2, 3:
+			fsys["GOAL.md"].ModTime = base.Add(time.Duration(n) * time.Second)
+		case 4:
+			fsys[".ai/design/overview.md"].ModTime = base.Add(10 * time.Second)
+		}
+	}
+
+	w := NewWatcher(fsys, project.DefaultStateDir, clock)
+	w.Interval = 100 * time.Millisecond
+	w.Debounce = 500 * time.Millisecond
+
+	runs := 0
+	step := func() int {
+		runs++
+		if runs == 1 {
+			return ExitMoreWork
+		}
+		return ExitDone
+	}
+
+	stop := make(chan struct{})
+	code := WatchLoop(w, step, stop)
+
+	if code != ExitDone {
+		t.Errorf("expected exit %d, got %d", ExitDone, code)
+	}
+	if runs != 2 {
+		t.Errorf("expected initial run plus exactly one rerun (2), got %d", runs)
+	}
+}
+
+func TestWatchLoop_StopsOnHumanNeeded(t *testing.T) {
+	fsys := fstest.MapFS{
+		"GOAL.md": &fstest.MapFile{Data: []byte("# Goal")},
+	}
+	w := NewWatcher(fsys, project.DefaultStateDir, &fakeClock{})
+
+	runs := 0
+	code := WatchLoop(w, func() int {
+		runs++
+		return ExitHumanNeeded
+	}, make(chan struct{}))
+
+	if code != ExitHumanNeeded {
+		t.Errorf("expected exit %d, got %d", ExitHumanNeeded, code)
+	}
+	if runs != 1 {
+		t.Errorf("expected a single run, got %d", runs)
+	}
+}
+
+func TestWatchLoop_StopsOnError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"GOAL.md": &fstest.MapFile{Data: []byte("# Goal")},
+	}
+	w := NewWatcher(fsys, project.DefaultStateDir, &fakeClock{})
+
+	runs := 0
+	code := WatchLoop(w, func() int {
+		runs++
+		return ExitError
+	}, make(chan struct{}))
+
+	if code != ExitError || runs != 1 {
+		t.Errorf("expected a single run ending in exit %d, got %d after %d runs", ExitError, code, runs)
+	}
+}
+
+func TestWatcher_StopReturnsFalse(t *testing.T) {
+	fsys := fstest.MapFS{
+		"GOAL.md": &fstest.MapFile{Data: []byte("# Goal")},
+	}
+	w := NewWatcher(fsys, project.DefaultStateDir, &fakeClock{})
+	stop := make(chan struct{})
+	close(stop)
+
+	if w.WaitForChange(stop) {
+		t.Error("expected WaitForChange to return false when stopped")
+	}
+}
+
+func TestStatusWatcher_RedrawsUntilDone(t *testing.T) {
+	sprintPath := filepath.Join(".ai", "sprints", "01-initial.md")
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Task\n  - [x] go-coder: Write code\n  - [ ] _reviewer: Review\n",
+	})
+
+	// The sprint finishes during the second sleep
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	clock.onSleep = func(n int) {
+		if n == 2 {
+			done := "# Sprint 1\n\n- [x] Task\n  - [x] go-coder: Write code\n  - [x] _reviewer: Review\n"
+			if err := os.WriteFile(filepath.Join(tmpDir, sprintPath), []byte(done), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	w := NewStatusWatcher(project.New(tmpDir), os.DirFS(tmpDir), clock)
+	w.Plain = true
+	clears := 0
+	w.Clear = func() { clears++ }
+
+	var out bytes.Buffer
+	code := w.Run(&out, make(chan struct{}))
+	if code != ExitDone {
+		t.Errorf("expected exit %d once the sprint is done, got %d", ExitDone, code)
+	}
+	if clears != 3 {
+		t.Errorf("expected 3 redraws, got %d", clears)
+	}
+	if clock.sleeps != 2 {
+		t.Errorf("expected the watcher to stop after the sprint finished, slept %d times", clock.sleeps)
+	}
+	if n := strings.Count(out.String(), "refreshing every"); n != 2 {
+		t.Errorf("expected a footer on the 2 unfinished redraws, got %d:\n%s", n, out.String())
+	}
+}
+
+func TestStatusWatcher_StopsWhenInterrupted(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Task\n  - [ ] go-coder: Write code\n",
+	})
+	stop := make(chan struct{})
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	clock.onSleep = func(n int) {
+		if n == 3 {
+			close(stop)
+		}
+	}
+
+	var out bytes.Buffer
+	code := NewStatusWatcher(project.New(tmpDir), os.DirFS(tmpDir), clock).Run(&out, stop)
+	if code != ExitMoreWork {
+		t.Errorf("expected exit %d, got %d", ExitMoreWork, code)
+	}
+	if clock.sleeps != 3 {
+		t.Errorf("expected to stop after the interrupt, slept %d times", clock.sleeps)
+	}
+}
@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout bounds each progress POST so a slow dashboard can't stall a run
+const webhookTimeout = 10 * time.Second
+
+// WebhookPayload is the JSON body POSTed to a progress webhook after each step
+type WebhookPayload struct {
+	Phase     PlanPhase `json:"phase"`
+	Sprint    int       `json:"sprint"`
+	Completed int       `json:"completed"` // Checked sub-tasks in the current sprint
+	Total     int       `json:"total"`     // All sub-tasks in the current sprint
+	ExitCode  int       `json:"exitCode"`
+}
+
+// NewWebhookPayload builds a payload from the workflow state after a step
+func NewWebhookPayload(status StatusResult, exitCode int) WebhookPayload {
+	payload := WebhookPayload{
+		Phase:    status.Phase,
+		Sprint:   status.CurrentSprintNum,
+		ExitCode: exitCode,
+	}
+	if status.Sprint != nil {
+		payload.Completed, payload.Total = status.Sprint.GetOverallProgress()
+	}
+	return payload
+}
+
+// HTTPDoer sends an HTTP request. *http.Client satisfies it; tests inject a
+// fake to capture requests without a server.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Webhook POSTs progress payloads to a URL
+type Webhook struct {
+	URL    string
+	Client HTTPDoer
+}
+
+// NewWebhook creates a webhook for url using an HTTP client with a timeout
+func NewWebhook(url string) *Webhook {
+	return &Webhook{URL: url, Client: &http.Client{Timeout: webhookTimeout}}
+}
+
+// Post sends payload as JSON. Any non-2xx response is an error.
+func (w *Webhook) Post(payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/strongdm/agate/internal/project"
+)
+
+// fakeDoer records requests and answers with a fixed status
+type fakeDoer struct {
+	requests []*http.Request
+	bodies   []string
+	status   int
+	err      error
+}
+
+func (f *fakeDoer) Do(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	f.requests = append(f.requests, req)
+	f.bodies = append(f.bodies, string(body))
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &http.Response{
+		StatusCode: f.status,
+		Status:     http.StatusText(f.status),
+		Body:       io.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+// TestWebhook_PostsStepPayload simulates a step that checks one of three
+// sub-tasks and verifies the POSTed payload describes the resulting state.
+func TestWebhook_PostsStepPayload(t *testing.T) {
+	tmpDir := setupExecutionProject(t, map[string]string{
+		"01-initial.md": "# Sprint 1\n\n- [ ] Build parser\n  - [ ] go-coder: Write parser\n  - [ ] _reviewer: Review parser\n- [ ] Wire CLI\n  - [ ] go-coder: Add command\n",
+	})
+
+	result, err := NextWithOptions(project.New(tmpDir), NextOptions{PreferredAgent: "dummy"})
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if !result.MoreWork {
+		t.Fatal("expected more work after one step")
+	}
+
+	doer := &fakeDoer{status: http.StatusOK}
+	hook := &Webhook{URL: "https://dashboard.example/hook", Client: doer}
+	status := GetStatus(os.DirFS(tmpDir), project.DefaultStateDir)
+	if err := hook.Post(NewWebhookPayload(status, GetExitCode(status))); err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+
+	if len(doer.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(doer.requests))
+	}
+	req := doer.requests[0]
+	if req.Method != http.MethodPost || req.URL.String() != hook.URL {
+		t.Errorf("unexpected request %s %s", req.Method, req.URL)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(doer.bodies[0]), &got); err != nil {
+		t.Fatalf("invalid JSON body %q: %v", doer.bodies[0], err)
+	}
+	want := map[string]interface{}{
+		"phase":     "execution",
+		"sprint":    float64(1),
+		"completed": float64(1),
+		"total":     float64(3),
+		"exitCode":  float64(ExitMoreWork),
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, got[key])
+		}
+	}
+}
+
+func TestWebhook_PostErrors(t *testing.T) {
+	hook := &Webhook{URL: "https://dashboard.example/hook", Client: &fakeDoer{status: http.StatusInternalServerError}}
+	if err := hook.Post(WebhookPayload{}); err == nil || !strings.Contains(err.Error(), "Internal Server Error") {
+		t.Errorf("expected status error, got %v", err)
+	}
+
+	hook.Client = &fakeDoer{err: errors.New("connection refused")}
+	if err := hook.Post(WebhookPayload{}); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected transport error, got %v", err)
+	}
+}